@@ -0,0 +1,225 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// comparedHeaders are the response headers that must match. Other headers, like Date and
+// Content-Length, are not part of the contract.
+var comparedHeaders = []string{
+	"Content-Type",
+	"Content-Disposition",
+	"Location",
+	"Allow",
+	"Vary",
+	"WWW-Authenticate",
+	"Retry-After",
+	"RateLimit-Limit",
+	"RateLimit-Policy",
+	"Access-Control-Allow-Origin",
+	"Access-Control-Allow-Methods",
+	"Access-Control-Allow-Headers",
+	"Access-Control-Expose-Headers",
+	"Access-Control-Max-Age",
+}
+
+// presentHeaders must be set by both servers or neither; their values differ per request.
+var presentHeaders = []string{"X-Request-ID", "RateLimit-Remaining", "RateLimit-Reset"}
+
+// volatileFields are body fields whose values differ between runs.
+var volatileFields = []string{"created_at", "updated_at", "deleted_at", "timestamp"}
+
+// result is a response of a server, normalized so that responses of both servers to the
+// same step can be compared.
+type result struct {
+	err     string
+	status  int
+	headers map[string]string
+	body    interface{} // Normalized JSON, or the text of other bodies
+}
+
+// normalize turns a response into a result. The values of the run's variables are replaced
+// with their placeholders, so that "{{day}}" compares equal although each server got its
+// own day.
+func normalize(resp *http.Response, body []byte, st step, v vars) result {
+	r := result{status: resp.StatusCode, headers: make(map[string]string)}
+	for _, name := range comparedHeaders {
+		if value := resp.Header.Get(name); value != "" {
+			if name == "Content-Type" {
+				// Fiber leaves out the charset parameter Gin adds to JSON; both mean UTF-8.
+				if mediaType, _, err := mime.ParseMediaType(value); err == nil {
+					value = mediaType
+				}
+			}
+			r.headers[name] = v.replaceValues(value)
+		}
+	}
+	for _, name := range presentHeaders {
+		if resp.Header.Get(name) != "" {
+			r.headers[name] = "<present>"
+		}
+	}
+	if st.SkipBody {
+		return r
+	}
+
+	ignore := make(map[string]bool)
+	for _, field := range append(volatileFields, st.Ignore...) {
+		ignore[field] = true
+	}
+	var parsed interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if len(body) > 0 && decoder.Decode(&parsed) == nil && !decoder.More() {
+		r.body = v.normalizeJSON("", parsed, ignore)
+	} else {
+		r.body = v.replaceValues(string(body))
+	}
+	return r
+}
+
+// replaceValues replaces the values of the variables in s with their placeholders. Numeric
+// values are only replaced in JSON ID fields, since they would match unrelated numbers.
+func (v vars) replaceValues(s string) string {
+	names := make([]string, 0, len(v))
+	for name, value := range v {
+		if _, err := strconv.Atoi(value); err != nil && value != "" {
+			names = append(names, name)
+		}
+	}
+	// Longer values first, so that a value containing another is replaced whole.
+	sort.Slice(names, func(i, j int) bool { return len(v[names[i]]) > len(v[names[j]]) })
+	for _, name := range names {
+		s = strings.ReplaceAll(s, v[name], "{{"+name+"}}")
+	}
+	return s
+}
+
+func (v vars) normalizeJSON(key string, value interface{}, ignore map[string]bool) interface{} {
+	if ignore[key] {
+		return "<ignored>"
+	}
+	switch val := value.(type) {
+	case map[string]interface{}:
+		for k, item := range val {
+			val[k] = v.normalizeJSON(k, item, ignore)
+		}
+		return val
+	case []interface{}:
+		for i, item := range val {
+			val[i] = v.normalizeJSON(key, item, ignore)
+		}
+		return val
+	case string:
+		return v.replaceValues(val)
+	case json.Number:
+		if key == "id" || strings.HasSuffix(key, "_id") {
+			for name, captured := range v {
+				if captured == val.String() {
+					return "{{" + name + "}}"
+				}
+			}
+		}
+		return val
+	default:
+		return val
+	}
+}
+
+// diff lists the differences between the results of two servers.
+func diff(names [2]string, a, b result) []string {
+	var diffs []string
+	if a.err != b.err {
+		return []string{fmt.Sprintf("error: %s %q, %s %q", names[0], a.err, names[1], b.err)}
+	}
+	if a.status != b.status {
+		diffs = append(diffs, fmt.Sprintf("status: %s %d, %s %d", names[0], a.status, names[1], b.status))
+	}
+	headers := make(map[string]bool)
+	for name := range a.headers {
+		headers[name] = true
+	}
+	for name := range b.headers {
+		headers[name] = true
+	}
+	sorted := make([]string, 0, len(headers))
+	for name := range headers {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	for _, name := range sorted {
+		if a.headers[name] != b.headers[name] {
+			diffs = append(diffs, fmt.Sprintf("header %s: %s %q, %s %q", name, names[0], a.headers[name], names[1], b.headers[name]))
+		}
+	}
+	diffValues(names, "body", a.body, b.body, &diffs)
+	return diffs
+}
+
+// maxBodyDiffs caps the body differences reported per step.
+const maxBodyDiffs = 10
+
+func diffValues(names [2]string, path string, a, b interface{}, diffs *[]string) {
+	if len(*diffs) >= maxBodyDiffs || reflect.DeepEqual(a, b) {
+		return
+	}
+	switch av := a.(type) {
+	case map[string]interface{}:
+		if bv, ok := b.(map[string]interface{}); ok {
+			keys := make(map[string]bool)
+			for k := range av {
+				keys[k] = true
+			}
+			for k := range bv {
+				keys[k] = true
+			}
+			sorted := make([]string, 0, len(keys))
+			for k := range keys {
+				sorted = append(sorted, k)
+			}
+			sort.Strings(sorted)
+			for _, k := range sorted {
+				x, inA := av[k]
+				y, inB := bv[k]
+				switch {
+				case !inA:
+					*diffs = append(*diffs, fmt.Sprintf("%s.%s: only in %s", path, k, names[1]))
+				case !inB:
+					*diffs = append(*diffs, fmt.Sprintf("%s.%s: only in %s", path, k, names[0]))
+				default:
+					diffValues(names, path+"."+k, x, y, diffs)
+				}
+			}
+			return
+		}
+	case []interface{}:
+		if bv, ok := b.([]interface{}); ok && len(av) == len(bv) {
+			for i := range av {
+				diffValues(names, fmt.Sprintf("%s[%d]", path, i), av[i], bv[i], diffs)
+			}
+			return
+		}
+	}
+	*diffs = append(*diffs, fmt.Sprintf("%s: %s %s, %s %s", path, names[0], show(a), names[1], show(b)))
+}
+
+// show renders a value of a diff, shortened.
+func show(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		data = []byte(fmt.Sprint(v))
+	}
+	const limit = 200
+	if len(data) > limit {
+		return string(data[:limit]) + "..."
+	}
+	return string(data)
+}
@@ -0,0 +1,340 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/config"
+	"github.com/aebalz/daily-vibe-tracker/internal/graph"
+	"github.com/aebalz/daily-vibe-tracker/internal/handler"
+	"github.com/aebalz/daily-vibe-tracker/internal/model"
+	"github.com/aebalz/daily-vibe-tracker/internal/repository"
+	"github.com/aebalz/daily-vibe-tracker/internal/service"
+	fiberserver "github.com/aebalz/daily-vibe-tracker/pkg/fiber"
+	ginserver "github.com/aebalz/daily-vibe-tracker/pkg/gin"
+)
+
+const contractAPIKey = "vbt_contract"
+
+// memVibes is an in-memory vibe service, shared by both servers like a database. As with the
+// unique index on the date, there is at most one vibe per day.
+type memVibes struct {
+	mu     sync.Mutex
+	vibes  map[uint]model.Vibe
+	nextID uint
+}
+
+func (s *memVibes) WithContext(ctx context.Context) service.VibeServiceInterface { return s }
+
+func (s *memVibes) CreateVibe(vibe *model.Vibe) (*model.Vibe, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, existing := range s.vibes {
+		if existing.Date.Equal(vibe.Date) {
+			return nil, fmt.Errorf("%w: a vibe already exists on %s", service.ErrValidation, vibe.Date.Format("2006-01-02"))
+		}
+	}
+	s.nextID++
+	created := *vibe
+	created.ID = s.nextID
+	created.CreatedAt = time.Now().UTC()
+	created.UpdatedAt = created.CreatedAt
+	s.vibes[created.ID] = created
+	return &created, nil
+}
+
+func (s *memVibes) GetVibeByID(id uint) (*model.Vibe, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	vibe, ok := s.vibes[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &vibe, nil
+}
+
+func (s *memVibes) GetVibesByIDs(ids []uint) ([]model.Vibe, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var vibes []model.Vibe
+	for _, id := range ids {
+		if vibe, ok := s.vibes[id]; ok {
+			vibes = append(vibes, vibe)
+		}
+	}
+	return vibes, nil
+}
+
+// selected returns the vibes matching the date and mood filters, ordered by date.
+func (s *memVibes) selected(filters map[string]interface{}, sortOrder string) []model.Vibe {
+	var vibes []model.Vibe
+	for _, vibe := range s.vibes {
+		day := vibe.Date.Format("2006-01-02")
+		if date, ok := filters["date"].(string); ok && day != date {
+			continue
+		}
+		if start, ok := filters["start_date"].(string); ok && day < start {
+			continue
+		}
+		if end, ok := filters["end_date"].(string); ok && day > end {
+			continue
+		}
+		if mood, ok := filters["mood"].(string); ok && vibe.Mood != mood {
+			continue
+		}
+		vibes = append(vibes, vibe)
+	}
+	sort.Slice(vibes, func(i, j int) bool {
+		if sortOrder == "asc" {
+			return vibes[i].Date.Before(vibes[j].Date)
+		}
+		return vibes[i].Date.After(vibes[j].Date)
+	})
+	return vibes
+}
+
+func (s *memVibes) GetAllVibes(filters map[string]interface{}, limit, offset int, sortBy, sortOrder string) ([]model.Vibe, int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	vibes := s.selected(filters, sortOrder)
+	total := int64(len(vibes))
+	vibes = vibes[min(offset, len(vibes)):]
+	return vibes[:min(limit, len(vibes))], total, nil
+}
+
+func (s *memVibes) UpdateVibe(id uint, updated *model.Vibe) (*model.Vibe, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	vibe, ok := s.vibes[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	vibe.Mood, vibe.EnergyLevel, vibe.Notes, vibe.Activities = updated.Mood, updated.EnergyLevel, updated.Notes, updated.Activities
+	vibe.UpdatedAt = time.Now().UTC()
+	s.vibes[id] = vibe
+	return &vibe, nil
+}
+
+func (s *memVibes) DeleteVibe(id uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.vibes[id]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	delete(s.vibes, id)
+	return nil
+}
+
+func (s *memVibes) GetVibeStatistics(period string) (map[string]interface{}, error) {
+	return map[string]interface{}{"period": period}, nil
+}
+
+func (s *memVibes) GetMoodStreak(mood string) (map[string]interface{}, error) {
+	if mood == "" {
+		return nil, fmt.Errorf("%w: mood is required", service.ErrValidation)
+	}
+	return map[string]interface{}{"mood": mood}, nil
+}
+
+func (s *memVibes) ExportVibes(filters map[string]interface{}, format string, sortBy, sortOrder string) ([]byte, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := json.Marshal(s.selected(filters, sortOrder))
+	return data, "application/json", err
+}
+
+func (s *memVibes) BulkImportVibes(vibes []*model.Vibe) (int64, error) {
+	return 0, fmt.Errorf("%w: bulk imports are not supported in the contract test", service.ErrValidation)
+}
+
+// memMoods is an in-memory mood repository behind the real mood service.
+type memMoods struct {
+	mu     sync.Mutex
+	moods  map[uint]model.Mood
+	nextID uint
+}
+
+func (r *memMoods) WithTx(tx *gorm.DB) repository.MoodRepositoryInterface              { return r }
+func (r *memMoods) WithContext(ctx context.Context) repository.MoodRepositoryInterface { return r }
+
+func (r *memMoods) CreateMood(mood *model.Mood) (*model.Mood, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	created := *mood
+	created.ID = r.nextID
+	created.CreatedAt = time.Now().UTC()
+	created.UpdatedAt = created.CreatedAt
+	r.moods[created.ID] = created
+	return &created, nil
+}
+
+func (r *memMoods) CreateMoodIfAbsent(mood *model.Mood) (*model.Mood, error) {
+	if existing, err := r.FindMoodByNameOrAlias(mood.Name); err == nil {
+		return existing, nil
+	}
+	return r.CreateMood(mood)
+}
+
+func (r *memMoods) GetMoodByID(id uint) (*model.Mood, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	mood, ok := r.moods[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &mood, nil
+}
+
+func (r *memMoods) GetAllMoods() ([]model.Mood, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	moods := make([]model.Mood, 0, len(r.moods))
+	for _, mood := range r.moods {
+		moods = append(moods, mood)
+	}
+	sort.Slice(moods, func(i, j int) bool { return moods[i].Name < moods[j].Name })
+	return moods, nil
+}
+
+func (r *memMoods) UpdateMood(id uint, updated *model.Mood) (*model.Mood, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	mood, ok := r.moods[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	updated.ID, updated.CreatedAt, updated.UpdatedAt = id, mood.CreatedAt, time.Now().UTC()
+	r.moods[id] = *updated
+	return updated, nil
+}
+
+func (r *memMoods) DeleteMood(id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.moods[id]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	delete(r.moods, id)
+	return nil
+}
+
+func (r *memMoods) FindMoodByNameOrAlias(name string) (*model.Mood, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, mood := range r.moods {
+		if mood.Name == name || slices.Contains(mood.Aliases, name) {
+			return &mood, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memMoods) CountMoods() (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return int64(len(r.moods)), nil
+}
+
+// contractUsers accepts the API key of the contract runner only.
+type contractUsers struct{}
+
+func (contractUsers) CreateUser(user *model.User) (*model.User, error) { return user, nil }
+
+func (contractUsers) CreateAPIKey(username, name string, expiresAt *time.Time) (string, *model.APIKey, error) {
+	return "", nil, nil
+}
+
+func (contractUsers) Authenticate(key string) (*model.User, error) {
+	if key != contractAPIKey {
+		return nil, service.ErrInvalidAPIKey
+	}
+	return &model.User{Username: "contract"}, nil
+}
+
+func (u contractUsers) WithContext(ctx context.Context) service.UserServiceInterface { return u }
+
+// newContractHandler wires the real handlers to in-memory services shared by both servers.
+func newContractHandler(t *testing.T, cfg *config.AppConfig) *handler.VibeHandler {
+	vibeSvc := &memVibes{vibes: make(map[uint]model.Vibe)}
+	moodSvc := service.NewMoodService(&memMoods{moods: make(map[uint]model.Mood)}, cfg)
+	if err := moodSvc.EnsureDefaultMoods(); err != nil {
+		t.Fatal(err)
+	}
+	graphServer, err := graph.NewServer(vibeSvc, moodSvc, nil, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &handler.VibeHandler{
+		Service:        vibeSvc,
+		MoodHandler:    handler.NewMoodHandler(moodSvc),
+		GraphQLHandler: handler.NewGraphQLHandler(graphServer),
+		AuthHandler:    handler.NewAuthHandler(contractUsers{}, true),
+	}
+}
+
+// TestScenarios replays the built-in scenarios against a Fiber and a Gin server built from
+// the same handlers, and fails on any difference between their responses.
+func TestScenarios(t *testing.T) {
+	scenarios, err := loadScenarios("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.AppConfig{
+		AppName:              "contract-test",
+		AppEnv:               "production",
+		CorsAllowedOrigins:   []string{"https://vibes.example.com"},
+		MoodUnknownPolicy:    service.MoodPolicyReject,
+		GraphQLMaxDepth:      10,
+		GraphQLMaxComplexity: 1000,
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	vibeHandler := newContractHandler(t, cfg)
+	// Fiber is served by fasthttp, as in production; its adaptor to net/http would change
+	// the headers under test.
+	app := fiberserver.NewFiberServer(cfg, vibeHandler, nil, logger)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() { _ = app.Listener(listener) }()
+	t.Cleanup(func() { _ = app.Shutdown() })
+	ginServer := httptest.NewServer(ginserver.NewGinServer(cfg, vibeHandler, nil, logger))
+	t.Cleanup(ginServer.Close)
+
+	var out strings.Builder
+	r := &runner{
+		client: &http.Client{
+			Timeout:       10 * time.Second,
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+		targets: [2]target{
+			{name: "fiber", baseURL: "http://" + listener.Addr().String()},
+			{name: "gin", baseURL: ginServer.URL},
+		},
+		apiKey: contractAPIKey,
+		out:    &out,
+	}
+	steps, differing := r.run(scenarios)
+	if steps == 0 {
+		t.Fatal("no scenario steps ran")
+	}
+	if differing > 0 {
+		t.Fatalf("%d of %d steps differ between the servers:\n%s", differing, steps, out.String())
+	}
+}
@@ -0,0 +1,204 @@
+// Command contract checks that the Fiber and Gin servers expose the same API. It replays the
+// same scenarios of requests against each server and reports the differences between their
+// status codes, headers and bodies. Run the servers side by side with SERVER_FRAMEWORK=both:
+//
+//	go run ./cmd/contract -fiber http://localhost:8080 -gin http://localhost:8081
+//
+// The scenarios create and delete their own data, so the servers may share a database, but
+// other clients writing at the same time can cause false differences.
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+//go:embed scenarios.yaml
+var defaultScenarios []byte
+
+// target is a server under test.
+type target struct {
+	name    string
+	baseURL string
+}
+
+type runner struct {
+	client  *http.Client
+	targets [2]target
+	apiKey  string
+	out     io.Writer
+	verbose bool
+}
+
+func main() {
+	fiberURL := flag.String("fiber", "http://localhost:8080", "Base URL of the Fiber server")
+	ginURL := flag.String("gin", "http://localhost:8081", "Base URL of the Gin server")
+	scenarioPath := flag.String("scenarios", "", "YAML file of scenarios (default: the built-in scenarios)")
+	apiKey := flag.String("api-key", os.Getenv("VIBE_API_KEY"), "API key sent as X-API-Key")
+	only := flag.String("run", "", "Only run scenarios whose name matches this regular expression")
+	verbose := flag.Bool("v", false, "Also list the steps without differences")
+	timeout := flag.Duration("timeout", 10*time.Second, "Timeout of each request")
+	flag.Parse()
+
+	scenarios, err := loadScenarios(*scenarioPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *only != "" {
+		pattern, err := regexp.Compile(*only)
+		if err != nil {
+			log.Fatalf("invalid -run pattern: %v", err)
+		}
+		var selected []scenario
+		for _, sc := range scenarios {
+			if pattern.MatchString(sc.Name) {
+				selected = append(selected, sc)
+			}
+		}
+		scenarios = selected
+	}
+
+	r := &runner{
+		client: &http.Client{
+			Timeout: *timeout,
+			// Redirects are part of the contract.
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+		targets: [2]target{
+			{name: "fiber", baseURL: strings.TrimSuffix(*fiberURL, "/")},
+			{name: "gin", baseURL: strings.TrimSuffix(*ginURL, "/")},
+		},
+		apiKey:  *apiKey,
+		out:     os.Stdout,
+		verbose: *verbose,
+	}
+	steps, differing := r.run(scenarios)
+	fmt.Fprintf(r.out, "\n%d scenarios, %d steps, %d with differences\n", len(scenarios), steps, differing)
+	if differing > 0 {
+		os.Exit(1)
+	}
+}
+
+// run replays the scenarios and returns the number of steps and of steps with differences.
+func (r *runner) run(scenarios []scenario) (int, int) {
+	steps, differing := 0, 0
+	names := [2]string{r.targets[0].name, r.targets[1].name}
+	for _, sc := range scenarios {
+		fmt.Fprintf(r.out, "%s\n", sc.Name)
+		// Each server runs the whole scenario before the other, with its own day and run ID,
+		// so that the data of one run does not get in the way of the other.
+		day := time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, rand.Intn(7000))
+		runID := fmt.Sprintf("%06x", rand.Intn(1<<24))
+		var results [2][]result
+		for i, t := range r.targets {
+			v := vars{"day": day.AddDate(0, 0, i).Format("2006-01-02"), "run": runID + "-" + t.name}
+			for _, st := range sc.Steps {
+				results[i] = append(results[i], r.do(t, st, v))
+			}
+		}
+		for i, st := range sc.Steps {
+			steps++
+			label := fmt.Sprintf("%s %s", st.Method, st.Path)
+			if st.Name != "" {
+				label = st.Name + " (" + label + ")"
+			}
+			diffs := diff(names, results[0][i], results[1][i])
+			if len(diffs) == 0 {
+				if r.verbose {
+					fmt.Fprintf(r.out, "  ok    %s: %d\n", label, results[0][i].status)
+				}
+				continue
+			}
+			differing++
+			fmt.Fprintf(r.out, "  DIFF  %s\n", label)
+			for _, d := range diffs {
+				fmt.Fprintf(r.out, "        %s\n", d)
+			}
+		}
+	}
+	return steps, differing
+}
+
+// do sends the request of a step to a server.
+func (r *runner) do(t target, st step, v vars) result {
+	path, err := v.expand(st.Path)
+	if err != nil {
+		return result{err: err.Error()}
+	}
+	var body io.Reader
+	contentType := ""
+	switch {
+	case st.RawBody != nil:
+		raw, err := v.expand(*st.RawBody)
+		if err != nil {
+			return result{err: err.Error()}
+		}
+		body, contentType = strings.NewReader(raw), "application/json"
+	case st.Body != nil:
+		value, err := v.expandValue(st.Body)
+		if err != nil {
+			return result{err: err.Error()}
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return result{err: err.Error()}
+		}
+		body, contentType = bytes.NewReader(data), "application/json"
+	}
+
+	req, err := http.NewRequest(strings.ToUpper(st.Method), t.baseURL+path, body)
+	if err != nil {
+		return result{err: err.Error()}
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if r.apiKey != "" && !st.NoAuth {
+		req.Header.Set("X-API-Key", r.apiKey)
+	}
+	for name, value := range st.Headers {
+		expanded, err := v.expand(value)
+		if err != nil {
+			return result{err: err.Error()}
+		}
+		req.Header.Set(name, expanded)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		// The message names the server; keep only what went wrong.
+		return result{err: strings.ReplaceAll(err.Error(), t.baseURL, "")}
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return result{err: err.Error()}
+	}
+
+	// Capture before normalizing, so that the captured IDs are replaced in this response too.
+	captureErr := ""
+	if len(st.Capture) > 0 {
+		var parsed interface{}
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		if err := decoder.Decode(&parsed); err != nil {
+			captureErr = fmt.Sprintf("cannot capture from a response that is not JSON (status %d)", resp.StatusCode)
+		} else if err := v.capture(st.Capture, parsed); err != nil {
+			captureErr = err.Error()
+		}
+	}
+	res := normalize(resp, data, st, v)
+	res.err = captureErr
+	return res
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// scenarioFile is the YAML file of scenarios; see scenarios.yaml for the format.
+type scenarioFile struct {
+	Scenarios []scenario `yaml:"scenarios"`
+}
+
+// scenario is a sequence of requests replayed against each server in turn. Scenarios must
+// clean up what they create, because the servers share the database.
+type scenario struct {
+	Name  string `yaml:"name"`
+	Steps []step `yaml:"steps"`
+}
+
+type step struct {
+	Name    string            `yaml:"name"`
+	Method  string            `yaml:"method"`
+	Path    string            `yaml:"path"`
+	Headers map[string]string `yaml:"headers"`
+	NoAuth  bool              `yaml:"no_auth"` // Do not send the -api-key
+	Body    interface{}       `yaml:"body"`    // Sent as JSON
+	RawBody *string           `yaml:"raw_body"`
+	// Capture stores fields of the JSON response, by dotted path, in variables.
+	Capture map[string]string `yaml:"capture"`
+	// Ignore lists body fields, by name at any depth, whose values are not compared.
+	Ignore []string `yaml:"ignore"`
+	// SkipBody compares only the status and headers, for bodies that cannot match, like
+	// exports with database IDs.
+	SkipBody bool `yaml:"skip_body"`
+}
+
+func loadScenarios(path string) ([]scenario, error) {
+	data := defaultScenarios
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, err
+		}
+	}
+	var file scenarioFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("could not parse scenarios: %w", err)
+	}
+	for _, sc := range file.Scenarios {
+		for i, st := range sc.Steps {
+			if st.Method == "" || st.Path == "" {
+				return nil, fmt.Errorf("scenario %q: step %d needs a method and a path", sc.Name, i+1)
+			}
+		}
+	}
+	return file.Scenarios, nil
+}
+
+// vars holds the variables of a scenario run against one server: the built-in "day" and
+// "run", which differ per server so the runs do not collide, and captured values.
+type vars map[string]string
+
+var placeholder = regexp.MustCompile(`\{\{\s*([a-zA-Z0-9_]+)\s*\}\}`)
+
+// expand replaces {{name}} with the value of a variable.
+func (v vars) expand(s string) (string, error) {
+	var missing string
+	out := placeholder.ReplaceAllStringFunc(s, func(m string) string {
+		name := placeholder.FindStringSubmatch(m)[1]
+		value, ok := v[name]
+		if !ok {
+			missing = name
+		}
+		return value
+	})
+	if missing != "" {
+		return "", fmt.Errorf("unknown variable %q", missing)
+	}
+	return out, nil
+}
+
+// expandValue expands the strings of a YAML value. A string that is only a placeholder of
+// a number, like "{{goal_id}}", becomes that number.
+func (v vars) expandValue(value interface{}) (interface{}, error) {
+	switch val := value.(type) {
+	case string:
+		expanded, err := v.expand(val)
+		if err != nil {
+			return nil, err
+		}
+		if m := placeholder.FindStringSubmatch(val); m != nil && m[0] == val {
+			if n, err := strconv.ParseInt(expanded, 10, 64); err == nil {
+				return n, nil
+			}
+		}
+		return expanded, nil
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			expanded, err := v.expandValue(item)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = expanded
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			expanded, err := v.expandValue(item)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = expanded
+		}
+		return out, nil
+	default:
+		return value, nil
+	}
+}
+
+// capture stores the fields of a JSON body named by dotted paths, like "data.0.id".
+func (v vars) capture(fields map[string]string, body interface{}) error {
+	for name, path := range fields {
+		value := body
+		for _, key := range strings.Split(path, ".") {
+			switch node := value.(type) {
+			case map[string]interface{}:
+				value = node[key]
+			case []interface{}:
+				i, err := strconv.Atoi(key)
+				if err != nil || i < 0 || i >= len(node) {
+					return fmt.Errorf("cannot capture %s: no element %q", name, key)
+				}
+				value = node[i]
+			default:
+				return fmt.Errorf("cannot capture %s: no field %q", name, key)
+			}
+		}
+		switch val := value.(type) {
+		case nil, map[string]interface{}, []interface{}:
+			return fmt.Errorf("cannot capture %s: %q is not a scalar", name, path)
+		case json.Number:
+			v[name] = val.String()
+		default:
+			v[name] = fmt.Sprint(val)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/config"
+	"github.com/aebalz/daily-vibe-tracker/internal/repository"
+	"github.com/aebalz/daily-vibe-tracker/pkg/database"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+)
+
+// Check results of the doctor command.
+const (
+	checkOK   = "ok"
+	checkWarn = "warn"
+	checkFail = "FAIL"
+)
+
+func newDoctorCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the configuration, database connectivity and schema",
+		Long: `Checks the configuration for invalid and unsafe values, connects to the database and
+looks for pending migrations, an empty mood catalog and vibes waiting in the trash.
+Exits with an error if a check fails; warnings do not fail.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+			failed := 0
+			report := func(status, name, detail string) {
+				if status == checkFail {
+					failed++
+				}
+				fmt.Fprintf(out, "[%-4s] %-12s %s\n", status, name, detail)
+			}
+
+			for _, w := range a.configWarnings {
+				report(checkWarn, "config", w)
+			}
+			for _, w := range configAdvice(a.cfg) {
+				report(checkWarn, "config", w)
+			}
+			if len(a.configWarnings) == 0 {
+				report(checkOK, "config", fmt.Sprintf("loaded (APP_ENV=%s, SERVER_FRAMEWORK=%s)", a.cfg.AppEnv, a.cfg.ServerFramework))
+			}
+
+			db, err := a.connectDB()
+			if err != nil {
+				report(checkFail, "database", err.Error())
+				return fmt.Errorf("doctor found %d problem(s)", failed)
+			}
+			defer database.CloseDB()
+			checkDatabase(db, report)
+
+			if failed > 0 {
+				return fmt.Errorf("doctor found %d problem(s)", failed)
+			}
+			fmt.Fprintln(out, "No problems found.")
+			return nil
+		},
+	}
+}
+
+// checkDatabase checks connectivity, the schema and the data the server depends on.
+func checkDatabase(db *gorm.DB, report func(status, name, detail string)) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	started := time.Now()
+	var version string
+	if err := db.WithContext(ctx).Raw("SELECT version()").Scan(&version).Error; err != nil {
+		report(checkFail, "database", fmt.Sprintf("query failed: %v", err))
+		return
+	}
+	if name, _, ok := strings.Cut(version, " on "); ok {
+		version = name
+	}
+	report(checkOK, "database", fmt.Sprintf("%s, answered in %s", version, time.Since(started).Round(time.Millisecond)))
+
+	pending, err := database.PendingMigrations(db)
+	switch {
+	case err != nil:
+		report(checkFail, "migrations", err.Error())
+		return
+	case len(pending) > 0:
+		report(checkFail, "migrations", fmt.Sprintf("%d pending (%s); run \"server migrate\"", len(pending), strings.Join(pending, ", ")))
+		return
+	default:
+		report(checkOK, "migrations", "schema is up to date")
+	}
+
+	if count, err := repository.NewMoodRepository(db, nil).CountMoods(); err != nil {
+		report(checkFail, "moods", err.Error())
+	} else if count == 0 {
+		report(checkWarn, "moods", "the mood catalog is empty; \"server migrate\" seeds it")
+	} else {
+		report(checkOK, "moods", fmt.Sprintf("%d moods in the catalog", count))
+	}
+
+	if count, err := repository.NewVibeRepository(db, nil).CountDeletedVibes(time.Now()); err != nil {
+		report(checkFail, "trash", err.Error())
+	} else if count > 0 {
+		report(checkWarn, "trash", fmt.Sprintf("%d deleted vibes still block their days; \"server purge-trash\" removes them", count))
+	} else {
+		report(checkOK, "trash", "empty")
+	}
+}
+
+// configAdvice lists settings that are valid but unsafe or inconsistent.
+func configAdvice(cfg *config.AppConfig) []string {
+	var advice []string
+	production := cfg.AppEnv == "production"
+	if production && cfg.DBPassword == "password" {
+		advice = append(advice, "DB_PASSWORD is the default password in production")
+	}
+	if production && cfg.DBSslMode == "disable" {
+		advice = append(advice, "DB_SSL_MODE=disable in production")
+	}
+	if production && len(cfg.CorsAllowedOrigins) == 1 && cfg.CorsAllowedOrigins[0] == "*" {
+		advice = append(advice, "CORS_ALLOWED_ORIGINS allows every origin in production")
+	}
+	for _, proxy := range cfg.TrustedProxies {
+		if prefix, err := netip.ParsePrefix(proxy); err == nil && prefix.Bits() == 0 {
+			advice = append(advice, fmt.Sprintf("TRUSTED_PROXIES %s trusts every address; clients can set their own IP", proxy))
+		}
+	}
+	if production && !cfg.AuthRequired {
+		advice = append(advice, "AUTH_REQUIRED is off in production; the HTTP API accepts anonymous requests")
+	}
+	if cfg.GRPCPort > 0 && len(cfg.GRPCAPIKeys) == 0 {
+		advice = append(advice, "the gRPC server runs without GRPC_API_KEYS")
+	}
+	if cfg.SMTPHost != "" && cfg.SMTPFrom == "" {
+		advice = append(advice, "SMTP_HOST is set without SMTP_FROM")
+	}
+	if cfg.ServerWriteTimeout > 0 && cfg.ServerWriteTimeout < time.Second {
+		advice = append(advice, fmt.Sprintf("SERVER_WRITE_TIMEOUT %s is very short; exports may be cut off", cfg.ServerWriteTimeout))
+	}
+	return advice
+}
@@ -1,21 +1,27 @@
+// Command server runs the Daily Vibe Tracker API and its maintenance tasks.
+//
+//	server                      # same as "server serve"
+//	server migrate
+//	server seed --days 365
+//	server create-user alex && server create-api-key alex --name laptop
+//	server doctor
+//
+// Every command reads the configuration with config.LoadConfig from --env-file and the
+// environment.
 package main
 
 import (
-	"log"
+	"fmt"
+	"log/slog"
 	"os"
-	"os/signal"
-	"syscall"
-	"time"
+	"strings"
 
-	"github.com/aebalz/daily-vibe-tracker/docs"
 	"github.com/aebalz/daily-vibe-tracker/internal/config"
-	"github.com/aebalz/daily-vibe-tracker/internal/handler"
-	"github.com/aebalz/daily-vibe-tracker/internal/repository"
-	"github.com/aebalz/daily-vibe-tracker/internal/service"
+	"github.com/aebalz/daily-vibe-tracker/internal/logging"
 	"github.com/aebalz/daily-vibe-tracker/pkg/database"
-
-	fiberserver "github.com/aebalz/daily-vibe-tracker/pkg/fiber"
-	ginserver "github.com/aebalz/daily-vibe-tracker/pkg/gin"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 )
 
 // @title Daily Vibe Tracker API
@@ -34,99 +40,95 @@ import (
 // @BasePath /
 // @schemes http https
 func main() {
-	// Load configuration
-	cfg, err := config.LoadConfig("config.env")
-	if err != nil {
-		log.Fatalf("Failed to load configuration: %v", err)
+	if err := newRootCmd().Execute(); err != nil {
+		os.Exit(1)
 	}
+}
 
-	// Setup logger (can be more sophisticated, e.g., using zerolog based on cfg.LogLevel)
-	log.SetOutput(os.Stdout)
-	log.Printf("Log level set to: %s", cfg.LogLevel) // Simple log, can be enhanced
+// app holds the configuration shared by the commands.
+type app struct {
+	envFile string
+	verbose bool
 
-	// Update Swagger info based on config
-	docs.SwaggerInfo.Version = "1.0" // Prompt specified version 1.0
-	docs.SwaggerInfo.Title = cfg.AppName + " - Daily Vibe Tracker API"
-	docs.SwaggerInfo.Description = "Complete API for the Daily Vibe Tracker application, including vibe management, analytics, and advanced features."
-	docs.SwaggerInfo.Host = cfg.SwaggerHost
-	docs.SwaggerInfo.BasePath = cfg.SwaggerBasePath // Should be /api/v1 as per spec for vibe routes
-	docs.SwaggerInfo.Schemes = cfg.SwaggerSchemes
+	cfg            *config.AppConfig
+	configWarnings []string     // Warnings returned while loading the configuration
+	logger         *slog.Logger // Logger of the maintenance commands, on stderr; serve logs to stdout
+}
 
-	// Connect to database
-	db, err := database.ConnectDB(cfg)
-	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+func newRootCmd() *cobra.Command {
+	a := &app{}
+	serveCmd := newServeCmd(a)
+	root := &cobra.Command{
+		Use:          "server",
+		Short:        "Daily Vibe Tracker API server and maintenance commands",
+		SilenceUsage: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return a.loadConfig()
+		},
+		// Without a command the server runs, as it always has.
+		Args: cobra.NoArgs,
+		Run:  serveCmd.Run,
 	}
-	defer database.CloseDB()
+	root.PersistentFlags().StringVar(&a.envFile, "env-file", "config.env", "Configuration file; environment variables take precedence")
+	root.PersistentFlags().BoolVarP(&a.verbose, "verbose", "v", false, "Log SQL statements of maintenance commands")
+	root.AddCommand(
+		serveCmd,
+		newMigrateCmd(a),
+		newSeedCmd(a),
+		newReindexCmd(a),
+		newPurgeTrashCmd(a),
+		newRecomputeStatsCmd(a),
+		newCreateUserCmd(a),
+		newCreateAPIKeyCmd(a),
+		newDoctorCmd(a),
+	)
+	return root
+}
 
-	// Run migrations
-	if err := database.MigrateDB(db); err != nil {
-		log.Fatalf("Failed to migrate database: %v", err)
+// loadConfig loads the configuration, logs its warnings and keeps them for "doctor".
+func (a *app) loadConfig() error {
+	cfg, warnings, err := config.LoadConfig(a.envFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
 	}
-
-	// Initialize dependencies (Repository, Service, Handler)
-	// This is a simplified wire-up. In a larger app, consider dependency injection frameworks.
-
-	// Health Handler (common for both frameworks)
-	healthHandler := handler.NewHealthHandler(db)
-
-	// Initialize Redis Cache
-	// redisCache, err := cache.NewRedisCache(cfg)
-	// if err != nil {
-	// 	log.Printf("Warning: Failed to connect to Redis, caching will be disabled: %v", err)
-	// 	// Depending on policy, you might choose to log.Fatalf here if cache is critical
-	// 	// For now, we'll allow the app to run without cache if Redis connection fails.
-	// 	redisCache = nil // Ensure it's nil if connection failed
-	// } else {
-	// 	log.Println("Successfully connected to Redis.")
-	// 	defer redisCache.Close()
-	// }
-
-	// Vibe specific components
-	vibeRepo := repository.NewVibeRepository(db)
-	vibeSvc := service.NewVibeService(vibeRepo, cfg) // Pass cache and config
-
-	// Main Vibe Handler (will contain all handlers)
-	mainVibeHandler := &handler.VibeHandler{
-		Service:       vibeSvc,
-		HealthHandler: healthHandler,
+	a.cfg = cfg
+	a.configWarnings = warnings
+	a.logger = logging.NewWithWriter(os.Stderr, cfg.LogLevel, cfg.LogFormat)
+	for _, warning := range warnings {
+		a.logger.Warn("Configuration warning", slog.String("warning", warning))
 	}
+	return nil
+}
 
-	// Graceful shutdown channel
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-
-	// Start the selected server
-	switch cfg.ServerFramework {
-	case "fiber":
-		fiberApp := fiberserver.NewFiberServer(cfg, mainVibeHandler)
-		go func() {
-			if err := fiberserver.StartFiberServer(fiberApp, cfg); err != nil {
-				log.Fatalf("Failed to start Fiber server: %v", err)
-			}
-		}()
-		<-quit
-		log.Println("Shutting down Fiber server...")
-		if err := fiberApp.Shutdown(); err != nil {
-			log.Printf("Error during Fiber server shutdown: %v", err)
-		}
-	case "gin":
-		ginEngine := ginserver.NewGinServer(cfg, mainVibeHandler)
-		httpServer, err := ginserver.StartGinServer(ginEngine, cfg)
-		if err != nil {
-			log.Fatalf("Failed to start GIN server: %v", err)
-		}
-		<-quit
-		log.Println("Shutting down GIN server...")
-		// Define a timeout for server shutdown, e.g., 5 seconds
-		shutdownTimeout := 5 * time.Second
-		ginserver.ShutdownGinServer(httpServer, shutdownTimeout)
-
-	default:
-		log.Fatalf("Unsupported server framework: %s. Supported: 'fiber', 'gin'", cfg.ServerFramework)
+// openDB connects to the database of a maintenance command and checks that the schema is
+// migrated. Close it with database.CloseDB.
+func (a *app) openDB() (*gorm.DB, error) {
+	db, err := a.connectDB()
+	if err != nil {
+		return nil, err
 	}
-
-	log.Println("Server gracefully stopped.")
+	pending, err := database.PendingMigrations(db)
+	if err != nil {
+		database.CloseDB()
+		return nil, err
+	}
+	if len(pending) > 0 {
+		database.CloseDB()
+		return nil, fmt.Errorf("the database schema is out of date (missing %s); run \"server migrate\" first", strings.Join(pending, ", "))
+	}
+	return db, nil
 }
 
-// End of file
+// connectDB connects to the database without checking the schema. SQL is only logged with
+// --verbose; slow and failed queries always are.
+func (a *app) connectDB() (*gorm.DB, error) {
+	db, err := database.ConnectDB(a.cfg, a.logger)
+	if err != nil {
+		return nil, err
+	}
+	level := logger.Warn
+	if a.verbose {
+		level = logger.Info
+	}
+	return db.Session(&gorm.Session{Logger: db.Logger.LogMode(level)}), nil
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/repository"
+	"github.com/aebalz/daily-vibe-tracker/internal/service"
+	"github.com/aebalz/daily-vibe-tracker/pkg/database"
+	"github.com/spf13/cobra"
+)
+
+const dayLayout = "2006-01-02"
+
+func newMigrateCmd(a *app) *cobra.Command {
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Create or update the database schema and seed the mood catalog",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := a.connectDB()
+			if err != nil {
+				return err
+			}
+			defer database.CloseDB()
+
+			pending, err := database.PendingMigrations(db)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if dryRun {
+				if len(pending) == 0 {
+					fmt.Fprintln(out, "The schema is up to date.")
+				}
+				for _, p := range pending {
+					fmt.Fprintf(out, "Would create %s\n", p)
+				}
+				return nil
+			}
+
+			if err := database.MigrateDB(db); err != nil {
+				return err
+			}
+			moodSvc := service.NewMoodService(repository.NewMoodRepository(db, a.logger), a.cfg)
+			if err := moodSvc.EnsureDefaultMoods(); err != nil {
+				return fmt.Errorf("failed to seed mood catalog: %w", err)
+			}
+			fmt.Fprintf(out, "Migrated the schema (%d missing tables or columns created).\n", len(pending))
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Only list the missing tables and columns")
+	return cmd
+}
+
+func newReindexCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "reindex",
+		Short: "Backfill the activity catalog from vibe history and canonicalize activity names",
+		Long: `Adds every activity used by a vibe to the activity catalog and rewrites aliases and
+differently spelled names in vibes to the canonical catalog name. "serve" does the same
+on startup.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := a.openDB()
+			if err != nil {
+				return err
+			}
+			defer database.CloseDB()
+
+			activitySvc := service.NewActivityService(repository.NewActivityRepository(db, a.logger), a.cfg)
+			rewritten, err := activitySvc.ReindexActivities()
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Normalized activities in %d vibes.\n", rewritten)
+			return nil
+		},
+	}
+}
+
+func newPurgeTrashCmd(a *app) *cobra.Command {
+	var (
+		olderThan time.Duration
+		dryRun    bool
+	)
+	cmd := &cobra.Command{
+		Use:   "purge-trash",
+		Short: "Permanently remove deleted vibes",
+		Long: `Deleted vibes are kept in the database, and keep their day taken, until they are
+purged. This removes those deleted longer ago than --older-than, with their metric values.`,
+		Example: "  server purge-trash --older-than 168h",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if olderThan < 0 {
+				return fmt.Errorf("--older-than must not be negative")
+			}
+			db, err := a.openDB()
+			if err != nil {
+				return err
+			}
+			defer database.CloseDB()
+
+			vibeRepo := repository.NewVibeRepository(db, a.logger)
+			before := time.Now().Add(-olderThan)
+			out := cmd.OutOrStdout()
+			if dryRun {
+				count, err := vibeRepo.CountDeletedVibes(before)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(out, "Would purge %d vibes deleted before %s.\n", count, before.Format(time.RFC3339))
+				return nil
+			}
+			purged, err := vibeRepo.PurgeDeletedVibes(before)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Purged %d vibes deleted before %s.\n", purged, before.Format(time.RFC3339))
+			return nil
+		},
+	}
+	cmd.Flags().DurationVar(&olderThan, "older-than", 30*24*time.Hour, "Only purge vibes deleted at least this long ago; 0 purges all")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Only count the vibes that would be purged")
+	return cmd
+}
+
+func newRecomputeStatsCmd(a *app) *cobra.Command {
+	var (
+		days     int
+		from, to string
+	)
+	cmd := &cobra.Command{
+		Use:   "recompute-stats",
+		Short: "Rebuild the stored anomaly events from vibe history",
+		Long: `Statistics, insights and goal progress are computed on request. The anomaly events
+are stored, so they go stale when history is edited or imported in bulk. This removes the
+events of the range and detects them again.`,
+		Example: `  server recompute-stats --days 90
+  server recompute-stats --from 2025-01-01 --to 2025-12-31`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if days < 1 {
+				return fmt.Errorf("--days must be at least 1")
+			}
+			end := time.Now()
+			start := end.AddDate(0, 0, -days+1)
+			var err error
+			if from != "" {
+				if start, err = time.Parse(dayLayout, from); err != nil {
+					return fmt.Errorf("invalid --from %q: use YYYY-MM-DD", from)
+				}
+			}
+			if to != "" {
+				if end, err = time.Parse(dayLayout, to); err != nil {
+					return fmt.Errorf("invalid --to %q: use YYYY-MM-DD", to)
+				}
+			}
+			if start.After(end) {
+				return fmt.Errorf("the range starts after it ends")
+			}
+
+			db, err := a.openDB()
+			if err != nil {
+				return err
+			}
+			defer database.CloseDB()
+
+			vibeRepo := repository.NewVibeRepository(db, a.logger)
+			moodSvc := service.NewMoodService(repository.NewMoodRepository(db, a.logger), a.cfg)
+			anomalySvc := service.NewAnomalyService(repository.NewAnomalyRepository(db), vibeRepo, moodSvc, a.cfg, a.logger)
+			removed, events, err := anomalySvc.RecomputeAnomalies(start, end)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Recomputed anomalies from %s to %s: %d removed, %d recorded.\n",
+				start.Format(dayLayout), end.Format(dayLayout), removed, len(events))
+			return nil
+		},
+	}
+	cmd.Flags().IntVar(&days, "days", 365, "Number of days up to today to recompute")
+	cmd.Flags().StringVar(&from, "from", "", "First day (YYYY-MM-DD); overrides --days")
+	cmd.Flags().StringVar(&to, "to", "", "Last day (YYYY-MM-DD, default today)")
+	return cmd
+}
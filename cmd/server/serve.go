@@ -0,0 +1,304 @@
+package main
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/aebalz/daily-vibe-tracker/docs"
+	"github.com/aebalz/daily-vibe-tracker/internal/config"
+	"github.com/aebalz/daily-vibe-tracker/internal/events"
+	"github.com/aebalz/daily-vibe-tracker/internal/graph"
+	"github.com/aebalz/daily-vibe-tracker/internal/handler"
+	"github.com/aebalz/daily-vibe-tracker/internal/logging"
+	"github.com/aebalz/daily-vibe-tracker/internal/metrics"
+	"github.com/aebalz/daily-vibe-tracker/internal/notifier"
+	"github.com/aebalz/daily-vibe-tracker/internal/ratelimit"
+	"github.com/aebalz/daily-vibe-tracker/internal/repository"
+	"github.com/aebalz/daily-vibe-tracker/internal/service"
+	"github.com/aebalz/daily-vibe-tracker/internal/stream"
+	"github.com/aebalz/daily-vibe-tracker/internal/tracing"
+	"github.com/aebalz/daily-vibe-tracker/pkg/database"
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+
+	fiberserver "github.com/aebalz/daily-vibe-tracker/pkg/fiber"
+	ginserver "github.com/aebalz/daily-vibe-tracker/pkg/gin"
+	grpcserver "github.com/aebalz/daily-vibe-tracker/pkg/grpc"
+)
+
+// streamClientBuffer is how many messages a stream client may fall behind before it is dropped.
+const streamClientBuffer = 64
+
+func newServeCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "serve",
+		Short: "Migrate the database and run the HTTP and gRPC servers",
+		Long: `Migrates the database, seeds the mood catalog, reindexes activities and runs the
+HTTP server selected by SERVER_FRAMEWORK (both runs Fiber on SERVER_PORT and Gin on
+GIN_PORT) and, with GRPC_PORT set, the gRPC server until
+SIGINT or SIGTERM. This is the default command.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			serve(a.cfg)
+		},
+	}
+}
+
+// serve runs the servers until SIGINT or SIGTERM. Startup failures are fatal.
+func serve(cfg *config.AppConfig) {
+	// Structured logger of LOG_LEVEL and LOG_FORMAT, on stdout. It is also the default
+	// logger, so the log.Printf lines of the other components come out in its format.
+	logger := logging.New(cfg)
+	logging.SetDefault(logger)
+	logger.Info("Logging configured", slog.String("level", cfg.LogLevel), slog.String("format", cfg.LogFormat))
+
+	// Tracer provider of TRACING_EXPORTER, before the servers and the database start spans
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg)
+	if err != nil {
+		log.Fatalf("Failed to set up tracing: %v", err)
+	}
+	if cfg.TracingExporter != "none" {
+		logger.Info("Tracing configured", slog.String("exporter", cfg.TracingExporter), slog.Float64("sample_ratio", cfg.TracingSampleRatio))
+	}
+
+	// Update Swagger info based on config
+	docs.SwaggerInfo.Version = "1.0" // Prompt specified version 1.0
+	docs.SwaggerInfo.Title = cfg.AppName + " - Daily Vibe Tracker API"
+	docs.SwaggerInfo.Description = "Complete API for the Daily Vibe Tracker application, including vibe management, analytics, and advanced features."
+	docs.SwaggerInfo.Host = cfg.SwaggerHost
+	docs.SwaggerInfo.BasePath = cfg.SwaggerBasePath // Should be /api/v1 as per spec for vibe routes
+	docs.SwaggerInfo.Schemes = cfg.SwaggerSchemes
+
+	// Connect to database
+	db, err := database.ConnectDB(cfg, logger)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer database.CloseDB()
+	// Connection pool statistics on /metrics
+	if sqlDB, err := db.DB(); err == nil {
+		if err := metrics.RegisterDBStats(sqlDB); err != nil {
+			log.Printf("Warning: Failed to export the database pool metrics: %v", err)
+		}
+	}
+
+	// Run migrations
+	if err := database.MigrateDB(db); err != nil {
+		log.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	// Initialize dependencies (Repository, Service, Handler)
+	// This is a simplified wire-up. In a larger app, consider dependency injection frameworks.
+
+	// Health Handler (common for both frameworks)
+	healthHandler := handler.NewHealthHandler(db)
+
+	// Initialize Redis Cache
+	// redisCache, err := cache.NewRedisCache(cfg)
+	// if err != nil {
+	// 	log.Printf("Warning: Failed to connect to Redis, caching will be disabled: %v", err)
+	// 	// Depending on policy, you might choose to log.Fatalf here if cache is critical
+	// 	// For now, we'll allow the app to run without cache if Redis connection fails.
+	// 	redisCache = nil // Ensure it's nil if connection failed
+	// } else {
+	// 	log.Println("Successfully connected to Redis.")
+	// 	defer redisCache.Close()
+	// }
+
+	// Mood catalog components
+	moodRepo := repository.NewMoodRepository(db, logger)
+	moodSvc := service.NewMoodService(moodRepo, cfg)
+	if err := moodSvc.EnsureDefaultMoods(); err != nil {
+		log.Fatalf("Failed to seed mood catalog: %v", err)
+	}
+
+	// Activity catalog components
+	activityRepo := repository.NewActivityRepository(db, logger)
+	activitySvc := service.NewActivityService(activityRepo, cfg)
+	if rewritten, err := activitySvc.ReindexActivities(); err != nil {
+		logger.Warn("Failed to reindex activities from vibe history", slog.String("error", err.Error()))
+	} else if rewritten > 0 {
+		log.Printf("Normalized activities in %d vibes.", rewritten)
+	}
+
+	// Custom metric components
+	metricRepo := repository.NewMetricRepository(db, logger)
+	metricSvc := service.NewMetricService(metricRepo, cfg)
+
+	// Vibe specific components
+	vibeRepo := repository.NewVibeRepository(db, logger)
+
+	// Anomaly detection: runs after writes and on a schedule
+	anomalyRepo := repository.NewAnomalyRepository(db)
+	anomalySvc := service.NewAnomalyService(anomalyRepo, vibeRepo, moodSvc, cfg, logger)
+	anomalySvc.AddHook(service.NewLogAnomalyHook(logger))
+	schedulerCtx, stopSchedulers := context.WithCancel(context.Background())
+	defer stopSchedulers()
+	go anomalySvc.RunScheduler(schedulerCtx)
+
+	// Recommendation components; written vibes are linked to the recommendations they followed
+	recommendationRepo := repository.NewRecommendationRepository(db)
+	recommendationSvc := service.NewRecommendationService(recommendationRepo, vibeRepo, moodSvc, activitySvc, cfg)
+
+	// Goal components
+	goalRepo := repository.NewGoalRepository(db)
+	goalSvc := service.NewGoalService(goalRepo, vibeRepo, moodSvc, activitySvc, cfg)
+
+	// Reminders: delivered through the configured notifiers by a background scheduler
+	reminderRepo := repository.NewReminderRepository(db)
+	reminderSvc := service.NewReminderService(reminderRepo, vibeRepo, notifier.NewNotifiers(cfg), cfg, logger)
+	go reminderSvc.RunScheduler(schedulerCtx)
+
+	// Outgoing webhooks: vibe events go to a persistent outbox drained by a background worker
+	webhookRepo := repository.NewWebhookRepository(db)
+	webhookSvc := service.NewWebhookService(webhookRepo, cfg, logger)
+	go webhookSvc.RunWorker(schedulerCtx)
+
+	// Domain events: vibe writes record events in a transactional outbox, which publishes them
+	// to the bus after the commit. The side effects of a write subscribe to the bus.
+	eventBus := events.NewBus(logger)
+	service.NewVibeSubscribers(vibeRepo, anomalySvc, recommendationSvc, webhookSvc, cfg).Register(eventBus)
+	outbox := events.NewOutbox(repository.NewDomainEventRepository(db, logger), eventBus, logger)
+	go outbox.RunRelay(schedulerCtx, cfg.EventRelayInterval, cfg.EventRetention)
+
+	// Real-time stream: vibe events and refreshed summaries are pushed to SSE and WebSocket clients
+	streamHub := stream.NewHub(cfg.StreamMaxConnections, cfg.StreamMaxConnectionsPerUser, cfg.StreamReplaySize, streamClientBuffer)
+	streamSvc := service.NewStreamService(streamHub, vibeRepo, cfg)
+	eventBus.SubscribeAsync("stream", cfg.EventQueueSize, streamSvc.HandleEvent,
+		events.TypeVibeCreated, events.TypeVibeUpdated, events.TypeVibeDeleted, events.TypeBulkImported)
+
+	vibeSvc := service.NewVibeService(vibeRepo, moodSvc, activitySvc, metricSvc, outbox, cfg, logger) // Pass cache and config
+	insightSvc := service.NewInsightService(vibeRepo, moodSvc, cfg)
+
+	// GraphQL API over the same services
+	graphServer, err := graph.NewServer(vibeSvc, moodSvc, recommendationSvc, cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize GraphQL: %v", err)
+	}
+
+	// Users and API keys; requests may authenticate with a key created by "create-api-key"
+	userSvc := service.NewUserService(repository.NewUserRepository(db), cfg, logger)
+
+	// Main Vibe Handler (will contain all handlers)
+	mainVibeHandler := &handler.VibeHandler{
+		Service:               vibeSvc,
+		HealthHandler:         healthHandler,
+		MoodHandler:           handler.NewMoodHandler(moodSvc),
+		ActivityHandler:       handler.NewActivityHandler(activitySvc),
+		MetricHandler:         handler.NewMetricHandler(metricSvc),
+		InsightHandler:        handler.NewInsightHandler(insightSvc, anomalySvc),
+		RecommendationHandler: handler.NewRecommendationHandler(recommendationSvc),
+		GoalHandler:           handler.NewGoalHandler(goalSvc),
+		ReminderHandler:       handler.NewReminderHandler(reminderSvc),
+		WebhookHandler:        handler.NewWebhookHandler(webhookSvc),
+		StreamHandler:         handler.NewStreamHandler(streamSvc, cfg.CorsAllowedOrigins, logger),
+		GraphQLHandler:        handler.NewGraphQLHandler(graphServer),
+		AuthHandler:           handler.NewAuthHandler(userSvc, cfg.AuthRequired),
+	}
+
+	// gRPC API on its own port
+	var grpcServer *grpc.Server
+	if cfg.GRPCPort > 0 {
+		grpcServer = grpcserver.NewGRPCServer(cfg, handler.NewVibeGRPCHandler(vibeSvc), logger)
+		if err := grpcserver.StartGRPCServer(grpcServer, cfg); err != nil {
+			log.Fatalf("Failed to start gRPC server: %v", err)
+		}
+	}
+
+	// Graceful shutdown channel
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+
+	// Start the selected server, or both on their own ports
+	if cfg.ServerFramework != "fiber" && cfg.ServerFramework != "gin" && cfg.ServerFramework != "both" {
+		log.Fatalf("Unsupported server framework: %s. Supported: 'fiber', 'gin', 'both'", cfg.ServerFramework)
+	}
+	// One limiter for both servers, so that a client has the same limit on either port
+	limiter, closeLimiter := newRateLimiter(cfg, logger)
+	defer closeLimiter()
+	var fiberApp *fiber.App
+	if cfg.ServerFramework == "fiber" || cfg.ServerFramework == "both" {
+		fiberApp = fiberserver.NewFiberServer(cfg, mainVibeHandler, limiter, logger)
+		go func() {
+			if err := fiberserver.StartFiberServer(fiberApp, cfg); err != nil {
+				log.Fatalf("Failed to start Fiber server: %v", err)
+			}
+		}()
+	}
+	var ginHTTPServer *http.Server
+	if cfg.ServerFramework == "gin" || cfg.ServerFramework == "both" {
+		ginEngine := ginserver.NewGinServer(cfg, mainVibeHandler, limiter, logger)
+		ginHTTPServer, err = ginserver.StartGinServer(ginEngine, cfg)
+		if err != nil {
+			log.Fatalf("Failed to start GIN server: %v", err)
+		}
+	}
+
+	<-quit
+	stopSchedulers()
+	streamHub.Close() // End open streams, which would otherwise hold the shutdown
+	if fiberApp != nil {
+		log.Println("Shutting down Fiber server...")
+		if err := fiberApp.Shutdown(); err != nil {
+			log.Printf("Error during Fiber server shutdown: %v", err)
+		}
+	}
+	if ginHTTPServer != nil {
+		// Define a timeout for server shutdown, e.g., 5 seconds
+		shutdownTimeout := 5 * time.Second
+		ginserver.ShutdownGinServer(ginHTTPServer, shutdownTimeout)
+	}
+
+	if grpcServer != nil {
+		grpcserver.ShutdownGRPCServer(grpcServer, 5*time.Second)
+	}
+
+	// Let asynchronous event subscribers finish their queues.
+	busCtx, cancelBus := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelBus()
+	if err := eventBus.Close(busCtx); err != nil {
+		logger.Warn("Event subscribers did not drain", slog.String("error", err.Error()))
+	}
+
+	// Send the spans still buffered by the exporter.
+	tracingCtx, cancelTracing := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelTracing()
+	if err := shutdownTracing(tracingCtx); err != nil {
+		logger.Warn("Failed to flush the pending spans", slog.String("error", err.Error()))
+	}
+
+	log.Println("Server gracefully stopped.")
+}
+
+// newRateLimiter creates the rate limiter of the HTTP servers from RATE_LIMIT_*, or nil when
+// RATE_LIMIT_MAX is 0. With RATE_LIMIT_STORE=redis replicas share their limits; when Redis
+// cannot be reached the limits are kept in memory instead. The returned function releases
+// the store.
+func newRateLimiter(cfg *config.AppConfig, logger *slog.Logger) (*ratelimit.Limiter, func()) {
+	if cfg.RateLimitMax == 0 {
+		logger.Info("Rate limiting is disabled (RATE_LIMIT_MAX=0)")
+		return nil, func() {}
+	}
+	policies := ratelimit.PoliciesFromConfig(cfg)
+	if cfg.RateLimitStore == "redis" {
+		client := redis.NewClient(&redis.Options{Addr: cfg.RedisAddr, Password: cfg.RedisPassword, DB: cfg.RedisDB})
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := client.Ping(ctx).Err(); err != nil {
+			logger.Warn("Failed to connect to Redis, rate limits are kept in memory", slog.String("addr", cfg.RedisAddr), slog.String("error", err.Error()))
+			client.Close()
+		} else {
+			logger.Info("Rate limits are shared through Redis", slog.String("addr", cfg.RedisAddr))
+			return ratelimit.NewLimiter(ratelimit.NewRedisStore(client, "ratelimit:"), policies...), func() { client.Close() }
+		}
+	}
+	return ratelimit.NewLimiter(ratelimit.NewMemoryStore(), policies...), func() {}
+}
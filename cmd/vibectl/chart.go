@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// palette maps day values to heatmap cells. With colors, every level is a colored square;
+// without, a glyph whose height grows with the value.
+type palette struct {
+	level  func(v float64) int // Index into colors and glyphs
+	colors []int               // ANSI 256-color codes
+	glyphs []string
+	low    string // Legend labels
+	high   string
+}
+
+var (
+	energyPalette = palette{
+		level:  func(v float64) int { return clampLevel(int((v-1)/9*4), 4) },
+		colors: []int{22, 28, 34, 46},
+		glyphs: []string{"▂", "▄", "▆", "█"},
+		low:    "Low",
+		high:   "High",
+	}
+	valencePalette = palette{
+		level:  func(v float64) int { return clampLevel(int((v+1)/2*5), 5) },
+		colors: []int{160, 210, 250, 114, 34},
+		glyphs: []string{"▁", "▂", "▄", "▆", "█"},
+		low:    "Negative",
+		high:   "Positive",
+	}
+)
+
+const emptyCellColor = 237
+
+func clampLevel(level, levels int) int {
+	return max(0, min(level, levels-1))
+}
+
+// colorEnabled decides whether to write ANSI colors: "always", "never" or "auto", which
+// colors terminals unless NO_COLOR is set.
+func colorEnabled(mode string, w io.Writer) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto":
+		if os.Getenv("NO_COLOR") != "" {
+			return false, nil
+		}
+		f, ok := w.(*os.File)
+		if !ok {
+			return false, nil
+		}
+		info, err := f.Stat()
+		return err == nil && info.Mode()&os.ModeCharDevice != 0, nil
+	default:
+		return false, fmt.Errorf("invalid color mode %q: use auto, always or never", mode)
+	}
+}
+
+func colored(s string, code int) string {
+	return fmt.Sprintf("\x1b[38;5;%dm%s\x1b[0m", code, s)
+}
+
+// cell renders one heatmap day.
+func (p palette) cell(v float64, ok, color bool) string {
+	switch {
+	case !ok && color:
+		return colored("■", emptyCellColor)
+	case !ok:
+		return "·"
+	case color:
+		return colored("■", p.colors[p.level(v)])
+	default:
+		return p.glyphs[p.level(v)]
+	}
+}
+
+// legend renders "Low ■■■■ High".
+func (p palette) legend(color bool) string {
+	var b strings.Builder
+	b.WriteString(p.low + " ")
+	for i := range p.glyphs {
+		if color {
+			b.WriteString(colored("■", p.colors[i]))
+		} else {
+			b.WriteString(p.glyphs[i])
+		}
+	}
+	b.WriteString(" " + p.high)
+	return b.String()
+}
+
+const heatmapLabelWidth = 4
+
+// weekGrid returns the Sunday starting the first column of a year's heatmap and the number
+// of week columns, like GitHub's contribution graph.
+func weekGrid(year int) (time.Time, int) {
+	first := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	start := first.AddDate(0, 0, -int(first.Weekday()))
+	last := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+	return start, int(last.Sub(start).Hours()/24)/7 + 1
+}
+
+// heatmap writes a calendar of a year with a column per week and a row per weekday. values
+// holds the value of each logged day, keyed by YYYY-MM-DD.
+func heatmap(w io.Writer, year int, values map[string]float64, p palette, color bool) {
+	start, weeks := weekGrid(year)
+
+	// Month names above the first week of each month, when there is room.
+	months := []byte(strings.Repeat(" ", weeks+3))
+	next := 0
+	for m := time.January; m <= time.December; m++ {
+		col := int(time.Date(year, m, 1, 0, 0, 0, 0, time.UTC).Sub(start).Hours()/24) / 7
+		if col < next {
+			continue
+		}
+		copy(months[col:], m.String()[:3])
+		next = col + 4
+	}
+	fmt.Fprintf(w, "%*s%s\n", heatmapLabelWidth, "", strings.TrimRight(string(months), " "))
+
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		label := ""
+		if weekday == time.Monday || weekday == time.Wednesday || weekday == time.Friday {
+			label = weekday.String()[:3]
+		}
+		var b strings.Builder
+		fmt.Fprintf(&b, "%-*s", heatmapLabelWidth, label)
+		for week := 0; week < weeks; week++ {
+			day := start.AddDate(0, 0, week*7+int(weekday))
+			if day.Year() != year {
+				b.WriteString(" ")
+				continue
+			}
+			v, ok := values[day.Format(dateLayout)]
+			b.WriteString(p.cell(v, ok, color))
+		}
+		fmt.Fprintln(w, strings.TrimRight(b.String(), " "))
+	}
+	fmt.Fprintf(w, "%*s%s\n", heatmapLabelWidth, "", p.legend(color))
+}
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline renders values between lo and hi as one block per value, with a space for NaN.
+func sparkline(values []float64, lo, hi float64) string {
+	var b strings.Builder
+	for _, v := range values {
+		if math.IsNaN(v) {
+			b.WriteByte(' ')
+			continue
+		}
+		i := int(math.Round((v - lo) / (hi - lo) * float64(len(sparkBlocks)-1)))
+		b.WriteRune(sparkBlocks[max(0, min(i, len(sparkBlocks)-1))])
+	}
+	return b.String()
+}
+
+var barEighths = []string{"", "▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+
+const barWidth = 40
+
+// barChart writes a horizontal bar per item, scaled so the largest count fills barWidth.
+func barChart(w io.Writer, items []activityCount) {
+	labelWidth, largest := 0, 0
+	for _, item := range items {
+		labelWidth = max(labelWidth, utf8.RuneCountInString(truncate(item.Activity, 20)))
+		largest = max(largest, item.Count)
+	}
+	for _, item := range items {
+		eighths := item.Count * barWidth * 8 / largest
+		bar := strings.Repeat("█", eighths/8) + barEighths[eighths%8]
+		label := truncate(item.Activity, 20)
+		fmt.Fprintf(w, "%s%s  %s %d\n", label, strings.Repeat(" ", labelWidth-utf8.RuneCountInString(label)), bar, item.Count)
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultServer is used when neither a flag, the environment nor the profile sets one.
+const defaultServer = "http://localhost:8080"
+
+// Profile holds the server and credentials of one environment.
+type Profile struct {
+	Server string `yaml:"server,omitempty"`
+	APIKey string `yaml:"api_key,omitempty"`
+	Token  string `yaml:"token,omitempty"` // Bearer token, e.g. a JWT
+	Output string `yaml:"output,omitempty"`
+}
+
+// Config is the vibectl configuration file.
+//
+//	current_profile: default
+//	profiles:
+//	  default:
+//	    server: http://localhost:8080
+//	  prod:
+//	    server: https://vibes.example.com
+//	    api_key: ...
+type Config struct {
+	CurrentProfile string              `yaml:"current_profile,omitempty"`
+	Profiles       map[string]*Profile `yaml:"profiles,omitempty"`
+}
+
+// defaultConfigPath returns $VIBECTL_CONFIG, or config.yaml in the user config directory.
+func defaultConfigPath() string {
+	if path := os.Getenv("VIBECTL_CONFIG"); path != "" {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ".vibectl.yaml"
+	}
+	return filepath.Join(dir, "vibectl", "config.yaml")
+}
+
+// loadConfig reads the configuration file. A missing file gives an empty configuration.
+func loadConfig(path string) (*Config, error) {
+	cfg := &Config{Profiles: make(map[string]*Profile)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read config %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("could not parse config %s: %w", path, err)
+	}
+	if cfg.Profiles == nil {
+		cfg.Profiles = make(map[string]*Profile)
+	}
+	return cfg, nil
+}
+
+// save writes the configuration. The file holds credentials, so only the owner may read it.
+func (c *Config) save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("could not create config directory: %w", err)
+	}
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("could not write config %s: %w", path, err)
+	}
+	return nil
+}
+
+// profileNames returns the names of the profiles in order.
+func (c *Config) profileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// mask hides all but the last four characters of a secret.
+func mask(secret string) string {
+	if len(secret) <= 4 {
+		return "****"
+	}
+	return "****" + secret[len(secret)-4:]
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+func newConfigCmd(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage profiles of the config file",
+	}
+	cmd.AddCommand(newConfigSetCmd(a), newConfigUseCmd(a), newConfigViewCmd(a), newConfigDeleteCmd(a))
+	return cmd
+}
+
+func newConfigSetCmd(a *app) *cobra.Command {
+	var p Profile
+	cmd := &cobra.Command{
+		Use:   "set-profile NAME",
+		Short: "Create or update a profile",
+		Long: `Creates or updates a profile. Only the given flags are changed. The first profile
+becomes the current one.`,
+		Example: "  vibectl config set-profile prod --server https://vibes.example.com --api-key $KEY",
+		Args:    cobra.ExactArgs(1),
+		// The root flags with the same names would be applied to the effective profile, so
+		// this command reads its own.
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig(a.configPath)
+			a.cfg = cfg
+			return err
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			existing, ok := a.cfg.Profiles[name]
+			if !ok {
+				existing = &Profile{}
+				a.cfg.Profiles[name] = existing
+			}
+			flags := cmd.Flags()
+			if flags.Changed("server") {
+				existing.Server = p.Server
+			}
+			if flags.Changed("api-key") {
+				existing.APIKey = p.APIKey
+			}
+			if flags.Changed("token") {
+				existing.Token = p.Token
+			}
+			if flags.Changed("output") {
+				if !isOutputFormat(p.Output) {
+					return fmt.Errorf("invalid output format %q", p.Output)
+				}
+				existing.Output = p.Output
+			}
+			if a.cfg.CurrentProfile == "" {
+				a.cfg.CurrentProfile = name
+			}
+			if err := a.cfg.save(a.configPath); err != nil {
+				return err
+			}
+			fmt.Fprintf(a.out, "Saved profile %q to %s\n", name, a.configPath)
+			return nil
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&p.Server, "server", "", "API base URL")
+	flags.StringVar(&p.APIKey, "api-key", "", "API key")
+	flags.StringVar(&p.Token, "token", "", "Bearer token, e.g. a JWT")
+	flags.StringVarP(&p.Output, "output", "o", "", "Default output format: table, json or yaml")
+	return cmd
+}
+
+func newConfigUseCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:               "use NAME",
+		Short:             "Make a profile the current one",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: a.completeProfiles,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, ok := a.cfg.Profiles[args[0]]; !ok {
+				return fmt.Errorf("unknown profile %q", args[0])
+			}
+			a.cfg.CurrentProfile = args[0]
+			if err := a.cfg.save(a.configPath); err != nil {
+				return err
+			}
+			fmt.Fprintf(a.out, "Using profile %q\n", args[0])
+			return nil
+		},
+	}
+}
+
+func newConfigDeleteCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:               "delete-profile NAME",
+		Short:             "Delete a profile",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: a.completeProfiles,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, ok := a.cfg.Profiles[args[0]]; !ok {
+				return fmt.Errorf("unknown profile %q", args[0])
+			}
+			delete(a.cfg.Profiles, args[0])
+			if a.cfg.CurrentProfile == args[0] {
+				a.cfg.CurrentProfile = ""
+			}
+			if err := a.cfg.save(a.configPath); err != nil {
+				return err
+			}
+			fmt.Fprintf(a.out, "Deleted profile %q\n", args[0])
+			return nil
+		},
+	}
+}
+
+func newConfigViewCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "view",
+		Short: "Show the profiles with masked credentials",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			type view struct {
+				Name    string `json:"name"`
+				Current bool   `json:"current"`
+				Server  string `json:"server,omitempty"`
+				APIKey  string `json:"api_key,omitempty"`
+				Token   string `json:"token,omitempty"`
+				Output  string `json:"output,omitempty"`
+			}
+			views := make([]view, 0, len(a.cfg.Profiles))
+			for _, name := range a.cfg.profileNames() {
+				p := a.cfg.Profiles[name]
+				v := view{Name: name, Current: name == a.cfg.CurrentProfile, Server: p.Server, Output: p.Output}
+				if p.APIKey != "" {
+					v.APIKey = mask(p.APIKey)
+				}
+				if p.Token != "" {
+					v.Token = mask(p.Token)
+				}
+				views = append(views, v)
+			}
+			return a.print(views, func(w io.Writer) error {
+				if len(views) == 0 {
+					_, err := fmt.Fprintf(w, "No profiles in %s. Create one with \"vibectl config set-profile\".\n", a.configPath)
+					return err
+				}
+				tw := newTable(w)
+				row(tw, "CURRENT", "NAME", "SERVER", "API KEY", "TOKEN", "OUTPUT")
+				for _, v := range views {
+					current := ""
+					if v.Current {
+						current = "*"
+					}
+					row(tw, current, v.Name, v.Server, v.APIKey, v.Token, v.Output)
+				}
+				return tw.Flush()
+			})
+		},
+	}
+}
@@ -0,0 +1,182 @@
+// Command vibectl is a command-line client for the Daily Vibe Tracker API.
+//
+//	vibectl log happy -e 7 -a gym,reading -n "Long run before work"
+//	vibectl ls --from 2025-06-01 --mood happy -o yaml
+//	vibectl export --format csv > vibes.csv
+//	vibectl report --by energy
+//
+// The server and credentials come from flags, the VIBECTL_SERVER, VIBECTL_API_KEY and
+// VIBECTL_TOKEN environment variables or a profile of the config file, in that order.
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/aebalz/daily-vibe-tracker/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := newRootCmd(os.Stdout).ExecuteContext(ctx); err != nil {
+		os.Exit(1)
+	}
+}
+
+// app holds the global flags and the resolved configuration shared by the commands.
+type app struct {
+	out io.Writer
+
+	configPath  string
+	profileName string
+	server      string
+	apiKey      string
+	token       string
+	output      string
+
+	cfg     *Config
+	profile Profile // Effective settings after flags and environment are applied
+}
+
+func newRootCmd(out io.Writer) *cobra.Command {
+	a := &app{out: out}
+	root := &cobra.Command{
+		Use:          "vibectl",
+		Short:        "Log and explore daily vibes from the terminal",
+		SilenceUsage: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return a.resolve(cmd)
+		},
+	}
+	root.SetOut(out)
+
+	flags := root.PersistentFlags()
+	flags.StringVar(&a.configPath, "config", defaultConfigPath(), "Config file")
+	flags.StringVarP(&a.profileName, "profile", "p", "", "Config profile to use (default: the current profile)")
+	flags.StringVar(&a.server, "server", "", "API base URL (env VIBECTL_SERVER)")
+	flags.StringVar(&a.apiKey, "api-key", "", "API key (env VIBECTL_API_KEY)")
+	flags.StringVar(&a.token, "token", "", "Bearer token, e.g. a JWT (env VIBECTL_TOKEN)")
+	flags.StringVarP(&a.output, "output", "o", "", "Output format: table, json or yaml")
+	_ = root.RegisterFlagCompletionFunc("output", fixedCompletion(outputFormats...))
+	_ = root.RegisterFlagCompletionFunc("profile", a.completeProfiles)
+
+	root.AddCommand(
+		newLogCmd(a),
+		newListCmd(a),
+		newStatsCmd(a),
+		newStreakCmd(a),
+		newExportCmd(a),
+		newImportCmd(a),
+		newReportCmd(a),
+		newConfigCmd(a),
+	)
+	return root
+}
+
+// resolve loads the config file and applies the profile, environment and flags, in
+// increasing order of precedence.
+func (a *app) resolve(cmd *cobra.Command) error {
+	cfg, err := loadConfig(a.configPath)
+	if err != nil {
+		return err
+	}
+	a.cfg = cfg
+
+	name := a.profileName
+	if name == "" {
+		name = cfg.CurrentProfile
+	}
+	if name != "" {
+		p, ok := cfg.Profiles[name]
+		if !ok && a.profileName != "" {
+			return fmt.Errorf("unknown profile %q", name)
+		}
+		if ok {
+			a.profile = *p
+		}
+	}
+
+	override := func(target *string, env, flag string) {
+		if v := os.Getenv(env); v != "" {
+			*target = v
+		}
+		if flag != "" {
+			*target = flag
+		}
+	}
+	override(&a.profile.Server, "VIBECTL_SERVER", a.server)
+	override(&a.profile.APIKey, "VIBECTL_API_KEY", a.apiKey)
+	override(&a.profile.Token, "VIBECTL_TOKEN", a.token)
+	override(&a.profile.Output, "VIBECTL_OUTPUT", a.output)
+	if a.profile.Server == "" {
+		a.profile.Server = defaultServer
+	}
+	if a.profile.Output == "" {
+		a.profile.Output = outputTable
+	}
+	a.profile.Output = strings.ToLower(a.profile.Output)
+	if !isOutputFormat(a.profile.Output) {
+		return fmt.Errorf("invalid output format %q: use %s", a.profile.Output, strings.Join(outputFormats, ", "))
+	}
+	return nil
+}
+
+// client creates an API client for the effective profile.
+func (a *app) client() (*client.Client, error) {
+	opts := []client.Option{client.WithUserAgent("vibectl/1.0")}
+	if a.profile.APIKey != "" {
+		opts = append(opts, client.WithAPIKey(a.profile.APIKey))
+	}
+	if a.profile.Token != "" {
+		opts = append(opts, client.WithBearerToken(a.profile.Token))
+	}
+	return client.New(a.profile.Server, opts...)
+}
+
+// fixedCompletion completes a flag or argument from a fixed list.
+func fixedCompletion(values ...string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return values, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func (a *app) completeProfiles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, err := loadConfig(a.configPath)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return cfg.profileNames(), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeMoods completes the first argument with the names of the mood catalog.
+func (a *app) completeMoods(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	if err := a.resolve(cmd); err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	c, err := a.client()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	moods, err := c.Moods(ctx)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	names := make([]string, 0, len(moods))
+	for _, m := range moods {
+		names = append(names, m.Name+"\t"+m.Emoji)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,331 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/model"
+	"github.com/aebalz/daily-vibe-tracker/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+// Heatmap metrics.
+const (
+	reportValence = "valence"
+	reportEnergy  = "energy"
+)
+
+// yearReport is what the report command shows; -o json and -o yaml print it as is.
+type yearReport struct {
+	Year               int             `json:"year"`
+	Metric             string          `json:"metric"`
+	Vibes              int             `json:"vibes"`
+	DaysLogged         int             `json:"days_logged"`
+	AverageEnergyLevel float64         `json:"average_energy_level"`
+	TopMood            string          `json:"top_mood,omitempty"`
+	Days               []dayValue      `json:"days"`
+	WeeklyEnergy       []weekEnergy    `json:"weekly_energy"`
+	TopActivities      []activityCount `json:"top_activities"`
+}
+
+// dayValue is the average valence or energy of the vibes of a day.
+type dayValue struct {
+	Date  string  `json:"date"`
+	Value float64 `json:"value"`
+}
+
+// weekEnergy is the average energy of a week starting on Sunday, nil without vibes.
+type weekEnergy struct {
+	WeekStart     string   `json:"week_start"`
+	AverageEnergy *float64 `json:"average_energy"`
+}
+
+type activityCount struct {
+	Activity string `json:"activity"`
+	Count    int    `json:"count"`
+}
+
+func newReportCmd(a *app) *cobra.Command {
+	var (
+		metric, file, format, color string
+		year, top                   int
+	)
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Show a year heatmap, weekly energy and top activities",
+		Long: `Shows a calendar heatmap of a year with the mood valence or energy of every day, a
+sparkline of the weekly average energy and a bar chart of the most frequent activities.
+
+The vibes come from the API, or from a file written by "vibectl export" with --file.
+Valence comes from the mood catalog of the server, or the default catalog when offline.`,
+		Example: `  vibectl report
+  vibectl report --by energy --year 2024
+  vibectl report --file vibes.csv --color never`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			metric = strings.ToLower(metric)
+			if metric != reportValence && metric != reportEnergy {
+				return fmt.Errorf("invalid metric %q: use valence or energy", metric)
+			}
+			useColor, err := colorEnabled(strings.ToLower(color), a.out)
+			if err != nil {
+				return err
+			}
+
+			var (
+				vibes []client.VibeInput
+				moods []client.Mood
+				stats *client.Statistics
+			)
+			if file != "" {
+				vibes, err = readVibeFile(cmd, file, format)
+				if err != nil {
+					return err
+				}
+				if year == 0 {
+					year = latestYear(vibes)
+				}
+				moods = defaultMoods()
+			} else {
+				if year == 0 {
+					year = time.Now().Year()
+				}
+				vibes, moods, stats, err = a.fetchReportData(cmd.Context(), year, metric)
+				if err != nil {
+					return err
+				}
+			}
+
+			report := buildReport(year, metric, vibes, moods, stats, top)
+			return a.print(report, func(w io.Writer) error {
+				return renderReport(w, report, useColor)
+			})
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&metric, "by", reportValence, "Heatmap metric: valence or energy")
+	flags.IntVar(&year, "year", 0, "Year to show (default: the current year, or the latest year of --file)")
+	flags.IntVar(&top, "top", 10, "Number of activities in the bar chart")
+	flags.StringVar(&file, "file", "", "Read vibes from an export file instead of the API; - reads stdin")
+	flags.StringVarP(&format, "format", "f", "", "File format: csv or json (default: from the file extension)")
+	flags.StringVar(&color, "color", "auto", "Use ANSI colors: auto, always or never")
+	_ = cmd.RegisterFlagCompletionFunc("by", fixedCompletion(reportValence, reportEnergy))
+	_ = cmd.RegisterFlagCompletionFunc("format", fixedCompletion("csv", "json"))
+	_ = cmd.RegisterFlagCompletionFunc("color", fixedCompletion("auto", "always", "never"))
+	return cmd
+}
+
+// fetchReportData loads the vibes of a year, the mood catalog when valence is shown and,
+// for the current year, the yearly statistics.
+func (a *app) fetchReportData(ctx context.Context, year int, metric string) ([]client.VibeInput, []client.Mood, *client.Statistics, error) {
+	c, err := a.client()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	filter := client.NewFilter().
+		From(time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)).
+		To(time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)).
+		SortBy("date").Asc().Limit(100)
+	var vibes []client.VibeInput
+	for v, err := range c.AllVibes(ctx, filter) {
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		vibes = append(vibes, client.VibeInput{Date: v.Date, Mood: v.Mood, EnergyLevel: v.EnergyLevel, Activities: v.Activities})
+	}
+
+	var moods []client.Mood
+	if metric == reportValence {
+		if moods, err = c.Moods(ctx); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	var stats *client.Statistics
+	if year == time.Now().Year() {
+		if stats, err = c.Stats(ctx, client.PeriodYear); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	return vibes, moods, stats, nil
+}
+
+// defaultMoods returns the catalog the server seeds, for reports without a server.
+func defaultMoods() []client.Mood {
+	moods := make([]client.Mood, 0, len(model.DefaultMoods))
+	for _, m := range model.DefaultMoods {
+		moods = append(moods, client.Mood{Name: m.Name, Aliases: m.Aliases, Valence: m.Valence})
+	}
+	return moods
+}
+
+func latestYear(vibes []client.VibeInput) int {
+	year := 0
+	for _, v := range vibes {
+		year = max(year, v.Date.UTC().Year())
+	}
+	if year == 0 {
+		return time.Now().Year()
+	}
+	return year
+}
+
+// buildReport computes the report of a year. stats, when given, supplies the average
+// energy and top mood; otherwise they are computed from the vibes.
+func buildReport(year int, metric string, vibes []client.VibeInput, moods []client.Mood, stats *client.Statistics, top int) *yearReport {
+	valence := make(map[string]float64)
+	for _, m := range moods {
+		valence[strings.ToLower(m.Name)] = m.Valence
+		for _, alias := range m.Aliases {
+			valence[strings.ToLower(alias)] = m.Valence
+		}
+	}
+
+	type sum struct {
+		total float64
+		n     int
+	}
+	days := make(map[string]*sum)
+	logged := make(map[string]bool)
+	start, weeks := weekGrid(year)
+	weekSums := make([]sum, weeks)
+	moodDays := make(map[string]int)
+	activities := make(map[string]int)
+	energyTotal := 0
+	report := &yearReport{Year: year, Metric: metric, Days: []dayValue{}, TopActivities: []activityCount{}}
+
+	for _, v := range vibes {
+		date := v.Date.UTC()
+		if date.Year() != year {
+			continue
+		}
+		key := date.Format(dateLayout)
+		report.Vibes++
+		logged[key] = true
+		energyTotal += v.EnergyLevel
+		moodDays[strings.ToLower(v.Mood)]++
+		for _, activity := range v.Activities {
+			if activity = strings.TrimSpace(activity); activity != "" {
+				activities[activity]++
+			}
+		}
+		week := int(date.Sub(start).Hours()/24) / 7
+		weekSums[week].total += float64(v.EnergyLevel)
+		weekSums[week].n++
+
+		value := float64(v.EnergyLevel)
+		if metric == reportValence {
+			var ok bool
+			if value, ok = valence[strings.ToLower(v.Mood)]; !ok {
+				continue // Moods missing from the catalog have no valence.
+			}
+		}
+		if days[key] == nil {
+			days[key] = &sum{}
+		}
+		days[key].total += value
+		days[key].n++
+	}
+
+	for key, s := range days {
+		report.Days = append(report.Days, dayValue{Date: key, Value: s.total / float64(s.n)})
+	}
+	sort.Slice(report.Days, func(i, j int) bool { return report.Days[i].Date < report.Days[j].Date })
+	report.DaysLogged = len(logged)
+
+	for i, s := range weekSums {
+		week := weekEnergy{WeekStart: start.AddDate(0, 0, i*7).Format(dateLayout)}
+		if s.n > 0 {
+			avg := s.total / float64(s.n)
+			week.AverageEnergy = &avg
+		}
+		report.WeeklyEnergy = append(report.WeeklyEnergy, week)
+	}
+
+	for activity, count := range activities {
+		report.TopActivities = append(report.TopActivities, activityCount{Activity: activity, Count: count})
+	}
+	sort.Slice(report.TopActivities, func(i, j int) bool {
+		a, b := report.TopActivities[i], report.TopActivities[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Activity < b.Activity
+	})
+	if top >= 0 && len(report.TopActivities) > top {
+		report.TopActivities = report.TopActivities[:top]
+	}
+
+	if stats != nil {
+		report.AverageEnergyLevel = stats.AverageEnergyLevel
+		best := client.MoodCount{}
+		for _, mc := range stats.MoodDistribution {
+			if mc.Count > best.Count {
+				best = mc
+			}
+		}
+		report.TopMood = best.Mood
+	} else {
+		if report.Vibes > 0 {
+			report.AverageEnergyLevel = float64(energyTotal) / float64(report.Vibes)
+		}
+		best := 0
+		for mood, n := range moodDays {
+			if n > best || (n == best && mood < report.TopMood) {
+				report.TopMood, best = mood, n
+			}
+		}
+	}
+	return report
+}
+
+// renderReport writes the heatmap, the weekly energy sparkline under it, with a character
+// per week column, and the activity bar chart.
+func renderReport(w io.Writer, r *yearReport, color bool) error {
+	fmt.Fprintf(w, "%d: %d vibes on %d days", r.Year, r.Vibes, r.DaysLogged)
+	if r.Vibes > 0 {
+		fmt.Fprintf(w, ", average energy %.1f", r.AverageEnergyLevel)
+		if r.TopMood != "" {
+			fmt.Fprintf(w, ", mostly %s", r.TopMood)
+		}
+	}
+	fmt.Fprintln(w)
+	if r.Vibes == 0 {
+		return nil
+	}
+
+	title := "Mood valence"
+	p := valencePalette
+	if r.Metric == reportEnergy {
+		title, p = "Energy", energyPalette
+	}
+	values := make(map[string]float64, len(r.Days))
+	for _, d := range r.Days {
+		values[d.Date] = d.Value
+	}
+	fmt.Fprintf(w, "\n%s\n", title)
+	heatmap(w, r.Year, values, p, color)
+
+	weekly := make([]float64, len(r.WeeklyEnergy))
+	lowest, highest := math.Inf(1), math.Inf(-1)
+	for i, week := range r.WeeklyEnergy {
+		weekly[i] = math.NaN()
+		if week.AverageEnergy != nil {
+			weekly[i] = *week.AverageEnergy
+			lowest, highest = math.Min(lowest, weekly[i]), math.Max(highest, weekly[i])
+		}
+	}
+	fmt.Fprintf(w, "\nWeekly average energy (lowest %.1f, highest %.1f)\n", lowest, highest)
+	fmt.Fprintf(w, "%*s%s\n", heatmapLabelWidth, "", strings.TrimRight(sparkline(weekly, 1, 10), " "))
+
+	if len(r.TopActivities) > 0 {
+		fmt.Fprintln(w, "\nTop activities")
+		barChart(w, r.TopActivities)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aebalz/daily-vibe-tracker/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+func newStatsCmd(a *app) *cobra.Command {
+	var period string
+	cmd := &cobra.Command{
+		Use:     "stats",
+		Short:   "Show statistics of the current week, month or year",
+		Example: "  vibectl stats --period month",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			stats, err := c.Stats(cmd.Context(), client.Period(strings.ToLower(period)))
+			if err != nil {
+				return err
+			}
+			return a.print(stats, func(w io.Writer) error {
+				return statsTable(w, period, stats)
+			})
+		},
+	}
+	cmd.Flags().StringVar(&period, "period", "month", "Period: week, month or year")
+	_ = cmd.RegisterFlagCompletionFunc("period", fixedCompletion("week", "month", "year"))
+	return cmd
+}
+
+// statsTable writes statistics as a few small tables.
+func statsTable(w io.Writer, period string, stats *client.Statistics) error {
+	fmt.Fprintf(w, "Average energy this %s: %.1f\n\n", period, stats.AverageEnergyLevel)
+
+	if len(stats.MoodDistribution) > 0 {
+		tw := newTable(w)
+		row(tw, "MOOD", "DAYS")
+		for _, mc := range stats.MoodDistribution {
+			row(tw, mc.Mood, mc.Count)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+		fmt.Fprintln(w)
+	}
+
+	if len(stats.MetricAggregates) > 0 {
+		tw := newTable(w)
+		row(tw, "METRIC", "UNIT", "COUNT", "AVERAGE", "MIN", "MAX")
+		for _, m := range stats.MetricAggregates {
+			row(tw, m.Name, m.Unit, m.Count, fmt.Sprintf("%.2f", m.Average), fmt.Sprintf("%.2f", m.Min), fmt.Sprintf("%.2f", m.Max))
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+		fmt.Fprintln(w)
+	}
+
+	// The analytics are free-form; show messages as they are and results as JSON.
+	for _, item := range []struct {
+		title string
+		value interface{}
+	}{
+		{"Mood-energy correlation", stats.MoodEnergyCorrelation},
+		{"Activity-mood correlation", stats.ActivityMoodCorrelation},
+		{"Mood patterns", stats.MoodPatterns},
+	} {
+		if item.value == nil {
+			continue
+		}
+		if msg, ok := item.value.(string); ok {
+			fmt.Fprintf(w, "%s: %s\n", item.title, msg)
+			continue
+		}
+		data, err := json.Marshal(item.value)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s: %s\n", item.title, data)
+	}
+	return nil
+}
+
+func newStreakCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:               "streak MOOD",
+		Short:             "Show the current and longest streak of a mood",
+		Example:           "  vibectl streak happy",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: a.completeMoods,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			streak, err := c.Streak(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			return a.print(streak, func(w io.Writer) error {
+				_, err := fmt.Fprintf(w, "%s: current streak %d days, longest %d days\n", streak.Mood, streak.CurrentStreak, streak.LongestStreak)
+				return err
+			})
+		},
+	}
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aebalz/daily-vibe-tracker/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+func newExportCmd(a *app) *cobra.Command {
+	var (
+		format, from, to, mood, outFile string
+	)
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export vibes as CSV or JSON",
+		Example: `  vibectl export --format csv > vibes.csv
+  vibectl export --format json --from 2025-01-01 --file vibes-2025.json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			exportFormat := client.ExportFormat(strings.ToLower(format))
+			if exportFormat != client.FormatCSV && exportFormat != client.FormatJSON {
+				return fmt.Errorf("invalid format %q: use csv or json", format)
+			}
+			filter := client.NewFilter()
+			if from != "" {
+				day, err := parseDay(from)
+				if err != nil {
+					return err
+				}
+				filter.From(day)
+			}
+			if to != "" {
+				day, err := parseDay(to)
+				if err != nil {
+					return err
+				}
+				filter.To(day)
+			}
+			if mood != "" {
+				filter.Mood(mood)
+			}
+
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			export, err := c.ExportVibes(cmd.Context(), exportFormat, filter)
+			if err != nil {
+				return err
+			}
+			defer export.Close()
+
+			var w io.Writer = a.out
+			if outFile != "" && outFile != "-" {
+				f, err := os.Create(outFile)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				w = f
+			}
+			n, err := io.Copy(w, export)
+			if err != nil {
+				return fmt.Errorf("export interrupted after %d bytes: %w", n, err)
+			}
+			if outFile != "" && outFile != "-" {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Wrote %d bytes to %s\n", n, outFile)
+			}
+			return nil
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVarP(&format, "format", "f", "csv", "Export format: csv or json")
+	flags.StringVar(&from, "from", "", "First day (YYYY-MM-DD)")
+	flags.StringVar(&to, "to", "", "Last day (YYYY-MM-DD)")
+	flags.StringVar(&mood, "mood", "", "Mood")
+	flags.StringVar(&outFile, "file", "", "Write to this file instead of stdout")
+	_ = cmd.RegisterFlagCompletionFunc("format", fixedCompletion("csv", "json"))
+	return cmd
+}
+
+func newImportCmd(a *app) *cobra.Command {
+	var format string
+	cmd := &cobra.Command{
+		Use:   "import FILE",
+		Short: "Import vibes from a CSV or JSON export",
+		Long: `Imports the vibes of a file written by "vibectl export" or the export endpoint. Columns
+after Activities in a CSV file are custom metrics. All vibes are imported in one
+transaction: if one is invalid, none is imported. Use - to read from stdin.`,
+		Example: "  vibectl import vibes.csv",
+		Args:    cobra.ExactArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return []string{"csv", "json"}, cobra.ShellCompDirectiveFilterFileExt
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vibes, err := readVibeFile(cmd, args[0], format)
+			if err != nil {
+				return err
+			}
+			if len(vibes) == 0 {
+				return errors.New("no vibes in the file")
+			}
+
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			result, err := c.BulkImportVibes(cmd.Context(), vibes)
+			if err != nil {
+				return err
+			}
+			return a.print(result, func(w io.Writer) error {
+				_, err := fmt.Fprintf(w, "Imported %d vibes.\n", result.ImportedCount)
+				return err
+			})
+		},
+	}
+	cmd.Flags().StringVarP(&format, "format", "f", "", "File format: csv or json (default: from the file extension)")
+	_ = cmd.RegisterFlagCompletionFunc("format", fixedCompletion("csv", "json"))
+	return cmd
+}
+
+// readVibeFile reads an export file, or stdin for "-".
+func readVibeFile(cmd *cobra.Command, path, format string) ([]client.VibeInput, error) {
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	}
+	var r io.Reader = cmd.InOrStdin()
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	return readVibes(r, format)
+}
+
+// readVibes reads vibes from an export in the given format.
+func readVibes(r io.Reader, format string) ([]client.VibeInput, error) {
+	switch format {
+	case "csv":
+		return readCSVVibes(r)
+	case "json":
+		var vibes []client.Vibe
+		if err := json.NewDecoder(r).Decode(&vibes); err != nil {
+			return nil, fmt.Errorf("could not parse JSON: %w", err)
+		}
+		inputs := make([]client.VibeInput, 0, len(vibes))
+		for _, v := range vibes {
+			inputs = append(inputs, client.VibeInput{
+				Date:        v.Date,
+				Mood:        v.Mood,
+				EnergyLevel: v.EnergyLevel,
+				Notes:       v.Notes,
+				Activities:  v.Activities,
+				Metrics:     v.Metrics,
+			})
+		}
+		return inputs, nil
+	default:
+		return nil, fmt.Errorf("unknown file format %q: use --format csv or json", format)
+	}
+}
+
+// readCSVVibes reads the CSV export: ID, Date, Mood, EnergyLevel, Notes, Activities (joined
+// with ";"), then one column per custom metric. Columns are matched by name, so their
+// order does not matter; the ID is ignored.
+func readCSVVibes(r io.Reader) ([]client.VibeInput, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("could not read CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), "_", ""))] = i
+	}
+	for _, required := range []string{"date", "mood", "energylevel"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("CSV header has no %s column", required)
+		}
+	}
+	known := map[string]bool{"id": true, "date": true, "mood": true, "energylevel": true, "notes": true, "activities": true}
+
+	var vibes []client.VibeInput
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return vibes, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		get := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		date, err := parseExportDate(get("date"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		energy, err := strconv.Atoi(get("energylevel"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid energy level %q", line, get("energylevel"))
+		}
+		vibe := client.VibeInput{Date: date, Mood: get("mood"), EnergyLevel: energy, Notes: get("notes")}
+		if activities := get("activities"); activities != "" {
+			vibe.Activities = strings.Split(activities, ";")
+		}
+		for i, name := range header {
+			if known[strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), "_", ""))] || i >= len(record) || strings.TrimSpace(record[i]) == "" {
+				continue
+			}
+			if vibe.Metrics == nil {
+				vibe.Metrics = make(map[string]interface{})
+			}
+			vibe.Metrics[strings.TrimSpace(name)] = parseMetricValue(strings.TrimSpace(record[i]))
+		}
+		vibes = append(vibes, vibe)
+	}
+}
+
+// parseExportDate parses the RFC 3339 dates of exports, or a plain YYYY-MM-DD day.
+func parseExportDate(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(dateLayout, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
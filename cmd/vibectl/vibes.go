@@ -0,0 +1,215 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aebalz/daily-vibe-tracker/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+const dateLayout = "2006-01-02"
+
+// parseDay parses a YYYY-MM-DD day, or "today" and "yesterday", as midnight UTC, the way
+// the API stores vibe dates.
+func parseDay(value string) (time.Time, error) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	switch strings.ToLower(value) {
+	case "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+	day, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q: use YYYY-MM-DD", value)
+	}
+	return day, nil
+}
+
+// parseMetricValue reads a metric value typed on the command line as a number, a boolean
+// or, failing both, text.
+func parseMetricValue(raw string) interface{} {
+	if n, err := strconv.ParseFloat(raw, 64); err == nil {
+		return n
+	}
+	if b, err := strconv.ParseBool(raw); err == nil {
+		return b
+	}
+	return raw
+}
+
+func newLogCmd(a *app) *cobra.Command {
+	var (
+		energy     int
+		activities []string
+		notes      string
+		date       string
+		metrics    []string
+	)
+	cmd := &cobra.Command{
+		Use:   "log MOOD",
+		Short: "Record today's vibe",
+		Example: `  vibectl log happy -e 7 -a gym,reading -n "Long run before work"
+  vibectl log tired -e 3 --date yesterday -m sleep_hours=5.5`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: a.completeMoods,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			day, err := parseDay(date)
+			if err != nil {
+				return err
+			}
+			in := client.VibeInput{
+				Date:        day,
+				Mood:        args[0],
+				EnergyLevel: energy,
+				Notes:       notes,
+				Activities:  activities,
+			}
+			if len(metrics) > 0 {
+				in.Metrics = make(map[string]interface{}, len(metrics))
+				for _, m := range metrics {
+					name, value, ok := strings.Cut(m, "=")
+					if !ok || name == "" {
+						return fmt.Errorf("invalid metric %q: use name=value", m)
+					}
+					in.Metrics[name] = parseMetricValue(value)
+				}
+			}
+
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			vibe, err := c.CreateVibe(cmd.Context(), in)
+			if err != nil {
+				return err
+			}
+			return a.print(vibe, func(w io.Writer) error {
+				_, err := fmt.Fprintf(w, "Logged vibe %d: %s, energy %d on %s\n", vibe.ID, vibe.Mood, vibe.EnergyLevel, vibe.Date.Format(dateLayout))
+				return err
+			})
+		},
+	}
+	flags := cmd.Flags()
+	flags.IntVarP(&energy, "energy", "e", 5, "Energy level, 1 to 10")
+	flags.StringSliceVarP(&activities, "activities", "a", nil, "Comma-separated activities")
+	flags.StringVarP(&notes, "notes", "n", "", "Notes")
+	flags.StringVarP(&date, "date", "d", "today", "Day of the vibe (YYYY-MM-DD, today or yesterday)")
+	flags.StringArrayVarP(&metrics, "metric", "m", nil, "Custom metric as name=value; repeatable")
+	return cmd
+}
+
+func newListCmd(a *app) *cobra.Command {
+	var (
+		from, to, date, mood string
+		metrics              []string
+		sortBy, order        string
+		limit                int
+	)
+	cmd := &cobra.Command{
+		Use:     "ls",
+		Aliases: []string{"list"},
+		Short:   "List vibes",
+		Example: `  vibectl ls --from 2025-06-01 --to 2025-06-30 --mood happy
+  vibectl ls --metric sleep_hours:gte:7 --sort energy_level --limit 0 -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			filter := client.NewFilter().SortBy(sortBy)
+			days := []struct {
+				value string
+				apply func(time.Time) *client.Filter
+			}{{from, filter.From}, {to, filter.To}, {date, filter.Date}}
+			for _, d := range days {
+				if d.value == "" {
+					continue
+				}
+				day, err := parseDay(d.value)
+				if err != nil {
+					return err
+				}
+				d.apply(day)
+			}
+			if mood != "" {
+				filter.Mood(mood)
+			}
+			for _, m := range metrics {
+				parts := strings.SplitN(m, ":", 3)
+				if len(parts) != 3 {
+					return fmt.Errorf("invalid metric filter %q: use name:op:value", m)
+				}
+				filter.Metric(parts[0], client.MetricOp(parts[1]), parts[2])
+			}
+			switch strings.ToLower(order) {
+			case "asc":
+				filter.Asc()
+			case "desc":
+				filter.Desc()
+			default:
+				return fmt.Errorf("invalid order %q: use asc or desc", order)
+			}
+
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			var vibes []client.Vibe
+			if limit > 0 && limit <= 100 {
+				page, err := c.ListVibes(cmd.Context(), filter.Limit(limit))
+				if err != nil {
+					return err
+				}
+				vibes = page.Data
+			} else {
+				for vibe, err := range c.AllVibes(cmd.Context(), filter.Limit(100)) {
+					if err != nil {
+						return err
+					}
+					vibes = append(vibes, vibe)
+					if limit > 0 && len(vibes) == limit {
+						break
+					}
+				}
+			}
+			if vibes == nil {
+				vibes = []client.Vibe{}
+			}
+			return a.print(vibes, func(w io.Writer) error {
+				return vibeTable(w, vibes)
+			})
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&from, "from", "", "First day (YYYY-MM-DD)")
+	flags.StringVar(&to, "to", "", "Last day (YYYY-MM-DD)")
+	flags.StringVar(&date, "date", "", "Single day (YYYY-MM-DD)")
+	flags.StringVar(&mood, "mood", "", "Mood")
+	flags.StringArrayVar(&metrics, "metric", nil, "Custom metric filter as name:op:value (op: eq, ne, gt, gte, lt, lte); repeatable")
+	flags.StringVar(&sortBy, "sort", "date", "Sort by date, mood, energy_level or metric:<name>")
+	flags.StringVar(&order, "order", "desc", "Sort order, asc or desc")
+	flags.IntVarP(&limit, "limit", "l", 20, "Maximum number of vibes; 0 lists all")
+	_ = cmd.RegisterFlagCompletionFunc("mood", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return a.completeMoods(cmd, nil, toComplete)
+	})
+	_ = cmd.RegisterFlagCompletionFunc("order", fixedCompletion("asc", "desc"))
+	_ = cmd.RegisterFlagCompletionFunc("sort", fixedCompletion("date", "mood", "energy_level", "created_at", "updated_at"))
+	return cmd
+}
+
+// vibeTable writes vibes as a table.
+func vibeTable(w io.Writer, vibes []client.Vibe) error {
+	if len(vibes) == 0 {
+		_, err := fmt.Fprintln(w, "No vibes found.")
+		return err
+	}
+	tw := newTable(w)
+	row(tw, "ID", "DATE", "MOOD", "ENERGY", "ACTIVITIES", "NOTES")
+	for _, v := range vibes {
+		row(tw, v.ID, v.Date.Format(dateLayout), v.Mood, v.EnergyLevel, truncate(strings.Join(v.Activities, ","), 30), truncate(v.Notes, 40))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,64 @@
+// Command webhook-receiver is a local stand-in for a webhook endpoint. It verifies the
+// signature of every delivery, logs the event and can fail a share of requests on purpose,
+// so retries, the dead-letter queue and redelivery can be tried out without a real receiver.
+//
+//	go run ./cmd/webhook-receiver -addr :9090 -secret whsec_... -fail-rate 0.5
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/notifier"
+	"github.com/aebalz/daily-vibe-tracker/internal/service"
+)
+
+func main() {
+	addr := flag.String("addr", ":9090", "Address to listen on")
+	secret := flag.String("secret", "", "Subscription secret; signatures are not checked when empty")
+	tolerance := flag.Duration("tolerance", 5*time.Minute, "Maximum age of a signature")
+	failRate := flag.Float64("fail-rate", 0, "Share of deliveries answered with 500 (0-1)")
+	flag.Parse()
+
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+		if err != nil {
+			http.Error(w, "could not read body", http.StatusBadRequest)
+			return
+		}
+		if *secret != "" {
+			if err := notifier.VerifySignature(*secret, r.Header.Get(notifier.SignatureHeader), body, *tolerance, time.Now()); err != nil {
+				log.Printf("Rejected delivery %s: %v", r.Header.Get(service.WebhookDeliveryHeader), err)
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+		}
+		if *failRate > 0 && rand.Float64() < *failRate {
+			log.Printf("Failing delivery %s on purpose", r.Header.Get(service.WebhookDeliveryHeader))
+			http.Error(w, "simulated failure", http.StatusInternalServerError)
+			return
+		}
+
+		var envelope service.WebhookEnvelope
+		if err := json.Unmarshal(body, &envelope); err != nil {
+			http.Error(w, "invalid JSON", http.StatusBadRequest)
+			return
+		}
+		log.Printf("Delivery %s: event %d (%s) %s", r.Header.Get(service.WebhookDeliveryHeader), envelope.ID, envelope.Type, envelope.Data)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	log.Printf("Webhook receiver listening on %s", *addr)
+	if err := http.ListenAndServe(*addr, nil); err != nil {
+		log.Fatalf("Webhook receiver stopped: %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package analytics
+
+import "math"
+
+// EWMA tracks an exponentially weighted moving average and variance, giving a
+// baseline that adapts to gradual change while still flagging sudden jumps.
+type EWMA struct {
+	Alpha    float64 // Weight of each new observation, in (0, 1]
+	Mean     float64
+	Variance float64
+	Count    int // Observations seen so far
+}
+
+// NewEWMA creates an EWMA with the given smoothing factor.
+func NewEWMA(alpha float64) *EWMA {
+	return &EWMA{Alpha: alpha}
+}
+
+// Update folds an observation into the baseline.
+func (e *EWMA) Update(x float64) {
+	if e.Count == 0 {
+		e.Mean = x
+		e.Count = 1
+		return
+	}
+	diff := x - e.Mean
+	incr := e.Alpha * diff
+	e.Mean += incr
+	e.Variance = (1 - e.Alpha) * (e.Variance + diff*incr)
+	e.Count++
+}
+
+// StdDev returns the exponentially weighted standard deviation.
+func (e *EWMA) StdDev() float64 {
+	return math.Sqrt(e.Variance)
+}
+
+// ZScore returns how many standard deviations x lies from the current mean.
+// minStdDev puts a floor under the deviation so a perfectly flat history does not
+// turn a tiny change into an extreme score. Returns NaN before any observation.
+func (e *EWMA) ZScore(x, minStdDev float64) float64 {
+	if e.Count == 0 {
+		return math.NaN()
+	}
+	return (x - e.Mean) / math.Max(e.StdDev(), minStdDev)
+}
+
+// ProportionZScore returns the z-score of an observed proportion over n trials
+// against an expected proportion p, using the normal approximation to the binomial.
+// p is clamped away from 0 and 1 so the score stays finite.
+func ProportionZScore(observed, p float64, n int) float64 {
+	if n <= 0 {
+		return math.NaN()
+	}
+	p = math.Max(0.05, math.Min(0.95, p))
+	return (observed - p) / math.Sqrt(p*(1-p)/float64(n))
+}
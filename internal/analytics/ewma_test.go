@@ -0,0 +1,92 @@
+package analytics
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEWMAUpdate(t *testing.T) {
+	tests := []struct {
+		name         string
+		alpha        float64
+		observations []float64
+		wantMean     float64
+		wantVariance float64
+	}{
+		{"no observations", 0.5, nil, 0, 0},
+		{"single day", 0.5, []float64{5}, 5, 0},
+		// 2 -> mean 3, variance 0.5 * (0 + 2*1) = 1; 3 -> mean 3, variance 0.5 * 1.
+		{"several days", 0.5, []float64{2, 4, 3}, 3, 0.5},
+		{"flat history has zero variance", 0.2, []float64{6, 6, 6, 6}, 6, 0},
+		{"alpha 1 follows the latest day", 1, []float64{2, 9, 4}, 4, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewEWMA(tt.alpha)
+			for _, x := range tt.observations {
+				e.Update(x)
+			}
+			if e.Count != len(tt.observations) {
+				t.Errorf("Count = %d, want %d", e.Count, len(tt.observations))
+			}
+			if !approxEqual(e.Mean, tt.wantMean) || !approxEqual(e.Variance, tt.wantVariance) {
+				t.Errorf("Mean, Variance = %v, %v, want %v, %v", e.Mean, e.Variance, tt.wantMean, tt.wantVariance)
+			}
+			if !approxEqual(e.StdDev(), math.Sqrt(tt.wantVariance)) {
+				t.Errorf("StdDev() = %v, want %v", e.StdDev(), math.Sqrt(tt.wantVariance))
+			}
+		})
+	}
+}
+
+func TestEWMAZScore(t *testing.T) {
+	tests := []struct {
+		name         string
+		observations []float64
+		x, minStdDev float64
+		want         float64
+	}{
+		{"no observations", nil, 5, 1, math.NaN()},
+		{"single day uses the floor", []float64{5}, 6, 0.5, 2},
+		{"zero variance uses the floor", []float64{6, 6, 6}, 3, 1.5, -2},
+		{"at the mean", []float64{6, 6, 6}, 6, 1, 0},
+		// Mean 3 and variance 0.5 after 2, 4, 3.
+		{"deviation above the floor", []float64{2, 4, 3}, 4, 0.1, 1 / math.Sqrt(0.5)},
+		{"floor above the deviation", []float64{2, 4, 3}, 4, 2, 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewEWMA(0.5)
+			for _, x := range tt.observations {
+				e.Update(x)
+			}
+			if got := e.ZScore(tt.x, tt.minStdDev); !approxEqual(got, tt.want) {
+				t.Errorf("ZScore(%v, %v) = %v, want %v", tt.x, tt.minStdDev, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProportionZScore(t *testing.T) {
+	tests := []struct {
+		name        string
+		observed, p float64
+		n           int
+		want        float64
+	}{
+		{"as expected", 0.5, 0.5, 100, 0},
+		{"two standard errors above", 0.6, 0.5, 100, 2},
+		{"below expected", 0.3, 0.5, 25, -2},
+		// p is clamped to 0.05, so the standard error over 19 trials is 0.05.
+		{"expected proportion of zero", 0.24, 0, 19, 3.8},
+		{"expected proportion of one", 0.76, 1, 19, -3.8},
+		{"no trials", 0.5, 0.5, 0, math.NaN()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ProportionZScore(tt.observed, tt.p, tt.n); !approxEqual(got, tt.want) {
+				t.Errorf("ProportionZScore(%v, %v, %d) = %v, want %v", tt.observed, tt.p, tt.n, got, tt.want)
+			}
+		})
+	}
+}
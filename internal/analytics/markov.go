@@ -0,0 +1,93 @@
+package analytics
+
+import "sort"
+
+// TransitionCounts accumulates observed transitions between states, keyed by the
+// originating state and then the following state.
+type TransitionCounts map[string]map[string]int
+
+// Add records one transition from one state to another.
+func (t TransitionCounts) Add(from, to string) {
+	row, ok := t[from]
+	if !ok {
+		row = make(map[string]int)
+		t[from] = row
+	}
+	row[to]++
+}
+
+// Total returns the number of transitions observed out of a state.
+func (t TransitionCounts) Total(from string) int {
+	total := 0
+	for _, n := range t[from] {
+		total += n
+	}
+	return total
+}
+
+// States returns every state that appears in a transition, sorted.
+func (t TransitionCounts) States() []string {
+	seen := make(map[string]bool)
+	for from, row := range t {
+		seen[from] = true
+		for to := range row {
+			seen[to] = true
+		}
+	}
+	states := make([]string, 0, len(seen))
+	for s := range seen {
+		states = append(states, s)
+	}
+	sort.Strings(states)
+	return states
+}
+
+// Distribution returns the probability of moving from a state to each of states.
+// With smoothing > 0, that pseudo-count is added to every state (additive smoothing),
+// so transitions never observed keep a small probability. Returns nil if there is
+// nothing to normalize.
+func (t TransitionCounts) Distribution(from string, states []string, smoothing float64) map[string]float64 {
+	row := t[from]
+	denominator := float64(t.Total(from)) + smoothing*float64(len(states))
+	if denominator == 0 {
+		return nil
+	}
+	dist := make(map[string]float64, len(states))
+	for _, s := range states {
+		dist[s] = (float64(row[s]) + smoothing) / denominator
+	}
+	return dist
+}
+
+// Probabilities returns the row-normalized transition matrix over the observed
+// transitions, without smoothing. Each row sums to 1.
+func (t TransitionCounts) Probabilities() map[string]map[string]float64 {
+	matrix := make(map[string]map[string]float64, len(t))
+	for from, row := range t {
+		total := float64(t.Total(from))
+		if total == 0 {
+			continue
+		}
+		probs := make(map[string]float64, len(row))
+		for to, n := range row {
+			probs[to] = float64(n) / total
+		}
+		matrix[from] = probs
+	}
+	return matrix
+}
+
+// Normalize scales the values of dist in place so they sum to 1. It leaves dist
+// unchanged if the values sum to zero.
+func Normalize(dist map[string]float64) {
+	sum := 0.0
+	for _, p := range dist {
+		sum += p
+	}
+	if sum == 0 {
+		return
+	}
+	for k, p := range dist {
+		dist[k] = p / sum
+	}
+}
@@ -0,0 +1,138 @@
+package analytics
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+// transitions builds counts from consecutive pairs of each sequence. Separate sequences
+// stand for runs of days split by a gap, which must not be linked.
+func transitions(sequences ...[]string) TransitionCounts {
+	counts := TransitionCounts{}
+	for _, seq := range sequences {
+		for i := 1; i < len(seq); i++ {
+			counts.Add(seq[i-1], seq[i])
+		}
+	}
+	return counts
+}
+
+func approxEqualMaps(got, want map[string]float64) bool {
+	return maps.EqualFunc(got, want, approxEqual)
+}
+
+func TestTransitionCountsTotalAndStates(t *testing.T) {
+	tests := []struct {
+		name       string
+		counts     TransitionCounts
+		totals     map[string]int
+		wantStates []string
+	}{
+		{"no transitions", transitions(), map[string]int{"happy": 0}, []string{}},
+		{"single day", transitions([]string{"happy"}), map[string]int{"happy": 0}, []string{}},
+		{
+			"consecutive days",
+			transitions([]string{"happy", "happy", "sad", "happy"}),
+			map[string]int{"happy": 2, "sad": 1},
+			[]string{"happy", "sad"},
+		},
+		{
+			"gap between days",
+			transitions([]string{"happy", "sad"}, []string{"calm", "happy"}),
+			map[string]int{"happy": 1, "sad": 0, "calm": 1},
+			[]string{"calm", "happy", "sad"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for from, want := range tt.totals {
+				if got := tt.counts.Total(from); got != want {
+					t.Errorf("Total(%q) = %d, want %d", from, got, want)
+				}
+			}
+			if got := tt.counts.States(); !slices.Equal(got, tt.wantStates) {
+				t.Errorf("States() = %v, want %v", got, tt.wantStates)
+			}
+		})
+	}
+}
+
+func TestTransitionCountsDistribution(t *testing.T) {
+	counts := transitions([]string{"happy", "sad", "happy", "sad", "sad"}, []string{"happy", "happy"})
+	states := []string{"calm", "happy", "sad"}
+	tests := []struct {
+		name      string
+		from      string
+		smoothing float64
+		want      map[string]float64
+	}{
+		{"observed", "happy", 0, map[string]float64{"calm": 0, "happy": 1.0 / 3, "sad": 2.0 / 3}},
+		{"observed with smoothing", "happy", 1, map[string]float64{"calm": 1.0 / 6, "happy": 2.0 / 6, "sad": 3.0 / 6}},
+		{"never left", "calm", 0, nil},
+		{"never left with smoothing", "calm", 0.5, map[string]float64{"calm": 1.0 / 3, "happy": 1.0 / 3, "sad": 1.0 / 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := counts.Distribution(tt.from, states, tt.smoothing)
+			if (got == nil) != (tt.want == nil) || !approxEqualMaps(got, tt.want) {
+				t.Errorf("Distribution(%q, %v) = %v, want %v", tt.from, tt.smoothing, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransitionCountsProbabilities(t *testing.T) {
+	tests := []struct {
+		name   string
+		counts TransitionCounts
+		want   map[string]map[string]float64
+	}{
+		{"no transitions", transitions(), map[string]map[string]float64{}},
+		{
+			"rows sum to one",
+			transitions([]string{"happy", "sad", "happy", "happy"}),
+			map[string]map[string]float64{
+				"happy": {"happy": 0.5, "sad": 0.5},
+				"sad":   {"happy": 1},
+			},
+		},
+		{
+			"state only reached before a gap has no row",
+			transitions([]string{"happy", "sad"}, []string{"calm", "calm"}),
+			map[string]map[string]float64{
+				"happy": {"sad": 1},
+				"calm":  {"calm": 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.counts.Probabilities()
+			if !maps.EqualFunc(got, tt.want, approxEqualMaps) {
+				t.Errorf("Probabilities() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		dist map[string]float64
+		want map[string]float64
+	}{
+		{"scaled to one", map[string]float64{"happy": 2, "sad": 6}, map[string]float64{"happy": 0.25, "sad": 0.75}},
+		{"already normalized", map[string]float64{"happy": 1}, map[string]float64{"happy": 1}},
+		{"all zero is unchanged", map[string]float64{"happy": 0, "sad": 0}, map[string]float64{"happy": 0, "sad": 0}},
+		{"empty", map[string]float64{}, map[string]float64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Normalize(tt.dist)
+			if !approxEqualMaps(tt.dist, tt.want) {
+				t.Errorf("Normalize() = %v, want %v", tt.dist, tt.want)
+			}
+		})
+	}
+}
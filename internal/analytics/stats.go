@@ -0,0 +1,200 @@
+// Package analytics contains the pure statistical routines behind the vibe insights.
+// Functions here operate on plain slices and have no knowledge of storage or HTTP.
+package analytics
+
+import (
+	"math"
+	"sort"
+)
+
+// Mean returns the arithmetic mean of xs, or NaN for an empty slice.
+func Mean(xs []float64) float64 {
+	if len(xs) == 0 {
+		return math.NaN()
+	}
+	sum := 0.0
+	for _, x := range xs {
+		sum += x
+	}
+	return sum / float64(len(xs))
+}
+
+// Median returns the median of xs, or NaN for an empty slice. xs is not modified.
+func Median(xs []float64) float64 {
+	if len(xs) == 0 {
+		return math.NaN()
+	}
+	sorted := append([]float64(nil), xs...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// Pearson returns the Pearson correlation coefficient of the paired samples xs and ys.
+// It returns NaN if the slices differ in length, have fewer than two samples, or
+// either side has zero variance.
+func Pearson(xs, ys []float64) float64 {
+	n := len(xs)
+	if n != len(ys) || n < 2 {
+		return math.NaN()
+	}
+	mx, my := Mean(xs), Mean(ys)
+	var sxy, sxx, syy float64
+	for i := 0; i < n; i++ {
+		dx, dy := xs[i]-mx, ys[i]-my
+		sxy += dx * dy
+		sxx += dx * dx
+		syy += dy * dy
+	}
+	if sxx == 0 || syy == 0 {
+		return math.NaN()
+	}
+	r := sxy / math.Sqrt(sxx*syy)
+	// Guard against rounding pushing |r| slightly above 1.
+	return math.Max(-1, math.Min(1, r))
+}
+
+// Spearman returns Spearman's rank correlation coefficient of xs and ys.
+// Tied values receive their average rank.
+func Spearman(xs, ys []float64) float64 {
+	if len(xs) != len(ys) {
+		return math.NaN()
+	}
+	return Pearson(Ranks(xs), Ranks(ys))
+}
+
+// Ranks returns the 1-based ranks of xs, assigning tied values their average rank.
+func Ranks(xs []float64) []float64 {
+	idx := make([]int, len(xs))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool { return xs[idx[a]] < xs[idx[b]] })
+
+	ranks := make([]float64, len(xs))
+	for i := 0; i < len(idx); {
+		j := i
+		for j+1 < len(idx) && xs[idx[j+1]] == xs[idx[i]] {
+			j++
+		}
+		avg := float64(i+j)/2 + 1
+		for k := i; k <= j; k++ {
+			ranks[idx[k]] = avg
+		}
+		i = j + 1
+	}
+	return ranks
+}
+
+// Lift returns P(outcome | factor) / P(outcome) for paired boolean observations.
+// A lift above 1 means the outcome is more likely on days with the factor.
+// It returns NaN if the factor or the outcome never occurs.
+func Lift(factor, outcome []bool) float64 {
+	if len(factor) != len(outcome) || len(factor) == 0 {
+		return math.NaN()
+	}
+	var factorCount, outcomeCount, both int
+	for i := range factor {
+		if factor[i] {
+			factorCount++
+		}
+		if outcome[i] {
+			outcomeCount++
+		}
+		if factor[i] && outcome[i] {
+			both++
+		}
+	}
+	if factorCount == 0 || outcomeCount == 0 {
+		return math.NaN()
+	}
+	pOutcome := float64(outcomeCount) / float64(len(factor))
+	pOutcomeGivenFactor := float64(both) / float64(factorCount)
+	return pOutcomeGivenFactor / pOutcome
+}
+
+// CorrelationPValue returns the two-sided p-value for the null hypothesis that the
+// true correlation is zero, given a sample correlation r over n samples. It uses the
+// t-test with n-2 degrees of freedom, which also serves as the usual large-sample
+// approximation for Spearman's rho.
+func CorrelationPValue(r float64, n int) float64 {
+	if math.IsNaN(r) || n < 3 {
+		return math.NaN()
+	}
+	if math.Abs(r) >= 1 {
+		return 0
+	}
+	df := float64(n - 2)
+	t2 := r * r * df / (1 - r*r)
+	return RegularizedIncompleteBeta(df/2, 0.5, df/(df+t2))
+}
+
+// RegularizedIncompleteBeta evaluates I_x(a, b) using the continued fraction
+// expansion (Numerical Recipes, 6.4).
+func RegularizedIncompleteBeta(a, b, x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	lga, _ := math.Lgamma(a)
+	lgb, _ := math.Lgamma(b)
+	lgab, _ := math.Lgamma(a + b)
+	front := math.Exp(lgab - lga - lgb + a*math.Log(x) + b*math.Log(1-x))
+	// The continued fraction converges quickly only for x < (a+1)/(a+b+2);
+	// use the symmetry relation otherwise.
+	if x < (a+1)/(a+b+2) {
+		return front * betaContinuedFraction(a, b, x) / a
+	}
+	return 1 - front*betaContinuedFraction(b, a, 1-x)/b
+}
+
+func betaContinuedFraction(a, b, x float64) float64 {
+	const (
+		maxIterations = 200
+		epsilon       = 3e-14
+		tiny          = 1e-300
+	)
+	qab, qap, qam := a+b, a+1, a-1
+	c, d := 1.0, 1-qab*x/qap
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+	for m := 1; m <= maxIterations; m++ {
+		fm := float64(m)
+		m2 := 2 * fm
+		aa := fm * (b - fm) * x / ((qam + m2) * (a + m2))
+		d = 1 + aa*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		h *= d * c
+		aa = -(a + fm) * (qab + fm) * x / ((a + m2) * (qap + m2))
+		d = 1 + aa*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		del := d * c
+		h *= del
+		if math.Abs(del-1) < epsilon {
+			break
+		}
+	}
+	return h
+}
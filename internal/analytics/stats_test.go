@@ -0,0 +1,199 @@
+package analytics
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+// approxEqual reports whether got is within 1e-9 of want, treating two NaNs as equal.
+func approxEqual(got, want float64) bool {
+	if math.IsNaN(want) {
+		return math.IsNaN(got)
+	}
+	return math.Abs(got-want) <= 1e-9
+}
+
+func TestMean(t *testing.T) {
+	tests := []struct {
+		name string
+		xs   []float64
+		want float64
+	}{
+		{"empty", nil, math.NaN()},
+		{"single sample", []float64{5}, 5},
+		{"several samples", []float64{1, 2, 3, 4}, 2.5},
+		{"negative samples", []float64{-3, 3, -6}, -2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Mean(tt.xs); !approxEqual(got, tt.want) {
+				t.Errorf("Mean(%v) = %v, want %v", tt.xs, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMedian(t *testing.T) {
+	tests := []struct {
+		name string
+		xs   []float64
+		want float64
+	}{
+		{"empty", nil, math.NaN()},
+		{"single sample", []float64{7}, 7},
+		{"odd count", []float64{3, 1, 2}, 2},
+		{"even count", []float64{4, 1, 3, 2}, 2.5},
+		{"all equal", []float64{6, 6, 6, 6}, 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			xs := slices.Clone(tt.xs)
+			if got := Median(xs); !approxEqual(got, tt.want) {
+				t.Errorf("Median(%v) = %v, want %v", tt.xs, got, tt.want)
+			}
+			if !slices.Equal(xs, tt.xs) {
+				t.Errorf("Median modified its input: %v, was %v", xs, tt.xs)
+			}
+		})
+	}
+}
+
+func TestPearson(t *testing.T) {
+	tests := []struct {
+		name   string
+		xs, ys []float64
+		want   float64
+	}{
+		{"perfect positive", []float64{1, 2, 3}, []float64{2, 4, 6}, 1},
+		{"perfect negative", []float64{1, 2, 3}, []float64{3, 2, 1}, -1},
+		{"partial", []float64{1, 2, 3, 4, 5}, []float64{2, 4, 5, 4, 5}, 6 / math.Sqrt(60)},
+		{"uncorrelated", []float64{1, 2, 3, 4}, []float64{1, 3, 3, 1}, 0},
+		{"zero variance in xs", []float64{4, 4, 4}, []float64{1, 2, 3}, math.NaN()},
+		{"zero variance in ys", []float64{1, 2, 3}, []float64{8, 8, 8}, math.NaN()},
+		{"single sample", []float64{1}, []float64{2}, math.NaN()},
+		{"empty", nil, nil, math.NaN()},
+		{"length mismatch", []float64{1, 2, 3}, []float64{1, 2}, math.NaN()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Pearson(tt.xs, tt.ys); !approxEqual(got, tt.want) {
+				t.Errorf("Pearson(%v, %v) = %v, want %v", tt.xs, tt.ys, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpearman(t *testing.T) {
+	tests := []struct {
+		name   string
+		xs, ys []float64
+		want   float64
+	}{
+		{"monotonic but not linear", []float64{1, 2, 3, 4}, []float64{1, 4, 9, 16}, 1},
+		{"reversed", []float64{1, 2, 3, 4}, []float64{10, 5, 2, 1}, -1},
+		{"ties", []float64{1, 2, 2, 3}, []float64{1, 2, 3, 4}, 4.5 / math.Sqrt(4.5*5)},
+		{"zero variance", []float64{2, 2, 2}, []float64{1, 2, 3}, math.NaN()},
+		{"length mismatch", []float64{1, 2}, []float64{1}, math.NaN()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Spearman(tt.xs, tt.ys); !approxEqual(got, tt.want) {
+				t.Errorf("Spearman(%v, %v) = %v, want %v", tt.xs, tt.ys, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRanks(t *testing.T) {
+	tests := []struct {
+		name string
+		xs   []float64
+		want []float64
+	}{
+		{"empty", nil, []float64{}},
+		{"single sample", []float64{3}, []float64{1}},
+		{"distinct", []float64{3, 1, 2}, []float64{3, 1, 2}},
+		{"ties get their average rank", []float64{10, 20, 20, 30}, []float64{1, 2.5, 2.5, 4}},
+		{"all tied", []float64{5, 5, 5}, []float64{2, 2, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Ranks(tt.xs); !slices.Equal(got, tt.want) {
+				t.Errorf("Ranks(%v) = %v, want %v", tt.xs, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLift(t *testing.T) {
+	tests := []struct {
+		name            string
+		factor, outcome []bool
+		want            float64
+	}{
+		{"twice as likely", []bool{true, true, false, false}, []bool{true, false, false, false}, 2},
+		{"never with the factor", []bool{true, false, false}, []bool{false, true, true}, 0},
+		{"factor on every day", []bool{true, true, true}, []bool{true, false, true}, 1},
+		{"factor never occurs", []bool{false, false}, []bool{true, false}, math.NaN()},
+		{"outcome never occurs", []bool{true, false}, []bool{false, false}, math.NaN()},
+		{"empty", nil, nil, math.NaN()},
+		{"length mismatch", []bool{true}, []bool{true, false}, math.NaN()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Lift(tt.factor, tt.outcome); !approxEqual(got, tt.want) {
+				t.Errorf("Lift(%v, %v) = %v, want %v", tt.factor, tt.outcome, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCorrelationPValue(t *testing.T) {
+	tests := []struct {
+		name string
+		r    float64
+		n    int
+		want float64
+	}{
+		{"no correlation", 0, 10, 1},
+		// t = 1.633 with 8 degrees of freedom.
+		{"moderate correlation", 0.5, 10, 0.1411132812},
+		{"negative correlation is symmetric", -0.5, 10, 0.1411132812},
+		{"perfect correlation", 1, 10, 0},
+		{"below three samples", 0.9, 2, math.NaN()},
+		{"undefined correlation", math.NaN(), 10, math.NaN()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CorrelationPValue(tt.r, tt.n)
+			if math.IsNaN(tt.want) != math.IsNaN(got) || math.Abs(got-tt.want) > 1e-6 {
+				t.Errorf("CorrelationPValue(%v, %d) = %v, want %v", tt.r, tt.n, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegularizedIncompleteBeta(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b, x float64
+		want    float64
+	}{
+		{"x at 0", 2, 3, 0, 0},
+		{"x below 0", 2, 3, -1, 0},
+		{"x at 1", 2, 3, 1, 1},
+		{"uniform", 1, 1, 0.3, 0.3},
+		{"b = 1 is x^a", 2, 1, 0.3, 0.09},
+		{"b = 1 past the symmetry switch", 2, 1, 0.8, 0.64},
+		{"a = 1 is 1-(1-x)^b", 1, 3, 0.3, 0.657},
+		{"symmetric at one half", 4.5, 4.5, 0.5, 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RegularizedIncompleteBeta(tt.a, tt.b, tt.x); !approxEqual(got, tt.want) {
+				t.Errorf("RegularizedIncompleteBeta(%v, %v, %v) = %v, want %v", tt.a, tt.b, tt.x, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,7 +1,8 @@
 package config
 
 import (
-	"log"
+	"fmt"
+	"net/netip"
 	"os"
 	"strconv"
 	"strings"
@@ -27,12 +28,16 @@ type AppConfig struct {
 	ServerIdleTimeout  time.Duration
 	AppEnv             string
 	LogLevel           string
+	LogFormat          string // text (key=value) or json
 	AppName            string
 	CorsAllowedOrigins []string
+	TrustedProxies     []string // CIDRs or addresses of the proxies whose Forwarded/X-Forwarded-For/X-Real-IP headers are trusted
 	RateLimitMax       int
 	RateLimitWindow    time.Duration
-	RateLimitPerSecond float64 // For middleware
-	RateLimitBurst     int     // For middleware
+	RateLimitBulkMax   int    // Bulk imports per RATE_LIMIT_WINDOW and client; 0 uses the default policy
+	RateLimitExportMax int    // Exports per RATE_LIMIT_WINDOW and client; 0 uses the default policy
+	RateLimitAuthMax   int    // Failed authentications per RATE_LIMIT_WINDOW and IP; 0 uses the default policy
+	RateLimitStore     string // memory (limits per replica) or redis (limits shared by replicas)
 	SwaggerHost        string
 	SwaggerBasePath    string
 	SwaggerSchemes     []string
@@ -40,77 +45,390 @@ type AppConfig struct {
 	RedisPassword      string
 	RedisDB            int
 	CacheTTLExpiration time.Duration
+	MoodUnknownPolicy  string // reject, create or other
+
+	DBSlowQueryThreshold time.Duration // Queries taking longer are logged as warnings; 0 turns this off
+
+	InsightsMinSamples    int     // Minimum paired days before a correlation is reported
+	InsightsMinConfidence float64 // Minimum confidence (1 - p-value) before a correlation is reported
+
+	AnomalyCheckInterval time.Duration // How often recent days are re-checked for anomalies; 0 disables the schedule
+	AnomalyZThreshold    float64       // Z-score from which a deviation counts as an anomaly
+	AnomalyEWMAAlpha     float64       // Smoothing factor of the energy baseline
+	AnomalyWindowDays    int           // Window for detecting clusters of negative moods
+
+	RecommendationSeed int64 // Fixed seed for recommendation tie-breaking; 0 picks a new seed per request
+
+	ReminderCheckInterval time.Duration // How often due reminders and retries are processed; 0 disables reminders
+	ReminderGracePeriod   time.Duration // How late a missed reminder may still fire, e.g. after a restart
+	ReminderMaxAttempts   int           // Delivery attempts before a reminder is marked failed
+	ReminderRetryBackoff  time.Duration // Delay before the first retry; doubles with each attempt
+	NotifierTimeout       time.Duration // Timeout of a single webhook or SMTP delivery
+	SMTPHost              string        // SMTP server for email reminders; empty disables the smtp channel
+	SMTPPort              int
+	SMTPUsername          string
+	SMTPPassword          string
+	SMTPFrom              string
+
+	WebhookPollInterval time.Duration // How often the outbox is polled for due deliveries; 0 disables the worker
+	WebhookMaxAttempts  int           // Delivery attempts before a delivery moves to the dead-letter queue
+	WebhookRetryBackoff time.Duration // Delay before the first retry; doubles with each attempt
+	WebhookMaxBackoff   time.Duration // Upper bound of the retry delay
+
+	EventQueueSize     int           // Queue capacity of each asynchronous event subscriber
+	EventRelayInterval time.Duration // How often undispatched outbox events are re-published; 0 disables the relay
+	EventRetention     time.Duration // How long dispatched events are kept in the outbox
+
+	StreamHeartbeatInterval     time.Duration // How often idle SSE and WebSocket connections get a heartbeat
+	StreamReplaySize            int           // Events kept for Last-Event-ID resume
+	StreamMaxConnections        int           // Open stream connections allowed in total
+	StreamMaxConnectionsPerUser int           // Open stream connections allowed per user
+
+	GraphQLMaxDepth      int // Deepest field nesting a GraphQL query may use
+	GraphQLMaxComplexity int // Highest cost a GraphQL query may have; list fields multiply by their page size
+
+	GRPCPort    int      // Port of the gRPC server; 0 disables it
+	GRPCAPIKeys []string // Keys accepted by the gRPC server; empty disables authentication
+
+	AuthRequired bool // Reject HTTP API requests without a valid API key; otherwise keys are optional
+
+	GinPort int // Port of the Gin server when SERVER_FRAMEWORK is both; Fiber uses SERVER_PORT
+
+	TracingExporter    string  // none, otlp (OTLP over gRPC) or stdout
+	TracingEndpoint    string  // host:port of the OTLP collector
+	TracingInsecure    bool    // Send OTLP without TLS, e.g. to a collector next to the server
+	TracingSampleRatio float64 // Share of traces started here that are recorded; incoming sampled traces always are
+	TracingServiceName string  // service.name of the spans
 }
 
-// LoadConfig loads configuration from .env file or environment variables.
-func LoadConfig(envFile ...string) (*AppConfig, error) {
+// LoadConfig loads configuration from .env file or environment variables. It also returns
+// warnings about the env file and about invalid values, which are replaced by defaults.
+func LoadConfig(envFile ...string) (*AppConfig, []string, error) {
+	var warnings []string
 	if len(envFile) > 0 {
 		if _, err := os.Stat(envFile[0]); err == nil {
 			err := godotenv.Load(envFile[0])
 			if err != nil {
-				log.Printf("Warning: Could not load .env file: %v. Using environment variables or defaults.", err)
+				warnings = append(warnings, fmt.Sprintf("Could not load .env file: %v. Using environment variables or defaults.", err))
 			}
 		} else {
-			log.Printf("Warning: Specified .env file %s not found. Using environment variables or defaults.", envFile[0])
+			warnings = append(warnings, fmt.Sprintf("Specified .env file %s not found. Using environment variables or defaults.", envFile[0]))
 		}
 	} else {
 		// Try loading default .env file if no specific file is provided
 		if _, err := os.Stat("config.env"); err == nil {
 			err := godotenv.Load("config.env")
 			if err != nil {
-				log.Printf("Warning: Could not load default config.env file: %v. Using environment variables or defaults.", err)
+				warnings = append(warnings, fmt.Sprintf("Could not load default config.env file: %v. Using environment variables or defaults.", err))
 			}
 		}
 	}
 
+	env := &envReader{}
 	cfg := &AppConfig{
-		DBHost:             getStringEnv("DB_HOST", "localhost"),
-		DBPort:             getIntEnv("DB_PORT", 5432),
-		DBUser:             getStringEnv("DB_USER", "postgres"),
-		DBPassword:         getStringEnv("DB_PASSWORD", "password"),
-		DBName:             getStringEnv("DB_NAME", "daily_vibe_tracker"),
-		DBSslMode:          getStringEnv("DB_SSL_MODE", "disable"),
-		DBTimezone:         getStringEnv("DB_TIMEZONE", "UTC"),
-		ServerPort:         getIntEnv("SERVER_PORT", 8080),
-		ServerHost:         getStringEnv("SERVER_HOST", "0.0.0.0"),
-		ServerFramework:    strings.ToLower(getStringEnv("SERVER_FRAMEWORK", "fiber")),
-		ServerReadTimeout:  getDurationEnv("SERVER_READ_TIMEOUT", "15s"),
-		ServerWriteTimeout: getDurationEnv("SERVER_WRITE_TIMEOUT", "15s"),
-		ServerIdleTimeout:  getDurationEnv("SERVER_IDLE_TIMEOUT", "60s"),
-		AppEnv:             strings.ToLower(getStringEnv("APP_ENV", "development")),
-		LogLevel:           strings.ToLower(getStringEnv("LOG_LEVEL", "info")),
-		AppName:            getStringEnv("APP_NAME", "Daily Vibe Tracker"),
-		CorsAllowedOrigins: getSliceEnv("CORS_ALLOWED_ORIGINS", "*"),
-		RateLimitMax:       getIntEnv("RATE_LIMIT_MAX", 100),         // Example, might not be directly used if rps/burst used
-		RateLimitWindow:    getDurationEnv("RATE_LIMIT_WINDOW", "1m"), // Example, might not be directly used
-		RateLimitPerSecond: getFloatEnv("RATE_LIMIT_RPS", 10),         // Requests per second for limiter
-		RateLimitBurst:     getIntEnv("RATE_LIMIT_BURST", 20),         // Burst for limiter
-		SwaggerHost:        getStringEnv("SWAGGER_HOST", "localhost:8080"),
-		SwaggerBasePath:    getStringEnv("SWAGGER_BASE_PATH", "/api/v1"), // Defaulting to /api/v1
-		SwaggerSchemes:     getSliceEnv("SWAGGER_SCHEMES", "http,https"),
-		RedisAddr:          getStringEnv("REDIS_ADDR", "localhost:6379"),
-		RedisPassword:      getStringEnv("REDIS_PASSWORD", ""), // No password by default
-		RedisDB:            getIntEnv("REDIS_DB", 0),           // Default Redis DB
-		CacheTTLExpiration: getDurationEnv("CACHE_TTL_EXPIRATION", "5m"),
+		DBHost:             env.getStringEnv("DB_HOST", "localhost"),
+		DBPort:             env.getIntEnv("DB_PORT", 5432),
+		DBUser:             env.getStringEnv("DB_USER", "postgres"),
+		DBPassword:         env.getStringEnv("DB_PASSWORD", "password"),
+		DBName:             env.getStringEnv("DB_NAME", "daily_vibe_tracker"),
+		DBSslMode:          env.getStringEnv("DB_SSL_MODE", "disable"),
+		DBTimezone:         env.getStringEnv("DB_TIMEZONE", "UTC"),
+		ServerPort:         env.getIntEnv("SERVER_PORT", 8080),
+		ServerHost:         env.getStringEnv("SERVER_HOST", "0.0.0.0"),
+		ServerFramework:    strings.ToLower(env.getStringEnv("SERVER_FRAMEWORK", "fiber")),
+		ServerReadTimeout:  env.getDurationEnv("SERVER_READ_TIMEOUT", "15s"),
+		ServerWriteTimeout: env.getDurationEnv("SERVER_WRITE_TIMEOUT", "15s"),
+		ServerIdleTimeout:  env.getDurationEnv("SERVER_IDLE_TIMEOUT", "60s"),
+		AppEnv:             strings.ToLower(env.getStringEnv("APP_ENV", "development")),
+		LogLevel:           strings.ToLower(env.getStringEnv("LOG_LEVEL", "info")),
+		LogFormat:          strings.ToLower(env.getStringEnv("LOG_FORMAT", "text")),
+		AppName:            env.getStringEnv("APP_NAME", "Daily Vibe Tracker"),
+		CorsAllowedOrigins: env.getSliceEnv("CORS_ALLOWED_ORIGINS", "*"),
+		TrustedProxies:     env.getSliceEnv("TRUSTED_PROXIES", ""), // None: the client IP is the peer address
+		RateLimitMax:       env.getIntEnv("RATE_LIMIT_MAX", 100),   // Requests per window and client; 0 disables rate limiting
+		RateLimitWindow:    env.getDurationEnv("RATE_LIMIT_WINDOW", "1m"),
+		RateLimitBulkMax:   env.getIntEnv("RATE_LIMIT_BULK_MAX", 5),
+		RateLimitExportMax: env.getIntEnv("RATE_LIMIT_EXPORT_MAX", 10),
+		RateLimitAuthMax:   env.getIntEnv("RATE_LIMIT_AUTH_MAX", 10),
+		RateLimitStore:     strings.ToLower(env.getStringEnv("RATE_LIMIT_STORE", "memory")),
+		SwaggerHost:        env.getStringEnv("SWAGGER_HOST", "localhost:8080"),
+		SwaggerBasePath:    env.getStringEnv("SWAGGER_BASE_PATH", "/api/v1"), // Defaulting to /api/v1
+		SwaggerSchemes:     env.getSliceEnv("SWAGGER_SCHEMES", "http,https"),
+		RedisAddr:          env.getStringEnv("REDIS_ADDR", "localhost:6379"),
+		RedisPassword:      env.getStringEnv("REDIS_PASSWORD", ""), // No password by default
+		RedisDB:            env.getIntEnv("REDIS_DB", 0),           // Default Redis DB
+		CacheTTLExpiration: env.getDurationEnv("CACHE_TTL_EXPIRATION", "5m"),
+		MoodUnknownPolicy:  strings.ToLower(env.getStringEnv("MOOD_UNKNOWN_POLICY", "create")),
+
+		DBSlowQueryThreshold: env.getDurationEnv("DB_SLOW_QUERY_THRESHOLD", "200ms"),
+
+		InsightsMinSamples:    env.getIntEnv("INSIGHTS_MIN_SAMPLES", 10),
+		InsightsMinConfidence: env.getFloatEnv("INSIGHTS_MIN_CONFIDENCE", 0.95),
+
+		AnomalyCheckInterval: env.getDurationEnv("ANOMALY_CHECK_INTERVAL", "1h"),
+		AnomalyZThreshold:    env.getFloatEnv("ANOMALY_Z_THRESHOLD", 2.5),
+		AnomalyEWMAAlpha:     env.getFloatEnv("ANOMALY_EWMA_ALPHA", 0.2),
+		AnomalyWindowDays:    env.getIntEnv("ANOMALY_WINDOW_DAYS", 7),
+
+		RecommendationSeed: int64(env.getIntEnv("RECOMMENDATION_SEED", 0)),
+
+		ReminderCheckInterval: env.getDurationEnv("REMINDER_CHECK_INTERVAL", "1m"),
+		ReminderGracePeriod:   env.getDurationEnv("REMINDER_GRACE_PERIOD", "2h"),
+		ReminderMaxAttempts:   env.getIntEnv("REMINDER_MAX_ATTEMPTS", 5),
+		ReminderRetryBackoff:  env.getDurationEnv("REMINDER_RETRY_BACKOFF", "1m"),
+		NotifierTimeout:       env.getDurationEnv("NOTIFIER_TIMEOUT", "10s"),
+		SMTPHost:              env.getStringEnv("SMTP_HOST", ""),
+		SMTPPort:              env.getIntEnv("SMTP_PORT", 587),
+		SMTPUsername:          env.getStringEnv("SMTP_USERNAME", ""),
+		SMTPPassword:          env.getStringEnv("SMTP_PASSWORD", ""),
+		SMTPFrom:              env.getStringEnv("SMTP_FROM", "vibes@localhost"),
+
+		WebhookPollInterval: env.getDurationEnv("WEBHOOK_POLL_INTERVAL", "2s"),
+		WebhookMaxAttempts:  env.getIntEnv("WEBHOOK_MAX_ATTEMPTS", 8),
+		WebhookRetryBackoff: env.getDurationEnv("WEBHOOK_RETRY_BACKOFF", "30s"),
+		WebhookMaxBackoff:   env.getDurationEnv("WEBHOOK_MAX_BACKOFF", "1h"),
+
+		EventQueueSize:     env.getIntEnv("EVENT_QUEUE_SIZE", 256),
+		EventRelayInterval: env.getDurationEnv("EVENT_RELAY_INTERVAL", "10s"),
+		EventRetention:     env.getDurationEnv("EVENT_RETENTION", "168h"),
+
+		StreamHeartbeatInterval:     env.getDurationEnv("STREAM_HEARTBEAT_INTERVAL", "15s"),
+		StreamReplaySize:            env.getIntEnv("STREAM_REPLAY_SIZE", 100),
+		StreamMaxConnections:        env.getIntEnv("STREAM_MAX_CONNECTIONS", 1000),
+		StreamMaxConnectionsPerUser: env.getIntEnv("STREAM_MAX_CONNECTIONS_PER_USER", 10),
+
+		GraphQLMaxDepth:      env.getIntEnv("GRAPHQL_MAX_DEPTH", 10),
+		GraphQLMaxComplexity: env.getIntEnv("GRAPHQL_MAX_COMPLEXITY", 1000),
+
+		GRPCPort:    env.getIntEnv("GRPC_PORT", 50051),
+		GRPCAPIKeys: env.getSliceEnv("GRPC_API_KEYS", ""),
+
+		AuthRequired: env.getBoolEnv("AUTH_REQUIRED", false),
+
+		GinPort: env.getIntEnv("GIN_PORT", 8081),
+
+		TracingExporter:    strings.ToLower(env.getStringEnv("TRACING_EXPORTER", "none")),
+		TracingEndpoint:    env.getStringEnv("TRACING_OTLP_ENDPOINT", "localhost:4317"),
+		TracingInsecure:    env.getBoolEnv("TRACING_OTLP_INSECURE", true),
+		TracingSampleRatio: env.getFloatEnv("TRACING_SAMPLE_RATIO", 1),
+		TracingServiceName: env.getStringEnv("TRACING_SERVICE_NAME", "daily-vibe-tracker"),
+	}
+
+	warnings = append(warnings, env.warnings...)
+	return cfg, append(warnings, Validate(cfg)...), nil
+}
+
+// Validate replaces invalid values of cfg by their defaults and returns a warning for each.
+func Validate(cfg *AppConfig) []string {
+	var warnings []string
+	warn := func(format string, args ...any) {
+		warnings = append(warnings, fmt.Sprintf(format, args...))
 	}
 
 	// Validate framework choice
-	if cfg.ServerFramework != "fiber" && cfg.ServerFramework != "gin" {
-		log.Printf("Warning: Invalid SERVER_FRAMEWORK '%s'. Defaulting to 'fiber'.", cfg.ServerFramework)
+	if cfg.ServerFramework != "fiber" && cfg.ServerFramework != "gin" && cfg.ServerFramework != "both" {
+		warn("Invalid SERVER_FRAMEWORK '%s'. Defaulting to 'fiber'.", cfg.ServerFramework)
 		cfg.ServerFramework = "fiber"
 	}
 
 	// Validate APP_ENV
 	validAppEnvs := map[string]bool{"development": true, "staging": true, "production": true}
 	if !validAppEnvs[cfg.AppEnv] {
-		log.Printf("Warning: Invalid APP_ENV '%s'. Defaulting to 'development'.", cfg.AppEnv)
+		warn("Invalid APP_ENV '%s'. Defaulting to 'development'.", cfg.AppEnv)
 		cfg.AppEnv = "development"
 	}
 
-	return cfg, nil
+	// Validate MOOD_UNKNOWN_POLICY
+	validMoodPolicies := map[string]bool{"reject": true, "create": true, "other": true}
+	if !validMoodPolicies[cfg.MoodUnknownPolicy] {
+		warn("Invalid MOOD_UNKNOWN_POLICY '%s'. Defaulting to 'create'.", cfg.MoodUnknownPolicy)
+		cfg.MoodUnknownPolicy = "create"
+	}
+
+	// Validate insight thresholds
+	if cfg.InsightsMinSamples < 3 {
+		warn("Invalid INSIGHTS_MIN_SAMPLES %d. Defaulting to 10.", cfg.InsightsMinSamples)
+		cfg.InsightsMinSamples = 10
+	}
+	if cfg.InsightsMinConfidence <= 0 || cfg.InsightsMinConfidence >= 1 {
+		warn("Invalid INSIGHTS_MIN_CONFIDENCE %f. Defaulting to 0.95.", cfg.InsightsMinConfidence)
+		cfg.InsightsMinConfidence = 0.95
+	}
+
+	// Validate anomaly detection settings
+	if cfg.AnomalyZThreshold <= 0 {
+		warn("Invalid ANOMALY_Z_THRESHOLD %f. Defaulting to 2.5.", cfg.AnomalyZThreshold)
+		cfg.AnomalyZThreshold = 2.5
+	}
+	if cfg.AnomalyEWMAAlpha <= 0 || cfg.AnomalyEWMAAlpha > 1 {
+		warn("Invalid ANOMALY_EWMA_ALPHA %f. Defaulting to 0.2.", cfg.AnomalyEWMAAlpha)
+		cfg.AnomalyEWMAAlpha = 0.2
+	}
+	if cfg.AnomalyWindowDays < 3 {
+		warn("Invalid ANOMALY_WINDOW_DAYS %d. Defaulting to 7.", cfg.AnomalyWindowDays)
+		cfg.AnomalyWindowDays = 7
+	}
+
+	// Validate reminder settings
+	if cfg.ReminderMaxAttempts < 1 {
+		warn("Invalid REMINDER_MAX_ATTEMPTS %d. Defaulting to 5.", cfg.ReminderMaxAttempts)
+		cfg.ReminderMaxAttempts = 5
+	}
+	if cfg.ReminderRetryBackoff <= 0 {
+		warn("Invalid REMINDER_RETRY_BACKOFF %s. Defaulting to 1m.", cfg.ReminderRetryBackoff)
+		cfg.ReminderRetryBackoff = time.Minute
+	}
+	if cfg.NotifierTimeout <= 0 {
+		warn("Invalid NOTIFIER_TIMEOUT %s. Defaulting to 10s.", cfg.NotifierTimeout)
+		cfg.NotifierTimeout = 10 * time.Second
+	}
+
+	// Validate webhook settings
+	if cfg.WebhookMaxAttempts < 1 {
+		warn("Invalid WEBHOOK_MAX_ATTEMPTS %d. Defaulting to 8.", cfg.WebhookMaxAttempts)
+		cfg.WebhookMaxAttempts = 8
+	}
+	if cfg.WebhookRetryBackoff <= 0 {
+		warn("Invalid WEBHOOK_RETRY_BACKOFF %s. Defaulting to 30s.", cfg.WebhookRetryBackoff)
+		cfg.WebhookRetryBackoff = 30 * time.Second
+	}
+	if cfg.WebhookMaxBackoff < cfg.WebhookRetryBackoff {
+		warn("Invalid WEBHOOK_MAX_BACKOFF %s. Defaulting to 1h.", cfg.WebhookMaxBackoff)
+		cfg.WebhookMaxBackoff = time.Hour
+	}
+
+	// Validate event bus settings
+	if cfg.EventQueueSize < 1 {
+		warn("Invalid EVENT_QUEUE_SIZE %d. Defaulting to 256.", cfg.EventQueueSize)
+		cfg.EventQueueSize = 256
+	}
+
+	// Validate stream settings
+	if cfg.StreamHeartbeatInterval <= 0 {
+		warn("Invalid STREAM_HEARTBEAT_INTERVAL %s. Defaulting to 15s.", cfg.StreamHeartbeatInterval)
+		cfg.StreamHeartbeatInterval = 15 * time.Second
+	}
+	if cfg.StreamReplaySize < 0 {
+		warn("Invalid STREAM_REPLAY_SIZE %d. Defaulting to 100.", cfg.StreamReplaySize)
+		cfg.StreamReplaySize = 100
+	}
+	if cfg.StreamMaxConnections < 1 {
+		warn("Invalid STREAM_MAX_CONNECTIONS %d. Defaulting to 1000.", cfg.StreamMaxConnections)
+		cfg.StreamMaxConnections = 1000
+	}
+	if cfg.StreamMaxConnectionsPerUser < 1 {
+		warn("Invalid STREAM_MAX_CONNECTIONS_PER_USER %d. Defaulting to 10.", cfg.StreamMaxConnectionsPerUser)
+		cfg.StreamMaxConnectionsPerUser = 10
+	}
+
+	// Validate GraphQL limits
+	if cfg.GraphQLMaxDepth < 1 {
+		warn("Invalid GRAPHQL_MAX_DEPTH %d. Defaulting to 10.", cfg.GraphQLMaxDepth)
+		cfg.GraphQLMaxDepth = 10
+	}
+	if cfg.GraphQLMaxComplexity < 1 {
+		warn("Invalid GRAPHQL_MAX_COMPLEXITY %d. Defaulting to 1000.", cfg.GraphQLMaxComplexity)
+		cfg.GraphQLMaxComplexity = 1000
+	}
+
+	// Validate gRPC settings
+	if cfg.GRPCPort < 0 || cfg.GRPCPort > 65535 {
+		warn("Invalid GRPC_PORT %d. Defaulting to 50051.", cfg.GRPCPort)
+		cfg.GRPCPort = 50051
+	}
+	if cfg.GRPCPort != 0 && cfg.GRPCPort == cfg.ServerPort {
+		warn("GRPC_PORT %d is the HTTP server port. Disabling the gRPC server.", cfg.GRPCPort)
+		cfg.GRPCPort = 0
+	}
+
+	// Validate logging
+	switch cfg.LogLevel {
+	case "debug", "info", "warn", "warning", "error", "fatal", "panic":
+	default:
+		warn("Invalid LOG_LEVEL '%s'. Defaulting to 'info'.", cfg.LogLevel)
+		cfg.LogLevel = "info"
+	}
+	if cfg.LogFormat != "text" && cfg.LogFormat != "json" {
+		warn("Invalid LOG_FORMAT '%s'. Defaulting to 'text'.", cfg.LogFormat)
+		cfg.LogFormat = "text"
+	}
+	if cfg.DBSlowQueryThreshold < 0 {
+		warn("Invalid DB_SLOW_QUERY_THRESHOLD %s. Defaulting to 200ms.", cfg.DBSlowQueryThreshold)
+		cfg.DBSlowQueryThreshold = 200 * time.Millisecond
+	}
+
+	// Validate tracing
+	if cfg.TracingExporter != "none" && cfg.TracingExporter != "otlp" && cfg.TracingExporter != "stdout" {
+		warn("Invalid TRACING_EXPORTER '%s'. Defaulting to 'none'.", cfg.TracingExporter)
+		cfg.TracingExporter = "none"
+	}
+	if cfg.TracingSampleRatio < 0 || cfg.TracingSampleRatio > 1 {
+		warn("Invalid TRACING_SAMPLE_RATIO %f. Defaulting to 1.", cfg.TracingSampleRatio)
+		cfg.TracingSampleRatio = 1
+	}
+
+	// Validate the trusted proxies
+	proxies := cfg.TrustedProxies[:0]
+	for _, proxy := range cfg.TrustedProxies {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+		if _, err := netip.ParsePrefix(proxy); err != nil {
+			if _, err := netip.ParseAddr(proxy); err != nil {
+				warn("Invalid TRUSTED_PROXIES entry '%s' is ignored. Use a CIDR like 10.0.0.0/8 or an IP address.", proxy)
+				continue
+			}
+		}
+		proxies = append(proxies, proxy)
+	}
+	cfg.TrustedProxies = proxies
+
+	// Validate rate limiting
+	if cfg.RateLimitMax < 0 {
+		warn("Invalid RATE_LIMIT_MAX %d. Defaulting to 100.", cfg.RateLimitMax)
+		cfg.RateLimitMax = 100
+	}
+	if cfg.RateLimitWindow <= 0 {
+		warn("Invalid RATE_LIMIT_WINDOW %s. Defaulting to 1m.", cfg.RateLimitWindow)
+		cfg.RateLimitWindow = time.Minute
+	}
+	if cfg.RateLimitStore != "memory" && cfg.RateLimitStore != "redis" {
+		warn("Invalid RATE_LIMIT_STORE '%s'. Defaulting to 'memory'.", cfg.RateLimitStore)
+		cfg.RateLimitStore = "memory"
+	}
+	for _, key := range []string{"RATE_LIMIT_RPS", "RATE_LIMIT_BURST"} {
+		if _, ok := os.LookupEnv(key); ok {
+			warn("%s is no longer used. Set RATE_LIMIT_MAX requests per RATE_LIMIT_WINDOW instead.", key)
+		}
+	}
+
+	// Validate the Gin port of SERVER_FRAMEWORK=both
+	if cfg.ServerFramework == "both" {
+		if cfg.GinPort < 1 || cfg.GinPort > 65535 || cfg.GinPort == cfg.ServerPort || cfg.GinPort == cfg.GRPCPort {
+			warn("Invalid GIN_PORT %d. Defaulting to SERVER_PORT + 1 (%d).", cfg.GinPort, cfg.ServerPort+1)
+			cfg.GinPort = cfg.ServerPort + 1
+		}
+		if cfg.GRPCPort != 0 && cfg.GRPCPort == cfg.GinPort {
+			warn("GRPC_PORT %d is the Gin server port. Disabling the gRPC server.", cfg.GRPCPort)
+			cfg.GRPCPort = 0
+		}
+	}
+
+	return warnings
+}
+
+// envReader reads typed settings from the environment and keeps a warning for each value
+// it could not parse.
+type envReader struct {
+	warnings []string
 }
 
-func getStringEnv(key, defaultValue string) string {
+func (e *envReader) warn(format string, args ...any) {
+	e.warnings = append(e.warnings, fmt.Sprintf(format, args...))
+}
+
+func (e *envReader) getStringEnv(key, defaultValue string) string {
 	value, exists := os.LookupEnv(key)
 	if !exists {
 		return defaultValue
@@ -118,27 +436,40 @@ func getStringEnv(key, defaultValue string) string {
 	return value
 }
 
-func getIntEnv(key string, defaultValue int) int {
+func (e *envReader) getIntEnv(key string, defaultValue int) int {
 	valueStr, exists := os.LookupEnv(key)
 	if !exists {
 		return defaultValue
 	}
 	value, err := strconv.Atoi(valueStr)
 	if err != nil {
-		log.Printf("Warning: Invalid value for %s: %s. Using default %d.", key, valueStr, defaultValue)
+		e.warn("Invalid value for %s: %s. Using default %d.", key, valueStr, defaultValue)
+		return defaultValue
+	}
+	return value
+}
+
+func (e *envReader) getBoolEnv(key string, defaultValue bool) bool {
+	valueStr, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+	value, err := strconv.ParseBool(valueStr)
+	if err != nil {
+		e.warn("Invalid value for %s: %s. Using default %t.", key, valueStr, defaultValue)
 		return defaultValue
 	}
 	return value
 }
 
-func getDurationEnv(key, defaultValue string) time.Duration {
+func (e *envReader) getDurationEnv(key, defaultValue string) time.Duration {
 	valueStr, exists := os.LookupEnv(key)
 	if !exists {
 		valueStr = defaultValue
 	}
 	value, err := time.ParseDuration(valueStr)
 	if err != nil {
-		log.Printf("Warning: Invalid duration value for %s: %s. Using default %s.", key, valueStr, defaultValue)
+		e.warn("Invalid duration value for %s: %s. Using default %s.", key, valueStr, defaultValue)
 		// Try parsing default value in case it's also bad (though it shouldn't be)
 		defaultDur, _ := time.ParseDuration(defaultValue)
 		return defaultDur
@@ -146,7 +477,7 @@ func getDurationEnv(key, defaultValue string) time.Duration {
 	return value
 }
 
-func getSliceEnv(key, defaultValue string) []string {
+func (e *envReader) getSliceEnv(key, defaultValue string) []string {
 	valueStr, exists := os.LookupEnv(key)
 	if !exists {
 		valueStr = defaultValue
@@ -157,14 +488,14 @@ func getSliceEnv(key, defaultValue string) []string {
 	return strings.Split(valueStr, ",")
 }
 
-func getFloatEnv(key string, defaultValue float64) float64 {
+func (e *envReader) getFloatEnv(key string, defaultValue float64) float64 {
 	valueStr, exists := os.LookupEnv(key)
 	if !exists {
 		return defaultValue
 	}
 	value, err := strconv.ParseFloat(valueStr, 64)
 	if err != nil {
-		log.Printf("Warning: Invalid float value for %s: %s. Using default %f.", key, valueStr, defaultValue)
+		e.warn("Invalid float value for %s: %s. Using default %f.", key, valueStr, defaultValue)
 		return defaultValue
 	}
 	return value
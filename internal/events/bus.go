@@ -0,0 +1,219 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/logging"
+)
+
+// ErrBusClosed is returned by Publish after Close.
+var ErrBusClosed = errors.New("event bus is closed")
+
+// ErrQueueFull is returned by PublishPending for an asynchronous subscriber whose queue
+// had no room for the event.
+var ErrQueueFull = errors.New("subscriber queue is full")
+
+// Handler reacts to an event.
+type Handler func(ctx context.Context, event Event) error
+
+// SubscriberStats describes the state of one subscriber.
+type SubscriberStats struct {
+	Name      string `json:"name"`
+	Async     bool   `json:"async"`
+	QueueLen  int    `json:"queue_len"`
+	QueueCap  int    `json:"queue_cap"`
+	Delivered int64  `json:"delivered"`
+	Failed    int64  `json:"failed"`
+	Dropped   int64  `json:"dropped"` // Events discarded because the queue was full
+}
+
+type subscriber struct {
+	name    string
+	types   map[string]bool // Empty means all event types
+	handler Handler
+	queue   chan Event // nil for synchronous subscribers
+
+	delivered atomic.Int64
+	failed    atomic.Int64
+	dropped   atomic.Int64
+}
+
+func (s *subscriber) wants(eventType string) bool {
+	return len(s.types) == 0 || s.types[eventType]
+}
+
+// handle runs the handler, turning a panic into an error so one subscriber cannot take
+// down the publisher or the other subscribers.
+func (s *subscriber) handle(ctx context.Context, event Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+		if err != nil {
+			s.failed.Add(1)
+		} else {
+			s.delivered.Add(1)
+		}
+	}()
+	return s.handler(ctx, event)
+}
+
+// Bus dispatches events to subscribers in the order they subscribed. Synchronous
+// subscribers run inside Publish; asynchronous subscribers each have a bounded queue and
+// a worker goroutine. Publish drops and counts events that do not fit a queue, while
+// PublishPending leaves them to be published again.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers []*subscriber
+	wg          sync.WaitGroup
+	closed      bool
+	logger      *slog.Logger
+}
+
+// NewBus creates an empty Bus. A nil logger logs to the default logger.
+func NewBus(logger *slog.Logger) *Bus {
+	return &Bus{logger: logging.OrDefault(logger)}
+}
+
+func typeSet(types []string) map[string]bool {
+	set := make(map[string]bool, len(types))
+	for _, t := range types {
+		set[t] = true
+	}
+	return set
+}
+
+// Subscribe registers a synchronous subscriber for the given event types, or for all
+// events when none are given. Its errors are returned from Publish. The name identifies the
+// subscriber in the stats and across outbox retries, so it must be unique.
+func (b *Bus) Subscribe(name string, handler Handler, types ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers = append(b.subscribers, &subscriber{name: name, types: typeSet(types), handler: handler})
+}
+
+// SubscribeAsync registers an asynchronous subscriber with a queue of queueSize events.
+// Its errors are logged.
+func (b *Bus) SubscribeAsync(name string, queueSize int, handler Handler, types ...string) {
+	if queueSize < 1 {
+		queueSize = 1
+	}
+	sub := &subscriber{name: name, types: typeSet(types), handler: handler, queue: make(chan Event, queueSize)}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers = append(b.subscribers, sub)
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		ctx := context.Background()
+		for event := range sub.queue {
+			if err := sub.handle(ctx, event); err != nil {
+				b.logger.WarnContext(ctx, "Event subscriber failed", slog.String("subscriber", sub.name), slog.String("event", event.EventType()), slog.String("error", err.Error()))
+			}
+		}
+	}()
+}
+
+// Publish hands the event to every interested subscriber. It returns the joined errors of
+// the synchronous subscribers; asynchronous failures and drops are only logged.
+func (b *Bus) Publish(ctx context.Context, event Event) error {
+	_, err := b.publish(ctx, event, nil, true)
+	return err
+}
+
+// PublishPending is Publish for the interested subscribers that are not in done. It also
+// returns the names of the subscribers that took the event: the synchronous ones that
+// succeeded and the asynchronous ones that queued it. An asynchronous subscriber with a
+// full queue does not take the event and fails with ErrQueueFull instead of dropping it.
+// Adding the names to done and publishing again retries only the other subscribers.
+func (b *Bus) PublishPending(ctx context.Context, event Event, done map[string]bool) ([]string, error) {
+	return b.publish(ctx, event, done, false)
+}
+
+// publish hands the event to the interested subscribers that are not in done. With drop,
+// an event that does not fit an asynchronous queue is dropped; otherwise it is an error.
+func (b *Bus) publish(ctx context.Context, event Event, done map[string]bool, drop bool) ([]string, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return nil, ErrBusClosed
+	}
+	var took []string
+	var errs []error
+	for _, sub := range b.subscribers {
+		if !sub.wants(event.EventType()) || done[sub.name] {
+			continue
+		}
+		if sub.queue == nil {
+			if err := sub.handle(ctx, event); err != nil {
+				errs = append(errs, fmt.Errorf("subscriber '%s': %w", sub.name, err))
+				continue
+			}
+			took = append(took, sub.name)
+			continue
+		}
+		select {
+		case sub.queue <- event:
+			took = append(took, sub.name)
+		default:
+			if !drop {
+				errs = append(errs, fmt.Errorf("subscriber '%s': %w", sub.name, ErrQueueFull))
+				continue
+			}
+			sub.dropped.Add(1)
+			b.logger.WarnContext(ctx, "Event subscriber queue is full, dropped the event", slog.String("subscriber", sub.name), slog.String("event", event.EventType()))
+		}
+	}
+	return took, errors.Join(errs...)
+}
+
+// Stats returns the state of every subscriber.
+func (b *Bus) Stats() []SubscriberStats {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	stats := make([]SubscriberStats, 0, len(b.subscribers))
+	for _, sub := range b.subscribers {
+		stats = append(stats, SubscriberStats{
+			Name:      sub.name,
+			Async:     sub.queue != nil,
+			QueueLen:  len(sub.queue),
+			QueueCap:  cap(sub.queue),
+			Delivered: sub.delivered.Load(),
+			Failed:    sub.failed.Load(),
+			Dropped:   sub.dropped.Load(),
+		})
+	}
+	return stats
+}
+
+// Close stops accepting events and waits until the asynchronous subscribers have drained
+// their queues or ctx is done.
+func (b *Bus) Close(ctx context.Context) error {
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		for _, sub := range b.subscribers {
+			if sub.queue != nil {
+				close(sub.queue)
+			}
+		}
+	}
+	b.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,147 @@
+// Package events is an in-process domain event bus. Services describe what happened as
+// typed events; side effects such as anomaly detection, recommendation links and webhooks
+// subscribe to them instead of being called from the service that made the change.
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/model"
+)
+
+// Event types.
+const (
+	TypeVibeCreated  = "vibe.created"
+	TypeVibeUpdated  = "vibe.updated"
+	TypeVibeDeleted  = "vibe.deleted"
+	TypeBulkImported = "bulk.imported"
+)
+
+// Event is a typed domain event.
+type Event interface {
+	EventType() string
+}
+
+// VibeCreated is published after a vibe was created.
+type VibeCreated struct {
+	Vibe model.Vibe `json:"vibe"`
+}
+
+// VibeUpdated is published after a vibe was updated. Changes lists the fields that differ
+// between Before and After.
+type VibeUpdated struct {
+	Before  model.Vibe    `json:"before"`
+	After   model.Vibe    `json:"after"`
+	Changes []FieldChange `json:"changes"`
+}
+
+// VibeDeleted is published after a vibe was deleted; Vibe is its last state.
+type VibeDeleted struct {
+	Vibe model.Vibe `json:"vibe"`
+}
+
+// BulkImported is published after a bulk import.
+type BulkImported struct {
+	Count int64        `json:"count"`
+	Vibes []model.Vibe `json:"vibes"`
+}
+
+func (VibeCreated) EventType() string  { return TypeVibeCreated }
+func (VibeUpdated) EventType() string  { return TypeVibeUpdated }
+func (VibeDeleted) EventType() string  { return TypeVibeDeleted }
+func (BulkImported) EventType() string { return TypeBulkImported }
+
+// FieldChange is one changed field of a vibe.
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// DiffVibes returns the user-visible fields that differ between two states of a vibe.
+// Metrics are compared per metric, as "metrics.<name>".
+func DiffVibes(before, after model.Vibe) []FieldChange {
+	changes := []FieldChange{}
+	if !before.Date.Equal(after.Date) {
+		changes = append(changes, FieldChange{Field: "date", Old: before.Date.Format(time.RFC3339), New: after.Date.Format(time.RFC3339)})
+	}
+	if before.Mood != after.Mood {
+		changes = append(changes, FieldChange{Field: "mood", Old: before.Mood, New: after.Mood})
+	}
+	if before.EnergyLevel != after.EnergyLevel {
+		changes = append(changes, FieldChange{Field: "energy_level", Old: before.EnergyLevel, New: after.EnergyLevel})
+	}
+	if before.Notes != after.Notes {
+		changes = append(changes, FieldChange{Field: "notes", Old: before.Notes, New: after.Notes})
+	}
+	if !equalStrings(before.Activities, after.Activities) {
+		changes = append(changes, FieldChange{Field: "activities", Old: before.Activities, New: after.Activities})
+	}
+
+	names := make(map[string]bool, len(before.Metrics)+len(after.Metrics))
+	for name := range before.Metrics {
+		names[name] = true
+	}
+	for name := range after.Metrics {
+		names[name] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	for _, name := range sorted {
+		oldValue, newValue := before.Metrics[name], after.Metrics[name]
+		if !reflect.DeepEqual(oldValue, newValue) {
+			changes = append(changes, FieldChange{Field: "metrics." + name, Old: oldValue, New: newValue})
+		}
+	}
+	return changes
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// decoders creates an empty typed event for each event type, for decoding from the outbox.
+var decoders = map[string]func() Event{
+	TypeVibeCreated:  func() Event { return &VibeCreated{} },
+	TypeVibeUpdated:  func() Event { return &VibeUpdated{} },
+	TypeVibeDeleted:  func() Event { return &VibeDeleted{} },
+	TypeBulkImported: func() Event { return &BulkImported{} },
+}
+
+// Encode converts a typed event into an outbox record.
+func Encode(event Event) (*model.DomainEvent, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode %s event: %w", event.EventType(), err)
+	}
+	return &model.DomainEvent{Type: event.EventType(), Payload: string(payload)}, nil
+}
+
+// Decode converts an outbox record back into its typed event.
+func Decode(record model.DomainEvent) (Event, error) {
+	newEvent, ok := decoders[record.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown event type '%s'", record.Type)
+	}
+	ptr := newEvent()
+	if err := json.Unmarshal([]byte(record.Payload), ptr); err != nil {
+		return nil, fmt.Errorf("could not decode %s event %d: %w", record.Type, record.ID, err)
+	}
+	// Subscribers receive events by value, like the publisher created them.
+	return reflect.ValueOf(ptr).Elem().Interface().(Event), nil
+}
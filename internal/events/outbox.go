@@ -0,0 +1,218 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/logging"
+	"github.com/aebalz/daily-vibe-tracker/internal/model"
+	"github.com/aebalz/daily-vibe-tracker/internal/repository"
+	"gorm.io/gorm"
+)
+
+const (
+	// relayBatchSize bounds the events published per relay round.
+	relayBatchSize = 100
+	// relayClaimLease is how long a relay has to publish the events it claimed before
+	// another relay may claim them again, e.g. after a crash mid-batch.
+	relayClaimLease = 5 * time.Minute
+	// relayRetryBackoff is the delay before an event whose synchronous subscribers failed
+	// is published to them again; it doubles with each attempt, up to relayMaxBackoff.
+	relayRetryBackoff = 10 * time.Second
+	relayMaxBackoff   = 10 * time.Minute
+	// relayMaxAttempts bounds the attempts per event; the failed subscribers then miss it.
+	relayMaxAttempts = 10
+)
+
+// Outbox is a transactional outbox in front of a Bus. Events are stored in the same
+// transaction as the write that caused them and only reach subscribers after the commit,
+// so a rolled back write never publishes anything and a committed one is never lost.
+type Outbox struct {
+	Repo   repository.DomainEventRepositoryInterface
+	Bus    *Bus
+	Logger *slog.Logger
+
+	relayMu sync.Mutex // One relay round at a time within this process; Write does not take it
+}
+
+// NewOutbox creates an Outbox that publishes to bus. A nil logger logs to the default logger.
+func NewOutbox(repo repository.DomainEventRepositoryInterface, bus *Bus, logger *slog.Logger) *Outbox {
+	return &Outbox{Repo: repo, Bus: bus, Logger: logging.OrDefault(logger)}
+}
+
+// Write runs fn in a transaction and stores the events it returns in that transaction.
+// After the commit these events, and only these, are published right away; if that fails,
+// the periodic relay picks them up later. Other pending events are left to the relay, so a
+// write never waits for a backlog or for another relay round. The transaction runs with ctx,
+// and the publishing after the commit with ctx minus its cancellation, so a write whose
+// caller went away still records the outcome of the subscribers that ran.
+func (o *Outbox) Write(ctx context.Context, fn func(tx *gorm.DB) ([]Event, error)) error {
+	var records []*model.DomainEvent
+	err := o.Repo.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		events, err := fn(tx)
+		if err != nil {
+			return err
+		}
+		records = make([]*model.DomainEvent, 0, len(events))
+		for _, event := range events {
+			record, err := Encode(event)
+			if err != nil {
+				return err
+			}
+			records = append(records, record)
+		}
+		return o.Repo.Append(tx, records...)
+	})
+	if err != nil || len(records) == 0 {
+		return err
+	}
+	ids := make([]uint, len(records))
+	for i, record := range records {
+		ids[i] = record.ID
+	}
+	ctx = context.WithoutCancel(ctx)
+	repo := o.Repo.WithContext(ctx)
+	claimed, err := repo.ClaimByIDs(ids, time.Now(), relayClaimLease)
+	if err == nil {
+		_, err = o.publish(ctx, repo, claimed)
+	}
+	if err != nil {
+		o.Logger.WarnContext(ctx, "Event relay after commit failed", slog.String("error", err.Error()))
+	}
+	return nil
+}
+
+// Relay publishes due events to the bus in the order they were written. The events are
+// claimed first, so the subscribers run outside of any transaction and concurrent relays
+// never publish the same event. An event stays pending while a synchronous subscriber fails
+// on it, or an asynchronous one has no room in its queue, and is retried with backoff, but
+// only for those subscribers, so one failing subscriber does not replay the event to all
+// the others.
+func (o *Outbox) Relay(ctx context.Context) (int, error) {
+	o.relayMu.Lock()
+	defer o.relayMu.Unlock()
+	repo := o.Repo.WithContext(ctx)
+	total := 0
+	for {
+		records, err := repo.ClaimPending(time.Now(), relayClaimLease, relayBatchSize)
+		if err != nil {
+			return total, fmt.Errorf("could not relay outbox events: %w", err)
+		}
+		published, err := o.publish(ctx, repo, records)
+		total += published
+		if err != nil || len(records) < relayBatchSize {
+			return total, err
+		}
+	}
+}
+
+// publish dispatches claimed events in order and saves the outcomes through repo. It returns
+// the number of events that were dispatched for good.
+func (o *Outbox) publish(ctx context.Context, repo repository.DomainEventRepositoryInterface, records []model.DomainEvent) (int, error) {
+	total := 0
+	for i := range records {
+		record := &records[i]
+		if err := o.dispatch(ctx, record); err != nil {
+			o.release(ctx, repo, records[i:])
+			return total, fmt.Errorf("could not relay outbox events: %w", err)
+		}
+		if err := repo.SaveDispatch(record); err != nil {
+			return total, fmt.Errorf("could not save outbox event %d: %w", record.ID, err)
+		}
+		if record.DispatchedAt != nil {
+			total++
+		}
+	}
+	return total, nil
+}
+
+// dispatch publishes a claimed event to the subscribers that have not taken it yet and
+// records the outcome on it. It only fails when the bus is closed.
+func (o *Outbox) dispatch(ctx context.Context, record *model.DomainEvent) error {
+	now := time.Now()
+	event, err := Decode(*record)
+	if err != nil {
+		o.Logger.WarnContext(ctx, "Skipping an outbox event", slog.Uint64("event_id", uint64(record.ID)), slog.String("error", err.Error()))
+		record.LastError = err.Error()
+		record.NextAttemptAt = nil
+		record.DispatchedAt = &now
+		return nil
+	}
+	done := make(map[string]bool, len(record.DoneSubscribers))
+	for _, name := range record.DoneSubscribers {
+		done[name] = true
+	}
+	took, err := o.Bus.PublishPending(ctx, event, done)
+	if errors.Is(err, ErrBusClosed) {
+		return err
+	}
+	record.DoneSubscribers = append(record.DoneSubscribers, took...)
+	record.Attempts++
+	if err == nil {
+		record.LastError = ""
+		record.NextAttemptAt = nil
+		record.DispatchedAt = &now
+		return nil
+	}
+	record.LastError = err.Error()
+	if record.Attempts >= relayMaxAttempts {
+		o.Logger.WarnContext(ctx, "Outbox event given up", slog.Uint64("event_id", uint64(record.ID)), slog.String("event", record.Type), slog.Int("attempts", record.Attempts), slog.String("error", err.Error()))
+		record.NextAttemptAt = nil
+		record.DispatchedAt = &now
+		return nil
+	}
+	o.Logger.WarnContext(ctx, "Outbox event failed, retrying", slog.Uint64("event_id", uint64(record.ID)), slog.String("event", record.Type), slog.Int("attempts", record.Attempts), slog.String("error", err.Error()))
+	next := now.Add(relayBackoff(record.Attempts))
+	record.NextAttemptAt = &next
+	return nil
+}
+
+// release gives up the claim of events that were not published, so that the next start
+// publishes them without waiting for the lease to run out.
+func (o *Outbox) release(ctx context.Context, repo repository.DomainEventRepositoryInterface, records []model.DomainEvent) {
+	for i := range records {
+		records[i].NextAttemptAt = nil
+		if err := repo.SaveDispatch(&records[i]); err != nil {
+			o.Logger.WarnContext(ctx, "Could not release an outbox event", slog.Uint64("event_id", uint64(records[i].ID)), slog.String("error", err.Error()))
+		}
+	}
+}
+
+// relayBackoff returns relayRetryBackoff * 2^(attempts-1), capped at relayMaxBackoff.
+func relayBackoff(attempts int) time.Duration {
+	delay := relayRetryBackoff
+	for i := 1; i < attempts && delay < relayMaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, relayMaxBackoff)
+}
+
+// RunRelay relays pending events every interval until ctx is cancelled, starting
+// immediately so events committed before a crash are published after the restart.
+// Dispatched events older than retention are purged.
+func (o *Outbox) RunRelay(ctx context.Context, interval, retention time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := o.Relay(ctx); err != nil {
+			o.Logger.WarnContext(ctx, "Event relay failed", slog.String("error", err.Error()))
+		}
+		if retention > 0 {
+			if _, err := o.Repo.WithContext(ctx).PurgeDispatched(time.Now().Add(-retention)); err != nil {
+				o.Logger.WarnContext(ctx, "Could not purge dispatched events", slog.String("error", err.Error()))
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
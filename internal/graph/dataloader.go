@@ -0,0 +1,145 @@
+package graph
+
+import (
+	"context"
+	"sync"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/metrics"
+	"github.com/aebalz/daily-vibe-tracker/internal/model"
+	"github.com/aebalz/daily-vibe-tracker/internal/service"
+)
+
+// BatchFunc loads the values for a batch of keys. Keys without a value are left out of the map.
+type BatchFunc[K comparable, V any] func(keys []K) (map[K]V, error)
+
+// Loader batches and caches lookups for the duration of one request. Load only queues the
+// key and returns a thunk; the first thunk that is called loads every queued key at once.
+// The executor calls thunks after resolving all fields of a level, so sibling lookups
+// share a single query. Lookups are counted as cache hits or misses under the loader's name.
+type Loader[K comparable, V any] struct {
+	name  string
+	fetch BatchFunc[K, V]
+
+	mu      sync.Mutex
+	pending []K
+	cache   map[K]V
+	errs    map[K]error
+}
+
+// NewLoader creates a Loader backed by fetch. name is its cache in the cache_requests_total
+// metric, one of the metrics.Cache* names.
+func NewLoader[K comparable, V any](name string, fetch BatchFunc[K, V]) *Loader[K, V] {
+	return &Loader[K, V]{
+		name:  name,
+		fetch: fetch,
+		cache: make(map[K]V),
+		errs:  make(map[K]error),
+	}
+}
+
+// Load queues key and returns a thunk yielding its value. ok is false when there is no value.
+func (l *Loader[K, V]) Load(key K) func() (value V, ok bool, err error) {
+	l.mu.Lock()
+	_, cached := l.cache[key]
+	_, failed := l.errs[key]
+	if !cached && !failed {
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+	metrics.ObserveCache(l.name, cached)
+
+	return func() (V, bool, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if err, ok := l.errs[key]; ok {
+			var zero V
+			return zero, false, err
+		}
+		if _, ok := l.cache[key]; !ok && len(l.pending) > 0 {
+			l.dispatch()
+		}
+		if err, ok := l.errs[key]; ok {
+			var zero V
+			return zero, false, err
+		}
+		value, ok := l.cache[key]
+		return value, ok, nil
+	}
+}
+
+// dispatch loads the pending keys. Keys the batch returned nothing for stay uncached as
+// misses until they are requested again.
+func (l *Loader[K, V]) dispatch() {
+	keys := unique(l.pending)
+	l.pending = nil
+	values, err := l.fetch(keys)
+	for _, key := range keys {
+		if err != nil {
+			l.errs[key] = err
+			continue
+		}
+		if value, ok := values[key]; ok {
+			l.cache[key] = value
+		}
+	}
+}
+
+func unique[K comparable](keys []K) []K {
+	seen := make(map[K]bool, len(keys))
+	out := make([]K, 0, len(keys))
+	for _, key := range keys {
+		if !seen[key] {
+			seen[key] = true
+			out = append(out, key)
+		}
+	}
+	return out
+}
+
+// Loaders holds the per-request loaders.
+type Loaders struct {
+	Vibes *Loader[uint, *model.Vibe]   // Vibes by ID
+	Moods *Loader[string, *model.Mood] // Mood catalog entries by canonical name
+}
+
+// NewLoaders creates fresh loaders for one request.
+func NewLoaders(vibeSvc service.VibeServiceInterface, moodSvc service.MoodServiceInterface) *Loaders {
+	return &Loaders{
+		Vibes: NewLoader(metrics.CacheGraphQLVibes, func(ids []uint) (map[uint]*model.Vibe, error) {
+			vibes, err := vibeSvc.GetVibesByIDs(ids)
+			if err != nil {
+				return nil, err
+			}
+			byID := make(map[uint]*model.Vibe, len(vibes))
+			for i := range vibes {
+				byID[vibes[i].ID] = &vibes[i]
+			}
+			return byID, nil
+		}),
+		Moods: NewLoader(metrics.CacheGraphQLMoods, func(names []string) (map[string]*model.Mood, error) {
+			// The catalog is small, so one read of all of it serves any batch.
+			moods, err := moodSvc.GetAllMoods()
+			if err != nil {
+				return nil, err
+			}
+			byName := make(map[string]*model.Mood, len(moods))
+			for i := range moods {
+				byName[moods[i].Name] = &moods[i]
+			}
+			return byName, nil
+		}),
+	}
+}
+
+type loadersKey struct{}
+
+// WithLoaders returns a context carrying the loaders.
+func WithLoaders(ctx context.Context, loaders *Loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, loaders)
+}
+
+// loadersFrom returns the loaders of the request.
+func loadersFrom(ctx context.Context) *Loaders {
+	loaders, _ := ctx.Value(loadersKey{}).(*Loaders)
+	return loaders
+}
@@ -0,0 +1,117 @@
+// Package graph serves the GraphQL API. It resolves against the same services as the REST
+// handlers, batches lookups with per-request loaders, and rejects queries that are nested
+// too deeply or would fetch too much.
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/config"
+	"github.com/aebalz/daily-vibe-tracker/internal/service"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// ErrMutationNotAllowed is returned for mutations sent with GET.
+var ErrMutationNotAllowed = errors.New("mutations must be sent with POST")
+
+// Request is a GraphQL request as sent over HTTP.
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Server executes GraphQL requests.
+type Server struct {
+	Schema        graphql.Schema
+	VibeSvc       service.VibeServiceInterface
+	MoodSvc       service.MoodServiceInterface
+	MaxDepth      int // Deepest allowed field nesting
+	MaxComplexity int // Highest allowed query cost, see checkLimits
+}
+
+// NewServer builds the schema and creates a Server. recommendationSvc may be nil.
+func NewServer(vibeSvc service.VibeServiceInterface, moodSvc service.MoodServiceInterface, recommendationSvc service.RecommendationServiceInterface, cfg *config.AppConfig) (*Server, error) {
+	schema, err := NewSchema(&Resolver{
+		VibeSvc:           vibeSvc,
+		MoodSvc:           moodSvc,
+		RecommendationSvc: recommendationSvc,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not build GraphQL schema: %w", err)
+	}
+	return &Server{
+		Schema:        schema,
+		VibeSvc:       vibeSvc,
+		MoodSvc:       moodSvc,
+		MaxDepth:      cfg.GraphQLMaxDepth,
+		MaxComplexity: cfg.GraphQLMaxComplexity,
+	}, nil
+}
+
+// Execute parses, validates, checks the limits of and runs a request. With readOnly set,
+// mutations are rejected. Errors are reported in the result, as GraphQL expects.
+func (s *Server) Execute(ctx context.Context, req Request, readOnly bool) *graphql.Result {
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{
+		Body: []byte(req.Query),
+		Name: "GraphQL request",
+	})})
+	if err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+	if validation := graphql.ValidateDocument(&s.Schema, doc, nil); !validation.IsValid {
+		return &graphql.Result{Errors: validation.Errors}
+	}
+
+	op, err := operation(doc, req.OperationName)
+	if err != nil {
+		return errorResult(err)
+	}
+	if readOnly && op.Operation == ast.OperationTypeMutation {
+		return errorResult(ErrMutationNotAllowed)
+	}
+	if err := checkLimits(doc, op, req.Variables, s.MaxDepth, s.MaxComplexity); err != nil {
+		return errorResult(err)
+	}
+
+	return graphql.Execute(graphql.ExecuteParams{
+		Schema:        s.Schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       WithLoaders(ctx, NewLoaders(s.VibeSvc.WithContext(ctx), s.MoodSvc)),
+	})
+}
+
+// operation returns the operation the request asks for.
+func operation(doc *ast.Document, name string) (*ast.OperationDefinition, error) {
+	var found *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if name == "" {
+			if found != nil {
+				return nil, errors.New("operationName is required when the document has several operations")
+			}
+			found = op
+		} else if op.Name != nil && op.Name.Value == name {
+			return op, nil
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("unknown operation '%s'", name)
+	}
+	return found, nil
+}
+
+func errorResult(err error) *graphql.Result {
+	return &graphql.Result{Errors: []gqlerrors.FormattedError{gqlerrors.FormatError(err)}}
+}
@@ -0,0 +1,113 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/service"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// ErrQueryTooComplex is returned for queries over the depth or complexity limit.
+var ErrQueryTooComplex = errors.New("query is too complex")
+
+// defaultPageSizes is the number of items a list field returns when the query does not set
+// first or limit. It multiplies the cost of the field's selections.
+var defaultPageSizes = map[string]int{
+	"vibes":           service.DefaultLimit,
+	"recommendations": service.DefaultRecommendationLimit,
+}
+
+// queryCost measures the selected operation of a validated document.
+type queryCost struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+}
+
+// checkLimits rejects operations nested deeper than maxDepth or costing more than
+// maxComplexity. Every field costs 1 plus the cost of its selections, multiplied by the page
+// size for list fields. Introspection fields are not counted.
+func checkLimits(doc *ast.Document, op *ast.OperationDefinition, variables map[string]interface{}, maxDepth, maxComplexity int) error {
+	qc := queryCost{fragments: make(map[string]*ast.FragmentDefinition), variables: variables}
+	for _, def := range doc.Definitions {
+		if fragment, ok := def.(*ast.FragmentDefinition); ok {
+			qc.fragments[fragment.Name.Value] = fragment
+		}
+	}
+	depth, complexity := qc.selectionSet(op.SelectionSet, 0)
+	if maxDepth > 0 && depth > maxDepth {
+		return fmt.Errorf("%w: depth %d exceeds the limit of %d", ErrQueryTooComplex, depth, maxDepth)
+	}
+	if maxComplexity > 0 && complexity > maxComplexity {
+		return fmt.Errorf("%w: complexity %d exceeds the limit of %d", ErrQueryTooComplex, complexity, maxComplexity)
+	}
+	return nil
+}
+
+// selectionSet returns the depth and the cost of a selection set at the given depth.
+// Validation has already rejected fragment cycles.
+func (qc queryCost) selectionSet(set *ast.SelectionSet, depth int) (int, int) {
+	if set == nil {
+		return depth, 0
+	}
+	maxDepth, cost := depth, 0
+	for _, selection := range set.Selections {
+		var d, c int
+		switch sel := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(sel.Name.Value, "__") {
+				continue
+			}
+			d, c = qc.selectionSet(sel.SelectionSet, depth+1)
+			c = 1 + c*qc.multiplier(sel)
+		case *ast.InlineFragment:
+			d, c = qc.selectionSet(sel.SelectionSet, depth)
+		case *ast.FragmentSpread:
+			fragment, ok := qc.fragments[sel.Name.Value]
+			if !ok {
+				continue
+			}
+			d, c = qc.selectionSet(fragment.SelectionSet, depth)
+		}
+		if d > maxDepth {
+			maxDepth = d
+		}
+		cost += c
+	}
+	return maxDepth, cost
+}
+
+// multiplier returns how many items a field returns at most: its first or limit argument, or
+// the default page size of list fields.
+func (qc queryCost) multiplier(field *ast.Field) int {
+	for _, arg := range field.Arguments {
+		if arg.Name.Value != "first" && arg.Name.Value != "limit" {
+			continue
+		}
+		if n, ok := qc.intValue(arg.Value); ok && n > 0 {
+			return n
+		}
+	}
+	if n, ok := defaultPageSizes[field.Name.Value]; ok {
+		return n
+	}
+	return 1
+}
+
+func (qc queryCost) intValue(value ast.Value) (int, bool) {
+	switch v := value.(type) {
+	case *ast.IntValue:
+		n, err := strconv.Atoi(v.Value)
+		return n, err == nil
+	case *ast.Variable:
+		switch n := qc.variables[v.Name.Value].(type) {
+		case int:
+			return n, true
+		case float64: // JSON numbers
+			return int(n), true
+		}
+	}
+	return 0, false
+}
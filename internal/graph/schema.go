@@ -0,0 +1,806 @@
+package graph
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/model"
+	"github.com/aebalz/daily-vibe-tracker/internal/service"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"gorm.io/gorm"
+)
+
+// dateLayout is the format of the Date scalar.
+const dateLayout = "2006-01-02"
+
+// cursorPrefix marks connection cursors, which encode the offset of an item.
+const cursorPrefix = "offset:"
+
+// Resolver holds the services the schema resolves against.
+type Resolver struct {
+	VibeSvc           service.VibeServiceInterface
+	MoodSvc           service.MoodServiceInterface
+	RecommendationSvc service.RecommendationServiceInterface // Optional; recommendation fields are left out without it
+}
+
+// dateScalar is a calendar day in YYYY-MM-DD form.
+var dateScalar = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "Date",
+	Description: "A calendar day in YYYY-MM-DD form.",
+	Serialize: func(value interface{}) interface{} {
+		switch v := value.(type) {
+		case time.Time:
+			return v.Format(dateLayout)
+		case *time.Time:
+			if v == nil {
+				return nil
+			}
+			return v.Format(dateLayout)
+		}
+		return nil
+	},
+	ParseValue: func(value interface{}) interface{} {
+		s, ok := value.(string)
+		if !ok {
+			return nil
+		}
+		t, err := time.Parse(dateLayout, s)
+		if err != nil {
+			return nil
+		}
+		return t
+	},
+	ParseLiteral: func(valueAST ast.Value) interface{} {
+		s, ok := valueAST.(*ast.StringValue)
+		if !ok {
+			return nil
+		}
+		t, err := time.Parse(dateLayout, s.Value)
+		if err != nil {
+			return nil
+		}
+		return t
+	},
+})
+
+// jsonScalar carries free-form values such as custom metrics and analytics.
+var jsonScalar = graphql.NewScalar(graphql.ScalarConfig{
+	Name:         "JSON",
+	Description:  "Any JSON value.",
+	Serialize:    func(value interface{}) interface{} { return value },
+	ParseValue:   func(value interface{}) interface{} { return value },
+	ParseLiteral: parseJSONLiteral,
+})
+
+func parseJSONLiteral(valueAST ast.Value) interface{} {
+	switch v := valueAST.(type) {
+	case *ast.StringValue:
+		return v.Value
+	case *ast.BooleanValue:
+		return v.Value
+	case *ast.IntValue:
+		n, _ := strconv.ParseFloat(v.Value, 64)
+		return n
+	case *ast.FloatValue:
+		n, _ := strconv.ParseFloat(v.Value, 64)
+		return n
+	case *ast.ListValue:
+		list := make([]interface{}, len(v.Values))
+		for i, item := range v.Values {
+			list[i] = parseJSONLiteral(item)
+		}
+		return list
+	case *ast.ObjectValue:
+		obj := make(map[string]interface{}, len(v.Fields))
+		for _, field := range v.Fields {
+			obj[field.Name.Value] = parseJSONLiteral(field.Value)
+		}
+		return obj
+	}
+	return nil
+}
+
+// statistics is the typed form of VibeServiceInterface.GetVibeStatistics.
+type statistics struct {
+	Period                  string                  `json:"-"`
+	AverageEnergyLevel      float64                 `json:"average_energy_level"`
+	MoodDistribution        []moodCount             `json:"mood_distribution"`
+	MetricAggregates        []model.MetricAggregate `json:"metric_aggregates"`
+	MoodPatterns            interface{}             `json:"mood_patterns"`
+	MoodEnergyCorrelation   interface{}             `json:"mood_energy_correlation"`
+	ActivityMoodCorrelation interface{}             `json:"activity_mood_correlation"`
+}
+
+type moodCount struct {
+	Mood  string
+	Count int
+}
+
+// streak is the typed form of VibeServiceInterface.GetMoodStreak.
+type streak struct {
+	Mood    string
+	Current int
+	Longest int
+}
+
+// today is the source of the today field.
+type today struct {
+	Date time.Time
+}
+
+// vibeEdge is one item of a vibe connection.
+type vibeEdge struct {
+	Cursor string
+	Node   *model.Vibe
+}
+
+type pageInfo struct {
+	HasNextPage     bool
+	HasPreviousPage bool
+	StartCursor     *string
+	EndCursor       *string
+}
+
+type vibeConnection struct {
+	Edges      []vibeEdge
+	Nodes      []*model.Vibe
+	PageInfo   pageInfo
+	TotalCount int64
+}
+
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(offset)))
+}
+
+func decodeCursor(cursor string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid cursor", service.ErrValidation)
+	}
+	offset, err := strconv.Atoi(strings.TrimPrefix(string(raw), cursorPrefix))
+	if err != nil || !strings.HasPrefix(string(raw), cursorPrefix) || offset < 0 {
+		return 0, fmt.Errorf("%w: invalid cursor", service.ErrValidation)
+	}
+	return offset, nil
+}
+
+// parseID converts an ID argument to a positive record ID.
+func parseID(value interface{}) (uint, error) {
+	s, _ := value.(string)
+	id, err := strconv.Atoi(s)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("%w: invalid ID '%v'", service.ErrValidation, value)
+	}
+	return uint(id), nil
+}
+
+// NewSchema builds the GraphQL schema.
+func NewSchema(r *Resolver) (graphql.Schema, error) {
+	moodType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Mood",
+		Description: "An entry of the mood catalog.",
+		Fields: graphql.Fields{
+			"id":      &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+			"name":    &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"aliases": &graphql.Field{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+			"valence": &graphql.Field{Type: graphql.NewNonNull(graphql.Float), Description: "-1 (very negative) to 1 (very positive)."},
+			"arousal": &graphql.Field{Type: graphql.NewNonNull(graphql.Float), Description: "0 (calm) to 1 (activated)."},
+			"emoji":   &graphql.Field{Type: graphql.String},
+			"color":   &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	vibeType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Vibe",
+		Description: "A daily vibe entry.",
+		Fields: graphql.Fields{
+			"id":   &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+			"date": &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+			"mood": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"moodDetails": &graphql.Field{
+				Type:        moodType,
+				Description: "The catalog entry of the mood.",
+				Resolve:     r.resolveMoodDetails,
+			},
+			"energyLevel": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"notes":       &graphql.Field{Type: graphql.String},
+			"activities":  &graphql.Field{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+			"metrics":     &graphql.Field{Type: jsonScalar, Description: "Custom metric values keyed by metric name."},
+			"createdAt":   &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+			"updatedAt":   &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+		},
+	})
+
+	pageInfoType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "PageInfo",
+		Fields: graphql.Fields{
+			"hasNextPage":     &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+			"hasPreviousPage": &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+			"startCursor":     &graphql.Field{Type: graphql.String},
+			"endCursor":       &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	vibeEdgeType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "VibeEdge",
+		Fields: graphql.Fields{
+			"cursor": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"node":   &graphql.Field{Type: graphql.NewNonNull(vibeType)},
+		},
+	})
+
+	vibeConnectionType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "VibeConnection",
+		Fields: graphql.Fields{
+			"edges":      &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(vibeEdgeType)))},
+			"nodes":      &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(vibeType)))},
+			"pageInfo":   &graphql.Field{Type: graphql.NewNonNull(pageInfoType)},
+			"totalCount": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		},
+	})
+
+	sortOrderEnum := graphql.NewEnum(graphql.EnumConfig{
+		Name: "SortOrder",
+		Values: graphql.EnumValueConfigMap{
+			"ASC":  &graphql.EnumValueConfig{Value: "asc"},
+			"DESC": &graphql.EnumValueConfig{Value: "desc"},
+		},
+	})
+
+	periodEnum := graphql.NewEnum(graphql.EnumConfig{
+		Name:        "Period",
+		Description: "The current week, month or year.",
+		Values: graphql.EnumValueConfigMap{
+			"WEEK":  &graphql.EnumValueConfig{Value: "week"},
+			"MONTH": &graphql.EnumValueConfig{Value: "month"},
+			"YEAR":  &graphql.EnumValueConfig{Value: "year"},
+		},
+	})
+
+	vibeFilterInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "VibeFilter",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"date": &graphql.InputObjectFieldConfig{Type: dateScalar},
+			"mood": &graphql.InputObjectFieldConfig{Type: graphql.String, Description: "Mood name or alias."},
+			"metrics": &graphql.InputObjectFieldConfig{
+				Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+				Description: "Custom metric filters as name:op:value, e.g. sleep_hours:gte:7.",
+			},
+		},
+	})
+
+	moodCountType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "MoodCount",
+		Fields: graphql.Fields{
+			"mood":  &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"count": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		},
+	})
+
+	metricAggregateType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "MetricAggregate",
+		Fields: graphql.Fields{
+			"name":    &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"type":    &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"unit":    &graphql.Field{Type: graphql.String},
+			"count":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"average": &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"min":     &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"max":     &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+		},
+	})
+
+	statisticsType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Statistics",
+		Fields: graphql.Fields{
+			"period":                  &graphql.Field{Type: graphql.NewNonNull(periodEnum)},
+			"averageEnergyLevel":      &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"moodDistribution":        &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(moodCountType)))},
+			"metricAggregates":        &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(metricAggregateType)))},
+			"moodPatterns":            &graphql.Field{Type: jsonScalar, Description: "Mood transition matrix over consecutive days."},
+			"moodEnergyCorrelation":   &graphql.Field{Type: jsonScalar},
+			"activityMoodCorrelation": &graphql.Field{Type: jsonScalar},
+		},
+	})
+
+	streakType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Streak",
+		Fields: graphql.Fields{
+			"mood":    &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"current": &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Description: "Consecutive days up to today."},
+			"longest": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		},
+	})
+
+	todayFields := graphql.Fields{
+		"date": &graphql.Field{Type: graphql.NewNonNull(dateScalar)},
+		"vibe": &graphql.Field{
+			Type:        vibeType,
+			Description: "Today's vibe, if logged.",
+			Resolve:     r.resolveTodayVibe,
+		},
+	}
+
+	queryFields := graphql.Fields{
+		"vibe": &graphql.Field{
+			Type: vibeType,
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+			},
+			Resolve: r.resolveVibe,
+		},
+		"vibes": &graphql.Field{
+			Type:        graphql.NewNonNull(vibeConnectionType),
+			Description: "Vibes, newest first by default, with cursor pagination.",
+			Args: graphql.FieldConfigArgument{
+				"filter":    &graphql.ArgumentConfig{Type: vibeFilterInput},
+				"first":     &graphql.ArgumentConfig{Type: graphql.Int, Description: fmt.Sprintf("Page size, 1 to %d. Defaults to %d.", service.MaxLimit, service.DefaultLimit)},
+				"after":     &graphql.ArgumentConfig{Type: graphql.String, Description: "Cursor of the item to start after."},
+				"sortBy":    &graphql.ArgumentConfig{Type: graphql.String, Description: "date, mood, energy_level, created_at, updated_at or metric:<name>."},
+				"sortOrder": &graphql.ArgumentConfig{Type: sortOrderEnum},
+			},
+			Resolve: r.resolveVibes,
+		},
+		"statistics": &graphql.Field{
+			Type: graphql.NewNonNull(statisticsType),
+			Args: graphql.FieldConfigArgument{
+				"period": &graphql.ArgumentConfig{Type: periodEnum, DefaultValue: "month"},
+			},
+			Resolve: r.resolveStatistics,
+		},
+		"streak": &graphql.Field{
+			Type: graphql.NewNonNull(streakType),
+			Args: graphql.FieldConfigArgument{
+				"mood": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+			},
+			Resolve: r.resolveStreak,
+		},
+		"moods": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(moodType))),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return r.MoodSvc.GetAllMoods()
+			},
+		},
+	}
+
+	vibeInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "VibeInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"date":        &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.DateTime)},
+			"mood":        &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"energyLevel": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Int)},
+			"notes":       &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"activities":  &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+			"metrics":     &graphql.InputObjectFieldConfig{Type: jsonScalar},
+		},
+	})
+
+	vibePatchInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        "VibePatch",
+		Description: "Fields to change on a vibe; omitted fields keep their value.",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"date":        &graphql.InputObjectFieldConfig{Type: graphql.DateTime},
+			"mood":        &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"energyLevel": &graphql.InputObjectFieldConfig{Type: graphql.Int},
+			"notes":       &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"activities":  &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+			"metrics":     &graphql.InputObjectFieldConfig{Type: jsonScalar, Description: "Replaces all custom metric values."},
+		},
+	})
+
+	mutationFields := graphql.Fields{
+		"createVibe": &graphql.Field{
+			Type: graphql.NewNonNull(vibeType),
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(vibeInput)},
+			},
+			Resolve: r.resolveCreateVibe,
+		},
+		"updateVibe": &graphql.Field{
+			Type: graphql.NewNonNull(vibeType),
+			Args: graphql.FieldConfigArgument{
+				"id":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				"patch": &graphql.ArgumentConfig{Type: graphql.NewNonNull(vibePatchInput)},
+			},
+			Resolve: r.resolveUpdateVibe,
+		},
+		"deleteVibe": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.ID),
+			Description: "Deletes a vibe and returns its ID.",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+			},
+			Resolve: r.resolveDeleteVibe,
+		},
+	}
+
+	if r.RecommendationSvc != nil {
+		r.addRecommendationFields(vibeType, todayFields, queryFields, mutationFields)
+	}
+
+	todayType := graphql.NewObject(graphql.ObjectConfig{
+		Name:   "Today",
+		Fields: todayFields,
+	})
+	queryFields["today"] = &graphql.Field{
+		Type: graphql.NewNonNull(todayType),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return &today{Date: time.Now()}, nil
+		},
+	}
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query:    graphql.NewObject(graphql.ObjectConfig{Name: "Query", Fields: queryFields}),
+		Mutation: graphql.NewObject(graphql.ObjectConfig{Name: "Mutation", Fields: mutationFields}),
+	})
+}
+
+// addRecommendationFields adds the recommendation types and their query and mutation fields.
+func (r *Resolver) addRecommendationFields(vibeType *graphql.Object, todayFields, queryFields, mutationFields graphql.Fields) {
+	suggestionType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "ActivitySuggestion",
+		Description: "A scored activity recommendation.",
+		Fields: graphql.Fields{
+			"id":           &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Description: "ID of the served recommendation, used for feedback."},
+			"activity":     &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"score":        &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"lift":         &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"energyDelta":  &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"dayOfWeekFit": &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"feedback":     &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"occurrences":  &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"lastDone":     &graphql.Field{Type: graphql.DateTime},
+			"reasons":      &graphql.Field{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+		},
+	})
+
+	resultType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "RecommendationResult",
+		Fields: graphql.Fields{
+			"date":        &graphql.Field{Type: graphql.NewNonNull(dateScalar)},
+			"seed":        &graphql.Field{Type: graphql.NewNonNull(graphql.String), Description: "Pass back to reproduce the same ranking."},
+			"suggestions": &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(suggestionType)))},
+			"suggestion":  &graphql.Field{Type: graphql.String},
+			"reason":      &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	recommendationType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Recommendation",
+		Description: "A recommendation that was served for a day.",
+		Fields: graphql.Fields{
+			"id":         &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+			"date":       &graphql.Field{Type: graphql.NewNonNull(dateScalar)},
+			"activity":   &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"rank":       &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"score":      &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"feedback":   &graphql.Field{Type: graphql.String},
+			"feedbackAt": &graphql.Field{Type: graphql.DateTime},
+			"linkedVibe": &graphql.Field{
+				Type:        vibeType,
+				Description: "The later vibe that included the activity.",
+				Resolve:     r.resolveLinkedVibe,
+			},
+			"linkedAt":  &graphql.Field{Type: graphql.DateTime},
+			"createdAt": &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+		},
+	})
+
+	feedbackEnum := graphql.NewEnum(graphql.EnumConfig{
+		Name: "RecommendationFeedback",
+		Values: graphql.EnumValueConfigMap{
+			"ACCEPTED":  &graphql.EnumValueConfig{Value: model.FeedbackAccepted},
+			"DISMISSED": &graphql.EnumValueConfig{Value: model.FeedbackDismissed},
+			"DID_IT":    &graphql.EnumValueConfig{Value: model.FeedbackDidIt},
+		},
+	})
+
+	recommendationArgs := graphql.FieldConfigArgument{
+		"limit": &graphql.ArgumentConfig{Type: graphql.Int, Description: fmt.Sprintf("Number of suggestions, 1 to %d.", service.MaxRecommendationLimit)},
+		"seed":  &graphql.ArgumentConfig{Type: graphql.String, Description: "Seed for tie-breaking, to reproduce a ranking."},
+	}
+	todayFields["recommendations"] = &graphql.Field{
+		Type:    graphql.NewNonNull(resultType),
+		Args:    recommendationArgs,
+		Resolve: r.resolveRecommendations,
+	}
+	queryFields["recommendations"] = &graphql.Field{
+		Type: graphql.NewNonNull(resultType),
+		Args: graphql.FieldConfigArgument{
+			"date":  &graphql.ArgumentConfig{Type: dateScalar, Description: "Day to recommend for. Defaults to today."},
+			"limit": recommendationArgs["limit"],
+			"seed":  recommendationArgs["seed"],
+		},
+		Resolve: r.resolveRecommendations,
+	}
+	queryFields["recommendation"] = &graphql.Field{
+		Type: recommendationType,
+		Args: graphql.FieldConfigArgument{
+			"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			id, err := parseID(p.Args["id"])
+			if err != nil {
+				return nil, err
+			}
+			rec, err := r.RecommendationSvc.GetRecommendation(id)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, nil
+			}
+			return rec, err
+		},
+	}
+	mutationFields["submitRecommendationFeedback"] = &graphql.Field{
+		Type: graphql.NewNonNull(recommendationType),
+		Args: graphql.FieldConfigArgument{
+			"id":       &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+			"feedback": &graphql.ArgumentConfig{Type: graphql.NewNonNull(feedbackEnum)},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			id, err := parseID(p.Args["id"])
+			if err != nil {
+				return nil, err
+			}
+			feedback, _ := p.Args["feedback"].(string)
+			return notFound(r.RecommendationSvc.SubmitFeedback(id, feedback))
+		},
+	}
+}
+
+// notFound replaces gorm.ErrRecordNotFound with a message naming the missing record.
+func notFound[T any](value T, err error) (interface{}, error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New("record not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// loadVibe resolves a vibe through the request's loader, so sibling lookups are batched.
+func loadVibe(p graphql.ResolveParams, id uint) (interface{}, error) {
+	thunk := loadersFrom(p.Context).Vibes.Load(id)
+	return func() (interface{}, error) {
+		vibe, ok, err := thunk()
+		if err != nil || !ok {
+			return nil, err
+		}
+		return vibe, nil
+	}, nil
+}
+
+func (r *Resolver) resolveVibe(p graphql.ResolveParams) (interface{}, error) {
+	id, err := parseID(p.Args["id"])
+	if err != nil {
+		return nil, err
+	}
+	return loadVibe(p, id)
+}
+
+func (r *Resolver) resolveLinkedVibe(p graphql.ResolveParams) (interface{}, error) {
+	rec, ok := p.Source.(*model.Recommendation)
+	if !ok || rec.LinkedVibeID == nil {
+		return nil, nil
+	}
+	return loadVibe(p, *rec.LinkedVibeID)
+}
+
+func (r *Resolver) resolveMoodDetails(p graphql.ResolveParams) (interface{}, error) {
+	vibe, ok := p.Source.(*model.Vibe)
+	if !ok {
+		return nil, nil
+	}
+	thunk := loadersFrom(p.Context).Moods.Load(vibe.Mood)
+	return func() (interface{}, error) {
+		mood, ok, err := thunk()
+		if err != nil || !ok {
+			return nil, err
+		}
+		return mood, nil
+	}, nil
+}
+
+func (r *Resolver) resolveVibes(p graphql.ResolveParams) (interface{}, error) {
+	filters := make(map[string]interface{})
+	if filter, ok := p.Args["filter"].(map[string]interface{}); ok {
+		if date, ok := filter["date"].(time.Time); ok {
+			filters["date"] = date.Format(dateLayout)
+		}
+		if mood, ok := filter["mood"].(string); ok && mood != "" {
+			filters["mood"] = mood
+		}
+		if metrics, ok := filter["metrics"].([]interface{}); ok && len(metrics) > 0 {
+			raw := make([]string, 0, len(metrics))
+			for _, m := range metrics {
+				raw = append(raw, fmt.Sprint(m))
+			}
+			filters["metric_filters"] = raw
+		}
+	}
+
+	first := service.DefaultLimit
+	if n, ok := p.Args["first"].(int); ok {
+		if n < 1 || n > service.MaxLimit {
+			return nil, fmt.Errorf("%w: first must be between 1 and %d", service.ErrValidation, service.MaxLimit)
+		}
+		first = n
+	}
+	offset := 0
+	if after, ok := p.Args["after"].(string); ok && after != "" {
+		afterOffset, err := decodeCursor(after)
+		if err != nil {
+			return nil, err
+		}
+		offset = afterOffset + 1
+	}
+	sortBy, _ := p.Args["sortBy"].(string)
+	sortOrder, _ := p.Args["sortOrder"].(string)
+
+	vibes, total, err := r.VibeSvc.WithContext(p.Context).GetAllVibes(filters, first, offset, sortBy, sortOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	conn := &vibeConnection{
+		Edges:      make([]vibeEdge, len(vibes)),
+		Nodes:      make([]*model.Vibe, len(vibes)),
+		TotalCount: total,
+		PageInfo: pageInfo{
+			HasPreviousPage: offset > 0,
+			HasNextPage:     int64(offset+len(vibes)) < total,
+		},
+	}
+	for i := range vibes {
+		conn.Nodes[i] = &vibes[i]
+		conn.Edges[i] = vibeEdge{Cursor: encodeCursor(offset + i), Node: &vibes[i]}
+	}
+	if len(vibes) > 0 {
+		conn.PageInfo.StartCursor = &conn.Edges[0].Cursor
+		conn.PageInfo.EndCursor = &conn.Edges[len(vibes)-1].Cursor
+	}
+	return conn, nil
+}
+
+func (r *Resolver) resolveTodayVibe(p graphql.ResolveParams) (interface{}, error) {
+	t, ok := p.Source.(*today)
+	if !ok {
+		return nil, nil
+	}
+	vibes, _, err := r.VibeSvc.WithContext(p.Context).GetAllVibes(map[string]interface{}{"date": t.Date.Format(dateLayout)}, 1, 0, "", "")
+	if err != nil || len(vibes) == 0 {
+		return nil, err
+	}
+	return &vibes[0], nil
+}
+
+func (r *Resolver) resolveStatistics(p graphql.ResolveParams) (interface{}, error) {
+	period, _ := p.Args["period"].(string)
+	raw, err := r.VibeSvc.WithContext(p.Context).GetVibeStatistics(period)
+	if err != nil {
+		return nil, err
+	}
+	// The service returns a loosely typed map; its JSON form is the REST contract.
+	encoded, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	stats := &statistics{Period: period}
+	if err := json.Unmarshal(encoded, stats); err != nil {
+		return nil, fmt.Errorf("could not read statistics: %w", err)
+	}
+	if stats.MoodDistribution == nil {
+		stats.MoodDistribution = []moodCount{}
+	}
+	if stats.MetricAggregates == nil {
+		stats.MetricAggregates = []model.MetricAggregate{}
+	}
+	return stats, nil
+}
+
+func (r *Resolver) resolveStreak(p graphql.ResolveParams) (interface{}, error) {
+	mood, _ := p.Args["mood"].(string)
+	raw, err := r.VibeSvc.WithContext(p.Context).GetMoodStreak(mood)
+	if err != nil {
+		return nil, err
+	}
+	s := &streak{}
+	s.Mood, _ = raw["mood"].(string)
+	s.Current, _ = raw["current_streak"].(int)
+	s.Longest, _ = raw["longest_streak"].(int)
+	return s, nil
+}
+
+func (r *Resolver) resolveRecommendations(p graphql.ResolveParams) (interface{}, error) {
+	var opts service.RecommendationOptions
+	if date, ok := p.Args["date"].(time.Time); ok {
+		opts.Date = date
+	}
+	if t, ok := p.Source.(*today); ok {
+		opts.Date = t.Date
+	}
+	if limit, ok := p.Args["limit"].(int); ok {
+		if limit < 1 {
+			return nil, fmt.Errorf("%w: limit must be between 1 and %d", service.ErrValidation, service.MaxRecommendationLimit)
+		}
+		opts.Limit = limit
+	}
+	if seed, ok := p.Args["seed"].(string); ok && seed != "" {
+		parsed, err := strconv.ParseInt(seed, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: seed must be an integer", service.ErrValidation)
+		}
+		opts.Seed = parsed
+	}
+	return r.RecommendationSvc.RecommendActivities(opts)
+}
+
+// vibeFromInput builds a vibe from a VibeInput or applies a VibePatch to vibe.
+func vibeFromInput(input map[string]interface{}, vibe *model.Vibe) {
+	if date, ok := input["date"].(time.Time); ok {
+		vibe.Date = date
+	}
+	if mood, ok := input["mood"].(string); ok {
+		vibe.Mood = mood
+	}
+	if energy, ok := input["energyLevel"].(int); ok {
+		vibe.EnergyLevel = energy
+	}
+	if notes, ok := input["notes"].(string); ok {
+		vibe.Notes = notes
+	}
+	if activities, ok := input["activities"].([]interface{}); ok {
+		vibe.Activities = make([]string, 0, len(activities))
+		for _, a := range activities {
+			vibe.Activities = append(vibe.Activities, fmt.Sprint(a))
+		}
+	}
+	if metrics, ok := input["metrics"].(map[string]interface{}); ok {
+		vibe.Metrics = metrics
+	}
+}
+
+func (r *Resolver) resolveCreateVibe(p graphql.ResolveParams) (interface{}, error) {
+	input, _ := p.Args["input"].(map[string]interface{})
+	vibe := &model.Vibe{}
+	vibeFromInput(input, vibe)
+	return r.VibeSvc.WithContext(p.Context).CreateVibe(vibe)
+}
+
+func (r *Resolver) resolveUpdateVibe(p graphql.ResolveParams) (interface{}, error) {
+	id, err := parseID(p.Args["id"])
+	if err != nil {
+		return nil, err
+	}
+	existing, err := r.VibeSvc.WithContext(p.Context).GetVibeByID(id)
+	if err != nil {
+		return notFound(existing, err)
+	}
+	patch, _ := p.Args["patch"].(map[string]interface{})
+	updated := &model.Vibe{
+		Date:        existing.Date,
+		Mood:        existing.Mood,
+		EnergyLevel: existing.EnergyLevel,
+		Notes:       existing.Notes,
+		Activities:  existing.Activities,
+	}
+	vibeFromInput(patch, updated) // Metrics stay nil unless patched, which keeps the stored values
+	return notFound(r.VibeSvc.WithContext(p.Context).UpdateVibe(id, updated))
+}
+
+func (r *Resolver) resolveDeleteVibe(p graphql.ResolveParams) (interface{}, error) {
+	id, err := parseID(p.Args["id"])
+	if err != nil {
+		return nil, err
+	}
+	if err := r.VibeSvc.WithContext(p.Context).DeleteVibe(id); err != nil {
+		return notFound(id, err)
+	}
+	return id, nil
+}
@@ -0,0 +1,372 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/model"
+	"github.com/aebalz/daily-vibe-tracker/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// ActivityHandler handles activity catalog requests.
+type ActivityHandler struct {
+	Service service.ActivityServiceInterface
+}
+
+// NewActivityHandler creates a new ActivityHandler.
+func NewActivityHandler(svc service.ActivityServiceInterface) *ActivityHandler {
+	return &ActivityHandler{Service: svc}
+}
+
+// ActivityRequest defines the expected body for creating or updating an activity.
+type ActivityRequest struct {
+	Name     string   `json:"name" binding:"required"`
+	Aliases  []string `json:"aliases"`
+	ParentID *uint    `json:"parent_id"`
+}
+
+func (r ActivityRequest) toModel() model.Activity {
+	return model.Activity{
+		Name:     r.Name,
+		Aliases:  r.Aliases,
+		ParentID: r.ParentID,
+	}
+}
+
+// MergeActivityRequest defines the expected body for merging an activity into another.
+type MergeActivityRequest struct {
+	TargetID uint `json:"target_id" binding:"required"`
+}
+
+// --- Fiber Handlers ---
+
+// GetAllActivitiesFiber godoc
+// @Summary List activities with usage stats
+// @Description Lists the activity catalog with usage counts, last-used date and average mood/energy.
+// @Tags activities
+// @Produce json
+// @Success 200 {array} model.ActivityWithUsage "Activities with usage"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/activities [get]
+func (ah *ActivityHandler) GetAllActivitiesFiber(c *fiber.Ctx) error {
+	activities, err := ah.Service.GetAllActivitiesWithUsage()
+	if err != nil {
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to retrieve activities", err)
+	}
+	return c.JSON(activities)
+}
+
+// CreateActivityFiber godoc
+// @Summary Add an activity to the catalog
+// @Description Adds a new activity, optionally under a parent category.
+// @Tags activities
+// @Accept json
+// @Produce json
+// @Param activity body ActivityRequest true "Activity to add"
+// @Success 201 {object} model.Activity "Created activity"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Router /api/v1/activities [post]
+func (ah *ActivityHandler) CreateActivityFiber(c *fiber.Ctx) error {
+	var req ActivityRequest
+	if err := bindJSONFiber(c, &req); err != nil {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid request body", err)
+	}
+	activity := req.toModel()
+	created, err := ah.Service.CreateActivity(&activity)
+	if err != nil {
+		return handleError("fiber", c, http.StatusBadRequest, "Failed to create activity", err)
+	}
+	return c.Status(http.StatusCreated).JSON(created)
+}
+
+// GetActivityByIDFiber godoc
+// @Summary Get an activity
+// @Description Retrieves a single activity from the catalog by its ID.
+// @Tags activities
+// @Produce json
+// @Param id path int true "Activity ID"
+// @Success 200 {object} model.Activity "Activity details"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 404 {object} map[string]string "Activity not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/activities/{id} [get]
+func (ah *ActivityHandler) GetActivityByIDFiber(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil || id <= 0 {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid activity ID", err)
+	}
+	activity, err := ah.Service.GetActivityByID(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return handleError("fiber", c, http.StatusNotFound, "Activity not found", nil)
+		}
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to retrieve activity", err)
+	}
+	return c.JSON(activity)
+}
+
+// UpdateActivityFiber godoc
+// @Summary Update or rename an activity
+// @Description Modifies an activity. Renaming rewrites the old name in all existing vibes.
+// @Tags activities
+// @Accept json
+// @Produce json
+// @Param id path int true "Activity ID"
+// @Param activity body ActivityRequest true "Updated activity data"
+// @Success 200 {object} model.Activity "Updated activity"
+// @Failure 400 {object} map[string]string "Invalid input or ID format"
+// @Failure 404 {object} map[string]string "Activity not found"
+// @Router /api/v1/activities/{id} [put]
+func (ah *ActivityHandler) UpdateActivityFiber(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil || id <= 0 {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid activity ID", err)
+	}
+	var req ActivityRequest
+	if err := bindJSONFiber(c, &req); err != nil {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid request body", err)
+	}
+	activity := req.toModel()
+	updated, err := ah.Service.UpdateActivity(uint(id), &activity)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return handleError("fiber", c, http.StatusNotFound, "Activity not found to update", nil)
+		}
+		return handleError("fiber", c, http.StatusBadRequest, "Failed to update activity", err)
+	}
+	return c.JSON(updated)
+}
+
+// DeleteActivityFiber godoc
+// @Summary Delete an activity
+// @Description Removes an activity from the catalog. Child activities become top-level.
+// @Tags activities
+// @Produce json
+// @Param id path int true "Activity ID"
+// @Success 200 {object} map[string]string "Success message"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 404 {object} map[string]string "Activity not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/activities/{id} [delete]
+func (ah *ActivityHandler) DeleteActivityFiber(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil || id <= 0 {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid activity ID", err)
+	}
+	if err := ah.Service.DeleteActivity(uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return handleError("fiber", c, http.StatusNotFound, "Activity not found to delete", nil)
+		}
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to delete activity", err)
+	}
+	return c.Status(http.StatusOK).JSON(fiber.Map{"message": "Activity deleted successfully"})
+}
+
+// MergeActivityFiber godoc
+// @Summary Merge an activity into another
+// @Description Merges the activity into the target: vibes are rewritten and the name becomes an alias of the target.
+// @Tags activities
+// @Accept json
+// @Produce json
+// @Param id path int true "Activity ID to merge (source)"
+// @Param merge body MergeActivityRequest true "Target activity"
+// @Success 200 {object} model.Activity "Merged target activity"
+// @Failure 400 {object} map[string]string "Invalid input or ID format"
+// @Failure 404 {object} map[string]string "Activity not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/activities/{id}/merge [post]
+func (ah *ActivityHandler) MergeActivityFiber(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil || id <= 0 {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid activity ID", err)
+	}
+	var req MergeActivityRequest
+	if err := bindJSONFiber(c, &req); err != nil || req.TargetID == 0 {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid request body, 'target_id' is required", err)
+	}
+	merged, err := ah.Service.MergeActivities(uint(id), req.TargetID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return handleError("fiber", c, http.StatusNotFound, "Activity not found to merge", nil)
+		}
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to merge activities", err)
+	}
+	return c.JSON(merged)
+}
+
+// --- Gin Handlers ---
+
+// GetAllActivitiesGin godoc
+// @Summary List activities with usage stats
+// @Description Lists the activity catalog with usage counts, last-used date and average mood/energy.
+// @Tags activities
+// @Produce json
+// @Success 200 {array} model.ActivityWithUsage "Activities with usage"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/activities [get]
+func (ah *ActivityHandler) GetAllActivitiesGin(c *gin.Context) {
+	activities, err := ah.Service.GetAllActivitiesWithUsage()
+	if err != nil {
+		handleError("gin", c, http.StatusInternalServerError, "Failed to retrieve activities", err)
+		return
+	}
+	c.JSON(http.StatusOK, activities)
+}
+
+// CreateActivityGin godoc
+// @Summary Add an activity to the catalog
+// @Description Adds a new activity, optionally under a parent category.
+// @Tags activities
+// @Accept json
+// @Produce json
+// @Param activity body ActivityRequest true "Activity to add"
+// @Success 201 {object} model.Activity "Created activity"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Router /api/v1/activities [post]
+func (ah *ActivityHandler) CreateActivityGin(c *gin.Context) {
+	var req ActivityRequest
+	if err := bindJSONGin(c, &req); err != nil {
+		handleError("gin", c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	activity := req.toModel()
+	created, err := ah.Service.CreateActivity(&activity)
+	if err != nil {
+		handleError("gin", c, http.StatusBadRequest, "Failed to create activity", err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// GetActivityByIDGin godoc
+// @Summary Get an activity
+// @Description Retrieves a single activity from the catalog by its ID.
+// @Tags activities
+// @Produce json
+// @Param id path int true "Activity ID"
+// @Success 200 {object} model.Activity "Activity details"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 404 {object} map[string]string "Activity not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/activities/{id} [get]
+func (ah *ActivityHandler) GetActivityByIDGin(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		handleError("gin", c, http.StatusBadRequest, "Invalid activity ID", err)
+		return
+	}
+	activity, err := ah.Service.GetActivityByID(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			handleError("gin", c, http.StatusNotFound, "Activity not found", nil)
+			return
+		}
+		handleError("gin", c, http.StatusInternalServerError, "Failed to retrieve activity", err)
+		return
+	}
+	c.JSON(http.StatusOK, activity)
+}
+
+// UpdateActivityGin godoc
+// @Summary Update or rename an activity
+// @Description Modifies an activity. Renaming rewrites the old name in all existing vibes.
+// @Tags activities
+// @Accept json
+// @Produce json
+// @Param id path int true "Activity ID"
+// @Param activity body ActivityRequest true "Updated activity data"
+// @Success 200 {object} model.Activity "Updated activity"
+// @Failure 400 {object} map[string]string "Invalid input or ID format"
+// @Failure 404 {object} map[string]string "Activity not found"
+// @Router /api/v1/activities/{id} [put]
+func (ah *ActivityHandler) UpdateActivityGin(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		handleError("gin", c, http.StatusBadRequest, "Invalid activity ID", err)
+		return
+	}
+	var req ActivityRequest
+	if err := bindJSONGin(c, &req); err != nil {
+		handleError("gin", c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	activity := req.toModel()
+	updated, err := ah.Service.UpdateActivity(uint(id), &activity)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			handleError("gin", c, http.StatusNotFound, "Activity not found to update", nil)
+			return
+		}
+		handleError("gin", c, http.StatusBadRequest, "Failed to update activity", err)
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// DeleteActivityGin godoc
+// @Summary Delete an activity
+// @Description Removes an activity from the catalog. Child activities become top-level.
+// @Tags activities
+// @Produce json
+// @Param id path int true "Activity ID"
+// @Success 200 {object} map[string]string "Success message"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 404 {object} map[string]string "Activity not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/activities/{id} [delete]
+func (ah *ActivityHandler) DeleteActivityGin(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		handleError("gin", c, http.StatusBadRequest, "Invalid activity ID", err)
+		return
+	}
+	if err := ah.Service.DeleteActivity(uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			handleError("gin", c, http.StatusNotFound, "Activity not found to delete", nil)
+			return
+		}
+		handleError("gin", c, http.StatusInternalServerError, "Failed to delete activity", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Activity deleted successfully"})
+}
+
+// MergeActivityGin godoc
+// @Summary Merge an activity into another
+// @Description Merges the activity into the target: vibes are rewritten and the name becomes an alias of the target.
+// @Tags activities
+// @Accept json
+// @Produce json
+// @Param id path int true "Activity ID to merge (source)"
+// @Param merge body MergeActivityRequest true "Target activity"
+// @Success 200 {object} model.Activity "Merged target activity"
+// @Failure 400 {object} map[string]string "Invalid input or ID format"
+// @Failure 404 {object} map[string]string "Activity not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/activities/{id}/merge [post]
+func (ah *ActivityHandler) MergeActivityGin(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		handleError("gin", c, http.StatusBadRequest, "Invalid activity ID", err)
+		return
+	}
+	var req MergeActivityRequest
+	if err := bindJSONGin(c, &req); err != nil {
+		handleError("gin", c, http.StatusBadRequest, "Invalid request body, 'target_id' is required", err)
+		return
+	}
+	merged, err := ah.Service.MergeActivities(uint(id), req.TargetID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			handleError("gin", c, http.StatusNotFound, "Activity not found to merge", nil)
+			return
+		}
+		handleError("gin", c, http.StatusInternalServerError, "Failed to merge activities", err)
+		return
+	}
+	c.JSON(http.StatusOK, merged)
+}
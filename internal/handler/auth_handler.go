@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/gofiber/fiber/v2"
+)
+
+// APIKeyHeader carries an API key; "Authorization: Bearer <key>" works as well.
+const APIKeyHeader = "X-API-Key"
+
+// AuthHandler authenticates requests by API key and stores the username under UserIDKey.
+// Requests without a key pass anonymously unless Required is set; a key that is sent must
+// be valid.
+type AuthHandler struct {
+	Service  service.UserServiceInterface
+	Required bool
+}
+
+// NewAuthHandler creates a new AuthHandler.
+func NewAuthHandler(svc service.UserServiceInterface, required bool) *AuthHandler {
+	return &AuthHandler{Service: svc, Required: required}
+}
+
+// apiKeyFromHeaders reads the key from X-API-Key or a Bearer authorization header.
+func apiKeyFromHeaders(apiKey, authorization string) string {
+	if apiKey != "" {
+		return strings.TrimSpace(apiKey)
+	}
+	if scheme, token, ok := strings.Cut(authorization, " "); ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+	return ""
+}
+
+// authenticate returns the username of key, or the status and message to reject with.
+func (h *AuthHandler) authenticate(ctx context.Context, key string) (string, int, string, error) {
+	if key == "" {
+		if h.Required {
+			return "", http.StatusUnauthorized, "Authentication required", nil
+		}
+		return "", 0, "", nil
+	}
+	user, err := h.Service.WithContext(ctx).Authenticate(key)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidAPIKey) {
+			return "", http.StatusUnauthorized, "Unauthorized", err
+		}
+		return "", http.StatusInternalServerError, "Failed to authenticate", err
+	}
+	return user.Username, 0, "", nil
+}
+
+// AuthenticateFiber is a Fiber middleware that authenticates the request.
+func (h *AuthHandler) AuthenticateFiber(c *fiber.Ctx) error {
+	username, code, msg, err := h.authenticate(c.UserContext(), apiKeyFromHeaders(c.Get(APIKeyHeader), c.Get(fiber.HeaderAuthorization)))
+	if code != 0 {
+		c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+		return handleError("fiber", c, code, msg, err)
+	}
+	if username != "" {
+		c.Locals(UserIDKey, username)
+	}
+	return c.Next()
+}
+
+// AuthenticateGin is a Gin middleware that authenticates the request.
+func (h *AuthHandler) AuthenticateGin(c *gin.Context) {
+	username, code, msg, err := h.authenticate(c.Request.Context(), apiKeyFromHeaders(c.GetHeader(APIKeyHeader), c.GetHeader("Authorization")))
+	if code != 0 {
+		c.Header("WWW-Authenticate", "Bearer")
+		_ = handleError("gin", c, code, msg, err)
+		c.Abort()
+		return
+	}
+	if username != "" {
+		c.Set(UserIDKey, username)
+	}
+	c.Next()
+}
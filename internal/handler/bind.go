@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gofiber/fiber/v2"
+)
+
+// bindJSONFiber decodes a JSON request body into v and checks its binding tags. Both
+// servers bind bodies this way, so they reject the same bodies with the same errors.
+func bindJSONFiber(c *fiber.Ctx, v interface{}) error {
+	return decodeJSON(c.Body(), v)
+}
+
+// bindJSONGin is bindJSONFiber for Gin.
+func bindJSONGin(c *gin.Context, v interface{}) error {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return err
+	}
+	return decodeJSON(body, v)
+}
+
+func decodeJSON(body []byte, v interface{}) error {
+	if err := json.Unmarshal(body, v); err != nil {
+		return err
+	}
+	return binding.Validator.ValidateStruct(v)
+}
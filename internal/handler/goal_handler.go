@@ -0,0 +1,518 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/model"
+	"github.com/aebalz/daily-vibe-tracker/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// GoalHandler handles goal requests.
+type GoalHandler struct {
+	Service service.GoalServiceInterface
+}
+
+// NewGoalHandler creates a new GoalHandler.
+func NewGoalHandler(svc service.GoalServiceInterface) *GoalHandler {
+	return &GoalHandler{Service: svc}
+}
+
+// GoalRequest defines the expected body for creating or updating a goal. The target is given
+// either as an expression, e.g. "activity:exercise:gte:3", or with metric, subject, operator and target.
+type GoalRequest struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Expression  string  `json:"expression"`
+	Metric      string  `json:"metric"`  // activity, mood, average_energy or logged_days
+	Subject     string  `json:"subject"` // Activity or mood name
+	Operator    string  `json:"operator"`
+	Target      float64 `json:"target"`
+	Period      string  `json:"period" binding:"required"` // daily, weekly or monthly
+	StartDate   string  `json:"start_date"`                // YYYY-MM-DD, defaults to today
+	Active      *bool   `json:"active"`                    // Defaults to true
+}
+
+func (r GoalRequest) toModel() (model.Goal, error) {
+	goal := model.Goal{
+		Name:        r.Name,
+		Description: r.Description,
+		Expression:  r.Expression,
+		Metric:      r.Metric,
+		Subject:     r.Subject,
+		Operator:    r.Operator,
+		Target:      r.Target,
+		Period:      r.Period,
+		Active:      r.Active == nil || *r.Active,
+	}
+	if r.StartDate != "" {
+		startDate, err := time.Parse("2006-01-02", r.StartDate)
+		if err != nil {
+			return goal, fmt.Errorf("invalid start_date format. Use YYYY-MM-DD")
+		}
+		goal.StartDate = startDate
+	}
+	return goal, nil
+}
+
+// --- Fiber Handlers ---
+
+// CreateGoalFiber godoc
+// @Summary Create a goal
+// @Description Adds a goal evaluated once per period, e.g. exercise 3x/week ("activity:exercise:gte:3", weekly) or average energy of at least 6 each month ("average_energy:gte:6", monthly).
+// @Tags goals
+// @Accept json
+// @Produce json
+// @Param goal body GoalRequest true "Goal"
+// @Success 201 {object} model.Goal "Created goal"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/goals [post]
+func (gh *GoalHandler) CreateGoalFiber(c *fiber.Ctx) error {
+	var req GoalRequest
+	if err := bindJSONFiber(c, &req); err != nil {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid request body", err)
+	}
+	goal, err := req.toModel()
+	if err != nil {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid request body", err)
+	}
+	created, err := gh.Service.CreateGoal(&goal)
+	if err != nil {
+		if isClientError(err) {
+			return handleError("fiber", c, http.StatusBadRequest, "Failed to create goal", err)
+		}
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to create goal", err)
+	}
+	return c.Status(http.StatusCreated).JSON(created)
+}
+
+// GetAllGoalsFiber godoc
+// @Summary List goals
+// @Description Retrieves all goals.
+// @Tags goals
+// @Produce json
+// @Param active query bool false "Only list active goals"
+// @Success 200 {array} model.Goal "Goals"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/goals [get]
+func (gh *GoalHandler) GetAllGoalsFiber(c *fiber.Ctx) error {
+	goals, err := gh.Service.GetAllGoals(c.Query("active") == "true")
+	if err != nil {
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to retrieve goals", err)
+	}
+	return c.JSON(goals)
+}
+
+// GetGoalByIDFiber godoc
+// @Summary Get a goal
+// @Description Retrieves a goal by its ID.
+// @Tags goals
+// @Produce json
+// @Param id path int true "Goal ID"
+// @Success 200 {object} model.Goal "Goal"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 404 {object} map[string]string "Goal not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/goals/{id} [get]
+func (gh *GoalHandler) GetGoalByIDFiber(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil || id <= 0 {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid goal ID", err)
+	}
+	goal, err := gh.Service.GetGoalByID(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return handleError("fiber", c, http.StatusNotFound, "Goal not found", nil)
+		}
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to retrieve goal", err)
+	}
+	return c.JSON(goal)
+}
+
+// UpdateGoalFiber godoc
+// @Summary Update a goal
+// @Description Replaces a goal's target, period and details. History is re-evaluated from the start date.
+// @Tags goals
+// @Accept json
+// @Produce json
+// @Param id path int true "Goal ID"
+// @Param goal body GoalRequest true "Updated goal"
+// @Success 200 {object} model.Goal "Updated goal"
+// @Failure 400 {object} map[string]string "Invalid input or ID format"
+// @Failure 404 {object} map[string]string "Goal not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/goals/{id} [put]
+func (gh *GoalHandler) UpdateGoalFiber(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil || id <= 0 {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid goal ID", err)
+	}
+	var req GoalRequest
+	if err := bindJSONFiber(c, &req); err != nil {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid request body", err)
+	}
+	goal, err := req.toModel()
+	if err != nil {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid request body", err)
+	}
+	updated, err := gh.Service.UpdateGoal(uint(id), &goal)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return handleError("fiber", c, http.StatusNotFound, "Goal not found", nil)
+		}
+		if isClientError(err) {
+			return handleError("fiber", c, http.StatusBadRequest, "Failed to update goal", err)
+		}
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to update goal", err)
+	}
+	return c.JSON(updated)
+}
+
+// DeleteGoalFiber godoc
+// @Summary Delete a goal
+// @Description Removes a goal.
+// @Tags goals
+// @Produce json
+// @Param id path int true "Goal ID"
+// @Success 200 {object} map[string]string "Success message"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 404 {object} map[string]string "Goal not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/goals/{id} [delete]
+func (gh *GoalHandler) DeleteGoalFiber(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil || id <= 0 {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid goal ID", err)
+	}
+	if err := gh.Service.DeleteGoal(uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return handleError("fiber", c, http.StatusNotFound, "Goal not found", nil)
+		}
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to delete goal", err)
+	}
+	return c.Status(http.StatusOK).JSON(fiber.Map{"message": "Goal deleted successfully"})
+}
+
+// GetAllGoalProgressFiber godoc
+// @Summary Get progress of all active goals
+// @Description Evaluates the current period of every active goal, with streaks and hit rates.
+// @Tags goals
+// @Produce json
+// @Success 200 {array} model.GoalProgress "Goal progress"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/goals/progress [get]
+func (gh *GoalHandler) GetAllGoalProgressFiber(c *fiber.Ctx) error {
+	progress, err := gh.Service.GetAllGoalProgress()
+	if err != nil {
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to evaluate goals", err)
+	}
+	return c.JSON(progress)
+}
+
+// GetGoalProgressFiber godoc
+// @Summary Get goal progress
+// @Description Evaluates the current period of a goal: value, percent of target, remaining days needed, days left, and current and longest streaks of hit periods.
+// @Tags goals
+// @Produce json
+// @Param id path int true "Goal ID"
+// @Success 200 {object} model.GoalProgress "Goal progress"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 404 {object} map[string]string "Goal not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/goals/{id}/progress [get]
+func (gh *GoalHandler) GetGoalProgressFiber(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil || id <= 0 {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid goal ID", err)
+	}
+	progress, err := gh.Service.GetGoalProgress(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return handleError("fiber", c, http.StatusNotFound, "Goal not found", nil)
+		}
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to evaluate goal", err)
+	}
+	return c.JSON(progress)
+}
+
+// GetGoalHistoryFiber godoc
+// @Summary Get goal history
+// @Description Lists the hits and misses of the most recent periods of a goal, newest first.
+// @Tags goals
+// @Produce json
+// @Param id path int true "Goal ID"
+// @Param limit query int false "Number of periods (1-366)" default(12)
+// @Success 200 {object} model.GoalHistory "Goal history"
+// @Failure 400 {object} map[string]string "Invalid ID format or query parameters"
+// @Failure 404 {object} map[string]string "Goal not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/goals/{id}/history [get]
+func (gh *GoalHandler) GetGoalHistoryFiber(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil || id <= 0 {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid goal ID", err)
+	}
+	limit, err := strconv.Atoi(c.Query("limit", "0"))
+	if err != nil {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid limit", err)
+	}
+	history, err := gh.Service.GetGoalHistory(uint(id), limit)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return handleError("fiber", c, http.StatusNotFound, "Goal not found", nil)
+		}
+		if isClientError(err) {
+			return handleError("fiber", c, http.StatusBadRequest, "Invalid query parameters", err)
+		}
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to evaluate goal", err)
+	}
+	return c.JSON(history)
+}
+
+// --- Gin Handlers ---
+
+// CreateGoalGin godoc
+// @Summary Create a goal
+// @Description Adds a goal evaluated once per period, e.g. exercise 3x/week ("activity:exercise:gte:3", weekly) or average energy of at least 6 each month ("average_energy:gte:6", monthly).
+// @Tags goals
+// @Accept json
+// @Produce json
+// @Param goal body GoalRequest true "Goal"
+// @Success 201 {object} model.Goal "Created goal"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/goals [post]
+func (gh *GoalHandler) CreateGoalGin(c *gin.Context) {
+	var req GoalRequest
+	if err := bindJSONGin(c, &req); err != nil {
+		handleError("gin", c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	goal, err := req.toModel()
+	if err != nil {
+		handleError("gin", c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	created, err := gh.Service.CreateGoal(&goal)
+	if err != nil {
+		if isClientError(err) {
+			handleError("gin", c, http.StatusBadRequest, "Failed to create goal", err)
+			return
+		}
+		handleError("gin", c, http.StatusInternalServerError, "Failed to create goal", err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// GetAllGoalsGin godoc
+// @Summary List goals
+// @Description Retrieves all goals.
+// @Tags goals
+// @Produce json
+// @Param active query bool false "Only list active goals"
+// @Success 200 {array} model.Goal "Goals"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/goals [get]
+func (gh *GoalHandler) GetAllGoalsGin(c *gin.Context) {
+	goals, err := gh.Service.GetAllGoals(c.Query("active") == "true")
+	if err != nil {
+		handleError("gin", c, http.StatusInternalServerError, "Failed to retrieve goals", err)
+		return
+	}
+	c.JSON(http.StatusOK, goals)
+}
+
+// GetGoalByIDGin godoc
+// @Summary Get a goal
+// @Description Retrieves a goal by its ID.
+// @Tags goals
+// @Produce json
+// @Param id path int true "Goal ID"
+// @Success 200 {object} model.Goal "Goal"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 404 {object} map[string]string "Goal not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/goals/{id} [get]
+func (gh *GoalHandler) GetGoalByIDGin(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		handleError("gin", c, http.StatusBadRequest, "Invalid goal ID", err)
+		return
+	}
+	goal, err := gh.Service.GetGoalByID(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			handleError("gin", c, http.StatusNotFound, "Goal not found", nil)
+			return
+		}
+		handleError("gin", c, http.StatusInternalServerError, "Failed to retrieve goal", err)
+		return
+	}
+	c.JSON(http.StatusOK, goal)
+}
+
+// UpdateGoalGin godoc
+// @Summary Update a goal
+// @Description Replaces a goal's target, period and details. History is re-evaluated from the start date.
+// @Tags goals
+// @Accept json
+// @Produce json
+// @Param id path int true "Goal ID"
+// @Param goal body GoalRequest true "Updated goal"
+// @Success 200 {object} model.Goal "Updated goal"
+// @Failure 400 {object} map[string]string "Invalid input or ID format"
+// @Failure 404 {object} map[string]string "Goal not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/goals/{id} [put]
+func (gh *GoalHandler) UpdateGoalGin(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		handleError("gin", c, http.StatusBadRequest, "Invalid goal ID", err)
+		return
+	}
+	var req GoalRequest
+	if err := bindJSONGin(c, &req); err != nil {
+		handleError("gin", c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	goal, err := req.toModel()
+	if err != nil {
+		handleError("gin", c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	updated, err := gh.Service.UpdateGoal(uint(id), &goal)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			handleError("gin", c, http.StatusNotFound, "Goal not found", nil)
+			return
+		}
+		if isClientError(err) {
+			handleError("gin", c, http.StatusBadRequest, "Failed to update goal", err)
+			return
+		}
+		handleError("gin", c, http.StatusInternalServerError, "Failed to update goal", err)
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// DeleteGoalGin godoc
+// @Summary Delete a goal
+// @Description Removes a goal.
+// @Tags goals
+// @Produce json
+// @Param id path int true "Goal ID"
+// @Success 200 {object} map[string]string "Success message"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 404 {object} map[string]string "Goal not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/goals/{id} [delete]
+func (gh *GoalHandler) DeleteGoalGin(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		handleError("gin", c, http.StatusBadRequest, "Invalid goal ID", err)
+		return
+	}
+	if err := gh.Service.DeleteGoal(uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			handleError("gin", c, http.StatusNotFound, "Goal not found", nil)
+			return
+		}
+		handleError("gin", c, http.StatusInternalServerError, "Failed to delete goal", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Goal deleted successfully"})
+}
+
+// GetAllGoalProgressGin godoc
+// @Summary Get progress of all active goals
+// @Description Evaluates the current period of every active goal, with streaks and hit rates.
+// @Tags goals
+// @Produce json
+// @Success 200 {array} model.GoalProgress "Goal progress"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/goals/progress [get]
+func (gh *GoalHandler) GetAllGoalProgressGin(c *gin.Context) {
+	progress, err := gh.Service.GetAllGoalProgress()
+	if err != nil {
+		handleError("gin", c, http.StatusInternalServerError, "Failed to evaluate goals", err)
+		return
+	}
+	c.JSON(http.StatusOK, progress)
+}
+
+// GetGoalProgressGin godoc
+// @Summary Get goal progress
+// @Description Evaluates the current period of a goal: value, percent of target, remaining days needed, days left, and current and longest streaks of hit periods.
+// @Tags goals
+// @Produce json
+// @Param id path int true "Goal ID"
+// @Success 200 {object} model.GoalProgress "Goal progress"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 404 {object} map[string]string "Goal not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/goals/{id}/progress [get]
+func (gh *GoalHandler) GetGoalProgressGin(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		handleError("gin", c, http.StatusBadRequest, "Invalid goal ID", err)
+		return
+	}
+	progress, err := gh.Service.GetGoalProgress(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			handleError("gin", c, http.StatusNotFound, "Goal not found", nil)
+			return
+		}
+		handleError("gin", c, http.StatusInternalServerError, "Failed to evaluate goal", err)
+		return
+	}
+	c.JSON(http.StatusOK, progress)
+}
+
+// GetGoalHistoryGin godoc
+// @Summary Get goal history
+// @Description Lists the hits and misses of the most recent periods of a goal, newest first.
+// @Tags goals
+// @Produce json
+// @Param id path int true "Goal ID"
+// @Param limit query int false "Number of periods (1-366)" default(12)
+// @Success 200 {object} model.GoalHistory "Goal history"
+// @Failure 400 {object} map[string]string "Invalid ID format or query parameters"
+// @Failure 404 {object} map[string]string "Goal not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/goals/{id}/history [get]
+func (gh *GoalHandler) GetGoalHistoryGin(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		handleError("gin", c, http.StatusBadRequest, "Invalid goal ID", err)
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	if err != nil {
+		handleError("gin", c, http.StatusBadRequest, "Invalid limit", err)
+		return
+	}
+	history, err := gh.Service.GetGoalHistory(uint(id), limit)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			handleError("gin", c, http.StatusNotFound, "Goal not found", nil)
+			return
+		}
+		if isClientError(err) {
+			handleError("gin", c, http.StatusBadRequest, "Invalid query parameters", err)
+			return
+		}
+		handleError("gin", c, http.StatusInternalServerError, "Failed to evaluate goal", err)
+		return
+	}
+	c.JSON(http.StatusOK, history)
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/graph"
+	"github.com/gin-gonic/gin"
+	"github.com/gofiber/fiber/v2"
+)
+
+// GraphQLHandler serves the GraphQL endpoint.
+type GraphQLHandler struct {
+	Server *graph.Server
+}
+
+// NewGraphQLHandler creates a new GraphQLHandler.
+func NewGraphQLHandler(server *graph.Server) *GraphQLHandler {
+	return &GraphQLHandler{Server: server}
+}
+
+// parseGraphQLQuery reads a GET request from the query, operationName and variables
+// (JSON-encoded) query parameters.
+func parseGraphQLQuery(get func(key string) string) (graph.Request, error) {
+	req := graph.Request{Query: get("query"), OperationName: get("operationName")}
+	if raw := get("variables"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &req.Variables); err != nil {
+			return req, errors.New("variables must be a JSON object")
+		}
+	}
+	if req.Query == "" {
+		return req, errors.New("missing 'query'")
+	}
+	return req, nil
+}
+
+// --- Fiber Handlers ---
+
+// ExecuteFiber godoc
+// @Summary Execute a GraphQL query
+// @Description Runs a GraphQL query or mutation over vibes, statistics, streaks and recommendations. POST a JSON body {query, operationName, variables}, or use GET with the same fields as query parameters (queries only). Errors from the query are reported in the `errors` field with status 200.
+// @Tags graphql
+// @Accept json
+// @Produce json
+// @Param request body graph.Request false "GraphQL request (POST)"
+// @Param query query string false "Query (GET)"
+// @Param operationName query string false "Operation name (GET)"
+// @Param variables query string false "JSON-encoded variables (GET)"
+// @Success 200 {object} map[string]interface{} "GraphQL result with data and errors"
+// @Failure 400 {object} map[string]string "Malformed request"
+// @Router /graphql [post]
+func (h *GraphQLHandler) ExecuteFiber(c *fiber.Ctx) error {
+	var req graph.Request
+	readOnly := c.Method() == fiber.MethodGet
+	if readOnly {
+		var err error
+		if req, err = parseGraphQLQuery(func(key string) string { return c.Query(key) }); err != nil {
+			return handleError("fiber", c, http.StatusBadRequest, "Invalid GraphQL request", err)
+		}
+	} else if err := bindJSONFiber(c, &req); err != nil {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid GraphQL request body", err)
+	} else if req.Query == "" {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid GraphQL request", errors.New("missing 'query'"))
+	}
+	return c.JSON(h.Server.Execute(c.UserContext(), req, readOnly))
+}
+
+// --- Gin Handlers ---
+
+// ExecuteGin godoc
+// @Summary Execute a GraphQL query
+// @Description Runs a GraphQL query or mutation over vibes, statistics, streaks and recommendations. POST a JSON body {query, operationName, variables}, or use GET with the same fields as query parameters (queries only). Errors from the query are reported in the `errors` field with status 200.
+// @Tags graphql
+// @Accept json
+// @Produce json
+// @Param request body graph.Request false "GraphQL request (POST)"
+// @Param query query string false "Query (GET)"
+// @Param operationName query string false "Operation name (GET)"
+// @Param variables query string false "JSON-encoded variables (GET)"
+// @Success 200 {object} map[string]interface{} "GraphQL result with data and errors"
+// @Failure 400 {object} map[string]string "Malformed request"
+// @Router /graphql [post]
+func (h *GraphQLHandler) ExecuteGin(c *gin.Context) {
+	var req graph.Request
+	readOnly := c.Request.Method == http.MethodGet
+	if readOnly {
+		var err error
+		if req, err = parseGraphQLQuery(c.Query); err != nil {
+			handleError("gin", c, http.StatusBadRequest, "Invalid GraphQL request", err)
+			return
+		}
+	} else if err := bindJSONGin(c, &req); err != nil {
+		handleError("gin", c, http.StatusBadRequest, "Invalid GraphQL request body", err)
+		return
+	} else if req.Query == "" {
+		handleError("gin", c, http.StatusBadRequest, "Invalid GraphQL request", errors.New("missing 'query'"))
+		return
+	}
+	c.JSON(http.StatusOK, h.Server.Execute(c.Request.Context(), req, readOnly))
+}
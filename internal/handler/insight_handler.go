@@ -0,0 +1,298 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/model"
+	"github.com/aebalz/daily-vibe-tracker/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/gofiber/fiber/v2"
+)
+
+// InsightHandler handles statistical insight and anomaly requests.
+type InsightHandler struct {
+	Service        service.InsightServiceInterface
+	AnomalyService service.AnomalyServiceInterface
+}
+
+// NewInsightHandler creates a new InsightHandler.
+func NewInsightHandler(svc service.InsightServiceInterface, anomalySvc service.AnomalyServiceInterface) *InsightHandler {
+	return &InsightHandler{Service: svc, AnomalyService: anomalySvc}
+}
+
+// PaginatedAnomaliesResponse defines the structure for paginated anomaly event responses.
+type PaginatedAnomaliesResponse struct {
+	Data   []model.AnomalyEvent `json:"data"`
+	Total  int64                `json:"total"`
+	Limit  int                  `json:"limit"`
+	Offset int                  `json:"offset"`
+}
+
+// parseAnomalyFilters reads the anomaly query parameters using the framework's query getter.
+func parseAnomalyFilters(query func(key string) string) (map[string]interface{}, error) {
+	filters := make(map[string]interface{})
+	if v := query("start_date"); v != "" {
+		parsed, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start_date format. Use YYYY-MM-DD")
+		}
+		filters["start_date"] = parsed
+	}
+	if v := query("end_date"); v != "" {
+		parsed, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid end_date format. Use YYYY-MM-DD")
+		}
+		filters["end_date"] = parsed.Add(23*time.Hour + 59*time.Minute + 59*time.Second)
+	}
+	if v := query("kind"); v != "" {
+		filters["kind"] = v
+	}
+	if v := query("severity"); v != "" {
+		filters["severity"] = v
+	}
+	return filters, nil
+}
+
+// parseCorrelationOptions reads the correlation query parameters using the framework's query getter.
+func parseCorrelationOptions(query func(key string) string) (service.CorrelationOptions, error) {
+	opts := service.CorrelationOptions{
+		Period: strings.ToLower(query("period")),
+		MaxLag: service.DefaultCorrelationLag,
+	}
+	if opts.Period != "" {
+		validPeriods := map[string]bool{"week": true, "month": true, "year": true}
+		if !validPeriods[opts.Period] {
+			return opts, fmt.Errorf("invalid period. Allowed values: week, month, year")
+		}
+	}
+	if v := query("start_date"); v != "" {
+		parsed, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return opts, fmt.Errorf("invalid start_date format. Use YYYY-MM-DD")
+		}
+		opts.StartDate = parsed
+	}
+	if v := query("end_date"); v != "" {
+		parsed, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return opts, fmt.Errorf("invalid end_date format. Use YYYY-MM-DD")
+		}
+		opts.EndDate = parsed.Add(23*time.Hour + 59*time.Minute + 59*time.Second) // Include the whole end day
+	}
+	if v := query("max_lag"); v != "" {
+		lag, err := strconv.Atoi(v)
+		if err != nil {
+			return opts, fmt.Errorf("max_lag must be an integer")
+		}
+		opts.MaxLag = lag
+	}
+	if v := query("min_samples"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return opts, fmt.Errorf("min_samples must be an integer")
+		}
+		opts.MinSamples = n
+	}
+	if v := query("min_confidence"); v != "" {
+		conf, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return opts, fmt.Errorf("min_confidence must be a number")
+		}
+		opts.MinConfidence = conf
+	}
+	return opts, nil
+}
+
+// --- Fiber Handlers ---
+
+// GetCorrelationsFiber godoc
+// @Summary Get correlation insights
+// @Description Correlates activities and custom metrics with energy, mood valence and other metrics using Pearson, Spearman and lift, including lagged effects (e.g. exercise yesterday -> energy today). Only correlations meeting the sample and confidence thresholds are returned.
+// @Tags vibes-analytics
+// @Produce json
+// @Param period query string false "Time period (week, month, year), used when start_date/end_date are not set" default(month)
+// @Param start_date query string false "Start of the range (YYYY-MM-DD), requires end_date"
+// @Param end_date query string false "End of the range, inclusive (YYYY-MM-DD), requires start_date"
+// @Param max_lag query int false "Analyze effects up to this many days later (0-7)" default(1)
+// @Param min_samples query int false "Minimum paired days per correlation (defaults to INSIGHTS_MIN_SAMPLES)"
+// @Param min_confidence query number false "Minimum confidence, 1 - p-value (defaults to INSIGHTS_MIN_CONFIDENCE)"
+// @Success 200 {object} model.CorrelationReport "Correlation report"
+// @Failure 400 {object} map[string]string "Invalid query parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/vibes/insights/correlations [get]
+func (ih *InsightHandler) GetCorrelationsFiber(c *fiber.Ctx) error {
+	opts, err := parseCorrelationOptions(func(key string) string { return c.Query(key) })
+	if err != nil {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid query parameters", err)
+	}
+	report, err := ih.Service.GetCorrelations(opts)
+	if err != nil {
+		if isClientError(err) {
+			return handleError("fiber", c, http.StatusBadRequest, "Invalid query parameters", err)
+		}
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to compute correlations", err)
+	}
+	return c.JSON(report)
+}
+
+// GetMoodForecastFiber godoc
+// @Summary Get next-day mood forecast
+// @Description Forecasts the mood for the day after the most recent vibe from mood transitions over consecutive days (the last two days when there is enough history, otherwise the last day) weighted by day-of-week effects.
+// @Tags vibes-analytics
+// @Produce json
+// @Param lookback_days query int false "Days of history to learn from (7-1095)" default(365)
+// @Success 200 {object} model.MoodForecast "Mood forecast with probabilities"
+// @Failure 400 {object} map[string]string "Invalid query parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/vibes/insights/forecast [get]
+func (ih *InsightHandler) GetMoodForecastFiber(c *fiber.Ctx) error {
+	lookbackDays, err := strconv.Atoi(c.Query("lookback_days", strconv.Itoa(service.DefaultForecastLookbackDays)))
+	if err != nil {
+		return handleError("fiber", c, http.StatusBadRequest, "lookback_days must be an integer", err)
+	}
+	forecast, err := ih.Service.GetMoodForecast(lookbackDays)
+	if err != nil {
+		if isClientError(err) {
+			return handleError("fiber", c, http.StatusBadRequest, "Invalid query parameters", err)
+		}
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to forecast mood", err)
+	}
+	return c.JSON(forecast)
+}
+
+// GetAnomaliesFiber godoc
+// @Summary List detected anomalies
+// @Description Lists anomaly events, most recent first: sudden energy drops against an EWMA baseline and clusters of negative moods. Detection runs after each write and on a schedule.
+// @Tags vibes-analytics
+// @Produce json
+// @Param start_date query string false "Only anomalies on or after this date (YYYY-MM-DD)"
+// @Param end_date query string false "Only anomalies on or before this date (YYYY-MM-DD)"
+// @Param kind query string false "Filter by kind (energy_drop, negative_mood_cluster)"
+// @Param severity query string false "Filter by severity (low, medium, high)"
+// @Param limit query int false "Pagination limit" default(10)
+// @Param offset query int false "Pagination offset" default(0)
+// @Success 200 {object} PaginatedAnomaliesResponse "Anomaly events with pagination"
+// @Failure 400 {object} map[string]string "Invalid query parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/vibes/insights/anomalies [get]
+func (ih *InsightHandler) GetAnomaliesFiber(c *fiber.Ctx) error {
+	filters, err := parseAnomalyFilters(func(key string) string { return c.Query(key) })
+	if err != nil {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid query parameters", err)
+	}
+	limit, _ := strconv.Atoi(c.Query("limit", strconv.Itoa(service.DefaultLimit)))
+	offset, _ := strconv.Atoi(c.Query("offset", strconv.Itoa(service.DefaultOffset)))
+
+	events, total, err := ih.AnomalyService.GetAnomalies(filters, limit, offset)
+	if err != nil {
+		if isClientError(err) {
+			return handleError("fiber", c, http.StatusBadRequest, "Invalid query parameters", err)
+		}
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to retrieve anomalies", err)
+	}
+	return c.JSON(PaginatedAnomaliesResponse{Data: events, Total: total, Limit: limit, Offset: offset})
+}
+
+// --- Gin Handlers ---
+
+// GetCorrelationsGin godoc
+// @Summary Get correlation insights
+// @Description Correlates activities and custom metrics with energy, mood valence and other metrics using Pearson, Spearman and lift, including lagged effects (e.g. exercise yesterday -> energy today). Only correlations meeting the sample and confidence thresholds are returned.
+// @Tags vibes-analytics
+// @Produce json
+// @Param period query string false "Time period (week, month, year), used when start_date/end_date are not set" default(month)
+// @Param start_date query string false "Start of the range (YYYY-MM-DD), requires end_date"
+// @Param end_date query string false "End of the range, inclusive (YYYY-MM-DD), requires start_date"
+// @Param max_lag query int false "Analyze effects up to this many days later (0-7)" default(1)
+// @Param min_samples query int false "Minimum paired days per correlation (defaults to INSIGHTS_MIN_SAMPLES)"
+// @Param min_confidence query number false "Minimum confidence, 1 - p-value (defaults to INSIGHTS_MIN_CONFIDENCE)"
+// @Success 200 {object} model.CorrelationReport "Correlation report"
+// @Failure 400 {object} map[string]string "Invalid query parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/vibes/insights/correlations [get]
+func (ih *InsightHandler) GetCorrelationsGin(c *gin.Context) {
+	opts, err := parseCorrelationOptions(c.Query)
+	if err != nil {
+		handleError("gin", c, http.StatusBadRequest, "Invalid query parameters", err)
+		return
+	}
+	report, err := ih.Service.GetCorrelations(opts)
+	if err != nil {
+		if isClientError(err) {
+			handleError("gin", c, http.StatusBadRequest, "Invalid query parameters", err)
+			return
+		}
+		handleError("gin", c, http.StatusInternalServerError, "Failed to compute correlations", err)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// GetMoodForecastGin godoc
+// @Summary Get next-day mood forecast
+// @Description Forecasts the mood for the day after the most recent vibe from mood transitions over consecutive days (the last two days when there is enough history, otherwise the last day) weighted by day-of-week effects.
+// @Tags vibes-analytics
+// @Produce json
+// @Param lookback_days query int false "Days of history to learn from (7-1095)" default(365)
+// @Success 200 {object} model.MoodForecast "Mood forecast with probabilities"
+// @Failure 400 {object} map[string]string "Invalid query parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/vibes/insights/forecast [get]
+func (ih *InsightHandler) GetMoodForecastGin(c *gin.Context) {
+	lookbackDays, err := strconv.Atoi(c.DefaultQuery("lookback_days", strconv.Itoa(service.DefaultForecastLookbackDays)))
+	if err != nil {
+		handleError("gin", c, http.StatusBadRequest, "lookback_days must be an integer", err)
+		return
+	}
+	forecast, err := ih.Service.GetMoodForecast(lookbackDays)
+	if err != nil {
+		if isClientError(err) {
+			handleError("gin", c, http.StatusBadRequest, "Invalid query parameters", err)
+			return
+		}
+		handleError("gin", c, http.StatusInternalServerError, "Failed to forecast mood", err)
+		return
+	}
+	c.JSON(http.StatusOK, forecast)
+}
+
+// GetAnomaliesGin godoc
+// @Summary List detected anomalies
+// @Description Lists anomaly events, most recent first: sudden energy drops against an EWMA baseline and clusters of negative moods. Detection runs after each write and on a schedule.
+// @Tags vibes-analytics
+// @Produce json
+// @Param start_date query string false "Only anomalies on or after this date (YYYY-MM-DD)"
+// @Param end_date query string false "Only anomalies on or before this date (YYYY-MM-DD)"
+// @Param kind query string false "Filter by kind (energy_drop, negative_mood_cluster)"
+// @Param severity query string false "Filter by severity (low, medium, high)"
+// @Param limit query int false "Pagination limit" default(10)
+// @Param offset query int false "Pagination offset" default(0)
+// @Success 200 {object} PaginatedAnomaliesResponse "Anomaly events with pagination"
+// @Failure 400 {object} map[string]string "Invalid query parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/vibes/insights/anomalies [get]
+func (ih *InsightHandler) GetAnomaliesGin(c *gin.Context) {
+	filters, err := parseAnomalyFilters(c.Query)
+	if err != nil {
+		handleError("gin", c, http.StatusBadRequest, "Invalid query parameters", err)
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(service.DefaultLimit)))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", strconv.Itoa(service.DefaultOffset)))
+
+	events, total, err := ih.AnomalyService.GetAnomalies(filters, limit, offset)
+	if err != nil {
+		if isClientError(err) {
+			handleError("gin", c, http.StatusBadRequest, "Invalid query parameters", err)
+			return
+		}
+		handleError("gin", c, http.StatusInternalServerError, "Failed to retrieve anomalies", err)
+		return
+	}
+	c.JSON(http.StatusOK, PaginatedAnomaliesResponse{Data: events, Total: total, Limit: limit, Offset: offset})
+}
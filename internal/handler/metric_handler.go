@@ -0,0 +1,307 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/model"
+	"github.com/aebalz/daily-vibe-tracker/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// MetricHandler handles custom metric definition requests.
+type MetricHandler struct {
+	Service service.MetricServiceInterface
+}
+
+// NewMetricHandler creates a new MetricHandler.
+func NewMetricHandler(svc service.MetricServiceInterface) *MetricHandler {
+	return &MetricHandler{Service: svc}
+}
+
+// MetricDefinitionRequest defines the expected body for creating or updating a metric definition.
+type MetricDefinitionRequest struct {
+	Name        string   `json:"name" binding:"required"`
+	Label       string   `json:"label"`
+	Type        string   `json:"type" binding:"required"` // int, float, bool or scale
+	Unit        string   `json:"unit"`
+	Min         *float64 `json:"min"`
+	Max         *float64 `json:"max"`
+	Description string   `json:"description"`
+}
+
+func (r MetricDefinitionRequest) toModel() model.MetricDefinition {
+	return model.MetricDefinition{
+		Name:        r.Name,
+		Label:       r.Label,
+		Type:        r.Type,
+		Unit:        r.Unit,
+		Min:         r.Min,
+		Max:         r.Max,
+		Description: r.Description,
+	}
+}
+
+// --- Fiber Handlers ---
+
+// CreateMetricDefinitionFiber godoc
+// @Summary Define a custom metric
+// @Description Adds a custom metric (e.g. sleep_hours, stress) that can be recorded on each vibe.
+// @Tags metrics
+// @Accept json
+// @Produce json
+// @Param metric body MetricDefinitionRequest true "Metric definition"
+// @Success 201 {object} model.MetricDefinition "Created metric definition"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Router /api/v1/metrics [post]
+func (mh *MetricHandler) CreateMetricDefinitionFiber(c *fiber.Ctx) error {
+	var req MetricDefinitionRequest
+	if err := bindJSONFiber(c, &req); err != nil {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid request body", err)
+	}
+	def := req.toModel()
+	created, err := mh.Service.CreateMetricDefinition(&def)
+	if err != nil {
+		return handleError("fiber", c, http.StatusBadRequest, "Failed to create metric definition", err)
+	}
+	return c.Status(http.StatusCreated).JSON(created)
+}
+
+// GetAllMetricDefinitionsFiber godoc
+// @Summary List custom metrics
+// @Description Retrieves all custom metric definitions.
+// @Tags metrics
+// @Produce json
+// @Success 200 {array} model.MetricDefinition "Metric definitions"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/metrics [get]
+func (mh *MetricHandler) GetAllMetricDefinitionsFiber(c *fiber.Ctx) error {
+	defs, err := mh.Service.GetAllMetricDefinitions()
+	if err != nil {
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to retrieve metric definitions", err)
+	}
+	return c.JSON(defs)
+}
+
+// GetMetricDefinitionByIDFiber godoc
+// @Summary Get a custom metric
+// @Description Retrieves a single custom metric definition by its ID.
+// @Tags metrics
+// @Produce json
+// @Param id path int true "Metric definition ID"
+// @Success 200 {object} model.MetricDefinition "Metric definition"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 404 {object} map[string]string "Metric definition not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/metrics/{id} [get]
+func (mh *MetricHandler) GetMetricDefinitionByIDFiber(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil || id <= 0 {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid metric definition ID", err)
+	}
+	def, err := mh.Service.GetMetricDefinitionByID(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return handleError("fiber", c, http.StatusNotFound, "Metric definition not found", nil)
+		}
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to retrieve metric definition", err)
+	}
+	return c.JSON(def)
+}
+
+// UpdateMetricDefinitionFiber godoc
+// @Summary Update a custom metric
+// @Description Modifies a custom metric definition. Name and type are locked once values are recorded.
+// @Tags metrics
+// @Accept json
+// @Produce json
+// @Param id path int true "Metric definition ID"
+// @Param metric body MetricDefinitionRequest true "Updated metric definition"
+// @Success 200 {object} model.MetricDefinition "Updated metric definition"
+// @Failure 400 {object} map[string]string "Invalid input or ID format"
+// @Failure 404 {object} map[string]string "Metric definition not found"
+// @Router /api/v1/metrics/{id} [put]
+func (mh *MetricHandler) UpdateMetricDefinitionFiber(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil || id <= 0 {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid metric definition ID", err)
+	}
+	var req MetricDefinitionRequest
+	if err := bindJSONFiber(c, &req); err != nil {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid request body", err)
+	}
+	def := req.toModel()
+	updated, err := mh.Service.UpdateMetricDefinition(uint(id), &def)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return handleError("fiber", c, http.StatusNotFound, "Metric definition not found to update", nil)
+		}
+		return handleError("fiber", c, http.StatusBadRequest, "Failed to update metric definition", err)
+	}
+	return c.JSON(updated)
+}
+
+// DeleteMetricDefinitionFiber godoc
+// @Summary Delete a custom metric
+// @Description Removes a custom metric definition and all values recorded for it.
+// @Tags metrics
+// @Produce json
+// @Param id path int true "Metric definition ID"
+// @Success 200 {object} map[string]string "Success message"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 404 {object} map[string]string "Metric definition not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/metrics/{id} [delete]
+func (mh *MetricHandler) DeleteMetricDefinitionFiber(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil || id <= 0 {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid metric definition ID", err)
+	}
+	if err := mh.Service.DeleteMetricDefinition(uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return handleError("fiber", c, http.StatusNotFound, "Metric definition not found to delete", nil)
+		}
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to delete metric definition", err)
+	}
+	return c.Status(http.StatusOK).JSON(fiber.Map{"message": "Metric definition deleted successfully"})
+}
+
+// --- Gin Handlers ---
+
+// CreateMetricDefinitionGin godoc
+// @Summary Define a custom metric
+// @Description Adds a custom metric (e.g. sleep_hours, stress) that can be recorded on each vibe.
+// @Tags metrics
+// @Accept json
+// @Produce json
+// @Param metric body MetricDefinitionRequest true "Metric definition"
+// @Success 201 {object} model.MetricDefinition "Created metric definition"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Router /api/v1/metrics [post]
+func (mh *MetricHandler) CreateMetricDefinitionGin(c *gin.Context) {
+	var req MetricDefinitionRequest
+	if err := bindJSONGin(c, &req); err != nil {
+		handleError("gin", c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	def := req.toModel()
+	created, err := mh.Service.CreateMetricDefinition(&def)
+	if err != nil {
+		handleError("gin", c, http.StatusBadRequest, "Failed to create metric definition", err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// GetAllMetricDefinitionsGin godoc
+// @Summary List custom metrics
+// @Description Retrieves all custom metric definitions.
+// @Tags metrics
+// @Produce json
+// @Success 200 {array} model.MetricDefinition "Metric definitions"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/metrics [get]
+func (mh *MetricHandler) GetAllMetricDefinitionsGin(c *gin.Context) {
+	defs, err := mh.Service.GetAllMetricDefinitions()
+	if err != nil {
+		handleError("gin", c, http.StatusInternalServerError, "Failed to retrieve metric definitions", err)
+		return
+	}
+	c.JSON(http.StatusOK, defs)
+}
+
+// GetMetricDefinitionByIDGin godoc
+// @Summary Get a custom metric
+// @Description Retrieves a single custom metric definition by its ID.
+// @Tags metrics
+// @Produce json
+// @Param id path int true "Metric definition ID"
+// @Success 200 {object} model.MetricDefinition "Metric definition"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 404 {object} map[string]string "Metric definition not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/metrics/{id} [get]
+func (mh *MetricHandler) GetMetricDefinitionByIDGin(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		handleError("gin", c, http.StatusBadRequest, "Invalid metric definition ID", err)
+		return
+	}
+	def, err := mh.Service.GetMetricDefinitionByID(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			handleError("gin", c, http.StatusNotFound, "Metric definition not found", nil)
+			return
+		}
+		handleError("gin", c, http.StatusInternalServerError, "Failed to retrieve metric definition", err)
+		return
+	}
+	c.JSON(http.StatusOK, def)
+}
+
+// UpdateMetricDefinitionGin godoc
+// @Summary Update a custom metric
+// @Description Modifies a custom metric definition. Name and type are locked once values are recorded.
+// @Tags metrics
+// @Accept json
+// @Produce json
+// @Param id path int true "Metric definition ID"
+// @Param metric body MetricDefinitionRequest true "Updated metric definition"
+// @Success 200 {object} model.MetricDefinition "Updated metric definition"
+// @Failure 400 {object} map[string]string "Invalid input or ID format"
+// @Failure 404 {object} map[string]string "Metric definition not found"
+// @Router /api/v1/metrics/{id} [put]
+func (mh *MetricHandler) UpdateMetricDefinitionGin(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		handleError("gin", c, http.StatusBadRequest, "Invalid metric definition ID", err)
+		return
+	}
+	var req MetricDefinitionRequest
+	if err := bindJSONGin(c, &req); err != nil {
+		handleError("gin", c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	def := req.toModel()
+	updated, err := mh.Service.UpdateMetricDefinition(uint(id), &def)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			handleError("gin", c, http.StatusNotFound, "Metric definition not found to update", nil)
+			return
+		}
+		handleError("gin", c, http.StatusBadRequest, "Failed to update metric definition", err)
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// DeleteMetricDefinitionGin godoc
+// @Summary Delete a custom metric
+// @Description Removes a custom metric definition and all values recorded for it.
+// @Tags metrics
+// @Produce json
+// @Param id path int true "Metric definition ID"
+// @Success 200 {object} map[string]string "Success message"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 404 {object} map[string]string "Metric definition not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/metrics/{id} [delete]
+func (mh *MetricHandler) DeleteMetricDefinitionGin(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		handleError("gin", c, http.StatusBadRequest, "Invalid metric definition ID", err)
+		return
+	}
+	if err := mh.Service.DeleteMetricDefinition(uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			handleError("gin", c, http.StatusNotFound, "Metric definition not found to delete", nil)
+			return
+		}
+		handleError("gin", c, http.StatusInternalServerError, "Failed to delete metric definition", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Metric definition deleted successfully"})
+}
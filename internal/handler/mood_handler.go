@@ -0,0 +1,309 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/model"
+	"github.com/aebalz/daily-vibe-tracker/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// MoodHandler handles mood catalog requests.
+type MoodHandler struct {
+	Service service.MoodServiceInterface
+}
+
+// NewMoodHandler creates a new MoodHandler.
+func NewMoodHandler(svc service.MoodServiceInterface) *MoodHandler {
+	return &MoodHandler{Service: svc}
+}
+
+// MoodRequest defines the expected body for creating or updating a mood.
+type MoodRequest struct {
+	Name    string   `json:"name" binding:"required"`
+	Aliases []string `json:"aliases"`
+	Valence float64  `json:"valence" binding:"min=-1,max=1"`
+	Arousal float64  `json:"arousal" binding:"min=0,max=1"`
+	Emoji   string   `json:"emoji"`
+	Color   string   `json:"color"`
+}
+
+func (r MoodRequest) toModel() model.Mood {
+	return model.Mood{
+		Name:    r.Name,
+		Aliases: r.Aliases,
+		Valence: r.Valence,
+		Arousal: r.Arousal,
+		Emoji:   r.Emoji,
+		Color:   r.Color,
+	}
+}
+
+// --- Fiber Handlers ---
+
+// CreateMoodFiber godoc
+// @Summary Add a mood to the catalog
+// @Description Adds a new canonical mood with aliases, valence/arousal scores, emoji and color.
+// @Tags moods
+// @Accept json
+// @Produce json
+// @Param mood body MoodRequest true "Mood to add"
+// @Success 201 {object} model.Mood "Created mood"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/moods [post]
+func (mh *MoodHandler) CreateMoodFiber(c *fiber.Ctx) error {
+	var req MoodRequest
+	if err := bindJSONFiber(c, &req); err != nil {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid request body", err)
+	}
+	mood := req.toModel()
+	created, err := mh.Service.CreateMood(&mood)
+	if err != nil {
+		return handleError("fiber", c, http.StatusBadRequest, "Failed to create mood", err)
+	}
+	return c.Status(http.StatusCreated).JSON(created)
+}
+
+// GetAllMoodsFiber godoc
+// @Summary List the mood catalog
+// @Description Retrieves all moods in the catalog.
+// @Tags moods
+// @Produce json
+// @Success 200 {array} model.Mood "Mood catalog"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/moods [get]
+func (mh *MoodHandler) GetAllMoodsFiber(c *fiber.Ctx) error {
+	moods, err := mh.Service.GetAllMoods()
+	if err != nil {
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to retrieve moods", err)
+	}
+	return c.JSON(moods)
+}
+
+// GetMoodByIDFiber godoc
+// @Summary Get a mood
+// @Description Retrieves a single mood from the catalog by its ID.
+// @Tags moods
+// @Produce json
+// @Param id path int true "Mood ID"
+// @Success 200 {object} model.Mood "Mood details"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 404 {object} map[string]string "Mood not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/moods/{id} [get]
+func (mh *MoodHandler) GetMoodByIDFiber(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil || id <= 0 {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid mood ID", err)
+	}
+	mood, err := mh.Service.GetMoodByID(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return handleError("fiber", c, http.StatusNotFound, "Mood not found", nil)
+		}
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to retrieve mood", err)
+	}
+	return c.JSON(mood)
+}
+
+// UpdateMoodFiber godoc
+// @Summary Update a mood
+// @Description Modifies an existing mood in the catalog.
+// @Tags moods
+// @Accept json
+// @Produce json
+// @Param id path int true "Mood ID"
+// @Param mood body MoodRequest true "Updated mood data"
+// @Success 200 {object} model.Mood "Updated mood"
+// @Failure 400 {object} map[string]string "Invalid input or ID format"
+// @Failure 404 {object} map[string]string "Mood not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/moods/{id} [put]
+func (mh *MoodHandler) UpdateMoodFiber(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil || id <= 0 {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid mood ID", err)
+	}
+	var req MoodRequest
+	if err := bindJSONFiber(c, &req); err != nil {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid request body", err)
+	}
+	mood := req.toModel()
+	updated, err := mh.Service.UpdateMood(uint(id), &mood)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return handleError("fiber", c, http.StatusNotFound, "Mood not found to update", nil)
+		}
+		return handleError("fiber", c, http.StatusBadRequest, "Failed to update mood", err)
+	}
+	return c.JSON(updated)
+}
+
+// DeleteMoodFiber godoc
+// @Summary Delete a mood
+// @Description Removes a mood from the catalog. Existing vibes keep their mood name.
+// @Tags moods
+// @Produce json
+// @Param id path int true "Mood ID"
+// @Success 200 {object} map[string]string "Success message"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 404 {object} map[string]string "Mood not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/moods/{id} [delete]
+func (mh *MoodHandler) DeleteMoodFiber(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil || id <= 0 {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid mood ID", err)
+	}
+	if err := mh.Service.DeleteMood(uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return handleError("fiber", c, http.StatusNotFound, "Mood not found to delete", nil)
+		}
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to delete mood", err)
+	}
+	return c.Status(http.StatusOK).JSON(fiber.Map{"message": "Mood deleted successfully"})
+}
+
+// --- Gin Handlers ---
+
+// CreateMoodGin godoc
+// @Summary Add a mood to the catalog
+// @Description Adds a new canonical mood with aliases, valence/arousal scores, emoji and color.
+// @Tags moods
+// @Accept json
+// @Produce json
+// @Param mood body MoodRequest true "Mood to add"
+// @Success 201 {object} model.Mood "Created mood"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/moods [post]
+func (mh *MoodHandler) CreateMoodGin(c *gin.Context) {
+	var req MoodRequest
+	if err := bindJSONGin(c, &req); err != nil {
+		handleError("gin", c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	mood := req.toModel()
+	created, err := mh.Service.CreateMood(&mood)
+	if err != nil {
+		handleError("gin", c, http.StatusBadRequest, "Failed to create mood", err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// GetAllMoodsGin godoc
+// @Summary List the mood catalog
+// @Description Retrieves all moods in the catalog.
+// @Tags moods
+// @Produce json
+// @Success 200 {array} model.Mood "Mood catalog"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/moods [get]
+func (mh *MoodHandler) GetAllMoodsGin(c *gin.Context) {
+	moods, err := mh.Service.GetAllMoods()
+	if err != nil {
+		handleError("gin", c, http.StatusInternalServerError, "Failed to retrieve moods", err)
+		return
+	}
+	c.JSON(http.StatusOK, moods)
+}
+
+// GetMoodByIDGin godoc
+// @Summary Get a mood
+// @Description Retrieves a single mood from the catalog by its ID.
+// @Tags moods
+// @Produce json
+// @Param id path int true "Mood ID"
+// @Success 200 {object} model.Mood "Mood details"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 404 {object} map[string]string "Mood not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/moods/{id} [get]
+func (mh *MoodHandler) GetMoodByIDGin(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		handleError("gin", c, http.StatusBadRequest, "Invalid mood ID", err)
+		return
+	}
+	mood, err := mh.Service.GetMoodByID(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			handleError("gin", c, http.StatusNotFound, "Mood not found", nil)
+			return
+		}
+		handleError("gin", c, http.StatusInternalServerError, "Failed to retrieve mood", err)
+		return
+	}
+	c.JSON(http.StatusOK, mood)
+}
+
+// UpdateMoodGin godoc
+// @Summary Update a mood
+// @Description Modifies an existing mood in the catalog.
+// @Tags moods
+// @Accept json
+// @Produce json
+// @Param id path int true "Mood ID"
+// @Param mood body MoodRequest true "Updated mood data"
+// @Success 200 {object} model.Mood "Updated mood"
+// @Failure 400 {object} map[string]string "Invalid input or ID format"
+// @Failure 404 {object} map[string]string "Mood not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/moods/{id} [put]
+func (mh *MoodHandler) UpdateMoodGin(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		handleError("gin", c, http.StatusBadRequest, "Invalid mood ID", err)
+		return
+	}
+	var req MoodRequest
+	if err := bindJSONGin(c, &req); err != nil {
+		handleError("gin", c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	mood := req.toModel()
+	updated, err := mh.Service.UpdateMood(uint(id), &mood)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			handleError("gin", c, http.StatusNotFound, "Mood not found to update", nil)
+			return
+		}
+		handleError("gin", c, http.StatusBadRequest, "Failed to update mood", err)
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// DeleteMoodGin godoc
+// @Summary Delete a mood
+// @Description Removes a mood from the catalog. Existing vibes keep their mood name.
+// @Tags moods
+// @Produce json
+// @Param id path int true "Mood ID"
+// @Success 200 {object} map[string]string "Success message"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 404 {object} map[string]string "Mood not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/moods/{id} [delete]
+func (mh *MoodHandler) DeleteMoodGin(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		handleError("gin", c, http.StatusBadRequest, "Invalid mood ID", err)
+		return
+	}
+	if err := mh.Service.DeleteMood(uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			handleError("gin", c, http.StatusNotFound, "Mood not found to delete", nil)
+			return
+		}
+		handleError("gin", c, http.StatusInternalServerError, "Failed to delete mood", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Mood deleted successfully"})
+}
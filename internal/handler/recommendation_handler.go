@@ -0,0 +1,469 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// RecommendationHandler handles activity recommendation requests.
+type RecommendationHandler struct {
+	Service service.RecommendationServiceInterface
+}
+
+// NewRecommendationHandler creates a new RecommendationHandler.
+func NewRecommendationHandler(svc service.RecommendationServiceInterface) *RecommendationHandler {
+	return &RecommendationHandler{Service: svc}
+}
+
+// NotInterestedRequest defines the expected body for marking an activity as not interesting.
+type NotInterestedRequest struct {
+	Activity string `json:"activity" binding:"required"`
+}
+
+// RecommendationFeedbackRequest defines the expected body for feedback on a served recommendation.
+type RecommendationFeedbackRequest struct {
+	Feedback string `json:"feedback" binding:"required"` // accepted, dismissed or did_it
+}
+
+// parseRecommendationStatsRange reads the optional start_date and end_date query parameters.
+func parseRecommendationStatsRange(query func(key string) string) (time.Time, time.Time, error) {
+	var startDate, endDate time.Time
+	if v := query("start_date"); v != "" {
+		parsed, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return startDate, endDate, fmt.Errorf("invalid start_date format. Use YYYY-MM-DD")
+		}
+		startDate = parsed
+	}
+	if v := query("end_date"); v != "" {
+		parsed, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return startDate, endDate, fmt.Errorf("invalid end_date format. Use YYYY-MM-DD")
+		}
+		endDate = parsed.Add(23*time.Hour + 59*time.Minute + 59*time.Second)
+	}
+	return startDate, endDate, nil
+}
+
+// parseRecommendationOptions reads the recommendation query parameters using the framework's query getter.
+func parseRecommendationOptions(query func(key string) string) (service.RecommendationOptions, error) {
+	var opts service.RecommendationOptions
+	if v := query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			return opts, fmt.Errorf("limit must be an integer")
+		}
+		opts.Limit = limit
+	}
+	if v := query("seed"); v != "" {
+		seed, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return opts, fmt.Errorf("seed must be an integer")
+		}
+		opts.Seed = seed
+	}
+	if v := query("date"); v != "" {
+		date, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return opts, fmt.Errorf("invalid date format. Use YYYY-MM-DD")
+		}
+		opts.Date = date
+	}
+	return opts, nil
+}
+
+// --- Fiber Handlers ---
+
+// GetTodaysRecommendationFiber godoc
+// @Summary Get today's activity recommendations
+// @Description Ranks activities by how much they lift mood and energy on past days (weighted toward recent history) and how well they fit today's weekday. Skips activities already logged today and those marked not interested.
+// @Tags vibes-analytics
+// @Produce json
+// @Param limit query int false "Number of suggestions (1-10)" default(3)
+// @Param seed query int false "Seed for tie-breaking; the same seed and data give the same ranking"
+// @Param date query string false "Recommend for this day instead of today (YYYY-MM-DD)"
+// @Success 200 {object} model.RecommendationResult "Ranked suggestions with explanations"
+// @Failure 400 {object} map[string]string "Invalid query parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/vibes/today [get]
+func (rh *RecommendationHandler) GetTodaysRecommendationFiber(c *fiber.Ctx) error {
+	opts, err := parseRecommendationOptions(func(key string) string { return c.Query(key) })
+	if err != nil {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid query parameters", err)
+	}
+	result, err := rh.Service.RecommendActivities(opts)
+	if err != nil {
+		if isClientError(err) {
+			return handleError("fiber", c, http.StatusBadRequest, "Invalid query parameters", err)
+		}
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to generate recommendation", err)
+	}
+	return c.JSON(result)
+}
+
+// GetNotInterestedFiber godoc
+// @Summary List activities marked not interested
+// @Description Lists the activities that are never recommended.
+// @Tags recommendations
+// @Produce json
+// @Success 200 {array} model.NotInterestedActivity "Not interested activities"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/recommendations/not-interested [get]
+func (rh *RecommendationHandler) GetNotInterestedFiber(c *fiber.Ctx) error {
+	entries, err := rh.Service.GetNotInterested()
+	if err != nil {
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to retrieve not interested activities", err)
+	}
+	return c.JSON(entries)
+}
+
+// AddNotInterestedFiber godoc
+// @Summary Mark an activity as not interested
+// @Description Stops an activity (or any of its aliases) from being recommended.
+// @Tags recommendations
+// @Accept json
+// @Produce json
+// @Param feedback body NotInterestedRequest true "Activity"
+// @Success 201 {object} model.NotInterestedActivity "Not interested entry"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/recommendations/not-interested [post]
+func (rh *RecommendationHandler) AddNotInterestedFiber(c *fiber.Ctx) error {
+	var req NotInterestedRequest
+	if err := bindJSONFiber(c, &req); err != nil {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid request body", err)
+	}
+	entry, err := rh.Service.AddNotInterested(req.Activity)
+	if err != nil {
+		if isClientError(err) {
+			return handleError("fiber", c, http.StatusBadRequest, "Failed to save feedback", err)
+		}
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to save feedback", err)
+	}
+	return c.Status(http.StatusCreated).JSON(entry)
+}
+
+// RemoveNotInterestedFiber godoc
+// @Summary Allow an activity to be recommended again
+// @Description Removes the not interested mark from an activity.
+// @Tags recommendations
+// @Produce json
+// @Param activity path string true "Activity name"
+// @Success 200 {object} map[string]string "Success message"
+// @Failure 404 {object} map[string]string "Activity is not marked not interested"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/recommendations/not-interested/{activity} [delete]
+func (rh *RecommendationHandler) RemoveNotInterestedFiber(c *fiber.Ctx) error {
+	activity, err := url.PathUnescape(c.Params("activity"))
+	if err != nil {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid activity", err)
+	}
+	if err := rh.Service.RemoveNotInterested(activity); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return handleError("fiber", c, http.StatusNotFound, "Activity is not marked not interested", nil)
+		}
+		if isClientError(err) {
+			return handleError("fiber", c, http.StatusBadRequest, "Invalid activity", err)
+		}
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to remove feedback", err)
+	}
+	return c.Status(http.StatusOK).JSON(fiber.Map{"message": "Activity can be recommended again"})
+}
+
+// GetRecommendationFiber godoc
+// @Summary Get a served recommendation
+// @Description Retrieves a served recommendation with its feedback and linked vibe.
+// @Tags recommendations
+// @Produce json
+// @Param id path int true "Recommendation ID"
+// @Success 200 {object} model.Recommendation "Recommendation"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 404 {object} map[string]string "Recommendation not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/recommendations/{id} [get]
+func (rh *RecommendationHandler) GetRecommendationFiber(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil || id <= 0 {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid recommendation ID", err)
+	}
+	recommendation, err := rh.Service.GetRecommendation(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return handleError("fiber", c, http.StatusNotFound, "Recommendation not found", nil)
+		}
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to retrieve recommendation", err)
+	}
+	return c.JSON(recommendation)
+}
+
+// SubmitFeedbackFiber godoc
+// @Summary Give feedback on a recommendation
+// @Description Records whether a served recommendation was accepted, dismissed or done. Feedback adjusts future rankings of the activity.
+// @Tags recommendations
+// @Accept json
+// @Produce json
+// @Param id path int true "Recommendation ID"
+// @Param feedback body RecommendationFeedbackRequest true "Feedback (accepted, dismissed, did_it)"
+// @Success 200 {object} model.Recommendation "Updated recommendation"
+// @Failure 400 {object} map[string]string "Invalid input or ID format"
+// @Failure 404 {object} map[string]string "Recommendation not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/recommendations/{id}/feedback [post]
+func (rh *RecommendationHandler) SubmitFeedbackFiber(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil || id <= 0 {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid recommendation ID", err)
+	}
+	var req RecommendationFeedbackRequest
+	if err := bindJSONFiber(c, &req); err != nil {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid request body", err)
+	}
+	recommendation, err := rh.Service.SubmitFeedback(uint(id), req.Feedback)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return handleError("fiber", c, http.StatusNotFound, "Recommendation not found", nil)
+		}
+		if isClientError(err) {
+			return handleError("fiber", c, http.StatusBadRequest, "Invalid feedback", err)
+		}
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to save feedback", err)
+	}
+	return c.JSON(recommendation)
+}
+
+// GetRecommendationStatsFiber godoc
+// @Summary Get recommendation feedback statistics
+// @Description Reports, per activity, how often recommendations were accepted and followed, and the outcome lift: the good-day rate on recommended days the activity was done divided by the rate on days it was not.
+// @Tags recommendations
+// @Produce json
+// @Param start_date query string false "Start of the range (YYYY-MM-DD); defaults to 90 days before end_date"
+// @Param end_date query string false "End of the range, inclusive (YYYY-MM-DD); defaults to today"
+// @Success 200 {object} model.RecommendationStats "Recommendation statistics"
+// @Failure 400 {object} map[string]string "Invalid query parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/recommendations/stats [get]
+func (rh *RecommendationHandler) GetRecommendationStatsFiber(c *fiber.Ctx) error {
+	startDate, endDate, err := parseRecommendationStatsRange(func(key string) string { return c.Query(key) })
+	if err != nil {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid query parameters", err)
+	}
+	stats, err := rh.Service.GetRecommendationStats(startDate, endDate)
+	if err != nil {
+		if isClientError(err) {
+			return handleError("fiber", c, http.StatusBadRequest, "Invalid query parameters", err)
+		}
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to retrieve recommendation statistics", err)
+	}
+	return c.JSON(stats)
+}
+
+// --- Gin Handlers ---
+
+// GetTodaysRecommendationGin godoc
+// @Summary Get today's activity recommendations
+// @Description Ranks activities by how much they lift mood and energy on past days (weighted toward recent history) and how well they fit today's weekday. Skips activities already logged today and those marked not interested.
+// @Tags vibes-analytics
+// @Produce json
+// @Param limit query int false "Number of suggestions (1-10)" default(3)
+// @Param seed query int false "Seed for tie-breaking; the same seed and data give the same ranking"
+// @Param date query string false "Recommend for this day instead of today (YYYY-MM-DD)"
+// @Success 200 {object} model.RecommendationResult "Ranked suggestions with explanations"
+// @Failure 400 {object} map[string]string "Invalid query parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/vibes/today [get]
+func (rh *RecommendationHandler) GetTodaysRecommendationGin(c *gin.Context) {
+	opts, err := parseRecommendationOptions(c.Query)
+	if err != nil {
+		handleError("gin", c, http.StatusBadRequest, "Invalid query parameters", err)
+		return
+	}
+	result, err := rh.Service.RecommendActivities(opts)
+	if err != nil {
+		if isClientError(err) {
+			handleError("gin", c, http.StatusBadRequest, "Invalid query parameters", err)
+			return
+		}
+		handleError("gin", c, http.StatusInternalServerError, "Failed to generate recommendation", err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// GetNotInterestedGin godoc
+// @Summary List activities marked not interested
+// @Description Lists the activities that are never recommended.
+// @Tags recommendations
+// @Produce json
+// @Success 200 {array} model.NotInterestedActivity "Not interested activities"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/recommendations/not-interested [get]
+func (rh *RecommendationHandler) GetNotInterestedGin(c *gin.Context) {
+	entries, err := rh.Service.GetNotInterested()
+	if err != nil {
+		handleError("gin", c, http.StatusInternalServerError, "Failed to retrieve not interested activities", err)
+		return
+	}
+	c.JSON(http.StatusOK, entries)
+}
+
+// AddNotInterestedGin godoc
+// @Summary Mark an activity as not interested
+// @Description Stops an activity (or any of its aliases) from being recommended.
+// @Tags recommendations
+// @Accept json
+// @Produce json
+// @Param feedback body NotInterestedRequest true "Activity"
+// @Success 201 {object} model.NotInterestedActivity "Not interested entry"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/recommendations/not-interested [post]
+func (rh *RecommendationHandler) AddNotInterestedGin(c *gin.Context) {
+	var req NotInterestedRequest
+	if err := bindJSONGin(c, &req); err != nil {
+		handleError("gin", c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	entry, err := rh.Service.AddNotInterested(req.Activity)
+	if err != nil {
+		if isClientError(err) {
+			handleError("gin", c, http.StatusBadRequest, "Failed to save feedback", err)
+			return
+		}
+		handleError("gin", c, http.StatusInternalServerError, "Failed to save feedback", err)
+		return
+	}
+	c.JSON(http.StatusCreated, entry)
+}
+
+// RemoveNotInterestedGin godoc
+// @Summary Allow an activity to be recommended again
+// @Description Removes the not interested mark from an activity.
+// @Tags recommendations
+// @Produce json
+// @Param activity path string true "Activity name"
+// @Success 200 {object} map[string]string "Success message"
+// @Failure 404 {object} map[string]string "Activity is not marked not interested"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/recommendations/not-interested/{activity} [delete]
+func (rh *RecommendationHandler) RemoveNotInterestedGin(c *gin.Context) {
+	if err := rh.Service.RemoveNotInterested(c.Param("activity")); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			handleError("gin", c, http.StatusNotFound, "Activity is not marked not interested", nil)
+			return
+		}
+		if isClientError(err) {
+			handleError("gin", c, http.StatusBadRequest, "Invalid activity", err)
+			return
+		}
+		handleError("gin", c, http.StatusInternalServerError, "Failed to remove feedback", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Activity can be recommended again"})
+}
+
+// GetRecommendationGin godoc
+// @Summary Get a served recommendation
+// @Description Retrieves a served recommendation with its feedback and linked vibe.
+// @Tags recommendations
+// @Produce json
+// @Param id path int true "Recommendation ID"
+// @Success 200 {object} model.Recommendation "Recommendation"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 404 {object} map[string]string "Recommendation not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/recommendations/{id} [get]
+func (rh *RecommendationHandler) GetRecommendationGin(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		handleError("gin", c, http.StatusBadRequest, "Invalid recommendation ID", err)
+		return
+	}
+	recommendation, err := rh.Service.GetRecommendation(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			handleError("gin", c, http.StatusNotFound, "Recommendation not found", nil)
+			return
+		}
+		handleError("gin", c, http.StatusInternalServerError, "Failed to retrieve recommendation", err)
+		return
+	}
+	c.JSON(http.StatusOK, recommendation)
+}
+
+// SubmitFeedbackGin godoc
+// @Summary Give feedback on a recommendation
+// @Description Records whether a served recommendation was accepted, dismissed or done. Feedback adjusts future rankings of the activity.
+// @Tags recommendations
+// @Accept json
+// @Produce json
+// @Param id path int true "Recommendation ID"
+// @Param feedback body RecommendationFeedbackRequest true "Feedback (accepted, dismissed, did_it)"
+// @Success 200 {object} model.Recommendation "Updated recommendation"
+// @Failure 400 {object} map[string]string "Invalid input or ID format"
+// @Failure 404 {object} map[string]string "Recommendation not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/recommendations/{id}/feedback [post]
+func (rh *RecommendationHandler) SubmitFeedbackGin(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		handleError("gin", c, http.StatusBadRequest, "Invalid recommendation ID", err)
+		return
+	}
+	var req RecommendationFeedbackRequest
+	if err := bindJSONGin(c, &req); err != nil {
+		handleError("gin", c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	recommendation, err := rh.Service.SubmitFeedback(uint(id), req.Feedback)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			handleError("gin", c, http.StatusNotFound, "Recommendation not found", nil)
+			return
+		}
+		if isClientError(err) {
+			handleError("gin", c, http.StatusBadRequest, "Invalid feedback", err)
+			return
+		}
+		handleError("gin", c, http.StatusInternalServerError, "Failed to save feedback", err)
+		return
+	}
+	c.JSON(http.StatusOK, recommendation)
+}
+
+// GetRecommendationStatsGin godoc
+// @Summary Get recommendation feedback statistics
+// @Description Reports, per activity, how often recommendations were accepted and followed, and the outcome lift: the good-day rate on recommended days the activity was done divided by the rate on days it was not.
+// @Tags recommendations
+// @Produce json
+// @Param start_date query string false "Start of the range (YYYY-MM-DD); defaults to 90 days before end_date"
+// @Param end_date query string false "End of the range, inclusive (YYYY-MM-DD); defaults to today"
+// @Success 200 {object} model.RecommendationStats "Recommendation statistics"
+// @Failure 400 {object} map[string]string "Invalid query parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/recommendations/stats [get]
+func (rh *RecommendationHandler) GetRecommendationStatsGin(c *gin.Context) {
+	startDate, endDate, err := parseRecommendationStatsRange(c.Query)
+	if err != nil {
+		handleError("gin", c, http.StatusBadRequest, "Invalid query parameters", err)
+		return
+	}
+	stats, err := rh.Service.GetRecommendationStats(startDate, endDate)
+	if err != nil {
+		if isClientError(err) {
+			handleError("gin", c, http.StatusBadRequest, "Invalid query parameters", err)
+			return
+		}
+		handleError("gin", c, http.StatusInternalServerError, "Failed to retrieve recommendation statistics", err)
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
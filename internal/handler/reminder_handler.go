@@ -0,0 +1,400 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/model"
+	"github.com/aebalz/daily-vibe-tracker/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// ReminderHandler handles reminder requests.
+type ReminderHandler struct {
+	Service service.ReminderServiceInterface
+}
+
+// NewReminderHandler creates a new ReminderHandler.
+func NewReminderHandler(svc service.ReminderServiceInterface) *ReminderHandler {
+	return &ReminderHandler{Service: svc}
+}
+
+// ReminderRequest defines the expected body for creating or updating a reminder.
+type ReminderRequest struct {
+	Recipient string   `json:"recipient" binding:"required"`
+	Channel   string   `json:"channel" binding:"required"` // webhook, smtp or stdout
+	Address   string   `json:"address"`                    // Webhook URL or email address
+	Time      string   `json:"time" binding:"required"`    // Local time, HH:MM
+	Days      []string `json:"days"`                       // mon..sun; empty means every day
+	Timezone  string   `json:"timezone"`                   // IANA time zone, defaults to UTC
+	Message   string   `json:"message"`
+	Enabled   *bool    `json:"enabled"` // Defaults to true
+}
+
+func (r ReminderRequest) toModel() model.Reminder {
+	return model.Reminder{
+		Recipient: r.Recipient,
+		Channel:   r.Channel,
+		Address:   r.Address,
+		Time:      r.Time,
+		Days:      r.Days,
+		Timezone:  r.Timezone,
+		Message:   r.Message,
+		Enabled:   r.Enabled == nil || *r.Enabled,
+	}
+}
+
+// PaginatedDeliveriesResponse defines the structure for a paginated reminder delivery log.
+type PaginatedDeliveriesResponse struct {
+	Data   []model.ReminderDelivery `json:"data"`
+	Total  int64                    `json:"total"`
+	Limit  int                      `json:"limit"`
+	Offset int                      `json:"offset"`
+}
+
+// --- Fiber Handlers ---
+
+// CreateReminderFiber godoc
+// @Summary Create a reminder
+// @Description Schedules a reminder to log the daily vibe at a local time on chosen weekdays. It only fires on days without a vibe.
+// @Tags reminders
+// @Accept json
+// @Produce json
+// @Param reminder body ReminderRequest true "Reminder"
+// @Success 201 {object} model.Reminder "Created reminder"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/reminders [post]
+func (rh *ReminderHandler) CreateReminderFiber(c *fiber.Ctx) error {
+	var req ReminderRequest
+	if err := bindJSONFiber(c, &req); err != nil {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid request body", err)
+	}
+	reminder := req.toModel()
+	created, err := rh.Service.CreateReminder(&reminder)
+	if err != nil {
+		if isClientError(err) {
+			return handleError("fiber", c, http.StatusBadRequest, "Failed to create reminder", err)
+		}
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to create reminder", err)
+	}
+	return c.Status(http.StatusCreated).JSON(created)
+}
+
+// GetAllRemindersFiber godoc
+// @Summary List reminders
+// @Description Retrieves all reminders.
+// @Tags reminders
+// @Produce json
+// @Success 200 {array} model.Reminder "Reminders"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/reminders [get]
+func (rh *ReminderHandler) GetAllRemindersFiber(c *fiber.Ctx) error {
+	reminders, err := rh.Service.GetAllReminders()
+	if err != nil {
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to retrieve reminders", err)
+	}
+	return c.JSON(reminders)
+}
+
+// GetReminderByIDFiber godoc
+// @Summary Get a reminder
+// @Description Retrieves a reminder by its ID.
+// @Tags reminders
+// @Produce json
+// @Param id path int true "Reminder ID"
+// @Success 200 {object} model.Reminder "Reminder"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 404 {object} map[string]string "Reminder not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/reminders/{id} [get]
+func (rh *ReminderHandler) GetReminderByIDFiber(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil || id <= 0 {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid reminder ID", err)
+	}
+	reminder, err := rh.Service.GetReminderByID(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return handleError("fiber", c, http.StatusNotFound, "Reminder not found", nil)
+		}
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to retrieve reminder", err)
+	}
+	return c.JSON(reminder)
+}
+
+// UpdateReminderFiber godoc
+// @Summary Update a reminder
+// @Description Replaces a reminder's schedule and delivery settings. Occurrences before the update do not fire.
+// @Tags reminders
+// @Accept json
+// @Produce json
+// @Param id path int true "Reminder ID"
+// @Param reminder body ReminderRequest true "Updated reminder"
+// @Success 200 {object} model.Reminder "Updated reminder"
+// @Failure 400 {object} map[string]string "Invalid input or ID format"
+// @Failure 404 {object} map[string]string "Reminder not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/reminders/{id} [put]
+func (rh *ReminderHandler) UpdateReminderFiber(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil || id <= 0 {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid reminder ID", err)
+	}
+	var req ReminderRequest
+	if err := bindJSONFiber(c, &req); err != nil {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid request body", err)
+	}
+	reminder := req.toModel()
+	updated, err := rh.Service.UpdateReminder(uint(id), &reminder)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return handleError("fiber", c, http.StatusNotFound, "Reminder not found", nil)
+		}
+		if isClientError(err) {
+			return handleError("fiber", c, http.StatusBadRequest, "Failed to update reminder", err)
+		}
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to update reminder", err)
+	}
+	return c.JSON(updated)
+}
+
+// DeleteReminderFiber godoc
+// @Summary Delete a reminder
+// @Description Removes a reminder and its delivery log.
+// @Tags reminders
+// @Produce json
+// @Param id path int true "Reminder ID"
+// @Success 200 {object} map[string]string "Success message"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 404 {object} map[string]string "Reminder not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/reminders/{id} [delete]
+func (rh *ReminderHandler) DeleteReminderFiber(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil || id <= 0 {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid reminder ID", err)
+	}
+	if err := rh.Service.DeleteReminder(uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return handleError("fiber", c, http.StatusNotFound, "Reminder not found", nil)
+		}
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to delete reminder", err)
+	}
+	return c.Status(http.StatusOK).JSON(fiber.Map{"message": "Reminder deleted successfully"})
+}
+
+// GetDeliveriesFiber godoc
+// @Summary Get the delivery log of a reminder
+// @Description Lists the scheduled occurrences of a reminder, most recent first, with their status (pending, sent, failed, skipped), attempts and last error.
+// @Tags reminders
+// @Produce json
+// @Param id path int true "Reminder ID"
+// @Param limit query int false "Pagination limit" default(10)
+// @Param offset query int false "Pagination offset" default(0)
+// @Success 200 {object} PaginatedDeliveriesResponse "Deliveries with pagination"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 404 {object} map[string]string "Reminder not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/reminders/{id}/deliveries [get]
+func (rh *ReminderHandler) GetDeliveriesFiber(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil || id <= 0 {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid reminder ID", err)
+	}
+	limit, _ := strconv.Atoi(c.Query("limit", strconv.Itoa(service.DefaultLimit)))
+	offset, _ := strconv.Atoi(c.Query("offset", strconv.Itoa(service.DefaultOffset)))
+
+	deliveries, total, err := rh.Service.GetDeliveries(uint(id), limit, offset)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return handleError("fiber", c, http.StatusNotFound, "Reminder not found", nil)
+		}
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to retrieve deliveries", err)
+	}
+	return c.JSON(PaginatedDeliveriesResponse{Data: deliveries, Total: total, Limit: limit, Offset: offset})
+}
+
+// --- Gin Handlers ---
+
+// CreateReminderGin godoc
+// @Summary Create a reminder
+// @Description Schedules a reminder to log the daily vibe at a local time on chosen weekdays. It only fires on days without a vibe.
+// @Tags reminders
+// @Accept json
+// @Produce json
+// @Param reminder body ReminderRequest true "Reminder"
+// @Success 201 {object} model.Reminder "Created reminder"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/reminders [post]
+func (rh *ReminderHandler) CreateReminderGin(c *gin.Context) {
+	var req ReminderRequest
+	if err := bindJSONGin(c, &req); err != nil {
+		handleError("gin", c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	reminder := req.toModel()
+	created, err := rh.Service.CreateReminder(&reminder)
+	if err != nil {
+		if isClientError(err) {
+			handleError("gin", c, http.StatusBadRequest, "Failed to create reminder", err)
+			return
+		}
+		handleError("gin", c, http.StatusInternalServerError, "Failed to create reminder", err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// GetAllRemindersGin godoc
+// @Summary List reminders
+// @Description Retrieves all reminders.
+// @Tags reminders
+// @Produce json
+// @Success 200 {array} model.Reminder "Reminders"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/reminders [get]
+func (rh *ReminderHandler) GetAllRemindersGin(c *gin.Context) {
+	reminders, err := rh.Service.GetAllReminders()
+	if err != nil {
+		handleError("gin", c, http.StatusInternalServerError, "Failed to retrieve reminders", err)
+		return
+	}
+	c.JSON(http.StatusOK, reminders)
+}
+
+// GetReminderByIDGin godoc
+// @Summary Get a reminder
+// @Description Retrieves a reminder by its ID.
+// @Tags reminders
+// @Produce json
+// @Param id path int true "Reminder ID"
+// @Success 200 {object} model.Reminder "Reminder"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 404 {object} map[string]string "Reminder not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/reminders/{id} [get]
+func (rh *ReminderHandler) GetReminderByIDGin(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		handleError("gin", c, http.StatusBadRequest, "Invalid reminder ID", err)
+		return
+	}
+	reminder, err := rh.Service.GetReminderByID(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			handleError("gin", c, http.StatusNotFound, "Reminder not found", nil)
+			return
+		}
+		handleError("gin", c, http.StatusInternalServerError, "Failed to retrieve reminder", err)
+		return
+	}
+	c.JSON(http.StatusOK, reminder)
+}
+
+// UpdateReminderGin godoc
+// @Summary Update a reminder
+// @Description Replaces a reminder's schedule and delivery settings. Occurrences before the update do not fire.
+// @Tags reminders
+// @Accept json
+// @Produce json
+// @Param id path int true "Reminder ID"
+// @Param reminder body ReminderRequest true "Updated reminder"
+// @Success 200 {object} model.Reminder "Updated reminder"
+// @Failure 400 {object} map[string]string "Invalid input or ID format"
+// @Failure 404 {object} map[string]string "Reminder not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/reminders/{id} [put]
+func (rh *ReminderHandler) UpdateReminderGin(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		handleError("gin", c, http.StatusBadRequest, "Invalid reminder ID", err)
+		return
+	}
+	var req ReminderRequest
+	if err := bindJSONGin(c, &req); err != nil {
+		handleError("gin", c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	reminder := req.toModel()
+	updated, err := rh.Service.UpdateReminder(uint(id), &reminder)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			handleError("gin", c, http.StatusNotFound, "Reminder not found", nil)
+			return
+		}
+		if isClientError(err) {
+			handleError("gin", c, http.StatusBadRequest, "Failed to update reminder", err)
+			return
+		}
+		handleError("gin", c, http.StatusInternalServerError, "Failed to update reminder", err)
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// DeleteReminderGin godoc
+// @Summary Delete a reminder
+// @Description Removes a reminder and its delivery log.
+// @Tags reminders
+// @Produce json
+// @Param id path int true "Reminder ID"
+// @Success 200 {object} map[string]string "Success message"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 404 {object} map[string]string "Reminder not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/reminders/{id} [delete]
+func (rh *ReminderHandler) DeleteReminderGin(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		handleError("gin", c, http.StatusBadRequest, "Invalid reminder ID", err)
+		return
+	}
+	if err := rh.Service.DeleteReminder(uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			handleError("gin", c, http.StatusNotFound, "Reminder not found", nil)
+			return
+		}
+		handleError("gin", c, http.StatusInternalServerError, "Failed to delete reminder", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Reminder deleted successfully"})
+}
+
+// GetDeliveriesGin godoc
+// @Summary Get the delivery log of a reminder
+// @Description Lists the scheduled occurrences of a reminder, most recent first, with their status (pending, sent, failed, skipped), attempts and last error.
+// @Tags reminders
+// @Produce json
+// @Param id path int true "Reminder ID"
+// @Param limit query int false "Pagination limit" default(10)
+// @Param offset query int false "Pagination offset" default(0)
+// @Success 200 {object} PaginatedDeliveriesResponse "Deliveries with pagination"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 404 {object} map[string]string "Reminder not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/reminders/{id}/deliveries [get]
+func (rh *ReminderHandler) GetDeliveriesGin(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		handleError("gin", c, http.StatusBadRequest, "Invalid reminder ID", err)
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(service.DefaultLimit)))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", strconv.Itoa(service.DefaultOffset)))
+
+	deliveries, total, err := rh.Service.GetDeliveries(uint(id), limit, offset)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			handleError("gin", c, http.StatusNotFound, "Reminder not found", nil)
+			return
+		}
+		handleError("gin", c, http.StatusInternalServerError, "Failed to retrieve deliveries", err)
+		return
+	}
+	c.JSON(http.StatusOK, PaginatedDeliveriesResponse{Data: deliveries, Total: total, Limit: limit, Offset: offset})
+}
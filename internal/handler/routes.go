@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofiber/adaptor/v2"
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/ratelimit"
+)
+
+// Route is an HTTP route of the API with its handler for each framework. NewFiberServer and
+// NewGinServer both register the table returned by VibeHandler.Routes, so the two servers
+// expose the same API.
+type Route struct {
+	Method string
+	Path   string // ":name" parameters, no trailing slash
+	Auth   bool   // Behind API key authentication
+	// RateLimit names the rate limit policy of the route; empty for the default policy
+	RateLimit string
+	Fiber     fiber.Handler
+	Gin       gin.HandlerFunc
+}
+
+// Routes returns the route table of the API. Groups whose handler is not set are left out.
+// The swagger UI, whose wrappers differ between the frameworks, is mounted by the servers.
+func (h *VibeHandler) Routes() []Route {
+	var routes []Route
+	add := func(method, path string, auth bool, f fiber.Handler, g gin.HandlerFunc) {
+		routes = append(routes, Route{Method: method, Path: path, Auth: auth, Fiber: f, Gin: g})
+	}
+	public := func(method, path string, f fiber.Handler, g gin.HandlerFunc) { add(method, path, false, f, g) }
+	api := func(method, path string, f fiber.Handler, g gin.HandlerFunc) { add(method, "/api/v1"+path, true, f, g) }
+
+	// Prometheus Metrics Endpoint
+	public(http.MethodGet, "/metrics", adaptor.HTTPHandler(promhttp.Handler()), gin.WrapH(promhttp.Handler()))
+
+	// Health Check Route
+	if h != nil && h.HealthHandler != nil {
+		public(http.MethodGet, "/health", h.HealthHandler.CheckHealthFiber, h.HealthHandler.CheckHealthGin)
+	} else {
+		public(http.MethodGet, "/health",
+			func(c *fiber.Ctx) error {
+				return c.Status(fiber.StatusOK).JSON(fiber.Map{"status": "initializing health handler"})
+			},
+			func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"status": "initializing health handler"})
+			})
+	}
+	if h == nil {
+		return routes
+	}
+
+	// GraphQL Route
+	if h.GraphQLHandler != nil {
+		add(http.MethodGet, "/graphql", true, h.GraphQLHandler.ExecuteFiber, h.GraphQLHandler.ExecuteGin)
+		add(http.MethodPost, "/graphql", true, h.GraphQLHandler.ExecuteFiber, h.GraphQLHandler.ExecuteGin)
+	}
+
+	// Vibe Routes
+	api(http.MethodPost, "/vibes", h.CreateVibeFiber, h.CreateVibeGin)
+	api(http.MethodGet, "/vibes", h.GetAllVibesFiber, h.GetAllVibesGin)
+	api(http.MethodGet, "/vibes/stats", h.GetVibeStatsFiber, h.GetVibeStatsGin)
+	if h.RecommendationHandler != nil {
+		api(http.MethodGet, "/vibes/today", h.RecommendationHandler.GetTodaysRecommendationFiber, h.RecommendationHandler.GetTodaysRecommendationGin)
+	}
+	api(http.MethodGet, "/vibes/streak", h.GetMoodStreakFiber, h.GetMoodStreakGin)
+	api(http.MethodGet, "/vibes/export", h.ExportVibesFiber, h.ExportVibesGin)
+	routes[len(routes)-1].RateLimit = ratelimit.PolicyExport
+	api(http.MethodPost, "/vibes/bulk", h.BulkImportVibesFiber, h.BulkImportVibesGin)
+	routes[len(routes)-1].RateLimit = ratelimit.PolicyBulk
+	if h.InsightHandler != nil {
+		api(http.MethodGet, "/vibes/insights/correlations", h.InsightHandler.GetCorrelationsFiber, h.InsightHandler.GetCorrelationsGin)
+		api(http.MethodGet, "/vibes/insights/forecast", h.InsightHandler.GetMoodForecastFiber, h.InsightHandler.GetMoodForecastGin)
+		api(http.MethodGet, "/vibes/insights/anomalies", h.InsightHandler.GetAnomaliesFiber, h.InsightHandler.GetAnomaliesGin)
+	}
+	api(http.MethodGet, "/vibes/:id", h.GetVibeByIDFiber, h.GetVibeByIDGin)
+	api(http.MethodPut, "/vibes/:id", h.UpdateVibeFiber, h.UpdateVibeGin)
+	api(http.MethodPatch, "/vibes/:id", h.PatchVibeFiber, h.PatchVibeGin)
+	api(http.MethodDelete, "/vibes/:id", h.DeleteVibeFiber, h.DeleteVibeGin)
+
+	if m := h.MoodHandler; m != nil {
+		api(http.MethodPost, "/moods", m.CreateMoodFiber, m.CreateMoodGin)
+		api(http.MethodGet, "/moods", m.GetAllMoodsFiber, m.GetAllMoodsGin)
+		api(http.MethodGet, "/moods/:id", m.GetMoodByIDFiber, m.GetMoodByIDGin)
+		api(http.MethodPut, "/moods/:id", m.UpdateMoodFiber, m.UpdateMoodGin)
+		api(http.MethodDelete, "/moods/:id", m.DeleteMoodFiber, m.DeleteMoodGin)
+	}
+
+	if a := h.ActivityHandler; a != nil {
+		api(http.MethodGet, "/activities", a.GetAllActivitiesFiber, a.GetAllActivitiesGin)
+		api(http.MethodPost, "/activities", a.CreateActivityFiber, a.CreateActivityGin)
+		api(http.MethodGet, "/activities/:id", a.GetActivityByIDFiber, a.GetActivityByIDGin)
+		api(http.MethodPut, "/activities/:id", a.UpdateActivityFiber, a.UpdateActivityGin)
+		api(http.MethodDelete, "/activities/:id", a.DeleteActivityFiber, a.DeleteActivityGin)
+		api(http.MethodPost, "/activities/:id/merge", a.MergeActivityFiber, a.MergeActivityGin)
+	}
+
+	if m := h.MetricHandler; m != nil {
+		api(http.MethodGet, "/metrics", m.GetAllMetricDefinitionsFiber, m.GetAllMetricDefinitionsGin)
+		api(http.MethodPost, "/metrics", m.CreateMetricDefinitionFiber, m.CreateMetricDefinitionGin)
+		api(http.MethodGet, "/metrics/:id", m.GetMetricDefinitionByIDFiber, m.GetMetricDefinitionByIDGin)
+		api(http.MethodPut, "/metrics/:id", m.UpdateMetricDefinitionFiber, m.UpdateMetricDefinitionGin)
+		api(http.MethodDelete, "/metrics/:id", m.DeleteMetricDefinitionFiber, m.DeleteMetricDefinitionGin)
+	}
+
+	if g := h.GoalHandler; g != nil {
+		api(http.MethodGet, "/goals", g.GetAllGoalsFiber, g.GetAllGoalsGin)
+		api(http.MethodPost, "/goals", g.CreateGoalFiber, g.CreateGoalGin)
+		api(http.MethodGet, "/goals/progress", g.GetAllGoalProgressFiber, g.GetAllGoalProgressGin)
+		api(http.MethodGet, "/goals/:id", g.GetGoalByIDFiber, g.GetGoalByIDGin)
+		api(http.MethodPut, "/goals/:id", g.UpdateGoalFiber, g.UpdateGoalGin)
+		api(http.MethodDelete, "/goals/:id", g.DeleteGoalFiber, g.DeleteGoalGin)
+		api(http.MethodGet, "/goals/:id/progress", g.GetGoalProgressFiber, g.GetGoalProgressGin)
+		api(http.MethodGet, "/goals/:id/history", g.GetGoalHistoryFiber, g.GetGoalHistoryGin)
+	}
+
+	if r := h.ReminderHandler; r != nil {
+		api(http.MethodGet, "/reminders", r.GetAllRemindersFiber, r.GetAllRemindersGin)
+		api(http.MethodPost, "/reminders", r.CreateReminderFiber, r.CreateReminderGin)
+		api(http.MethodGet, "/reminders/:id", r.GetReminderByIDFiber, r.GetReminderByIDGin)
+		api(http.MethodPut, "/reminders/:id", r.UpdateReminderFiber, r.UpdateReminderGin)
+		api(http.MethodDelete, "/reminders/:id", r.DeleteReminderFiber, r.DeleteReminderGin)
+		api(http.MethodGet, "/reminders/:id/deliveries", r.GetDeliveriesFiber, r.GetDeliveriesGin)
+	}
+
+	if w := h.WebhookHandler; w != nil {
+		api(http.MethodGet, "/webhooks", w.GetAllSubscriptionsFiber, w.GetAllSubscriptionsGin)
+		api(http.MethodPost, "/webhooks", w.CreateSubscriptionFiber, w.CreateSubscriptionGin)
+		api(http.MethodGet, "/webhooks/deliveries", w.GetDeliveriesFiber, w.GetDeliveriesGin)
+		api(http.MethodGet, "/webhooks/dead-letters", w.GetDeadLettersFiber, w.GetDeadLettersGin)
+		api(http.MethodPost, "/webhooks/deliveries/:id/redeliver", w.RedeliverFiber, w.RedeliverGin)
+		api(http.MethodGet, "/webhooks/:id", w.GetSubscriptionByIDFiber, w.GetSubscriptionByIDGin)
+		api(http.MethodPut, "/webhooks/:id", w.UpdateSubscriptionFiber, w.UpdateSubscriptionGin)
+		api(http.MethodDelete, "/webhooks/:id", w.DeleteSubscriptionFiber, w.DeleteSubscriptionGin)
+		api(http.MethodPost, "/webhooks/:id/ping", w.PingSubscriptionFiber, w.PingSubscriptionGin)
+	}
+
+	if s := h.StreamHandler; s != nil {
+		api(http.MethodGet, "/stream", s.StreamFiber, s.StreamGin)
+		api(http.MethodGet, "/stream/ws", s.StreamWebSocketFiber, s.StreamWebSocketGin)
+	}
+
+	if r := h.RecommendationHandler; r != nil {
+		api(http.MethodGet, "/recommendations/not-interested", r.GetNotInterestedFiber, r.GetNotInterestedGin)
+		api(http.MethodPost, "/recommendations/not-interested", r.AddNotInterestedFiber, r.AddNotInterestedGin)
+		api(http.MethodDelete, "/recommendations/not-interested/:activity", r.RemoveNotInterestedFiber, r.RemoveNotInterestedGin)
+		api(http.MethodGet, "/recommendations/stats", r.GetRecommendationStatsFiber, r.GetRecommendationStatsGin)
+		api(http.MethodGet, "/recommendations/:id", r.GetRecommendationFiber, r.GetRecommendationGin)
+		api(http.MethodPost, "/recommendations/:id/feedback", r.SubmitFeedbackFiber, r.SubmitFeedbackGin)
+	}
+
+	return routes
+}
+
+// AllowedMethods returns the methods of the routes matching a request path, for the Allow
+// header of 405 responses. GET routes also answer HEAD. A trailing slash is ignored.
+func AllowedMethods(routes []Route, path string) []string {
+	seen := make(map[string]bool)
+	for _, r := range routes {
+		if !matchPath(r.Path, path) {
+			continue
+		}
+		seen[r.Method] = true
+		if r.Method == http.MethodGet {
+			seen[http.MethodHead] = true
+		}
+	}
+	methods := make([]string, 0, len(seen))
+	for m := range seen {
+		methods = append(methods, m)
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// matchPath reports whether a path matches a route pattern, where a ":name" segment matches
+// any non-empty segment.
+func matchPath(pattern, path string) bool {
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	want := strings.Split(pattern, "/")
+	got := strings.Split(path, "/")
+	if len(want) != len(got) {
+		return false
+	}
+	for i, segment := range want {
+		if strings.HasPrefix(segment, ":") {
+			if got[i] == "" {
+				return false
+			}
+		} else if segment != got[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,366 @@
+package handler
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/logging"
+	"github.com/aebalz/daily-vibe-tracker/internal/service"
+	"github.com/aebalz/daily-vibe-tracker/internal/stream"
+	"github.com/gin-gonic/gin"
+	fiberws "github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gorilla/websocket"
+)
+
+// UserIDKey is the Fiber local / Gin context key holding the authenticated user.
+// Stream connections of anonymous clients count as stream.AnonymousUser.
+const UserIDKey = "user_id"
+
+// streamRetry is the reconnection delay suggested to SSE clients.
+const streamRetry = 3 * time.Second
+
+// wsWriteTimeout bounds every WebSocket write, so a stalled peer cannot block the stream.
+const wsWriteTimeout = 10 * time.Second
+
+// StreamHandler handles the real-time SSE and WebSocket streams.
+type StreamHandler struct {
+	Service         service.StreamServiceInterface
+	Logger          *slog.Logger
+	upgrader        websocket.Upgrader
+	allowAllOrigins bool
+	origins         map[string]bool // Origins allowed to open WebSockets, from CORS_ALLOWED_ORIGINS
+}
+
+// NewStreamHandler creates a new StreamHandler. WebSocket handshakes are accepted from the
+// allowed origins ("*" for any), as CORS requests are. A nil logger logs to the default logger.
+func NewStreamHandler(svc service.StreamServiceInterface, allowedOrigins []string, logger *slog.Logger) *StreamHandler {
+	h := &StreamHandler{Service: svc, Logger: logging.OrDefault(logger), origins: make(map[string]bool)}
+	for _, origin := range allowedOrigins {
+		origin = strings.TrimSpace(origin)
+		if origin == "*" {
+			h.allowAllOrigins = true
+		}
+		h.origins[strings.TrimSuffix(origin, "/")] = true
+	}
+	h.upgrader = websocket.Upgrader{CheckOrigin: func(r *http.Request) bool {
+		return h.originAllowed(r.Header.Get("Origin"), r.Host)
+	}}
+	return h
+}
+
+// originAllowed tells whether a WebSocket handshake from origin may open a stream. CORS does
+// not apply to WebSockets, so without this check any web page could read the stream through
+// the browser of its visitors. Clients that are not browsers send no Origin and are allowed,
+// as are pages served from the host of the API itself.
+func (h *StreamHandler) originAllowed(origin, host string) bool {
+	if origin == "" || h.allowAllOrigins || h.origins[strings.TrimSuffix(origin, "/")] {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host != "" && strings.EqualFold(u.Host, host)
+}
+
+// lastEventID reads the resume point from the Last-Event-ID header, falling back to the
+// last_event_id query parameter for clients that cannot set headers.
+func lastEventID(header, query string) string {
+	if header != "" {
+		return header
+	}
+	return query
+}
+
+func streamUser(user string) string {
+	if user == "" {
+		return stream.AnonymousUser
+	}
+	return user
+}
+
+// streamBacklog returns what a new client receives before live events: a resync notice when
+// events were lost, the missed events and the current summary.
+func (h *StreamHandler) streamBacklog(missed []stream.Message, complete bool) ([]stream.Message, error) {
+	var backlog []stream.Message
+	if !complete {
+		msg, err := stream.NewMessage(stream.EventResync, map[string]string{"reason": "events were missed beyond the replay buffer"})
+		if err != nil {
+			return nil, err
+		}
+		backlog = append(backlog, msg)
+	}
+	backlog = append(backlog, missed...)
+
+	summary, err := h.Service.GetLiveSummary(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	msg, err := stream.NewMessage(service.StreamEventSummary, summary)
+	if err != nil {
+		return nil, err
+	}
+	return append(backlog, msg), nil
+}
+
+// subscribe connects a client and prepares its backlog, mapping the errors to a status code.
+func (h *StreamHandler) subscribe(user, lastID string) (*stream.Client, []stream.Message, int, error) {
+	client, missed, complete, err := h.Service.Subscribe(user, lastID)
+	if err != nil {
+		if errors.Is(err, stream.ErrTooManyConnections) {
+			return nil, nil, http.StatusTooManyRequests, err
+		}
+		return nil, nil, http.StatusServiceUnavailable, err
+	}
+	backlog, err := h.streamBacklog(missed, complete)
+	if err != nil {
+		h.Service.Unsubscribe(client)
+		return nil, nil, http.StatusInternalServerError, err
+	}
+	return client, backlog, http.StatusOK, nil
+}
+
+// writeSSE writes the stream to w until the client leaves or is disconnected by the hub.
+// flush pushes buffered output to the client; its error reveals a closed connection.
+func (h *StreamHandler) writeSSE(w io.Writer, flush func() error, done <-chan struct{}, client *stream.Client, backlog []stream.Message) {
+	defer h.Service.Unsubscribe(client)
+
+	if _, err := fmt.Fprintf(w, "retry: %d\n\n", streamRetry.Milliseconds()); err != nil {
+		return
+	}
+	for _, msg := range backlog {
+		if err := stream.WriteSSE(w, msg); err != nil {
+			return
+		}
+	}
+	if err := flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(h.Service.HeartbeatInterval())
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case msg, ok := <-client.C:
+			if !ok {
+				return
+			}
+			if err := stream.WriteSSE(w, msg); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		if err := flush(); err != nil {
+			return
+		}
+	}
+}
+
+// wsConn is the part of a WebSocket connection the stream uses; both the gorilla and the
+// Fiber connections provide it.
+type wsConn interface {
+	WriteJSON(v interface{}) error
+	WriteControl(messageType int, data []byte, deadline time.Time) error
+	ReadMessage() (messageType int, p []byte, err error)
+	SetReadDeadline(t time.Time) error
+	SetWriteDeadline(t time.Time) error
+	SetPongHandler(h func(appData string) error)
+	Close() error
+}
+
+// serveWebSocket streams messages as JSON text frames. Heartbeats are ping frames; a client
+// that answers neither with a pong nor with any message within two intervals is dropped.
+func (h *StreamHandler) serveWebSocket(conn wsConn, user, lastID string) {
+	defer conn.Close()
+
+	client, backlog, code, err := h.subscribe(user, lastID)
+	if err != nil {
+		closeCode := websocket.CloseInternalServerErr
+		if code == http.StatusTooManyRequests || code == http.StatusServiceUnavailable {
+			closeCode = websocket.CloseTryAgainLater
+		}
+		_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(closeCode, err.Error()), time.Now().Add(wsWriteTimeout))
+		return
+	}
+	defer h.Service.Unsubscribe(client)
+
+	interval := h.Service.HeartbeatInterval()
+	_ = conn.SetReadDeadline(time.Now().Add(2 * interval))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(2 * interval))
+	})
+
+	// Incoming messages are ignored; reading processes pongs and notices when the client leaves.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+			_ = conn.SetReadDeadline(time.Now().Add(2 * interval))
+		}
+	}()
+
+	send := func(msg stream.Message) error {
+		_ = conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+		return conn.WriteJSON(msg)
+	}
+	for _, msg := range backlog {
+		if err := send(msg); err != nil {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(interval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-closed:
+			return
+		case msg, ok := <-client.C:
+			if !ok {
+				_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "stream closed"), time.Now().Add(wsWriteTimeout))
+				return
+			}
+			if err := send(msg); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// --- Fiber Handlers ---
+
+// StreamFiber godoc
+// @Summary Stream vibe updates (SSE)
+// @Description Server-Sent Events stream of vibe.created, vibe.updated, vibe.deleted and bulk.imported events, each followed by a refreshed `summary` of today's vibe and streaks. A summary is also sent on connect. Reconnect with the Last-Event-ID header (or last_event_id query parameter) to receive missed events; a `resync` event means some were no longer buffered. Idle connections get a heartbeat comment.
+// @Tags stream
+// @Produce text/event-stream
+// @Param Last-Event-ID header string false "ID of the last event received"
+// @Param last_event_id query string false "Alternative to the Last-Event-ID header"
+// @Success 200 {string} string "Event stream"
+// @Failure 429 {object} map[string]string "Too many stream connections"
+// @Failure 503 {object} map[string]string "Server is shutting down"
+// @Router /api/v1/stream [get]
+func (h *StreamHandler) StreamFiber(c *fiber.Ctx) error {
+	user, _ := c.Locals(UserIDKey).(string)
+	client, backlog, code, err := h.subscribe(streamUser(user), lastEventID(c.Get("Last-Event-ID"), c.Query("last_event_id")))
+	if err != nil {
+		return handleError("fiber", c, code, "Failed to open stream", err)
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no") // Disable proxy buffering, e.g. in nginx
+	// The writer runs after the handler returns, so it must not use c. A failed flush means
+	// the client is gone.
+	conn := c.Context().Conn()
+	ctx := c.UserContext()
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		// Streams outlive the server's write timeout, which fasthttp sets before writing the body.
+		if err := conn.SetWriteDeadline(time.Time{}); err != nil {
+			h.Logger.WarnContext(ctx, "Could not clear the write deadline of a stream", slog.String("error", err.Error()))
+		}
+		h.writeSSE(w, w.Flush, nil, client, backlog)
+	})
+	return nil
+}
+
+// StreamWebSocketFiber godoc
+// @Summary Stream vibe updates (WebSocket)
+// @Description WebSocket variant of /api/v1/stream. Every event is a JSON text frame {id, event, data, at}. Pass last_event_id to resume. Heartbeats are ping frames. Connections over the limit are closed with code 1013.
+// @Tags stream
+// @Param last_event_id query string false "ID of the last event received"
+// @Success 101 {string} string "Switching Protocols"
+// @Failure 403 {object} map[string]string "Origin not allowed"
+// @Failure 426 {object} map[string]string "WebSocket upgrade required"
+// @Router /api/v1/stream/ws [get]
+func (h *StreamHandler) StreamWebSocketFiber(c *fiber.Ctx) error {
+	if !fiberws.IsWebSocketUpgrade(c) {
+		return handleError("fiber", c, http.StatusUpgradeRequired, "WebSocket upgrade required", nil)
+	}
+	// Checked here rather than with the Origins of fiberws, which rejects clients without
+	// an Origin header, so that both servers answer alike.
+	if !h.originAllowed(c.Get(fiber.HeaderOrigin), c.Hostname()) {
+		return handleError("fiber", c, http.StatusForbidden, "Origin not allowed", nil)
+	}
+	return fiberws.New(func(conn *fiberws.Conn) {
+		user, _ := conn.Locals(UserIDKey).(string)
+		h.serveWebSocket(conn, streamUser(user), lastEventID(conn.Headers("Last-Event-ID"), conn.Query("last_event_id")))
+	})(c)
+}
+
+// --- Gin Handlers ---
+
+// StreamGin godoc
+// @Summary Stream vibe updates (SSE)
+// @Description Server-Sent Events stream of vibe.created, vibe.updated, vibe.deleted and bulk.imported events, each followed by a refreshed `summary` of today's vibe and streaks. A summary is also sent on connect. Reconnect with the Last-Event-ID header (or last_event_id query parameter) to receive missed events; a `resync` event means some were no longer buffered. Idle connections get a heartbeat comment.
+// @Tags stream
+// @Produce text/event-stream
+// @Param Last-Event-ID header string false "ID of the last event received"
+// @Param last_event_id query string false "Alternative to the Last-Event-ID header"
+// @Success 200 {string} string "Event stream"
+// @Failure 429 {object} map[string]string "Too many stream connections"
+// @Failure 503 {object} map[string]string "Server is shutting down"
+// @Router /api/v1/stream [get]
+func (h *StreamHandler) StreamGin(c *gin.Context) {
+	client, backlog, code, err := h.subscribe(streamUser(c.GetString(UserIDKey)), lastEventID(c.GetHeader("Last-Event-ID"), c.Query("last_event_id")))
+	if err != nil {
+		handleError("gin", c, code, "Failed to open stream", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Disable proxy buffering, e.g. in nginx
+	c.Status(http.StatusOK)
+
+	rc := http.NewResponseController(c.Writer)
+	// Streams outlive the server's write timeout.
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		h.Logger.WarnContext(c.Request.Context(), "Could not clear the write deadline of a stream", slog.String("error", err.Error()))
+	}
+	h.writeSSE(c.Writer, rc.Flush, c.Request.Context().Done(), client, backlog)
+}
+
+// StreamWebSocketGin godoc
+// @Summary Stream vibe updates (WebSocket)
+// @Description WebSocket variant of /api/v1/stream. Every event is a JSON text frame {id, event, data, at}. Pass last_event_id to resume. Heartbeats are ping frames. Connections over the limit are closed with code 1013.
+// @Tags stream
+// @Param last_event_id query string false "ID of the last event received"
+// @Success 101 {string} string "Switching Protocols"
+// @Failure 403 {object} map[string]string "Origin not allowed"
+// @Failure 426 {object} map[string]string "WebSocket upgrade required"
+// @Router /api/v1/stream/ws [get]
+func (h *StreamHandler) StreamWebSocketGin(c *gin.Context) {
+	if !websocket.IsWebSocketUpgrade(c.Request) {
+		handleError("gin", c, http.StatusUpgradeRequired, "WebSocket upgrade required", nil)
+		return
+	}
+	if !h.originAllowed(c.GetHeader("Origin"), c.Request.Host) {
+		handleError("gin", c, http.StatusForbidden, "Origin not allowed", nil)
+		return
+	}
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return // The upgrader has already replied with an error
+	}
+	h.serveWebSocket(conn, streamUser(c.GetString(UserIDKey)), lastEventID(c.GetHeader("Last-Event-ID"), c.Query("last_event_id")))
+}
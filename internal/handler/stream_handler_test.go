@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/config"
+	"github.com/aebalz/daily-vibe-tracker/internal/events"
+	"github.com/aebalz/daily-vibe-tracker/internal/model"
+	"github.com/aebalz/daily-vibe-tracker/internal/repository"
+	"github.com/aebalz/daily-vibe-tracker/internal/service"
+	"github.com/aebalz/daily-vibe-tracker/internal/stream"
+	"github.com/gin-gonic/gin"
+	"github.com/gofiber/fiber/v2"
+)
+
+// streamUsers authenticates API keys from a map of key to username.
+type streamUsers map[string]string
+
+func (u streamUsers) CreateUser(user *model.User) (*model.User, error) { return user, nil }
+
+func (u streamUsers) CreateAPIKey(username, name string, expiresAt *time.Time) (string, *model.APIKey, error) {
+	return "", nil, nil
+}
+
+func (u streamUsers) WithContext(ctx context.Context) service.UserServiceInterface { return u }
+
+func (u streamUsers) Authenticate(key string) (*model.User, error) {
+	username, ok := u[key]
+	if !ok {
+		return nil, service.ErrInvalidAPIKey
+	}
+	return &model.User{Username: username}, nil
+}
+
+// emptyVibeRepo is a repository without vibes, enough for the live summary.
+type emptyVibeRepo struct {
+	repository.VibeRepositoryInterface
+}
+
+func (emptyVibeRepo) GetVibesForDateRange(start, end time.Time) ([]model.Vibe, error) {
+	return nil, nil
+}
+
+// newStreamTestService returns a stream service with a hub allowing one connection per
+// user. The hub is closed at the end of the test, before the server, so open streams end
+// as on shutdown.
+func newStreamTestService(t *testing.T, closeServer func()) service.StreamServiceInterface {
+	hub := stream.NewHub(10, 1, 10, 16)
+	t.Cleanup(closeServer)
+	t.Cleanup(hub.Close)
+	return service.NewStreamService(hub, emptyVibeRepo{}, &config.AppConfig{StreamHeartbeatInterval: time.Minute})
+}
+
+// openStream opens an SSE stream with the API key and returns its events, after the
+// initial summary has been read.
+func openStream(t *testing.T, url, key string) <-chan string {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set(APIKeyHeader, key)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET %s: status %d, want 200", url, resp.StatusCode)
+	}
+
+	names := make(chan string, 16)
+	go func() {
+		defer close(names)
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if name, ok := strings.CutPrefix(scanner.Text(), "event: "); ok {
+				names <- name
+			}
+		}
+	}()
+	if name := nextEvent(t, names); name != service.StreamEventSummary {
+		t.Fatalf("first event %q, want %q", name, service.StreamEventSummary)
+	}
+	return names
+}
+
+func nextEvent(t *testing.T, names <-chan string) string {
+	t.Helper()
+	select {
+	case name, ok := <-names:
+		if !ok {
+			t.Fatal("stream ended")
+		}
+		return name
+	case <-time.After(5 * time.Second):
+		t.Fatal("no stream event within 5s")
+	}
+	return ""
+}
+
+// checkAuthenticatedStream checks that a client authenticated as alex receives vibe
+// events, and that the connection counts against the limit of alex.
+func checkAuthenticatedStream(t *testing.T, svc service.StreamServiceInterface, url string) {
+	names := openStream(t, url, "alex-key")
+
+	if err := svc.HandleEvent(context.Background(), events.VibeCreated{Vibe: model.Vibe{ID: 1, Mood: "happy"}}); err != nil {
+		t.Fatal(err)
+	}
+	if name := nextEvent(t, names); name != events.TypeVibeCreated {
+		t.Fatalf("event %q, want %q", name, events.TypeVibeCreated)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	req.Header.Set(APIKeyHeader, "alex-key")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("second stream of alex: status %d, want 429", resp.StatusCode)
+	}
+}
+
+func TestStreamGinAuthenticated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	server := httptest.NewServer(router)
+	svc := newStreamTestService(t, server.Close)
+	auth := NewAuthHandler(streamUsers{"alex-key": "alex"}, true)
+	streams := NewStreamHandler(svc, []string{"*"}, nil)
+	router.GET("/stream", auth.AuthenticateGin, streams.StreamGin)
+
+	checkAuthenticatedStream(t, svc, server.URL+"/stream")
+}
+
+func TestStreamFiberAuthenticated(t *testing.T) {
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	svc := newStreamTestService(t, func() { _ = app.Shutdown() })
+	auth := NewAuthHandler(streamUsers{"alex-key": "alex"}, true)
+	streams := NewStreamHandler(svc, []string{"*"}, nil)
+	app.Get("/stream", auth.AuthenticateFiber, streams.StreamFiber)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() { _ = app.Listener(listener) }()
+
+	checkAuthenticatedStream(t, svc, "http://"+listener.Addr().String()+"/stream")
+}
@@ -0,0 +1,363 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/model"
+	"github.com/aebalz/daily-vibe-tracker/internal/service"
+	vibev1 "github.com/aebalz/daily-vibe-tracker/pkg/proto/vibe/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+// statisticsAnalyticsKeys are the statistics entries computed by the service rather than the
+// repository. gRPC returns them in Statistics.analytics.
+var statisticsAnalyticsKeys = []string{"mood_patterns", "mood_energy_correlation", "activity_mood_correlation"}
+
+// VibeGRPCHandler serves the vibe gRPC API on top of VibeServiceInterface.
+type VibeGRPCHandler struct {
+	vibev1.UnimplementedVibeServiceServer
+	Service service.VibeServiceInterface
+}
+
+// NewVibeGRPCHandler creates a new VibeGRPCHandler.
+func NewVibeGRPCHandler(s service.VibeServiceInterface) *VibeGRPCHandler {
+	return &VibeGRPCHandler{Service: s}
+}
+
+// grpcError maps service errors to gRPC status codes, like handleError does for HTTP.
+func grpcError(msg string, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return status.Error(codes.NotFound, "Vibe not found")
+	case isClientError(err):
+		return status.Errorf(codes.InvalidArgument, "%s: %v", msg, err)
+	default:
+		return status.Errorf(codes.Internal, "%s: %v", msg, err)
+	}
+}
+
+// vibeToProto converts a vibe to its protobuf form.
+func vibeToProto(v *model.Vibe) (*vibev1.Vibe, error) {
+	pb := &vibev1.Vibe{
+		Id:          uint64(v.ID),
+		Date:        timestamppb.New(v.Date),
+		Mood:        v.Mood,
+		EnergyLevel: int32(v.EnergyLevel),
+		Notes:       v.Notes,
+		Activities:  v.Activities,
+		CreatedAt:   timestamppb.New(v.CreatedAt),
+		UpdatedAt:   timestamppb.New(v.UpdatedAt),
+	}
+	if len(v.Metrics) > 0 {
+		metrics, err := structpb.NewStruct(v.Metrics)
+		if err != nil {
+			return nil, fmt.Errorf("could not convert metrics of vibe %d: %w", v.ID, err)
+		}
+		pb.Metrics = metrics
+	}
+	return pb, nil
+}
+
+// vibeFromInput converts the writable fields of a request to a vibe. Metrics stays nil when
+// the input has none, so updates keep the current values.
+func vibeFromInput(in *vibev1.VibeInput) *model.Vibe {
+	v := &model.Vibe{
+		Mood:        in.GetMood(),
+		EnergyLevel: int(in.GetEnergyLevel()),
+		Notes:       in.GetNotes(),
+		Activities:  in.GetActivities(),
+	}
+	if in.GetDate() != nil {
+		v.Date = in.GetDate().AsTime()
+	}
+	if in.GetMetrics() != nil {
+		v.Metrics = in.GetMetrics().AsMap()
+	}
+	return v
+}
+
+// validateVibeInput runs the same required-field checks as the HTTP create handlers.
+func validateVibeInput(in *vibev1.VibeInput) error {
+	if in == nil || in.GetDate() == nil || in.GetMood() == "" || in.GetEnergyLevel() < 1 || in.GetEnergyLevel() > 10 {
+		return errors.New("missing required fields or invalid energy level")
+	}
+	if err := in.GetDate().CheckValid(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// vibeFilters converts a filter message to the filter map the service expects.
+func vibeFilters(f *vibev1.VibeFilter) map[string]interface{} {
+	filters := make(map[string]interface{})
+	if f.GetDate() != "" {
+		filters["date"] = f.GetDate()
+	}
+	if f.GetMood() != "" {
+		filters["mood"] = f.GetMood()
+	}
+	if len(f.GetMetrics()) > 0 {
+		filters["metric_filters"] = f.GetMetrics()
+	}
+	return filters
+}
+
+// validateVibeFilter checks the date filter, which the service does not parse itself.
+func validateVibeFilter(f *vibev1.VibeFilter) error {
+	if f.GetDate() == "" {
+		return nil
+	}
+	if _, err := time.Parse("2006-01-02", f.GetDate()); err != nil {
+		return status.Error(codes.InvalidArgument, "Invalid date format for 'date' filter. Use YYYY-MM-DD.")
+	}
+	return nil
+}
+
+// CreateVibe creates a vibe.
+func (h *VibeGRPCHandler) CreateVibe(ctx context.Context, req *vibev1.CreateVibeRequest) (*vibev1.Vibe, error) {
+	if err := validateVibeInput(req.GetVibe()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Failed to create vibe: %v", err)
+	}
+	created, err := h.Service.WithContext(ctx).CreateVibe(vibeFromInput(req.GetVibe()))
+	if err != nil {
+		return nil, grpcError("Failed to create vibe", err)
+	}
+	return vibeToProto(created)
+}
+
+// GetVibe returns a vibe by ID.
+func (h *VibeGRPCHandler) GetVibe(ctx context.Context, req *vibev1.GetVibeRequest) (*vibev1.Vibe, error) {
+	if req.GetId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Invalid vibe ID")
+	}
+	vibe, err := h.Service.WithContext(ctx).GetVibeByID(uint(req.GetId()))
+	if err != nil {
+		return nil, grpcError("Failed to retrieve vibe", err)
+	}
+	return vibeToProto(vibe)
+}
+
+// ListVibes returns one page of vibes.
+func (h *VibeGRPCHandler) ListVibes(ctx context.Context, req *vibev1.ListVibesRequest) (*vibev1.ListVibesResponse, error) {
+	if err := validateVibeFilter(req.GetFilter()); err != nil {
+		return nil, err
+	}
+	limit, offset := int(req.GetLimit()), int(req.GetOffset())
+	if limit <= 0 || limit > service.MaxLimit {
+		limit = service.DefaultLimit
+	}
+	if offset < 0 {
+		offset = service.DefaultOffset
+	}
+
+	vibes, total, err := h.Service.WithContext(ctx).GetAllVibes(vibeFilters(req.GetFilter()), limit, offset, req.GetSortBy(), req.GetSortOrder())
+	if err != nil {
+		return nil, grpcError("Failed to retrieve vibes", err)
+	}
+	resp := &vibev1.ListVibesResponse{
+		Vibes:  make([]*vibev1.Vibe, 0, len(vibes)),
+		Total:  total,
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	}
+	for i := range vibes {
+		pb, err := vibeToProto(&vibes[i])
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		resp.Vibes = append(resp.Vibes, pb)
+	}
+	return resp, nil
+}
+
+// UpdateVibe updates a vibe.
+func (h *VibeGRPCHandler) UpdateVibe(ctx context.Context, req *vibev1.UpdateVibeRequest) (*vibev1.Vibe, error) {
+	if req.GetId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Invalid vibe ID")
+	}
+	if req.GetVibe() == nil {
+		return nil, status.Error(codes.InvalidArgument, "Failed to update vibe: missing vibe")
+	}
+	vibe := vibeFromInput(req.GetVibe())
+	if req.GetVibe().GetDate() == nil {
+		existing, err := h.Service.WithContext(ctx).GetVibeByID(uint(req.GetId()))
+		if err != nil {
+			return nil, grpcError("Failed to update vibe", err)
+		}
+		vibe.Date = existing.Date // Keep the stored date rather than saving a zero one
+	}
+	updated, err := h.Service.WithContext(ctx).UpdateVibe(uint(req.GetId()), vibe)
+	if err != nil {
+		return nil, grpcError("Failed to update vibe", err)
+	}
+	return vibeToProto(updated)
+}
+
+// DeleteVibe deletes a vibe.
+func (h *VibeGRPCHandler) DeleteVibe(ctx context.Context, req *vibev1.DeleteVibeRequest) (*emptypb.Empty, error) {
+	if req.GetId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Invalid vibe ID")
+	}
+	if err := h.Service.WithContext(ctx).DeleteVibe(uint(req.GetId())); err != nil {
+		return nil, grpcError("Failed to delete vibe", err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// GetStatistics returns the statistics of a period.
+func (h *VibeGRPCHandler) GetStatistics(ctx context.Context, req *vibev1.GetStatisticsRequest) (*vibev1.Statistics, error) {
+	period := "month"
+	switch req.GetPeriod() {
+	case vibev1.Period_PERIOD_WEEK:
+		period = "week"
+	case vibev1.Period_PERIOD_YEAR:
+		period = "year"
+	}
+	stats, err := h.Service.WithContext(ctx).GetVibeStatistics(period)
+	if err != nil {
+		return nil, grpcError("Failed to retrieve vibe statistics", err)
+	}
+
+	// The statistics map mixes repository scan structs and computed values, so it goes
+	// through its JSON form, which is also what the REST API returns.
+	raw, err := json.Marshal(stats)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to encode vibe statistics: %v", err)
+	}
+	var decoded struct {
+		AverageEnergyLevel float64 `json:"average_energy_level"`
+		MoodDistribution   []struct {
+			Mood  string `json:"mood"`
+			Count int32  `json:"count"`
+		} `json:"mood_distribution"`
+		MetricAggregates []model.MetricAggregate `json:"metric_aggregates"`
+	}
+	var generic map[string]interface{}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to decode vibe statistics: %v", err)
+	}
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to decode vibe statistics: %v", err)
+	}
+
+	resp := &vibev1.Statistics{AverageEnergyLevel: decoded.AverageEnergyLevel}
+	for _, mc := range decoded.MoodDistribution {
+		resp.MoodDistribution = append(resp.MoodDistribution, &vibev1.MoodCount{Mood: mc.Mood, Count: mc.Count})
+	}
+	for _, ma := range decoded.MetricAggregates {
+		resp.MetricAggregates = append(resp.MetricAggregates, &vibev1.MetricAggregate{
+			Name:    ma.Name,
+			Type:    ma.Type,
+			Unit:    ma.Unit,
+			Count:   ma.Count,
+			Average: ma.Average,
+			Min:     ma.Min,
+			Max:     ma.Max,
+		})
+	}
+	analytics := make(map[string]interface{})
+	for _, key := range statisticsAnalyticsKeys {
+		if value, ok := generic[key]; ok {
+			analytics[key] = value
+		}
+	}
+	if resp.Analytics, err = structpb.NewStruct(analytics); err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to encode vibe analytics: %v", err)
+	}
+	return resp, nil
+}
+
+// GetMoodStreak returns the streaks of a mood.
+func (h *VibeGRPCHandler) GetMoodStreak(ctx context.Context, req *vibev1.GetMoodStreakRequest) (*vibev1.MoodStreak, error) {
+	if req.GetMood() == "" {
+		return nil, status.Error(codes.InvalidArgument, "Mood parameter is required")
+	}
+	streak, err := h.Service.WithContext(ctx).GetMoodStreak(req.GetMood())
+	if err != nil {
+		return nil, grpcError("Failed to retrieve mood streak", err)
+	}
+	resp := &vibev1.MoodStreak{}
+	resp.Mood, _ = streak["mood"].(string)
+	if n, ok := streak["current_streak"].(int); ok {
+		resp.CurrentStreak = int32(n)
+	}
+	if n, ok := streak["longest_streak"].(int); ok {
+		resp.LongestStreak = int32(n)
+	}
+	return resp, nil
+}
+
+// ExportVibes streams the matching vibes page by page, so large exports are never held in
+// memory at once.
+func (h *VibeGRPCHandler) ExportVibes(req *vibev1.ExportVibesRequest, stream grpc.ServerStreamingServer[vibev1.Vibe]) error {
+	if err := validateVibeFilter(req.GetFilter()); err != nil {
+		return err
+	}
+	sortBy, sortOrder := req.GetSortBy(), req.GetSortOrder()
+	if sortBy == "" {
+		sortBy = service.DefaultSortBy
+	}
+	if sortOrder == "" {
+		sortOrder = "asc"
+	}
+
+	for offset := 0; ; offset += service.MaxLimit {
+		if err := stream.Context().Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+		vibes, total, err := h.Service.WithContext(stream.Context()).GetAllVibes(vibeFilters(req.GetFilter()), service.MaxLimit, offset, sortBy, sortOrder)
+		if err != nil {
+			return grpcError("Failed to export vibes", err)
+		}
+		for i := range vibes {
+			pb, err := vibeToProto(&vibes[i])
+			if err != nil {
+				return status.Error(codes.Internal, err.Error())
+			}
+			if err := stream.Send(pb); err != nil {
+				return err
+			}
+		}
+		if len(vibes) < service.MaxLimit || int64(offset+len(vibes)) >= total {
+			return nil
+		}
+	}
+}
+
+// BulkImportVibes reads vibes until the client closes the stream and imports them together.
+func (h *VibeGRPCHandler) BulkImportVibes(stream grpc.ClientStreamingServer[vibev1.VibeInput, vibev1.BulkImportVibesResponse]) error {
+	var vibes []*model.Vibe
+	for {
+		in, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if err := validateVibeInput(in); err != nil {
+			return status.Errorf(codes.InvalidArgument, "Failed to import vibe at index %d: %v", len(vibes), err)
+		}
+		vibes = append(vibes, vibeFromInput(in))
+	}
+	if len(vibes) == 0 {
+		return status.Error(codes.InvalidArgument, "No vibes provided for bulk import")
+	}
+
+	count, err := h.Service.WithContext(stream.Context()).BulkImportVibes(vibes)
+	if err != nil {
+		return grpcError("Failed to bulk import vibes", err)
+	}
+	return stream.SendAndClose(&vibev1.BulkImportVibesResponse{ImportedCount: count})
+}
@@ -17,8 +17,19 @@ import (
 
 // VibeHandler encapsulates all handlers for the application.
 type VibeHandler struct {
-	Service       service.VibeServiceInterface
-	HealthHandler *HealthHandler
+	Service               service.VibeServiceInterface
+	HealthHandler         *HealthHandler
+	MoodHandler           *MoodHandler
+	ActivityHandler       *ActivityHandler
+	MetricHandler         *MetricHandler
+	InsightHandler        *InsightHandler
+	RecommendationHandler *RecommendationHandler
+	GoalHandler           *GoalHandler
+	ReminderHandler       *ReminderHandler
+	WebhookHandler        *WebhookHandler
+	StreamHandler         *StreamHandler
+	GraphQLHandler        *GraphQLHandler
+	AuthHandler           *AuthHandler
 }
 
 // NewVibeHandler creates a new VibeHandler.
@@ -45,6 +56,28 @@ func handleError(framework string, ctx interface{}, code int, message string, er
 	return c.Status(code).JSON(fiber.Map{"error": fullMessage})
 }
 
+// isClientError reports whether a service error was caused by invalid input and should map to 400.
+func isClientError(err error) bool {
+	return errors.Is(err, service.ErrValidation) || errors.Is(err, service.ErrUnknownMood)
+}
+
+// parseVibeDateRange adds the start_date and end_date filters (YYYY-MM-DD, inclusive) read
+// with the framework's query getter.
+func parseVibeDateRange(query func(key string) string, filters map[string]interface{}) error {
+	for _, key := range []string{"start_date", "end_date"} {
+		v := query(key)
+		if v == "" {
+			continue
+		}
+		parsed, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return fmt.Errorf("invalid %s format. Use YYYY-MM-DD", key)
+		}
+		filters[key] = parsed.Format("2006-01-02")
+	}
+	return nil
+}
+
 // --- Request/Response Structs (examples, can be more specific) ---
 
 // CreateVibeRequest defines the expected body for creating a vibe.
@@ -64,6 +97,47 @@ type UpdateVibeRequest struct {
 	EnergyLevel int       `json:"energy_level" binding:"omitempty,min=1,max=10"`
 	Notes       string    `json:"notes"`
 	Activities  []string  `json:"activities"`
+	// Metrics replaces all custom metric values when present; omit it to keep the current values.
+	Metrics map[string]interface{} `json:"metrics"`
+}
+
+// PatchVibeRequest defines the body for a partial update. Omitted fields keep their value.
+type PatchVibeRequest struct {
+	Date        *time.Time `json:"date"`
+	Mood        *string    `json:"mood"`
+	EnergyLevel *int       `json:"energy_level"`
+	Notes       *string    `json:"notes"`
+	Activities  *[]string  `json:"activities"`
+	// Metrics replaces all custom metric values when present.
+	Metrics map[string]interface{} `json:"metrics"`
+}
+
+// apply returns a copy of the writable fields of existing with the fields of the patch set.
+func (r PatchVibeRequest) apply(existing *model.Vibe) *model.Vibe {
+	patched := &model.Vibe{
+		Date:        existing.Date,
+		Mood:        existing.Mood,
+		EnergyLevel: existing.EnergyLevel,
+		Notes:       existing.Notes,
+		Activities:  existing.Activities,
+		Metrics:     r.Metrics, // nil keeps the stored values
+	}
+	if r.Date != nil {
+		patched.Date = *r.Date
+	}
+	if r.Mood != nil {
+		patched.Mood = *r.Mood
+	}
+	if r.EnergyLevel != nil {
+		patched.EnergyLevel = *r.EnergyLevel
+	}
+	if r.Notes != nil {
+		patched.Notes = *r.Notes
+	}
+	if r.Activities != nil {
+		patched.Activities = *r.Activities
+	}
+	return patched
 }
 
 // PaginatedVibesResponse is a generic structure for paginated vibe lists.
@@ -91,7 +165,7 @@ type PaginatedVibesResponse struct {
 // @Router /api/v1/vibes [post]
 func (vh *VibeHandler) CreateVibeFiber(c *fiber.Ctx) error {
 	var req model.Vibe // Using model.Vibe directly for simplicity
-	if err := c.BodyParser(&req); err != nil {
+	if err := bindJSONFiber(c, &req); err != nil {
 		return handleError("fiber", c, http.StatusBadRequest, "Invalid request body", err)
 	}
 
@@ -100,8 +174,11 @@ func (vh *VibeHandler) CreateVibeFiber(c *fiber.Ctx) error {
 		return handleError("fiber", c, http.StatusBadRequest, "Missing required fields or invalid energy level", nil)
 	}
 
-	createdVibe, err := vh.Service.CreateVibe(&req)
+	createdVibe, err := vh.Service.WithContext(c.UserContext()).CreateVibe(&req)
 	if err != nil {
+		if isClientError(err) {
+			return handleError("fiber", c, http.StatusBadRequest, "Failed to create vibe", err)
+		}
 		// Check for specific errors, e.g., duplicate date if unique constraint is violated
 		// For now, a generic 500, but could be 409 Conflict etc.
 		return handleError("fiber", c, http.StatusInternalServerError, "Failed to create vibe", err)
@@ -116,10 +193,13 @@ func (vh *VibeHandler) CreateVibeFiber(c *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param date query string false "Filter by date (YYYY-MM-DD)"
+// @Param start_date query string false "Only vibes on or after this day (YYYY-MM-DD)"
+// @Param end_date query string false "Only vibes on or before this day (YYYY-MM-DD)"
 // @Param mood query string false "Filter by mood"
+// @Param metric query []string false "Filter by custom metric as name:op:value, e.g. sleep_hours:gte:7 (op: eq, ne, gt, gte, lt, lte)" collectionFormat(multi)
 // @Param limit query int false "Pagination limit" default(10)
 // @Param offset query int false "Pagination offset" default(0)
-// @Param sort_by query string false "Field to sort by (e.g., date, mood, energy_level)" default(date)
+// @Param sort_by query string false "Field to sort by (e.g., date, mood, energy_level, metric:sleep_hours)" default(date)
 // @Param sort_order query string false "Sort order (asc, desc)" default(desc)
 // @Success 200 {object} PaginatedVibesResponse "List of vibes with pagination"
 // @Failure 400 {object} map[string]string "Invalid query parameters"
@@ -134,9 +214,19 @@ func (vh *VibeHandler) GetAllVibesFiber(c *fiber.Ctx) error {
 		}
 		filters["date"] = parsedDate.Format("2006-01-02") // Service/Repo expects string YYYY-MM-DD for DATE() comparison
 	}
+	if err := parseVibeDateRange(func(key string) string { return c.Query(key) }, filters); err != nil {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid query parameters", err)
+	}
 	if mood := c.Query("mood"); mood != "" {
 		filters["mood"] = mood
 	}
+	if metricFilters := c.Context().QueryArgs().PeekMulti("metric"); len(metricFilters) > 0 {
+		rawFilters := make([]string, 0, len(metricFilters))
+		for _, f := range metricFilters {
+			rawFilters = append(rawFilters, string(f))
+		}
+		filters["metric_filters"] = rawFilters
+	}
 
 	limit, _ := strconv.Atoi(c.Query("limit", strconv.Itoa(service.DefaultLimit)))
 	offset, _ := strconv.Atoi(c.Query("offset", strconv.Itoa(service.DefaultOffset)))
@@ -144,8 +234,11 @@ func (vh *VibeHandler) GetAllVibesFiber(c *fiber.Ctx) error {
 	sortOrder := c.Query("sort_order", service.DefaultSortOrder)
 
 
-	vibes, total, err := vh.Service.GetAllVibes(filters, limit, offset, sortBy, sortOrder)
+	vibes, total, err := vh.Service.WithContext(c.UserContext()).GetAllVibes(filters, limit, offset, sortBy, sortOrder)
 	if err != nil {
+		if isClientError(err) {
+			return handleError("fiber", c, http.StatusBadRequest, "Failed to retrieve vibes", err)
+		}
 		return handleError("fiber", c, http.StatusInternalServerError, "Failed to retrieve vibes", err)
 	}
 
@@ -182,12 +275,12 @@ func (vh *VibeHandler) GetAllVibesFiber(c *fiber.Ctx) error {
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /api/v1/vibes/{id} [get]
 func (vh *VibeHandler) GetVibeByIDFiber(c *fiber.Ctx) error {
-	id, err := c.ParamsInt("id")
+	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil || id <= 0 {
 		return handleError("fiber", c, http.StatusBadRequest, "Invalid vibe ID", err)
 	}
 
-	vibe, err := vh.Service.GetVibeByID(uint(id))
+	vibe, err := vh.Service.WithContext(c.UserContext()).GetVibeByID(uint(id))
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return handleError("fiber", c, http.StatusNotFound, "Vibe not found", nil)
@@ -211,13 +304,13 @@ func (vh *VibeHandler) GetVibeByIDFiber(c *fiber.Ctx) error {
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /api/v1/vibes/{id} [put]
 func (vh *VibeHandler) UpdateVibeFiber(c *fiber.Ctx) error {
-	id, err := c.ParamsInt("id")
+	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil || id <= 0 {
 		return handleError("fiber", c, http.StatusBadRequest, "Invalid vibe ID", err)
 	}
 
 	var req UpdateVibeRequest // Use specific update request struct
-	if err := c.BodyParser(&req); err != nil {
+	if err := bindJSONFiber(c, &req); err != nil {
 		return handleError("fiber", c, http.StatusBadRequest, "Invalid request body", err)
 	}
 
@@ -232,16 +325,65 @@ func (vh *VibeHandler) UpdateVibeFiber(c *fiber.Ctx) error {
 		EnergyLevel: req.EnergyLevel,
 		Notes:       req.Notes,
 		Activities:  req.Activities,
+		Metrics:     req.Metrics,
 	}
 	// If a field is optional and not provided, it might be zero-valued.
 	// GORM's `Updates` method handles non-zero fields, or use `Select` for explicit fields.
 	// The service layer's `ValidateVibe` will run on this partial data.
 
-	updatedVibe, err := vh.Service.UpdateVibe(uint(id), &vibeToUpdate)
+	updatedVibe, err := vh.Service.WithContext(c.UserContext()).UpdateVibe(uint(id), &vibeToUpdate)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return handleError("fiber", c, http.StatusNotFound, "Vibe not found to update", nil)
 		}
+		if isClientError(err) {
+			return handleError("fiber", c, http.StatusBadRequest, "Failed to update vibe", err)
+		}
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to update vibe", err)
+	}
+	return c.JSON(updatedVibe)
+}
+
+// PatchVibeFiber godoc
+// @Summary Partially update vibe
+// @Description Changes only the fields present in the body; the others keep their value. Metrics, when present, replaces all custom metric values.
+// @Tags vibes
+// @Accept json
+// @Produce json
+// @Param id path int true "Vibe ID"
+// @Param vibe body PatchVibeRequest true "Fields to change"
+// @Success 200 {object} model.Vibe "Updated vibe"
+// @Failure 400 {object} map[string]string "Invalid input or ID format"
+// @Failure 404 {object} map[string]string "Vibe not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/vibes/{id} [patch]
+func (vh *VibeHandler) PatchVibeFiber(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil || id <= 0 {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid vibe ID", err)
+	}
+
+	var req PatchVibeRequest
+	if err := bindJSONFiber(c, &req); err != nil {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid request body", err)
+	}
+
+	existing, err := vh.Service.WithContext(c.UserContext()).GetVibeByID(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return handleError("fiber", c, http.StatusNotFound, "Vibe not found to update", nil)
+		}
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to update vibe", err)
+	}
+
+	updatedVibe, err := vh.Service.WithContext(c.UserContext()).UpdateVibe(uint(id), req.apply(existing))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return handleError("fiber", c, http.StatusNotFound, "Vibe not found to update", nil)
+		}
+		if isClientError(err) {
+			return handleError("fiber", c, http.StatusBadRequest, "Failed to update vibe", err)
+		}
 		return handleError("fiber", c, http.StatusInternalServerError, "Failed to update vibe", err)
 	}
 	return c.JSON(updatedVibe)
@@ -260,12 +402,12 @@ func (vh *VibeHandler) UpdateVibeFiber(c *fiber.Ctx) error {
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /api/v1/vibes/{id} [delete]
 func (vh *VibeHandler) DeleteVibeFiber(c *fiber.Ctx) error {
-	id, err := c.ParamsInt("id")
+	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil || id <= 0 {
 		return handleError("fiber", c, http.StatusBadRequest, "Invalid vibe ID", err)
 	}
 
-	err = vh.Service.DeleteVibe(uint(id))
+	err = vh.Service.WithContext(c.UserContext()).DeleteVibe(uint(id))
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return handleError("fiber", c, http.StatusNotFound, "Vibe not found to delete", nil)
@@ -293,30 +435,13 @@ func (vh *VibeHandler) GetVibeStatsFiber(c *fiber.Ctx) error {
 		return handleError("fiber", c, http.StatusBadRequest, "Invalid period. Allowed values: week, month, year.", nil)
 	}
 
-	stats, err := vh.Service.GetVibeStatistics(period)
+	stats, err := vh.Service.WithContext(c.UserContext()).GetVibeStatistics(period)
 	if err != nil {
 		return handleError("fiber", c, http.StatusInternalServerError, "Failed to retrieve vibe statistics", err)
 	}
 	return c.JSON(stats)
 }
 
-// GetTodaysVibeRecommendationFiber godoc
-// @Summary Get today's vibe recommendation
-// @Description Suggests activities based on historical vibe data.
-// @Tags vibes-analytics
-// @Accept json
-// @Produce json
-// @Success 200 {object} map[string]interface{} "Suggested activities and reason"
-// @Failure 500 {object} map[string]string "Internal server error"
-// @Router /api/v1/vibes/today [get]
-func (vh *VibeHandler) GetTodaysVibeRecommendationFiber(c *fiber.Ctx) error {
-	recommendation, err := vh.Service.GetTodaysVibeRecommendation()
-	if err != nil {
-		return handleError("fiber", c, http.StatusInternalServerError, "Failed to generate recommendation", err)
-	}
-	return c.JSON(recommendation)
-}
-
 // GetMoodStreakFiber godoc
 // @Summary Get current mood streak
 // @Description Calculates the current and longest streak for a specific mood.
@@ -334,7 +459,7 @@ func (vh *VibeHandler) GetMoodStreakFiber(c *fiber.Ctx) error {
 		return handleError("fiber", c, http.StatusBadRequest, "Missing 'mood' query parameter", nil)
 	}
 
-	streakInfo, err := vh.Service.GetMoodStreak(mood)
+	streakInfo, err := vh.Service.WithContext(c.UserContext()).GetMoodStreak(mood)
 	if err != nil {
 		return handleError("fiber", c, http.StatusInternalServerError, "Failed to calculate mood streak", err)
 	}
@@ -348,8 +473,11 @@ func (vh *VibeHandler) GetMoodStreakFiber(c *fiber.Ctx) error {
 // @Produce plain text/csv application/json
 // @Param format query string true "Export format (csv or json)"
 // @Param date query string false "Filter by date (YYYY-MM-DD)"
+// @Param start_date query string false "Only vibes on or after this day (YYYY-MM-DD)"
+// @Param end_date query string false "Only vibes on or before this day (YYYY-MM-DD)"
 // @Param mood query string false "Filter by mood"
-// @Param sort_by query string false "Field to sort by (e.g., date, mood, energy_level)" default(date)
+// @Param metric query []string false "Filter by custom metric as name:op:value, e.g. sleep_hours:gte:7" collectionFormat(multi)
+// @Param sort_by query string false "Field to sort by (e.g., date, mood, energy_level, metric:sleep_hours)" default(date)
 // @Param sort_order query string false "Sort order (asc, desc)" default(asc)
 // @Success 200 {file} string "Vibe data in specified format"
 // @Failure 400 {object} map[string]string "Invalid parameters"
@@ -374,15 +502,28 @@ func (vh *VibeHandler) ExportVibesFiber(c *fiber.Ctx) error {
 		}
 		filters["date"] = parsedDate.Format("2006-01-02")
 	}
+	if err := parseVibeDateRange(func(key string) string { return c.Query(key) }, filters); err != nil {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid query parameters", err)
+	}
 	if mood := c.Query("mood"); mood != "" {
 		filters["mood"] = mood
 	}
+	if metricFilters := c.Context().QueryArgs().PeekMulti("metric"); len(metricFilters) > 0 {
+		rawFilters := make([]string, 0, len(metricFilters))
+		for _, f := range metricFilters {
+			rawFilters = append(rawFilters, string(f))
+		}
+		filters["metric_filters"] = rawFilters
+	}
 	sortBy := c.Query("sort_by", service.DefaultSortBy) // Default sort for export might be different
 	sortOrder := c.Query("sort_order", "asc") // Default to ascending for exports usually
 
 
-	data, contentType, err := vh.Service.ExportVibes(filters, format, sortBy, sortOrder)
+	data, contentType, err := vh.Service.WithContext(c.UserContext()).ExportVibes(filters, format, sortBy, sortOrder)
 	if err != nil {
+		if isClientError(err) {
+			return handleError("fiber", c, http.StatusBadRequest, "Failed to export vibes", err)
+		}
 		return handleError("fiber", c, http.StatusInternalServerError, "Failed to export vibes", err)
 	}
 
@@ -408,7 +549,7 @@ func (vh *VibeHandler) ExportVibesFiber(c *fiber.Ctx) error {
 // @Router /api/v1/vibes/bulk [post]
 func (vh *VibeHandler) BulkImportVibesFiber(c *fiber.Ctx) error {
 	var vibesToImport []*model.Vibe
-	if err := c.BodyParser(&vibesToImport); err != nil {
+	if err := bindJSONFiber(c, &vibesToImport); err != nil {
 		return handleError("fiber", c, http.StatusBadRequest, "Invalid request body for bulk import", err)
 	}
 
@@ -416,8 +557,11 @@ func (vh *VibeHandler) BulkImportVibesFiber(c *fiber.Ctx) error {
 		return handleError("fiber", c, http.StatusBadRequest, "No vibes provided in the request body", nil)
 	}
 
-	count, err := vh.Service.BulkImportVibes(vibesToImport)
+	count, err := vh.Service.WithContext(c.UserContext()).BulkImportVibes(vibesToImport)
 	if err != nil {
+		if isClientError(err) {
+			return handleError("fiber", c, http.StatusBadRequest, "Failed during bulk import", err)
+		}
 		// This could be a mix of validation errors or DB errors.
 		// A more sophisticated error handling might return per-item status.
 		return handleError("fiber", c, http.StatusInternalServerError, "Failed during bulk import", err)
@@ -444,7 +588,7 @@ func (vh *VibeHandler) BulkImportVibesFiber(c *fiber.Ctx) error {
 // @Router /api/v1/vibes [post]
 func (vh *VibeHandler) CreateVibeGin(c *gin.Context) {
 	var req model.Vibe
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if err := bindJSONGin(c, &req); err != nil {
 		handleError("gin", c, http.StatusBadRequest, "Invalid request body", err)
 		return
 	}
@@ -453,8 +597,12 @@ func (vh *VibeHandler) CreateVibeGin(c *gin.Context) {
 		return
 	}
 
-	createdVibe, err := vh.Service.CreateVibe(&req)
+	createdVibe, err := vh.Service.WithContext(c.Request.Context()).CreateVibe(&req)
 	if err != nil {
+		if isClientError(err) {
+			handleError("gin", c, http.StatusBadRequest, "Failed to create vibe", err)
+			return
+		}
 		handleError("gin", c, http.StatusInternalServerError, "Failed to create vibe", err)
 		return
 	}
@@ -468,10 +616,13 @@ func (vh *VibeHandler) CreateVibeGin(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param date query string false "Filter by date (YYYY-MM-DD)"
+// @Param start_date query string false "Only vibes on or after this day (YYYY-MM-DD)"
+// @Param end_date query string false "Only vibes on or before this day (YYYY-MM-DD)"
 // @Param mood query string false "Filter by mood"
+// @Param metric query []string false "Filter by custom metric as name:op:value, e.g. sleep_hours:gte:7 (op: eq, ne, gt, gte, lt, lte)" collectionFormat(multi)
 // @Param limit query int false "Pagination limit" default(10)
 // @Param offset query int false "Pagination offset" default(0)
-// @Param sort_by query string false "Field to sort by (e.g., date, mood, energy_level)" default(date)
+// @Param sort_by query string false "Field to sort by (e.g., date, mood, energy_level, metric:sleep_hours)" default(date)
 // @Param sort_order query string false "Sort order (asc, desc)" default(desc)
 // @Success 200 {object} PaginatedVibesResponse "List of vibes with pagination"
 // @Failure 400 {object} map[string]string "Invalid query parameters"
@@ -487,9 +638,16 @@ func (vh *VibeHandler) GetAllVibesGin(c *gin.Context) {
 		}
 		filters["date"] = parsedDate.Format("2006-01-02")
 	}
+	if err := parseVibeDateRange(c.Query, filters); err != nil {
+		handleError("gin", c, http.StatusBadRequest, "Invalid query parameters", err)
+		return
+	}
 	if mood := c.Query("mood"); mood != "" {
 		filters["mood"] = mood
 	}
+	if metricFilters := c.QueryArray("metric"); len(metricFilters) > 0 {
+		filters["metric_filters"] = metricFilters
+	}
 
 	limitStr := c.DefaultQuery("limit", strconv.Itoa(service.DefaultLimit))
 	limit, errL := strconv.Atoi(limitStr)
@@ -507,8 +665,12 @@ func (vh *VibeHandler) GetAllVibesGin(c *gin.Context) {
 	sortBy := c.DefaultQuery("sort_by", service.DefaultSortBy)
 	sortOrder := c.DefaultQuery("sort_order", service.DefaultSortOrder)
 
-	vibes, total, err := vh.Service.GetAllVibes(filters, limit, offset, sortBy, sortOrder)
+	vibes, total, err := vh.Service.WithContext(c.Request.Context()).GetAllVibes(filters, limit, offset, sortBy, sortOrder)
 	if err != nil {
+		if isClientError(err) {
+			handleError("gin", c, http.StatusBadRequest, "Failed to retrieve vibes", err)
+			return
+		}
 		handleError("gin", c, http.StatusInternalServerError, "Failed to retrieve vibes", err)
 		return
 	}
@@ -552,7 +714,7 @@ func (vh *VibeHandler) GetVibeByIDGin(c *gin.Context) {
 		return
 	}
 
-	vibe, err := vh.Service.GetVibeByID(uint(id))
+	vibe, err := vh.Service.WithContext(c.Request.Context()).GetVibeByID(uint(id))
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			handleError("gin", c, http.StatusNotFound, "Vibe not found", nil)
@@ -586,7 +748,7 @@ func (vh *VibeHandler) UpdateVibeGin(c *gin.Context) {
 	}
 
 	var req UpdateVibeRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if err := bindJSONGin(c, &req); err != nil {
 		handleError("gin", c, http.StatusBadRequest, "Invalid request body", err)
 		return
 	}
@@ -596,9 +758,52 @@ func (vh *VibeHandler) UpdateVibeGin(c *gin.Context) {
 		EnergyLevel: req.EnergyLevel,
 		Notes:       req.Notes,
 		Activities:  req.Activities,
+		Metrics:     req.Metrics,
+	}
+
+	updatedVibe, err := vh.Service.WithContext(c.Request.Context()).UpdateVibe(uint(id), &vibeToUpdate)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			handleError("gin", c, http.StatusNotFound, "Vibe not found to update", nil)
+			return
+		}
+		if isClientError(err) {
+			handleError("gin", c, http.StatusBadRequest, "Failed to update vibe", err)
+			return
+		}
+		handleError("gin", c, http.StatusInternalServerError, "Failed to update vibe", err)
+		return
+	}
+	c.JSON(http.StatusOK, updatedVibe)
+}
+
+// PatchVibeGin godoc
+// @Summary Partially update vibe
+// @Description Changes only the fields present in the body; the others keep their value. Metrics, when present, replaces all custom metric values.
+// @Tags vibes
+// @Accept json
+// @Produce json
+// @Param id path int true "Vibe ID"
+// @Param vibe body PatchVibeRequest true "Fields to change"
+// @Success 200 {object} model.Vibe "Updated vibe"
+// @Failure 400 {object} map[string]string "Invalid input or ID format"
+// @Failure 404 {object} map[string]string "Vibe not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/vibes/{id} [patch]
+func (vh *VibeHandler) PatchVibeGin(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		handleError("gin", c, http.StatusBadRequest, "Invalid vibe ID", err)
+		return
 	}
 
-	updatedVibe, err := vh.Service.UpdateVibe(uint(id), &vibeToUpdate)
+	var req PatchVibeRequest
+	if err := bindJSONGin(c, &req); err != nil {
+		handleError("gin", c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	existing, err := vh.Service.WithContext(c.Request.Context()).GetVibeByID(uint(id))
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			handleError("gin", c, http.StatusNotFound, "Vibe not found to update", nil)
@@ -607,6 +812,20 @@ func (vh *VibeHandler) UpdateVibeGin(c *gin.Context) {
 		handleError("gin", c, http.StatusInternalServerError, "Failed to update vibe", err)
 		return
 	}
+
+	updatedVibe, err := vh.Service.WithContext(c.Request.Context()).UpdateVibe(uint(id), req.apply(existing))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			handleError("gin", c, http.StatusNotFound, "Vibe not found to update", nil)
+			return
+		}
+		if isClientError(err) {
+			handleError("gin", c, http.StatusBadRequest, "Failed to update vibe", err)
+			return
+		}
+		handleError("gin", c, http.StatusInternalServerError, "Failed to update vibe", err)
+		return
+	}
 	c.JSON(http.StatusOK, updatedVibe)
 }
 
@@ -630,7 +849,7 @@ func (vh *VibeHandler) DeleteVibeGin(c *gin.Context) {
 		return
 	}
 
-	err = vh.Service.DeleteVibe(uint(id))
+	err = vh.Service.WithContext(c.Request.Context()).DeleteVibe(uint(id))
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			handleError("gin", c, http.StatusNotFound, "Vibe not found to delete", nil)
@@ -661,7 +880,7 @@ func (vh *VibeHandler) GetVibeStatsGin(c *gin.Context) {
 		return
 	}
 
-	stats, err := vh.Service.GetVibeStatistics(period)
+	stats, err := vh.Service.WithContext(c.Request.Context()).GetVibeStatistics(period)
 	if err != nil {
 		handleError("gin", c, http.StatusInternalServerError, "Failed to retrieve vibe statistics", err)
 		return
@@ -669,24 +888,6 @@ func (vh *VibeHandler) GetVibeStatsGin(c *gin.Context) {
 	c.JSON(http.StatusOK, stats)
 }
 
-// GetTodaysVibeRecommendationGin godoc
-// @Summary Get today's vibe recommendation
-// @Description Suggests activities based on historical vibe data.
-// @Tags vibes-analytics
-// @Accept json
-// @Produce json
-// @Success 200 {object} map[string]interface{} "Suggested activities and reason"
-// @Failure 500 {object} map[string]string "Internal server error"
-// @Router /api/v1/vibes/today [get]
-func (vh *VibeHandler) GetTodaysVibeRecommendationGin(c *gin.Context) {
-	recommendation, err := vh.Service.GetTodaysVibeRecommendation()
-	if err != nil {
-		handleError("gin", c, http.StatusInternalServerError, "Failed to generate recommendation", err)
-		return
-	}
-	c.JSON(http.StatusOK, recommendation)
-}
-
 // GetMoodStreakGin godoc
 // @Summary Get current mood streak
 // @Description Calculates the current and longest streak for a specific mood.
@@ -705,7 +906,7 @@ func (vh *VibeHandler) GetMoodStreakGin(c *gin.Context) {
 		return
 	}
 
-	streakInfo, err := vh.Service.GetMoodStreak(mood)
+	streakInfo, err := vh.Service.WithContext(c.Request.Context()).GetMoodStreak(mood)
 	if err != nil {
 		handleError("gin", c, http.StatusInternalServerError, "Failed to calculate mood streak", err)
 		return
@@ -720,8 +921,11 @@ func (vh *VibeHandler) GetMoodStreakGin(c *gin.Context) {
 // @Produce plain text/csv application/json
 // @Param format query string true "Export format (csv or json)"
 // @Param date query string false "Filter by date (YYYY-MM-DD)"
+// @Param start_date query string false "Only vibes on or after this day (YYYY-MM-DD)"
+// @Param end_date query string false "Only vibes on or before this day (YYYY-MM-DD)"
 // @Param mood query string false "Filter by mood"
-// @Param sort_by query string false "Field to sort by (e.g., date, mood, energy_level)" default(date)
+// @Param metric query []string false "Filter by custom metric as name:op:value, e.g. sleep_hours:gte:7" collectionFormat(multi)
+// @Param sort_by query string false "Field to sort by (e.g., date, mood, energy_level, metric:sleep_hours)" default(date)
 // @Param sort_order query string false "Sort order (asc, desc)" default(asc)
 // @Success 200 {file} string "Vibe data in specified format"
 // @Failure 400 {object} map[string]string "Invalid parameters"
@@ -748,15 +952,26 @@ func (vh *VibeHandler) ExportVibesGin(c *gin.Context) {
 		}
 		filters["date"] = parsedDate.Format("2006-01-02")
 	}
+	if err := parseVibeDateRange(c.Query, filters); err != nil {
+		handleError("gin", c, http.StatusBadRequest, "Invalid query parameters", err)
+		return
+	}
 	if mood := c.Query("mood"); mood != "" {
 		filters["mood"] = mood
 	}
+	if metricFilters := c.QueryArray("metric"); len(metricFilters) > 0 {
+		filters["metric_filters"] = metricFilters
+	}
 	sortBy := c.DefaultQuery("sort_by", service.DefaultSortBy)
 	sortOrder := c.DefaultQuery("sort_order", "asc")
 
 
-	data, contentType, err := vh.Service.ExportVibes(filters, format, sortBy, sortOrder)
+	data, contentType, err := vh.Service.WithContext(c.Request.Context()).ExportVibes(filters, format, sortBy, sortOrder)
 	if err != nil {
+		if isClientError(err) {
+			handleError("gin", c, http.StatusBadRequest, "Failed to export vibes", err)
+			return
+		}
 		handleError("gin", c, http.StatusInternalServerError, "Failed to export vibes", err)
 		return
 	}
@@ -783,7 +998,7 @@ func (vh *VibeHandler) ExportVibesGin(c *gin.Context) {
 // @Router /api/v1/vibes/bulk [post]
 func (vh *VibeHandler) BulkImportVibesGin(c *gin.Context) {
 	var vibesToImport []*model.Vibe
-	if err := c.ShouldBindJSON(&vibesToImport); err != nil {
+	if err := bindJSONGin(c, &vibesToImport); err != nil {
 		handleError("gin", c, http.StatusBadRequest, "Invalid request body for bulk import", err)
 		return
 	}
@@ -793,8 +1008,12 @@ func (vh *VibeHandler) BulkImportVibesGin(c *gin.Context) {
 		return
 	}
 
-	count, err := vh.Service.BulkImportVibes(vibesToImport)
+	count, err := vh.Service.WithContext(c.Request.Context()).BulkImportVibes(vibesToImport)
 	if err != nil {
+		if isClientError(err) {
+			handleError("gin", c, http.StatusBadRequest, "Failed during bulk import", err)
+			return
+		}
 		handleError("gin", c, http.StatusInternalServerError, "Failed during bulk import", err)
 		return
 	}
@@ -0,0 +1,579 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/model"
+	"github.com/aebalz/daily-vibe-tracker/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// WebhookHandler handles webhook subscription and delivery requests.
+type WebhookHandler struct {
+	Service service.WebhookServiceInterface
+}
+
+// NewWebhookHandler creates a new WebhookHandler.
+func NewWebhookHandler(svc service.WebhookServiceInterface) *WebhookHandler {
+	return &WebhookHandler{Service: svc}
+}
+
+// WebhookSubscriptionRequest defines the expected body for creating or updating a webhook subscription.
+type WebhookSubscriptionRequest struct {
+	URL         string   `json:"url" binding:"required"`
+	Secret      string   `json:"secret"` // Signing secret; generated on create and kept on update when empty
+	Events      []string `json:"events"` // Event filter; empty means all events
+	Description string   `json:"description"`
+	Active      *bool    `json:"active"` // Defaults to true
+}
+
+func (r WebhookSubscriptionRequest) toModel() model.WebhookSubscription {
+	return model.WebhookSubscription{
+		URL:         r.URL,
+		Secret:      r.Secret,
+		Events:      r.Events,
+		Description: r.Description,
+		Active:      r.Active == nil || *r.Active,
+	}
+}
+
+// PaginatedWebhookDeliveriesResponse defines the structure for a paginated list of webhook deliveries.
+type PaginatedWebhookDeliveriesResponse struct {
+	Data   []model.WebhookDelivery `json:"data"`
+	Total  int64                   `json:"total"`
+	Limit  int                     `json:"limit"`
+	Offset int                     `json:"offset"`
+}
+
+// parseWebhookDeliveryFilters reads the subscription_id, status and event_type query parameters.
+func parseWebhookDeliveryFilters(get func(key string) string) (map[string]interface{}, error) {
+	filters := make(map[string]interface{})
+	if raw := get("subscription_id"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid subscription_id '%s'", raw)
+		}
+		filters["subscription_id"] = uint(id)
+	}
+	if status := get("status"); status != "" {
+		filters["status"] = status
+	}
+	if eventType := get("event_type"); eventType != "" {
+		filters["event_type"] = eventType
+	}
+	return filters, nil
+}
+
+// --- Fiber Handlers ---
+
+// CreateSubscriptionFiber godoc
+// @Summary Create a webhook subscription
+// @Description Subscribes a URL to vibe events (vibe.created, vibe.updated, vibe.deleted, bulk.imported, streak.milestone). Deliveries are signed with the secret, which is only returned here.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param subscription body WebhookSubscriptionRequest true "Subscription"
+// @Success 201 {object} model.WebhookSubscription "Created subscription, including its secret"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/webhooks [post]
+func (wh *WebhookHandler) CreateSubscriptionFiber(c *fiber.Ctx) error {
+	var req WebhookSubscriptionRequest
+	if err := bindJSONFiber(c, &req); err != nil {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid request body", err)
+	}
+	sub := req.toModel()
+	created, err := wh.Service.CreateSubscription(&sub)
+	if err != nil {
+		if isClientError(err) {
+			return handleError("fiber", c, http.StatusBadRequest, "Failed to create webhook subscription", err)
+		}
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to create webhook subscription", err)
+	}
+	return c.Status(http.StatusCreated).JSON(created)
+}
+
+// GetAllSubscriptionsFiber godoc
+// @Summary List webhook subscriptions
+// @Description Retrieves all webhook subscriptions. Secrets are not included.
+// @Tags webhooks
+// @Produce json
+// @Success 200 {array} model.WebhookSubscription "Subscriptions"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/webhooks [get]
+func (wh *WebhookHandler) GetAllSubscriptionsFiber(c *fiber.Ctx) error {
+	subs, err := wh.Service.GetAllSubscriptions()
+	if err != nil {
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to retrieve webhook subscriptions", err)
+	}
+	return c.JSON(subs)
+}
+
+// GetSubscriptionByIDFiber godoc
+// @Summary Get a webhook subscription
+// @Description Retrieves a webhook subscription by its ID. The secret is not included.
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Subscription ID"
+// @Success 200 {object} model.WebhookSubscription "Subscription"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 404 {object} map[string]string "Subscription not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/webhooks/{id} [get]
+func (wh *WebhookHandler) GetSubscriptionByIDFiber(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil || id <= 0 {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid subscription ID", err)
+	}
+	sub, err := wh.Service.GetSubscriptionByID(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return handleError("fiber", c, http.StatusNotFound, "Webhook subscription not found", nil)
+		}
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to retrieve webhook subscription", err)
+	}
+	return c.JSON(sub)
+}
+
+// UpdateSubscriptionFiber godoc
+// @Summary Update a webhook subscription
+// @Description Replaces a subscription's URL, event filter and state. An empty secret keeps the current one; a new secret is returned once.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "Subscription ID"
+// @Param subscription body WebhookSubscriptionRequest true "Updated subscription"
+// @Success 200 {object} model.WebhookSubscription "Updated subscription"
+// @Failure 400 {object} map[string]string "Invalid input or ID format"
+// @Failure 404 {object} map[string]string "Subscription not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/webhooks/{id} [put]
+func (wh *WebhookHandler) UpdateSubscriptionFiber(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil || id <= 0 {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid subscription ID", err)
+	}
+	var req WebhookSubscriptionRequest
+	if err := bindJSONFiber(c, &req); err != nil {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid request body", err)
+	}
+	sub := req.toModel()
+	updated, err := wh.Service.UpdateSubscription(uint(id), &sub)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return handleError("fiber", c, http.StatusNotFound, "Webhook subscription not found", nil)
+		}
+		if isClientError(err) {
+			return handleError("fiber", c, http.StatusBadRequest, "Failed to update webhook subscription", err)
+		}
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to update webhook subscription", err)
+	}
+	return c.JSON(updated)
+}
+
+// DeleteSubscriptionFiber godoc
+// @Summary Delete a webhook subscription
+// @Description Removes a webhook subscription and its deliveries.
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Subscription ID"
+// @Success 200 {object} map[string]string "Success message"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 404 {object} map[string]string "Subscription not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/webhooks/{id} [delete]
+func (wh *WebhookHandler) DeleteSubscriptionFiber(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil || id <= 0 {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid subscription ID", err)
+	}
+	if err := wh.Service.DeleteSubscription(uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return handleError("fiber", c, http.StatusNotFound, "Webhook subscription not found", nil)
+		}
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to delete webhook subscription", err)
+	}
+	return c.Status(http.StatusOK).JSON(fiber.Map{"message": "Webhook subscription deleted successfully"})
+}
+
+// PingSubscriptionFiber godoc
+// @Summary Ping a webhook subscription
+// @Description Queues a ping event for the subscription, regardless of its event filter, to test the receiver and its signature check.
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Subscription ID"
+// @Success 202 {object} model.WebhookDelivery "Queued delivery"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 404 {object} map[string]string "Subscription not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/webhooks/{id}/ping [post]
+func (wh *WebhookHandler) PingSubscriptionFiber(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil || id <= 0 {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid subscription ID", err)
+	}
+	delivery, err := wh.Service.Ping(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return handleError("fiber", c, http.StatusNotFound, "Webhook subscription not found", nil)
+		}
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to ping webhook subscription", err)
+	}
+	return c.Status(http.StatusAccepted).JSON(delivery)
+}
+
+// GetDeliveriesFiber godoc
+// @Summary List webhook deliveries
+// @Description Lists webhook deliveries, most recent first, with their status (pending, delivered, dead), attempts, last response status and error.
+// @Tags webhooks
+// @Produce json
+// @Param subscription_id query int false "Filter by subscription"
+// @Param status query string false "Filter by status (pending, delivered, dead)"
+// @Param event_type query string false "Filter by event type"
+// @Param limit query int false "Pagination limit" default(10)
+// @Param offset query int false "Pagination offset" default(0)
+// @Success 200 {object} PaginatedWebhookDeliveriesResponse "Deliveries with pagination"
+// @Failure 400 {object} map[string]string "Invalid filter"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/webhooks/deliveries [get]
+func (wh *WebhookHandler) GetDeliveriesFiber(c *fiber.Ctx) error {
+	filters, err := parseWebhookDeliveryFilters(func(key string) string { return c.Query(key) })
+	if err != nil {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid filter", err)
+	}
+	return wh.listDeliveriesFiber(c, filters)
+}
+
+// GetDeadLettersFiber godoc
+// @Summary List dead webhook deliveries
+// @Description Lists the dead-letter queue: deliveries that failed WEBHOOK_MAX_ATTEMPTS times, most recent first. They can be redelivered.
+// @Tags webhooks
+// @Produce json
+// @Param subscription_id query int false "Filter by subscription"
+// @Param event_type query string false "Filter by event type"
+// @Param limit query int false "Pagination limit" default(10)
+// @Param offset query int false "Pagination offset" default(0)
+// @Success 200 {object} PaginatedWebhookDeliveriesResponse "Dead deliveries with pagination"
+// @Failure 400 {object} map[string]string "Invalid filter"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/webhooks/dead-letters [get]
+func (wh *WebhookHandler) GetDeadLettersFiber(c *fiber.Ctx) error {
+	filters, err := parseWebhookDeliveryFilters(func(key string) string { return c.Query(key) })
+	if err != nil {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid filter", err)
+	}
+	filters["status"] = model.WebhookDeliveryDead
+	return wh.listDeliveriesFiber(c, filters)
+}
+
+func (wh *WebhookHandler) listDeliveriesFiber(c *fiber.Ctx, filters map[string]interface{}) error {
+	limit, _ := strconv.Atoi(c.Query("limit", strconv.Itoa(service.DefaultLimit)))
+	offset, _ := strconv.Atoi(c.Query("offset", strconv.Itoa(service.DefaultOffset)))
+
+	deliveries, total, err := wh.Service.GetDeliveries(filters, limit, offset)
+	if err != nil {
+		if isClientError(err) {
+			return handleError("fiber", c, http.StatusBadRequest, "Invalid filter", err)
+		}
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to retrieve webhook deliveries", err)
+	}
+	return c.JSON(PaginatedWebhookDeliveriesResponse{Data: deliveries, Total: total, Limit: limit, Offset: offset})
+}
+
+// RedeliverFiber godoc
+// @Summary Redeliver a webhook delivery
+// @Description Queues a delivery again with a fresh attempt budget, e.g. from the dead-letter queue. The receiver gets the same event ID.
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Delivery ID"
+// @Success 202 {object} model.WebhookDelivery "Queued delivery"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 404 {object} map[string]string "Delivery or subscription not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/webhooks/deliveries/{id}/redeliver [post]
+func (wh *WebhookHandler) RedeliverFiber(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil || id <= 0 {
+		return handleError("fiber", c, http.StatusBadRequest, "Invalid delivery ID", err)
+	}
+	delivery, err := wh.Service.Redeliver(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return handleError("fiber", c, http.StatusNotFound, "Webhook delivery not found", nil)
+		}
+		return handleError("fiber", c, http.StatusInternalServerError, "Failed to redeliver webhook", err)
+	}
+	return c.Status(http.StatusAccepted).JSON(delivery)
+}
+
+// --- Gin Handlers ---
+
+// CreateSubscriptionGin godoc
+// @Summary Create a webhook subscription
+// @Description Subscribes a URL to vibe events (vibe.created, vibe.updated, vibe.deleted, bulk.imported, streak.milestone). Deliveries are signed with the secret, which is only returned here.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param subscription body WebhookSubscriptionRequest true "Subscription"
+// @Success 201 {object} model.WebhookSubscription "Created subscription, including its secret"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/webhooks [post]
+func (wh *WebhookHandler) CreateSubscriptionGin(c *gin.Context) {
+	var req WebhookSubscriptionRequest
+	if err := bindJSONGin(c, &req); err != nil {
+		handleError("gin", c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	sub := req.toModel()
+	created, err := wh.Service.CreateSubscription(&sub)
+	if err != nil {
+		if isClientError(err) {
+			handleError("gin", c, http.StatusBadRequest, "Failed to create webhook subscription", err)
+			return
+		}
+		handleError("gin", c, http.StatusInternalServerError, "Failed to create webhook subscription", err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// GetAllSubscriptionsGin godoc
+// @Summary List webhook subscriptions
+// @Description Retrieves all webhook subscriptions. Secrets are not included.
+// @Tags webhooks
+// @Produce json
+// @Success 200 {array} model.WebhookSubscription "Subscriptions"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/webhooks [get]
+func (wh *WebhookHandler) GetAllSubscriptionsGin(c *gin.Context) {
+	subs, err := wh.Service.GetAllSubscriptions()
+	if err != nil {
+		handleError("gin", c, http.StatusInternalServerError, "Failed to retrieve webhook subscriptions", err)
+		return
+	}
+	c.JSON(http.StatusOK, subs)
+}
+
+// GetSubscriptionByIDGin godoc
+// @Summary Get a webhook subscription
+// @Description Retrieves a webhook subscription by its ID. The secret is not included.
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Subscription ID"
+// @Success 200 {object} model.WebhookSubscription "Subscription"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 404 {object} map[string]string "Subscription not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/webhooks/{id} [get]
+func (wh *WebhookHandler) GetSubscriptionByIDGin(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		handleError("gin", c, http.StatusBadRequest, "Invalid subscription ID", err)
+		return
+	}
+	sub, err := wh.Service.GetSubscriptionByID(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			handleError("gin", c, http.StatusNotFound, "Webhook subscription not found", nil)
+			return
+		}
+		handleError("gin", c, http.StatusInternalServerError, "Failed to retrieve webhook subscription", err)
+		return
+	}
+	c.JSON(http.StatusOK, sub)
+}
+
+// UpdateSubscriptionGin godoc
+// @Summary Update a webhook subscription
+// @Description Replaces a subscription's URL, event filter and state. An empty secret keeps the current one; a new secret is returned once.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "Subscription ID"
+// @Param subscription body WebhookSubscriptionRequest true "Updated subscription"
+// @Success 200 {object} model.WebhookSubscription "Updated subscription"
+// @Failure 400 {object} map[string]string "Invalid input or ID format"
+// @Failure 404 {object} map[string]string "Subscription not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/webhooks/{id} [put]
+func (wh *WebhookHandler) UpdateSubscriptionGin(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		handleError("gin", c, http.StatusBadRequest, "Invalid subscription ID", err)
+		return
+	}
+	var req WebhookSubscriptionRequest
+	if err := bindJSONGin(c, &req); err != nil {
+		handleError("gin", c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	sub := req.toModel()
+	updated, err := wh.Service.UpdateSubscription(uint(id), &sub)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			handleError("gin", c, http.StatusNotFound, "Webhook subscription not found", nil)
+			return
+		}
+		if isClientError(err) {
+			handleError("gin", c, http.StatusBadRequest, "Failed to update webhook subscription", err)
+			return
+		}
+		handleError("gin", c, http.StatusInternalServerError, "Failed to update webhook subscription", err)
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// DeleteSubscriptionGin godoc
+// @Summary Delete a webhook subscription
+// @Description Removes a webhook subscription and its deliveries.
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Subscription ID"
+// @Success 200 {object} map[string]string "Success message"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 404 {object} map[string]string "Subscription not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/webhooks/{id} [delete]
+func (wh *WebhookHandler) DeleteSubscriptionGin(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		handleError("gin", c, http.StatusBadRequest, "Invalid subscription ID", err)
+		return
+	}
+	if err := wh.Service.DeleteSubscription(uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			handleError("gin", c, http.StatusNotFound, "Webhook subscription not found", nil)
+			return
+		}
+		handleError("gin", c, http.StatusInternalServerError, "Failed to delete webhook subscription", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook subscription deleted successfully"})
+}
+
+// PingSubscriptionGin godoc
+// @Summary Ping a webhook subscription
+// @Description Queues a ping event for the subscription, regardless of its event filter, to test the receiver and its signature check.
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Subscription ID"
+// @Success 202 {object} model.WebhookDelivery "Queued delivery"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 404 {object} map[string]string "Subscription not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/webhooks/{id}/ping [post]
+func (wh *WebhookHandler) PingSubscriptionGin(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		handleError("gin", c, http.StatusBadRequest, "Invalid subscription ID", err)
+		return
+	}
+	delivery, err := wh.Service.Ping(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			handleError("gin", c, http.StatusNotFound, "Webhook subscription not found", nil)
+			return
+		}
+		handleError("gin", c, http.StatusInternalServerError, "Failed to ping webhook subscription", err)
+		return
+	}
+	c.JSON(http.StatusAccepted, delivery)
+}
+
+// GetDeliveriesGin godoc
+// @Summary List webhook deliveries
+// @Description Lists webhook deliveries, most recent first, with their status (pending, delivered, dead), attempts, last response status and error.
+// @Tags webhooks
+// @Produce json
+// @Param subscription_id query int false "Filter by subscription"
+// @Param status query string false "Filter by status (pending, delivered, dead)"
+// @Param event_type query string false "Filter by event type"
+// @Param limit query int false "Pagination limit" default(10)
+// @Param offset query int false "Pagination offset" default(0)
+// @Success 200 {object} PaginatedWebhookDeliveriesResponse "Deliveries with pagination"
+// @Failure 400 {object} map[string]string "Invalid filter"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/webhooks/deliveries [get]
+func (wh *WebhookHandler) GetDeliveriesGin(c *gin.Context) {
+	filters, err := parseWebhookDeliveryFilters(c.Query)
+	if err != nil {
+		handleError("gin", c, http.StatusBadRequest, "Invalid filter", err)
+		return
+	}
+	wh.listDeliveriesGin(c, filters)
+}
+
+// GetDeadLettersGin godoc
+// @Summary List dead webhook deliveries
+// @Description Lists the dead-letter queue: deliveries that failed WEBHOOK_MAX_ATTEMPTS times, most recent first. They can be redelivered.
+// @Tags webhooks
+// @Produce json
+// @Param subscription_id query int false "Filter by subscription"
+// @Param event_type query string false "Filter by event type"
+// @Param limit query int false "Pagination limit" default(10)
+// @Param offset query int false "Pagination offset" default(0)
+// @Success 200 {object} PaginatedWebhookDeliveriesResponse "Dead deliveries with pagination"
+// @Failure 400 {object} map[string]string "Invalid filter"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/webhooks/dead-letters [get]
+func (wh *WebhookHandler) GetDeadLettersGin(c *gin.Context) {
+	filters, err := parseWebhookDeliveryFilters(c.Query)
+	if err != nil {
+		handleError("gin", c, http.StatusBadRequest, "Invalid filter", err)
+		return
+	}
+	filters["status"] = model.WebhookDeliveryDead
+	wh.listDeliveriesGin(c, filters)
+}
+
+func (wh *WebhookHandler) listDeliveriesGin(c *gin.Context, filters map[string]interface{}) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(service.DefaultLimit)))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", strconv.Itoa(service.DefaultOffset)))
+
+	deliveries, total, err := wh.Service.GetDeliveries(filters, limit, offset)
+	if err != nil {
+		if isClientError(err) {
+			handleError("gin", c, http.StatusBadRequest, "Invalid filter", err)
+			return
+		}
+		handleError("gin", c, http.StatusInternalServerError, "Failed to retrieve webhook deliveries", err)
+		return
+	}
+	c.JSON(http.StatusOK, PaginatedWebhookDeliveriesResponse{Data: deliveries, Total: total, Limit: limit, Offset: offset})
+}
+
+// RedeliverGin godoc
+// @Summary Redeliver a webhook delivery
+// @Description Queues a delivery again with a fresh attempt budget, e.g. from the dead-letter queue. The receiver gets the same event ID.
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Delivery ID"
+// @Success 202 {object} model.WebhookDelivery "Queued delivery"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 404 {object} map[string]string "Delivery or subscription not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/webhooks/deliveries/{id}/redeliver [post]
+func (wh *WebhookHandler) RedeliverGin(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		handleError("gin", c, http.StatusBadRequest, "Invalid delivery ID", err)
+		return
+	}
+	delivery, err := wh.Service.Redeliver(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			handleError("gin", c, http.StatusNotFound, "Webhook delivery not found", nil)
+			return
+		}
+		handleError("gin", c, http.StatusInternalServerError, "Failed to redeliver webhook", err)
+		return
+	}
+	c.JSON(http.StatusAccepted, delivery)
+}
@@ -0,0 +1,108 @@
+// Package logging builds the structured logger of the application on log/slog. The level
+// and the format (text or JSON) come from LOG_LEVEL and LOG_FORMAT. Every line logged with a
+// context carries the request ID the HTTP servers store in it with WithRequestID, so the
+// lines of a request, including its SQL, can be found together; lines logged in a traced
+// request also carry its trace and span IDs.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/config"
+)
+
+// Attributes of the log lines logged with a context.
+const (
+	RequestIDAttr = "request_id"
+	TraceIDAttr   = "trace_id"
+	SpanIDAttr    = "span_id"
+)
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the request ID of an HTTP request.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the request ID stored in ctx by WithRequestID, or "".
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// New creates the logger of the configuration, writing to stdout.
+func New(cfg *config.AppConfig) *slog.Logger {
+	return NewWithWriter(os.Stdout, cfg.LogLevel, cfg.LogFormat)
+}
+
+// NewWithWriter creates a logger writing lines of the given level and above to w, as JSON
+// when format is "json" and as key=value text otherwise.
+func NewWithWriter(w io.Writer, level, format string) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: ParseLevel(level)}
+	var handler slog.Handler
+	if strings.ToLower(format) == "json" {
+		handler = slog.NewJSONHandler(w, opts)
+	} else {
+		handler = slog.NewTextHandler(w, opts)
+	}
+	return slog.New(contextHandler{handler})
+}
+
+// ParseLevel maps LOG_LEVEL to a slog level. fatal and panic, which slog does not have, log
+// errors only; unknown levels log info and above.
+func ParseLevel(level string) slog.Level {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error", "fatal", "panic":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// OrDefault returns logger, or the default logger when it is nil, so that components built
+// without a logger still log.
+func OrDefault(logger *slog.Logger) *slog.Logger {
+	if logger == nil {
+		return slog.Default()
+	}
+	return logger
+}
+
+// contextHandler adds the request ID and the span of the context to each record.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if requestID := RequestID(ctx); requestID != "" {
+		r.AddAttrs(slog.String(RequestIDAttr, requestID))
+	}
+	if ctx != nil {
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+			r.AddAttrs(slog.String(TraceIDAttr, sc.TraceID().String()), slog.String(SpanIDAttr, sc.SpanID().String()))
+		}
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
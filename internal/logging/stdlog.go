@@ -0,0 +1,36 @@
+package logging
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"strings"
+)
+
+// SetDefault makes logger the default slog logger and sends the lines of the standard log
+// package to it, so that components still using log.Printf log in the same format. Lines
+// starting with "Warning:" are logged as warnings, lines starting with "Error" or "Failed"
+// as errors and the others as info.
+func SetDefault(logger *slog.Logger) {
+	slog.SetDefault(logger)
+	log.SetFlags(0)
+	log.SetOutput(stdLogWriter{logger})
+}
+
+type stdLogWriter struct {
+	logger *slog.Logger
+}
+
+func (w stdLogWriter) Write(p []byte) (int, error) {
+	msg := strings.TrimSpace(string(p))
+	level := slog.LevelInfo
+	switch {
+	case strings.HasPrefix(msg, "Warning:"):
+		level = slog.LevelWarn
+		msg = strings.TrimSpace(strings.TrimPrefix(msg, "Warning:"))
+	case strings.HasPrefix(msg, "Error"), strings.HasPrefix(msg, "Failed"):
+		level = slog.LevelError
+	}
+	w.logger.Log(context.Background(), level, msg)
+	return len(p), nil
+}
@@ -0,0 +1,199 @@
+// Package metrics holds the domain metrics of the application, exposed on /metrics next to
+// the HTTP and gRPC metrics of the middleware. Labels only take values from fixed sets (the
+// Observe functions map anything else to "other"), so the number of series stays bounded
+// however the API is used: no label carries an ID, a user, a mood name or a path.
+package metrics
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/ratelimit"
+)
+
+// Label values.
+const (
+	SourceAPI  = "api"  // Vibes created one at a time, over any transport
+	SourceBulk = "bulk" // Vibes created by a bulk import
+
+	ResultSuccess = "success"
+	ResultInvalid = "invalid" // Rejected by validation; nothing was written
+	ResultFailed  = "failed"  // Failed in storage
+
+	CacheGraphQLVibes = "graphql_vibes" // Vibes of the GraphQL loaders, per request
+	CacheGraphQLMoods = "graphql_moods" // Moods of the GraphQL loaders, per request
+	CacheHit          = "hit"
+	CacheMiss         = "miss"
+
+	other = "other"
+)
+
+var (
+	vibesCreatedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "vibes_created_total",
+			Help: "Vibes created, by source (api or bulk). increase(vibes_created_total[1d]) gives the vibes per day.",
+		},
+		[]string{"source"},
+	)
+
+	vibeEnergyLevel = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "vibe_energy_level",
+			Help:    "Energy level (1 to 10) of the created vibes.",
+			Buckets: prometheus.LinearBuckets(1, 1, 10), // One bucket per level
+		},
+	)
+
+	bulkImportsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "vibe_bulk_imports_total",
+			Help: "Bulk imports, by result (success, invalid or failed).",
+		},
+		[]string{"result"},
+	)
+
+	bulkImportSize = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "vibe_bulk_import_size",
+			Help:    "Number of vibes sent per bulk import, whatever its result.",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 11), // 1 to 1024
+		},
+	)
+
+	exportsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "vibe_exports_total",
+			Help: "Exports, by format (csv, json or other) and result (success or failed).",
+		},
+		[]string{"format", "result"},
+	)
+
+	exportBytes = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "vibe_export_bytes",
+			Help:    "Size of the successful exports, by format.",
+			Buckets: prometheus.ExponentialBuckets(1024, 4, 9), // 1 KiB to 64 MiB
+		},
+		[]string{"format"},
+	)
+
+	exportDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "vibe_export_duration_seconds",
+			Help:    "Duration of the exports, by format, including the failed ones.",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"format"},
+	)
+
+	cacheRequestsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cache_requests_total",
+			Help: "Cache lookups, by cache and result (hit or miss).",
+		},
+		[]string{"cache", "result"},
+	)
+
+	rateLimitRejectionsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rate_limit_rejections_total",
+			Help: "Requests rejected with 429 by the rate limiter, by policy.",
+		},
+		[]string{"policy"},
+	)
+
+	rateLimitStoreErrorsTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "rate_limit_store_errors_total",
+			Help: "Rate limit checks that failed in the store; the requests were let through.",
+		},
+	)
+)
+
+// Allowed label values. A new cache, policy or export format is counted as "other" until
+// it is added here.
+var (
+	sources       = map[string]bool{SourceAPI: true, SourceBulk: true}
+	exportFormats = map[string]bool{"csv": true, "json": true}
+	cacheNames    = map[string]bool{CacheGraphQLVibes: true, CacheGraphQLMoods: true}
+	policyNames   = map[string]bool{ratelimit.PolicyDefault: true, ratelimit.PolicyBulk: true, ratelimit.PolicyExport: true, ratelimit.PolicyAuth: true}
+)
+
+// bounded returns value when it is one of allowed, and "other" otherwise.
+func bounded(value string, allowed map[string]bool) string {
+	if allowed[value] {
+		return value
+	}
+	return other
+}
+
+// ObserveVibeCreated counts a created vibe and its energy level.
+func ObserveVibeCreated(source string, energyLevel int) {
+	vibesCreatedTotal.WithLabelValues(bounded(source, sources)).Inc()
+	vibeEnergyLevel.Observe(float64(energyLevel))
+}
+
+// ObserveBulkImport records the size and result of a bulk import of size vibes. invalid
+// tells validation failures from storage failures when err is not nil.
+func ObserveBulkImport(size int, err error, invalid bool) {
+	bulkImportSize.Observe(float64(size))
+	bulkImportsTotal.WithLabelValues(result(err, invalid)).Inc()
+}
+
+// ObserveExport records an export of the given format that produced size bytes.
+func ObserveExport(format string, size int, duration time.Duration, err error) {
+	format = bounded(strings.ToLower(format), exportFormats)
+	exportDuration.WithLabelValues(format).Observe(duration.Seconds())
+	exportsTotal.WithLabelValues(format, result(err, false)).Inc()
+	if err == nil {
+		exportBytes.WithLabelValues(format).Observe(float64(size))
+	}
+}
+
+// ObserveCache counts a lookup of the named cache.
+func ObserveCache(cache string, hit bool) {
+	outcome := CacheMiss
+	if hit {
+		outcome = CacheHit
+	}
+	cacheRequestsTotal.WithLabelValues(bounded(cache, cacheNames), outcome).Inc()
+}
+
+// ObserveRateLimitRejection counts a request rejected under the named policy.
+func ObserveRateLimitRejection(policy string) {
+	rateLimitRejectionsTotal.WithLabelValues(bounded(policy, policyNames)).Inc()
+}
+
+// ObserveRateLimitStoreError counts a rate limit check that failed in the store.
+func ObserveRateLimitStoreError() {
+	rateLimitStoreErrorsTotal.Inc()
+}
+
+func result(err error, invalid bool) string {
+	switch {
+	case err == nil:
+		return ResultSuccess
+	case invalid:
+		return ResultInvalid
+	default:
+		return ResultFailed
+	}
+}
+
+// RegisterDBStats exports the connection pool statistics of db (sql.DB.Stats) as the
+// go_sql_* metrics, with db_name="daily_vibe_tracker". Registering the pool again is a no-op.
+func RegisterDBStats(db *sql.DB) error {
+	err := prometheus.Register(collectors.NewDBStatsCollector(db, "daily_vibe_tracker"))
+	var registered prometheus.AlreadyRegisteredError
+	if errors.As(err, &registered) {
+		return nil
+	}
+	return err
+}
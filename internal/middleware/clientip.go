@@ -0,0 +1,159 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ClientIPKey is the Fiber local / Gin context key holding the client IP resolved by
+// ClientIPFiber or ClientIPGin.
+const ClientIPKey = "client_ip"
+
+// clientIPResolver finds the client IP of a request behind trusted proxies. Both servers use
+// it rather than the proxy settings of their framework: Fiber trusts a single header and Gin
+// does not read Forwarded.
+type clientIPResolver struct {
+	trusted []netip.Prefix
+}
+
+// newClientIPResolver trusts the proxies in the given CIDRs or single addresses. Entries that
+// are neither are left out; config.LoadConfig already warns about them.
+func newClientIPResolver(proxies []string) clientIPResolver {
+	var r clientIPResolver
+	for _, proxy := range proxies {
+		proxy = strings.TrimSpace(proxy)
+		if prefix, err := netip.ParsePrefix(proxy); err == nil {
+			r.trusted = append(r.trusted, prefix.Masked())
+		} else if addr, err := netip.ParseAddr(proxy); err == nil {
+			r.trusted = append(r.trusted, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+		}
+	}
+	return r
+}
+
+func (r clientIPResolver) isTrusted(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range r.trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolve returns the client IP of a request from the address of its peer and its headers.
+// The headers are only read when the peer is a trusted proxy, in the order Forwarded,
+// X-Forwarded-For, X-Real-IP. The hops of a header are walked from the last, added by the
+// nearest proxy, to the first; the client is the first hop that is not a trusted proxy, so
+// a client cannot pass itself off as another by sending the header itself. A hop that is not
+// an address ends the walk at the proxy that added it.
+func (r clientIPResolver) resolve(remote string, header http.Header) string {
+	peer, ok := parseHop(remote)
+	if !ok || !r.isTrusted(peer) {
+		return remote
+	}
+	var hops []string
+	switch {
+	case len(header.Values("Forwarded")) > 0:
+		hops = forwardedFor(header.Values("Forwarded"))
+	case len(header.Values("X-Forwarded-For")) > 0:
+		for _, value := range header.Values("X-Forwarded-For") {
+			hops = append(hops, strings.Split(value, ",")...)
+		}
+	case header.Get("X-Real-IP") != "":
+		hops = []string{header.Get("X-Real-IP")}
+	}
+	client := peer
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, ok := parseHop(hops[i])
+		if !ok {
+			break
+		}
+		client = hop
+		if !r.isTrusted(hop) {
+			break
+		}
+	}
+	return client.String()
+}
+
+// forwardedFor returns the for= parameters of Forwarded header values (RFC 7239), in order.
+// An element without one counts as an unknown hop.
+func forwardedFor(values []string) []string {
+	var hops []string
+	for _, value := range values {
+		for _, element := range strings.Split(value, ",") {
+			hop := "unknown"
+			for _, pair := range strings.Split(element, ";") {
+				name, val, found := strings.Cut(strings.TrimSpace(pair), "=")
+				if found && strings.EqualFold(name, "for") {
+					hop = val
+				}
+			}
+			hops = append(hops, hop)
+		}
+	}
+	return hops
+}
+
+// parseHop parses an address of a forwarding header: an IP, optionally with a port, quoted,
+// or in brackets for IPv6 ("[2001:db8::1]:4711"). Obfuscated identifiers and "unknown" are
+// not addresses.
+func parseHop(hop string) (netip.Addr, bool) {
+	hop = strings.Trim(strings.TrimSpace(hop), `"`)
+	if host, _, err := net.SplitHostPort(hop); err == nil {
+		hop = host
+	}
+	hop = strings.TrimSuffix(strings.TrimPrefix(hop, "["), "]")
+	addr, err := netip.ParseAddr(hop)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
+
+// ClientIPFiber returns a Fiber middleware storing the client IP of each request under
+// ClientIPKey, trusting the forwarding headers of the given proxies (CIDRs or addresses).
+func ClientIPFiber(trustedProxies []string) fiber.Handler {
+	resolver := newClientIPResolver(trustedProxies)
+	return func(c *fiber.Ctx) error {
+		header := make(http.Header)
+		for _, name := range []string{"Forwarded", "X-Forwarded-For", "X-Real-IP"} {
+			for _, value := range c.Request().Header.PeekAll(name) {
+				header.Add(name, string(value))
+			}
+		}
+		c.Locals(ClientIPKey, resolver.resolve(c.Context().RemoteIP().String(), header))
+		return c.Next()
+	}
+}
+
+// ClientIPGin returns the Gin middleware of ClientIPFiber.
+func ClientIPGin(trustedProxies []string) gin.HandlerFunc {
+	resolver := newClientIPResolver(trustedProxies)
+	return func(c *gin.Context) {
+		c.Set(ClientIPKey, resolver.resolve(c.RemoteIP(), c.Request.Header))
+		c.Next()
+	}
+}
+
+// RealIPFiber returns the client IP stored by ClientIPFiber, or the address of the peer.
+func RealIPFiber(c *fiber.Ctx) string {
+	if ip, ok := c.Locals(ClientIPKey).(string); ok {
+		return ip
+	}
+	return c.Context().RemoteIP().String()
+}
+
+// RealIPGin returns the client IP stored by ClientIPGin, or the address of the peer.
+func RealIPGin(c *gin.Context) string {
+	if ip := c.GetString(ClientIPKey); ip != "" {
+		return ip
+	}
+	return c.RemoteIP()
+}
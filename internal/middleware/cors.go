@@ -1,42 +1,105 @@
 package middleware
 
-// CORS middleware placeholder.
-// Actual implementation is in pkg/fiber/server.go and pkg/gin/server.go for now.
-
-// Example for Fiber (if refactored here):
-/*
 import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/aebalz/daily-vibe-tracker/internal/config" // Assuming config is accessible
 )
 
-func FiberCORS(cfg *config.AppConfig) fiber.Handler {
-	return cors.New(cors.Config{
-		AllowOrigins: cfg.CorsAllowedOrigins[0],
-		AllowHeaders: "Origin, Content-Type, Accept, Authorization",
-		AllowMethods: "GET, POST, PUT, DELETE, OPTIONS",
-	})
+// CORS settings shared by the Fiber and Gin servers.
+var (
+	corsAllowMethods  = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	corsAllowHeaders  = []string{"Origin", "Content-Type", "Accept", "Authorization", "X-API-Key", "X-Request-ID"}
+	corsExposeHeaders = []string{"X-Request-ID", "Content-Disposition", "Retry-After",
+		"RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy"}
+)
+
+const corsMaxAge = 12 * time.Hour
+
+// corsPolicy decides the CORS headers of a request. Both servers use it rather than the
+// CORS middleware of their framework, whose behaviors differ.
+type corsPolicy struct {
+	allowAll bool
+	origins  map[string]bool
 }
-*/
 
-// Example for Gin (if refactored here):
-/*
-import (
-	"github.com/gin-contrib/cors"
-	"github.com/gin-gonic/gin"
-	"github.com/aebalz/daily-vibe-tracker/internal/config" // Assuming config is accessible
-)
+func newCORSPolicy(origins []string) corsPolicy {
+	p := corsPolicy{origins: make(map[string]bool)}
+	for _, origin := range origins {
+		origin = strings.TrimSpace(origin)
+		if origin == "*" {
+			p.allowAll = true
+		}
+		p.origins[strings.TrimSuffix(origin, "/")] = true
+	}
+	return p
+}
 
-func GinCORS(cfg *config.AppConfig) gin.HandlerFunc {
-	corsConfig := cors.DefaultConfig()
-	if len(cfg.CorsAllowedOrigins) == 1 && cfg.CorsAllowedOrigins[0] == "*" {
-		corsConfig.AllowAllOrigins = true
+// headers returns the CORS response headers of a request, and whether it is a preflight
+// request to answer with 204. Requests without an Origin header or from origins that are
+// not allowed get no CORS headers, so browsers block them; the request itself proceeds.
+func (p corsPolicy) headers(method, origin, requestMethod string) ([][2]string, bool) {
+	if origin == "" {
+		return nil, false
+	}
+	preflight := method == http.MethodOptions && requestMethod != ""
+	var headers [][2]string
+	if !p.allowAll {
+		headers = append(headers, [2]string{"Vary", "Origin"})
+	}
+	if !p.allowAll && !p.origins[origin] {
+		return headers, preflight
+	}
+	allowOrigin := origin
+	if p.allowAll {
+		allowOrigin = "*"
+	}
+	headers = append(headers, [2]string{"Access-Control-Allow-Origin", allowOrigin})
+	if preflight {
+		headers = append(headers,
+			[2]string{"Access-Control-Allow-Methods", strings.Join(corsAllowMethods, ", ")},
+			[2]string{"Access-Control-Allow-Headers", strings.Join(corsAllowHeaders, ", ")},
+			[2]string{"Access-Control-Max-Age", strconv.Itoa(int(corsMaxAge.Seconds()))},
+		)
 	} else {
-		corsConfig.AllowOrigins = cfg.CorsAllowedOrigins
+		headers = append(headers, [2]string{"Access-Control-Expose-Headers", strings.Join(corsExposeHeaders, ", ")})
+	}
+	return headers, preflight
+}
+
+// CORSFiber returns the CORS middleware for Fiber, allowing the given origins ("*" for any).
+func CORSFiber(origins []string) fiber.Handler {
+	policy := newCORSPolicy(origins)
+	return func(c *fiber.Ctx) error {
+		headers, preflight := policy.headers(c.Method(), c.Get(fiber.HeaderOrigin), c.Get(fiber.HeaderAccessControlRequestMethod))
+		for _, h := range headers {
+			c.Set(h[0], h[1])
+		}
+		if preflight {
+			return c.SendStatus(fiber.StatusNoContent)
+		}
+		return c.Next()
+	}
+}
+
+// CORSGin returns the CORS middleware for Gin, allowing the given origins ("*" for any).
+func CORSGin(origins []string) gin.HandlerFunc {
+	policy := newCORSPolicy(origins)
+	return func(c *gin.Context) {
+		headers, preflight := policy.headers(c.Request.Method, c.GetHeader("Origin"), c.GetHeader("Access-Control-Request-Method"))
+		for _, h := range headers {
+			c.Header(h[0], h[1])
+		}
+		if preflight {
+			// Gin sets Allow before running the middleware of a 405 response; it is no answer to a preflight.
+			c.Writer.Header().Del("Allow")
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+		c.Next()
 	}
-	corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization"}
-	corsConfig.AllowMethods = []string{"GET", "POST, PUT, DELETE, OPTIONS"}
-	return cors.New(corsConfig)
 }
-*/
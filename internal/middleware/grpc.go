@@ -0,0 +1,194 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/logging"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCRequestIDKey is the metadata key carrying the request ID, like the X-Request-ID header.
+const GRPCRequestIDKey = "x-request-id"
+
+var (
+	grpcRequestsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "grpc_requests_total",
+			Help: "Total number of gRPC calls.",
+		},
+		[]string{"code", "method"},
+	)
+
+	grpcRequestDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "grpc_request_duration_seconds",
+			Help:    "Duration of gRPC calls, including the whole stream for streaming calls.",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"code", "method"},
+	)
+)
+
+// grpcPublicMethods are the services reachable without credentials, so load balancers and
+// tools like grpcurl can use them.
+var grpcPublicMethods = []string{
+	"/grpc.health.v1.Health/",
+	"/grpc.reflection.",
+}
+
+// grpcAuthorize checks the API key sent as "authorization: Bearer <key>" or "x-api-key: <key>".
+// Without configured keys every call is allowed.
+func grpcAuthorize(ctx context.Context, method string, apiKeys []string) error {
+	if len(apiKeys) == 0 {
+		return nil
+	}
+	for _, prefix := range grpcPublicMethods {
+		if strings.HasPrefix(method, prefix) {
+			return nil
+		}
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	var presented string
+	if values := md.Get("authorization"); len(values) > 0 {
+		presented = strings.TrimSpace(strings.TrimPrefix(values[0], "Bearer "))
+	} else if values := md.Get("x-api-key"); len(values) > 0 {
+		presented = strings.TrimSpace(values[0])
+	}
+	if presented == "" {
+		return status.Error(codes.Unauthenticated, "missing API key")
+	}
+	for _, key := range apiKeys {
+		key = strings.TrimSpace(key)
+		if key != "" && subtle.ConstantTimeCompare([]byte(presented), []byte(key)) == 1 {
+			return nil
+		}
+	}
+	return status.Error(codes.Unauthenticated, "invalid API key")
+}
+
+// GRPCAuthUnaryInterceptor rejects unary calls without a valid API key.
+func GRPCAuthUnaryInterceptor(apiKeys []string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := grpcAuthorize(ctx, info.FullMethod, apiKeys); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// GRPCAuthStreamInterceptor rejects streaming calls without a valid API key.
+func GRPCAuthStreamInterceptor(apiKeys []string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := grpcAuthorize(ss.Context(), info.FullMethod, apiKeys); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// grpcRequestID returns the request ID sent by the client, or a new one.
+func grpcRequestID(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(GRPCRequestIDKey); len(values) > 0 && values[0] != "" {
+		return values[0]
+	}
+	return uuid.New().String()
+}
+
+// logGRPCCall logs a finished call like the HTTP request logs: failures on the server side
+// as errors, other failures as warnings. ctx carries the request ID.
+func logGRPCCall(logger *slog.Logger, ctx context.Context, method string, start time.Time, err error) {
+	clientAddr := ""
+	if p, ok := peer.FromContext(ctx); ok {
+		clientAddr = p.Addr.String()
+	}
+	st, _ := status.FromError(err)
+	attrs := []any{
+		slog.String("method", method),
+		slog.String("code", st.Code().String()),
+		slog.Duration("latency", time.Since(start)),
+		slog.String("peer", clientAddr),
+	}
+	switch st.Code() {
+	case codes.OK:
+		logger.InfoContext(ctx, "gRPC call", attrs...)
+	case codes.Unknown, codes.Internal, codes.DataLoss, codes.Unavailable:
+		logger.ErrorContext(ctx, "gRPC call", append(attrs, slog.String("error", st.Message()))...)
+	default:
+		logger.WarnContext(ctx, "gRPC call", append(attrs, slog.String("error", st.Message()))...)
+	}
+}
+
+// contextStream is a ServerStream with another context.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s contextStream) Context() context.Context { return s.ctx }
+
+// GRPCLoggingUnaryInterceptor logs unary calls to logger, stores the request ID in the context
+// of the call for the log lines of the handlers and returns it in the header metadata.
+func GRPCLoggingUnaryInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	logger = logging.OrDefault(logger)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		requestID := grpcRequestID(ctx)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(GRPCRequestIDKey, requestID))
+		ctx = logging.WithRequestID(ctx, requestID)
+		resp, err := handler(ctx, req)
+		logGRPCCall(logger, ctx, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// GRPCLoggingStreamInterceptor logs streaming calls when they end.
+func GRPCLoggingStreamInterceptor(logger *slog.Logger) grpc.StreamServerInterceptor {
+	logger = logging.OrDefault(logger)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		requestID := grpcRequestID(ss.Context())
+		_ = ss.SetHeader(metadata.Pairs(GRPCRequestIDKey, requestID))
+		ctx := logging.WithRequestID(ss.Context(), requestID)
+		err := handler(srv, contextStream{ServerStream: ss, ctx: ctx})
+		logGRPCCall(logger, ctx, info.FullMethod, start, err)
+		return err
+	}
+}
+
+func observeGRPCCall(method string, start time.Time, err error) {
+	code := status.Code(err).String()
+	grpcRequestsTotal.WithLabelValues(code, method).Inc()
+	grpcRequestDuration.WithLabelValues(code, method).Observe(time.Since(start).Seconds())
+}
+
+// GRPCMetricsUnaryInterceptor records Prometheus metrics for unary calls.
+func GRPCMetricsUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		observeGRPCCall(info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// GRPCMetricsStreamInterceptor records Prometheus metrics for streaming calls.
+func GRPCMetricsStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		observeGRPCCall(info.FullMethod, start, err)
+		return err
+	}
+}
@@ -1,60 +1,53 @@
 package middleware
 
-// Logging middleware placeholder.
-// Actual implementation is in pkg/fiber/server.go and pkg/gin/server.go for now.
-
-// Example for Fiber (if refactored here):
-/*
-import "github.com/gofiber/fiber/v2"
-import "github.com/gofiber/fiber/v2/middleware/logger"
-
-func FiberLogger() fiber.Handler {
-	return logger.New(logger.Config{
-		Format: "[${time}] ${ip} ${status} - ${method} ${path} ${latency}\nREQUEST_ID: ${locals:requestid}\n",
-	})
-}
-*/
-
-// Example for Gin (if refactored here):
-/*
 import (
-	"log"
+	"context"
+	"log/slog"
 	"time"
+
 	"github.com/gin-gonic/gin"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/logging"
 )
 
-const GinRequestIDKey = "requestID" // Assuming this constant would be shared or defined centrally
+// logRequest logs an answered HTTP request: server errors as errors, client errors as
+// warnings and the rest as info. ctx carries the request ID.
+func logRequest(logger *slog.Logger, ctx context.Context, method, path string, status int, latency time.Duration, clientIP string) {
+	level := slog.LevelInfo
+	switch {
+	case status >= 500:
+		level = slog.LevelError
+	case status >= 400:
+		level = slog.LevelWarn
+	}
+	logger.LogAttrs(ctx, level, "HTTP request",
+		slog.String("method", method),
+		slog.String("path", path),
+		slog.Int("status", status),
+		slog.Duration("latency", latency),
+		slog.String("client_ip", clientIP),
+	)
+}
+
+// RequestLoggerFiber returns a Fiber middleware logging each request to logger once it is
+// answered. It runs after the request ID and client IP middleware.
+func RequestLoggerFiber(logger *slog.Logger) fiber.Handler {
+	logger = logging.OrDefault(logger)
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+		logRequest(logger, c.UserContext(), c.Method(), c.OriginalURL(), fiberStatus(c, err), time.Since(start), RealIPFiber(c))
+		return err
+	}
+}
 
-func GinLogger() gin.HandlerFunc {
+// RequestLoggerGin returns the Gin middleware of RequestLoggerFiber.
+func RequestLoggerGin(logger *slog.Logger) gin.HandlerFunc {
+	logger = logging.OrDefault(logger)
 	return func(c *gin.Context) {
 		start := time.Now()
-		path := c.Request.URL.Path
-		raw := c.Request.URL.RawQuery
-
-		c.Next() // Process request
-
-		end := time.Now()
-		latency := end.Sub(start)
-		clientIP := c.ClientIP()
-		method := c.Request.Method
-		statusCode := c.Writer.Status()
-		errorMessage := c.Errors.ByType(gin.ErrorTypePrivate).String()
-		requestID, _ := c.Get(GinRequestIDKey)
-
-		if raw != "" {
-			path = path + "?" + raw
-		}
-
-		log.Printf("[GIN] %s | %3d | %13v | %15s | %s %s | %s | RequestID: %s",
-			end.Format("2006/01/02 - 15:04:05"),
-			statusCode,
-			latency,
-			clientIP,
-			method,
-			path,
-			errorMessage,
-			requestID,
-		)
+		c.Next()
+		logRequest(logger, c.Request.Context(), c.Request.Method, c.Request.URL.RequestURI(), c.Writer.Status(), time.Since(start), RealIPGin(c))
 	}
 }
-*/
@@ -33,44 +33,36 @@ var (
 	// Add more metrics as needed, e.g. active requests, response size
 )
 
-// normalizePath attempts to reduce cardinality for path labels.
-// Example: /api/v1/vibes/123 -> /api/v1/vibes/:id
-// This needs to be adjusted based on actual routing patterns.
-func normalizePath(path string, framework string, ctx interface{}) string {
-	// Simple normalization for paths with IDs.
-	// This is a basic example and might need to be more sophisticated
-	// depending on the route structure.
-	parts := strings.Split(strings.Trim(path, "/"), "/")
-
-	// Example: /api/v1/vibes/{id}
-	if len(parts) > 0 && strings.HasPrefix(path, "/api/v1/vibes/") && len(parts) == 4 {
-		_, err := strconv.Atoi(parts[3])
-		if err == nil {
-			return "/" + strings.Join(parts[:3], "/") + "/:id"
-		}
+// unmatchedRoute is the path label of requests no route matched, e.g. 404s of scanners, so
+// that arbitrary URLs do not each create a series.
+const unmatchedRoute = "unmatched"
+
+// fiberRoute returns the template of the route that answered c, e.g. /api/v1/vibes/:id, or
+// "" when no route matched: unmatched requests end on the route of the last middleware, "/".
+func fiberRoute(c *fiber.Ctx) string {
+	route := c.Route().Path
+	if route == "/" && c.Path() != "/" {
+		return ""
 	}
+	return route
+}
 
-	// For Fiber, try to get the matched route pattern if available
-	if framework == "fiber" {
-		fCtx := ctx.(*fiber.Ctx)
-		routePath := fCtx.Route().Path
-		if routePath != "" && routePath != "/" { // Avoid using generic "/" if specific route matched
-			// Fiber paths might already be in a good format e.g. /api/v1/vibes/:id
-			return routePath
-		}
+// ginRoute returns the template of the route that answered c, or "" when no route matched.
+// Routes are also registered with a trailing slash; both count under one template.
+func ginRoute(c *gin.Context) string {
+	route := c.FullPath()
+	if len(route) > 1 {
+		route = strings.TrimSuffix(route, "/")
 	}
+	return route
+}
 
-	// For Gin, try to get the matched route pattern
-	if framework == "gin" {
-		gCtx := ctx.(*gin.Context)
-		if gCtx.FullPath() != "" && gCtx.FullPath() != "/" {
-			// Gin FullPath() usually gives something like /api/v1/vibes/:id
-			return gCtx.FullPath()
-		}
+// routeLabel returns the path label of a route template.
+func routeLabel(route string) string {
+	if route == "" {
+		return unmatchedRoute
 	}
-
-	// Fallback to the provided path if no specific pattern matched or normalization applied
-	return path
+	return route
 }
 
 // MetricsMiddlewareFiber creates a Fiber middleware for collecting Prometheus metrics.
@@ -92,11 +84,8 @@ func MetricsMiddlewareFiber() fiber.Handler {
 			}
 		}
 
-		// Use c.Route().Path for potentially more accurate path templating if configured well.
-		// path := c.Route().Path
-		// If c.Route().Path is not specific enough (e.g. '/*' for a group), use c.Path()
-		// and normalize it.
-		path := normalizePath(c.Path(), "fiber", c)
+		// Route templates only, so the number of series stays bounded
+		path := routeLabel(fiberRoute(c))
 
 		duration := time.Since(start).Seconds()
 
@@ -115,13 +104,7 @@ func MetricsMiddlewareGin() gin.HandlerFunc {
 
 		statusCode := c.Writer.Status()
 
-		// Use c.FullPath() for Gin, which usually gives the template path like /users/:id
-		// path := c.FullPath()
-		// If c.FullPath() is empty (e.g. for NoRoute), use c.Request.URL.Path and normalize.
-		path := normalizePath(c.Request.URL.Path, "gin", c)
-		if c.FullPath() != "" { // Prefer FullPath if available and not just root
-			path = c.FullPath()
-		}
+		path := routeLabel(ginRoute(c))
 
 		duration := time.Since(start).Seconds()
 
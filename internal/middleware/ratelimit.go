@@ -1,92 +1,160 @@
 package middleware
 
 import (
+	"log/slog"
 	"net/http"
-	"sync"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gofiber/fiber/v2"
-	"golang.org/x/time/rate"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/logging"
+	"github.com/aebalz/daily-vibe-tracker/internal/metrics"
+	"github.com/aebalz/daily-vibe-tracker/internal/ratelimit"
 )
 
-// IPMeta stores the limiter and last seen time for an IP
-type IPMeta struct {
-	limiter  *rate.Limiter
-	lastSeen time.Time
-}
+const rateLimitMessage = "Too many requests. Please try again later."
 
-var (
-	mu      sync.Mutex
-	clients = make(map[string]*IPMeta)
-)
+// rateLimitClient identifies the client of a request: the authenticated user when there is
+// one, so that a user has the same limit from every address, and the client IP otherwise.
+func rateLimitClient(user, ip string) string {
+	if user != "" {
+		return "user:" + user
+	}
+	return "ip:" + ip
+}
 
-// Cleanup visitors every minute
-func init() {
-	go func() {
-		for {
-			time.Sleep(time.Minute)
-			mu.Lock()
-			for ip, client := range clients {
-				if time.Since(client.lastSeen) > 3*time.Minute {
-					delete(clients, ip)
-				}
-			}
-			mu.Unlock()
+// RateLimiterFiber creates a Fiber middleware limiting requests under the named policy of the
+// limiter. Routes behind authentication run it after the authentication middleware, which
+// stores the user under userKey. Every response carries the RateLimit-* headers; rejected
+// requests get 429 with Retry-After. A nil limiter, or a policy that does not limit, lets
+// every request through. Store errors are logged to logger.
+func RateLimiterFiber(limiter *ratelimit.Limiter, policy, userKey string, logger *slog.Logger) fiber.Handler {
+	logger = logging.OrDefault(logger)
+	if limiter == nil {
+		return func(c *fiber.Ctx) error { return c.Next() }
+	}
+	p, ok := limiter.Policy(policy)
+	if !ok {
+		return func(c *fiber.Ctx) error { return c.Next() }
+	}
+	return func(c *fiber.Ctx) error {
+		user, _ := c.Locals(userKey).(string)
+		result, err := limiter.Take(c.UserContext(), p, rateLimitClient(user, RealIPFiber(c)))
+		if err != nil {
+			// Fail open: an unavailable store should not take the API down.
+			metrics.ObserveRateLimitStoreError()
+			logger.WarnContext(c.UserContext(), "Rate limit check failed, allowing the request", slog.String("error", err.Error()))
+			return c.Next()
+		}
+		for name, value := range result.Headers(p) {
+			c.Set(name, value)
 		}
-	}()
+		if !result.Allowed {
+			metrics.ObserveRateLimitRejection(p.Name)
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{"error": rateLimitMessage})
+		}
+		return c.Next()
+	}
 }
 
-func getVisitor(ip string, r rate.Limit, b int) *rate.Limiter {
-	mu.Lock()
-	defer mu.Unlock()
-
-	client, exists := clients[ip]
-	if !exists {
-		limiter := rate.NewLimiter(r, b)
-		clients[ip] = &IPMeta{limiter, time.Now()}
-		return limiter
+// RateLimiterGin creates the Gin middleware of RateLimiterFiber.
+func RateLimiterGin(limiter *ratelimit.Limiter, policy, userKey string, logger *slog.Logger) gin.HandlerFunc {
+	logger = logging.OrDefault(logger)
+	if limiter == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	p, ok := limiter.Policy(policy)
+	if !ok {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return func(c *gin.Context) {
+		result, err := limiter.Take(c.Request.Context(), p, rateLimitClient(c.GetString(userKey), RealIPGin(c)))
+		if err != nil {
+			metrics.ObserveRateLimitStoreError()
+			logger.WarnContext(c.Request.Context(), "Rate limit check failed, allowing the request", slog.String("error", err.Error()))
+			c.Next()
+			return
+		}
+		for name, value := range result.Headers(p) {
+			c.Header(name, value)
+		}
+		if !result.Allowed {
+			metrics.ObserveRateLimitRejection(p.Name)
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": rateLimitMessage})
+			return
+		}
+		c.Next()
 	}
-
-	client.lastSeen = time.Now()
-	return client.limiter
 }
 
-// RateLimiterFiber creates a Fiber middleware for rate limiting.
-// It uses a token bucket algorithm based on IP address.
-func RateLimiterFiber(requestsPerSecond float64, burst int) fiber.Handler {
-	r := rate.Limit(requestsPerSecond)
+// AuthFailureLimiterFiber creates a Fiber middleware limiting failed authentications per IP
+// under the auth policy of the limiter, so API keys cannot be guessed at the rate of the
+// other limits. It runs before the authentication middleware: a client over the limit gets
+// 429 before its key is checked, and only requests answered 401 count against it. Store
+// errors are logged to logger.
+func AuthFailureLimiterFiber(limiter *ratelimit.Limiter, logger *slog.Logger) fiber.Handler {
+	logger = logging.OrDefault(logger)
+	if limiter == nil {
+		return func(c *fiber.Ctx) error { return c.Next() }
+	}
+	p, ok := limiter.Policy(ratelimit.PolicyAuth)
+	if !ok {
+		return func(c *fiber.Ctx) error { return c.Next() }
+	}
 	return func(c *fiber.Ctx) error {
-		ip := c.IP()
-		limiter := getVisitor(ip, r, burst)
-
-		if !limiter.Allow() {
-			// Adding a Retry-After header (optional, but good practice)
-			// This is a simplified calculation; a more accurate one might be needed
-			// depending on the rate.Limiter's state.
-			c.Set("Retry-After", "60") // Suggest retrying after 60 seconds
-			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
-				"error": "Too many requests. Please try again later.",
-			})
+		client := rateLimitClient("", RealIPFiber(c))
+		result, err := limiter.Peek(c.UserContext(), p, client)
+		if err != nil {
+			metrics.ObserveRateLimitStoreError()
+			logger.WarnContext(c.UserContext(), "Rate limit check failed, allowing the request", slog.String("error", err.Error()))
+		} else if !result.Allowed {
+			metrics.ObserveRateLimitRejection(p.Name)
+			for name, value := range result.Headers(p) {
+				c.Set(name, value)
+			}
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{"error": rateLimitMessage})
 		}
-		return c.Next()
+		err = c.Next()
+		if fiberStatus(c, err) == fiber.StatusUnauthorized {
+			if _, err := limiter.Take(c.UserContext(), p, client); err != nil {
+				metrics.ObserveRateLimitStoreError()
+				logger.WarnContext(c.UserContext(), "Failed to count a failed authentication", slog.String("error", err.Error()))
+			}
+		}
+		return err
 	}
 }
 
-// RateLimiterGin creates a Gin middleware for rate limiting.
-func RateLimiterGin(requestsPerSecond float64, burst int) gin.HandlerFunc {
-	r := rate.Limit(requestsPerSecond)
+// AuthFailureLimiterGin creates the Gin middleware of AuthFailureLimiterFiber.
+func AuthFailureLimiterGin(limiter *ratelimit.Limiter, logger *slog.Logger) gin.HandlerFunc {
+	logger = logging.OrDefault(logger)
+	if limiter == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	p, ok := limiter.Policy(ratelimit.PolicyAuth)
+	if !ok {
+		return func(c *gin.Context) { c.Next() }
+	}
 	return func(c *gin.Context) {
-		ip := c.ClientIP()
-		limiter := getVisitor(ip, r, burst)
-
-		if !limiter.Allow() {
-			c.Header("Retry-After", "60")
-			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
-				"error": "Too many requests. Please try again later.",
-			})
+		client := rateLimitClient("", RealIPGin(c))
+		result, err := limiter.Peek(c.Request.Context(), p, client)
+		if err != nil {
+			metrics.ObserveRateLimitStoreError()
+			logger.WarnContext(c.Request.Context(), "Rate limit check failed, allowing the request", slog.String("error", err.Error()))
+		} else if !result.Allowed {
+			metrics.ObserveRateLimitRejection(p.Name)
+			for name, value := range result.Headers(p) {
+				c.Header(name, value)
+			}
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": rateLimitMessage})
 			return
 		}
 		c.Next()
+		if c.Writer.Status() == http.StatusUnauthorized {
+			if _, err := limiter.Take(c.Request.Context(), p, client); err != nil {
+				metrics.ObserveRateLimitStoreError()
+				logger.WarnContext(c.Request.Context(), "Failed to count a failed authentication", slog.String("error", err.Error()))
+			}
+		}
 	}
 }
@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/tracing"
+)
+
+// startServerSpan starts the server span of a request, as a child of the span of its W3C
+// traceparent header when it has one. The span is renamed after its route once it matched.
+func startServerSpan(ctx context.Context, carrier propagation.TextMapCarrier, method, path, clientIP, userAgent string) (context.Context, trace.Span) {
+	ctx = otel.GetTextMapPropagator().Extract(ctx, carrier)
+	return tracing.Tracer().Start(ctx, method, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(
+		semconv.HTTPRequestMethodKey.String(method),
+		semconv.URLPath(path),
+		semconv.ClientAddress(clientIP),
+		semconv.UserAgentOriginal(userAgent),
+	))
+}
+
+// endServerSpan names the span "METHOD route" and records the status of the response.
+// Server errors are span errors; client errors are not, as the server worked as intended.
+func endServerSpan(span trace.Span, method, route string, status int) {
+	if route != "" {
+		span.SetName(method + " " + route)
+		span.SetAttributes(semconv.HTTPRoute(route))
+	}
+	span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+	if status >= 500 {
+		span.SetStatus(codes.Error, http.StatusText(status))
+	}
+	span.End()
+}
+
+// fiberStatus returns the status of a Fiber response. When a handler returned err, the
+// error handler writes the response only after the middleware returns.
+func fiberStatus(c *fiber.Ctx, err error) int {
+	if err == nil {
+		return c.Response().StatusCode()
+	}
+	var e *fiber.Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return fiber.StatusInternalServerError
+}
+
+// fiberHeaderCarrier adapts the request headers of Fiber to the propagators of OpenTelemetry.
+type fiberHeaderCarrier struct {
+	c *fiber.Ctx
+}
+
+func (h fiberHeaderCarrier) Get(key string) string { return h.c.Get(key) }
+
+func (h fiberHeaderCarrier) Set(key, value string) { h.c.Request().Header.Set(key, value) }
+
+func (h fiberHeaderCarrier) Keys() []string {
+	headers := h.c.GetReqHeaders()
+	keys := make([]string, 0, len(headers))
+	for key := range headers {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// TracingFiber returns a Fiber middleware starting a server span for each request and
+// storing it in the user context, where the services and GORM find it. It runs after the
+// request ID and client IP middleware.
+func TracingFiber() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Spans outlive the request, whose strings Fiber reuses, so they get copies.
+		method := strings.Clone(c.Method())
+		ctx, span := startServerSpan(c.UserContext(), fiberHeaderCarrier{c}, method, strings.Clone(c.Path()), strings.Clone(RealIPFiber(c)), strings.Clone(c.Get(fiber.HeaderUserAgent)))
+		c.SetUserContext(ctx)
+		err := c.Next()
+		endServerSpan(span, method, strings.Clone(fiberRoute(c)), fiberStatus(c, err))
+		return err
+	}
+}
+
+// TracingGin returns the Gin middleware of TracingFiber.
+func TracingGin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, span := startServerSpan(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header), c.Request.Method, c.Request.URL.Path, RealIPGin(c), c.Request.UserAgent())
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+		endServerSpan(span, c.Request.Method, ginRoute(c), c.Writer.Status())
+	}
+}
@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// Activity represents a canonical entry in the activity catalog.
+// Activities form a hierarchy through ParentID (e.g. "exercise" > "running"),
+// and Vibe.Activities stores canonical activity names.
+// Like moods, catalog entries are hard-deleted so that a removed name can be registered again.
+type Activity struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	Name      string    `json:"name" gorm:"uniqueIndex;not null"`
+	Aliases   []string  `json:"aliases" gorm:"type:text[]"`
+	ParentID  *uint     `json:"parent_id" gorm:"index"` // Category this activity belongs to, nil for top-level
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ActivityUsage holds usage statistics for a single activity name across all vibes.
+type ActivityUsage struct {
+	Name           string     `json:"name"`
+	UsageCount     int64      `json:"usage_count"`
+	LastUsed       *time.Time `json:"last_used"`
+	AverageEnergy  *float64   `json:"average_energy"`
+	AverageValence *float64   `json:"average_valence"` // Based on the mood catalog, nil if no vibe mood is catalogued
+}
+
+// ActivityWithUsage is an activity together with its usage statistics.
+// TotalUsageCount includes the usage of all descendant activities.
+type ActivityWithUsage struct {
+	Activity
+	Path            string     `json:"path"` // e.g. "exercise > running"
+	UsageCount      int64      `json:"usage_count"`
+	TotalUsageCount int64      `json:"total_usage_count"`
+	LastUsed        *time.Time `json:"last_used"`
+	AverageEnergy   *float64   `json:"average_energy"`
+	AverageValence  *float64   `json:"average_valence"`
+}
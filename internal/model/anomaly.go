@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// Anomaly kinds.
+const (
+	AnomalyEnergyDrop          = "energy_drop"           // Energy far below its rolling baseline
+	AnomalyNegativeMoodCluster = "negative_mood_cluster" // Unusually many negative moods in a short window
+)
+
+// Anomaly severities, from least to most severe.
+const (
+	SeverityLow    = "low"
+	SeverityMedium = "medium"
+	SeverityHigh   = "high"
+)
+
+// AnomalyEvent records an unusual change in the vibe history, detected against a rolling baseline.
+// An anomaly of a given kind is recorded at most once per day.
+type AnomalyEvent struct {
+	ID         uint      `json:"id" gorm:"primarykey"`
+	Kind       string    `json:"kind" gorm:"uniqueIndex:idx_anomaly_kind_date;not null"`
+	Date       time.Time `json:"date" gorm:"uniqueIndex:idx_anomaly_kind_date;index;not null"` // Day the anomaly was observed
+	Severity   string    `json:"severity" gorm:"index;not null"`
+	Value      float64   `json:"value"`    // Observed energy, or share of negative days in the window
+	Baseline   float64   `json:"baseline"` // Expected value from history
+	ZScore     float64   `json:"z_score"`
+	Message    string    `json:"message"`
+	DetectedAt time.Time `json:"detected_at" gorm:"autoCreateTime"`
+}
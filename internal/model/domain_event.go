@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// DomainEvent is an event in the transactional outbox. It is written in the same transaction
+// as the change it describes and published to in-process subscribers after the commit.
+type DomainEvent struct {
+	ID              uint       `json:"id" gorm:"primarykey"`
+	Type            string     `json:"type" gorm:"index;not null"`
+	Payload         string     `json:"payload" gorm:"type:jsonb;not null"`  // JSON encoded typed event
+	DoneSubscribers []string   `json:"done_subscribers" gorm:"type:text[]"` // Subscribers that took the event, skipped on a retry
+	Attempts        int        `json:"attempts"`
+	NextAttemptAt   *time.Time `json:"next_attempt_at,omitempty" gorm:"index"` // Claimed by a relay, or waiting for a retry, until then
+	LastError       string     `json:"last_error,omitempty"`
+	DispatchedAt    *time.Time `json:"dispatched_at,omitempty" gorm:"index"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Goal metrics. Activity and mood goals count the days in a period on which the activity was
+// logged or the mood was felt.
+const (
+	GoalMetricActivity      = "activity"       // Days with the activity in Subject
+	GoalMetricMood          = "mood"           // Days with the mood in Subject
+	GoalMetricAverageEnergy = "average_energy" // Average energy level of the logged days
+	GoalMetricLoggedDays    = "logged_days"    // Days with a vibe
+)
+
+// Goal periods.
+const (
+	GoalPeriodDaily   = "daily"
+	GoalPeriodWeekly  = "weekly" // Monday to Sunday
+	GoalPeriodMonthly = "monthly"
+)
+
+// Goal period statuses.
+const (
+	GoalStatusHit        = "hit"
+	GoalStatusMiss       = "miss"
+	GoalStatusInProgress = "in_progress" // Current period, target not reached yet
+)
+
+// Goal is a target evaluated once per period, e.g. "exercise 3x/week" is
+// {Metric: activity, Subject: exercise, Operator: gte, Target: 3, Period: weekly}.
+type Goal struct {
+	ID          uint      `json:"id" gorm:"primarykey"`
+	Name        string    `json:"name" gorm:"not null"`
+	Description string    `json:"description"`
+	Metric      string    `json:"metric" gorm:"not null"`
+	Subject     string    `json:"subject,omitempty"` // Activity or mood name for activity and mood goals
+	Operator    string    `json:"operator" gorm:"not null"`
+	Target      float64   `json:"target"`
+	Period      string    `json:"period" gorm:"not null"`
+	StartDate   time.Time `json:"start_date" gorm:"not null"` // The period containing this day is the first one evaluated
+	Active      bool      `json:"active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	// Expression is the target in the "metric[:subject]:op:target" form, e.g. "activity:exercise:gte:3".
+	Expression string `json:"expression" gorm:"-"`
+}
+
+// PopulateExpression fills Expression from the goal's target fields.
+func (g *Goal) PopulateExpression() {
+	target := strconv.FormatFloat(g.Target, 'f', -1, 64)
+	if g.Subject != "" {
+		g.Expression = fmt.Sprintf("%s:%s:%s:%s", g.Metric, g.Subject, g.Operator, target)
+		return
+	}
+	g.Expression = fmt.Sprintf("%s:%s:%s", g.Metric, g.Operator, target)
+}
+
+// GoalPeriodResult is the evaluation of a goal for one period.
+type GoalPeriodResult struct {
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+	Value       *float64  `json:"value"` // Nil for average energy when no day was logged
+	Status      string    `json:"status"`
+	LoggedDays  int       `json:"logged_days"`
+}
+
+// GoalProgress reports the current period of a goal together with its streaks.
+type GoalProgress struct {
+	Goal          Goal             `json:"goal"`
+	Current       GoalPeriodResult `json:"current"`
+	Percent       float64          `json:"percent"`             // Progress toward the target, 0-100
+	Remaining     *float64         `json:"remaining,omitempty"` // Days still needed for count goals with a minimum
+	DaysLeft      int              `json:"days_left"`           // Days left in the current period, including today
+	CurrentStreak int              `json:"current_streak"`      // Consecutive hit periods up to now
+	LongestStreak int              `json:"longest_streak"`
+	HitRate       *float64         `json:"hit_rate,omitempty"` // Share of completed periods that were hits
+}
+
+// GoalHistory lists the evaluated periods of a goal, most recent first.
+type GoalHistory struct {
+	Goal          Goal               `json:"goal"`
+	Periods       []GoalPeriodResult `json:"periods"`
+	Hits          int                `json:"hits"`
+	Misses        int                `json:"misses"`
+	CurrentStreak int                `json:"current_streak"`
+	LongestStreak int                `json:"longest_streak"`
+}
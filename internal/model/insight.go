@@ -0,0 +1,80 @@
+package model
+
+import "time"
+
+// Correlation factor kinds.
+const (
+	FactorTypeActivity = "activity" // 1 on days the activity was logged, 0 otherwise
+	FactorTypeMetric   = "metric"   // Custom metric value
+)
+
+// Correlation outcomes. Custom metrics can also be outcomes, using the metric name.
+const (
+	OutcomeEnergy  = "energy"  // Energy level (1-10)
+	OutcomeValence = "valence" // Valence of the mood from the mood catalog (-1..1)
+)
+
+// CorrelationInsight describes the relationship between a factor on one day and an
+// outcome Lag days later, e.g. "running" yesterday and energy today.
+type CorrelationInsight struct {
+	Factor      string   `json:"factor"`
+	FactorType  string   `json:"factor_type"`
+	Outcome     string   `json:"outcome"`
+	Lag         int      `json:"lag_days"`
+	Samples     int      `json:"samples"`               // Number of paired days
+	Occurrences *int     `json:"occurrences,omitempty"` // Paired days on which the activity was logged
+	Pearson     float64  `json:"pearson"`
+	Spearman    *float64 `json:"spearman,omitempty"`
+	Lift        *float64 `json:"lift,omitempty"` // P(good outcome | activity) / P(good outcome)
+	PValue      float64  `json:"p_value"`
+	Confidence  float64  `json:"confidence"` // 1 - p-value of the Pearson coefficient
+	Direction   string   `json:"direction"`  // positive or negative
+	Summary     string   `json:"summary"`
+}
+
+// CorrelationReport is the result of a correlation analysis over a date range.
+type CorrelationReport struct {
+	StartDate     time.Time            `json:"start_date"`
+	EndDate       time.Time            `json:"end_date"`
+	Days          int                  `json:"days"` // Days with a logged vibe in the range
+	MinSamples    int                  `json:"min_samples"`
+	MinConfidence float64              `json:"min_confidence"`
+	Lags          []int                `json:"lags"`
+	Tested        int                  `json:"tested"` // Factor/outcome pairs with enough samples to test
+	Correlations  []CorrelationInsight `json:"correlations"`
+}
+
+// MoodTransitionMatrix holds the day-to-day mood transitions over a period.
+// Only strictly consecutive calendar days are counted, so gaps in logging never
+// create a transition.
+type MoodTransitionMatrix struct {
+	Transitions   int                           `json:"transitions"` // Number of consecutive-day pairs
+	States        []string                      `json:"states"`
+	Counts        map[string]map[string]int     `json:"counts"`        // from -> to -> count
+	Probabilities map[string]map[string]float64 `json:"probabilities"` // from -> to -> probability; each row sums to 1
+}
+
+// Mood forecast methods, from most to least specific.
+const (
+	ForecastMethodSecondOrder = "second_order" // Based on the last two consecutive days
+	ForecastMethodFirstOrder  = "first_order"  // Based on the last day
+	ForecastMethodBaseline    = "baseline"     // No usable recent history; overall mood frequencies
+)
+
+// MoodProbability is the forecast probability of a single mood.
+type MoodProbability struct {
+	Mood        string  `json:"mood"`
+	Probability float64 `json:"probability"`
+}
+
+// MoodForecast predicts the mood for a single day from recent history and day-of-week effects.
+type MoodForecast struct {
+	TargetDate    time.Time         `json:"target_date"`
+	Weekday       string            `json:"weekday"`
+	RecentMoods   []string          `json:"recent_moods"` // Moods the forecast is conditioned on, oldest first
+	Method        string            `json:"method"`
+	Samples       int               `json:"samples"` // Observed transitions out of the conditioning state
+	MostLikely    string            `json:"most_likely,omitempty"`
+	Probabilities []MoodProbability `json:"probabilities"` // Sorted by probability, descending
+	Explanation   string            `json:"explanation"`
+}
@@ -0,0 +1,66 @@
+package model
+
+import "time"
+
+// Supported custom metric types.
+const (
+	MetricTypeInt   = "int"
+	MetricTypeFloat = "float"
+	MetricTypeBool  = "bool"
+	MetricTypeScale = "scale" // Integer rating within [Min, Max], e.g. stress 1-5
+)
+
+// MetricDefinition describes a user-defined numeric signal tracked alongside each vibe,
+// such as sleep hours, stress or water intake.
+type MetricDefinition struct {
+	ID          uint      `json:"id" gorm:"primarykey"`
+	Name        string    `json:"name" gorm:"uniqueIndex;not null"` // Key used in vibe payloads, filters and exports, e.g. "sleep_hours"
+	Label       string    `json:"label"`                            // Human readable name, e.g. "Sleep"
+	Type        string    `json:"type" gorm:"not null"`
+	Unit        string    `json:"unit"` // e.g. "hours", "glasses"
+	Min         *float64  `json:"min"`
+	Max         *float64  `json:"max"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// VibeMetricValue stores the value of a custom metric for a single vibe.
+// Booleans are stored as 0/1.
+type VibeMetricValue struct {
+	ID                 uint             `json:"-" gorm:"primarykey"`
+	VibeID             uint             `json:"-" gorm:"uniqueIndex:idx_vibe_metric;not null"`
+	MetricDefinitionID uint             `json:"-" gorm:"uniqueIndex:idx_vibe_metric;index;not null"`
+	Value              float64          `json:"value"`
+	Definition         MetricDefinition `json:"-" gorm:"foreignKey:MetricDefinitionID;constraint:OnDelete:CASCADE"`
+}
+
+// MetricFilter is a comparison against a custom metric value, e.g. sleep_hours >= 7.
+type MetricFilter struct {
+	Name     string
+	Operator string // eq, ne, gt, gte, lt, lte
+	Value    float64
+}
+
+// MetricAggregate summarizes the values of a custom metric over a period.
+type MetricAggregate struct {
+	Name    string  `json:"name"`
+	Type    string  `json:"type"`
+	Unit    string  `json:"unit"`
+	Count   int64   `json:"count"`
+	Average float64 `json:"average"` // For bool metrics this is the share of "true" values
+	Min     float64 `json:"min"`
+	Max     float64 `json:"max"`
+}
+
+// MetricValueForJSON converts a stored value back to its JSON representation for the metric type.
+func MetricValueForJSON(metricType string, value float64) interface{} {
+	switch metricType {
+	case MetricTypeBool:
+		return value != 0
+	case MetricTypeInt, MetricTypeScale:
+		return int64(value)
+	default:
+		return value
+	}
+}
@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+// Mood represents a canonical entry in the managed mood catalog.
+// Vibes store the canonical Name; Aliases are alternative spellings or synonyms
+// that are resolved to Name on write (e.g. "joyful" -> "happy").
+// Catalog entries are hard-deleted so that a removed name can be registered again.
+type Mood struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	Name      string    `json:"name" gorm:"uniqueIndex;not null"`
+	Aliases   []string  `json:"aliases" gorm:"type:text[]"`
+	Valence   float64   `json:"valence" gorm:"check:valence >= -1 AND valence <= 1"` // -1 (very negative) .. 1 (very positive)
+	Arousal   float64   `json:"arousal" gorm:"check:arousal >= 0 AND arousal <= 1"`  // 0 (calm/low) .. 1 (activated/high)
+	Emoji     string    `json:"emoji"`
+	Color     string    `json:"color"` // Hex color, e.g. "#FFD700"
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// IsPositive reports whether the mood has a positive valence.
+func (m *Mood) IsPositive() bool {
+	return m.Valence > 0
+}
+
+// IsNegative reports whether the mood has a negative valence.
+func (m *Mood) IsNegative() bool {
+	return m.Valence < 0
+}
+
+// OtherMoodName is the catch-all mood used by the "other" unknown-mood policy.
+const OtherMoodName = "other"
+
+// DefaultMoods is the catalog seeded on startup when the mood table is empty.
+var DefaultMoods = []Mood{
+	{Name: "happy", Aliases: []string{"joyful", "glad", "cheerful", "stoked"}, Valence: 0.8, Arousal: 0.6, Emoji: "😊", Color: "#FFD700"},
+	{Name: "great", Aliases: []string{"awesome", "fantastic", "amazing"}, Valence: 0.9, Arousal: 0.7, Emoji: "🤩", Color: "#FFA500"},
+	{Name: "excited", Aliases: []string{"thrilled", "pumped"}, Valence: 0.8, Arousal: 0.9, Emoji: "🥳", Color: "#FF6347"},
+	{Name: "energetic", Aliases: []string{"lively", "active"}, Valence: 0.6, Arousal: 0.9, Emoji: "⚡", Color: "#ADFF2F"},
+	{Name: "motivated", Aliases: []string{"driven", "inspired"}, Valence: 0.7, Arousal: 0.7, Emoji: "💪", Color: "#32CD32"},
+	{Name: "calm", Aliases: []string{"relaxed", "peaceful", "chill"}, Valence: 0.5, Arousal: 0.1, Emoji: "😌", Color: "#87CEEB"},
+	{Name: "content", Aliases: []string{"satisfied", "fine", "ok", "okay"}, Valence: 0.4, Arousal: 0.3, Emoji: "🙂", Color: "#98FB98"},
+	{Name: "neutral", Aliases: []string{"meh"}, Valence: 0, Arousal: 0.3, Emoji: "😐", Color: "#D3D3D3"},
+	{Name: "tired", Aliases: []string{"exhausted", "sleepy", "drained"}, Valence: -0.3, Arousal: 0.1, Emoji: "😴", Color: "#B0C4DE"},
+	{Name: "sad", Aliases: []string{"down", "unhappy", "blue"}, Valence: -0.7, Arousal: 0.2, Emoji: "😢", Color: "#4682B4"},
+	{Name: "stressed", Aliases: []string{"overwhelmed", "tense"}, Valence: -0.6, Arousal: 0.8, Emoji: "😫", Color: "#CD5C5C"},
+	{Name: "anxious", Aliases: []string{"worried", "nervous"}, Valence: -0.6, Arousal: 0.7, Emoji: "😰", Color: "#9370DB"},
+	{Name: "angry", Aliases: []string{"mad", "furious", "annoyed"}, Valence: -0.8, Arousal: 0.9, Emoji: "😠", Color: "#DC143C"},
+	{Name: OtherMoodName, Aliases: []string{}, Valence: 0, Arousal: 0.5, Emoji: "❔", Color: "#808080"},
+}
@@ -0,0 +1,95 @@
+package model
+
+import "time"
+
+// ActivitySuggestion is a single scored activity recommendation.
+type ActivitySuggestion struct {
+	ID           uint       `json:"id"` // ID of the persisted Recommendation, used to send feedback; 0 for another day than today
+	Activity     string     `json:"activity"`
+	Score        float64    `json:"score"`
+	Lift         float64    `json:"lift"`            // Recency-weighted P(good day | activity) / P(good day)
+	EnergyDelta  float64    `json:"energy_delta"`    // Average energy on days with the activity minus the overall average
+	DayOfWeekFit float64    `json:"day_of_week_fit"` // How much more often the activity is done on today's weekday than on an average day
+	Feedback     float64    `json:"feedback"`        // Score adjustment from past feedback on this activity
+	Occurrences  int        `json:"occurrences"`
+	LastDone     *time.Time `json:"last_done,omitempty"`
+	Reasons      []string   `json:"reasons"`
+}
+
+// RecommendationResult is the response of the recommender for a single day.
+type RecommendationResult struct {
+	Date        time.Time            `json:"date"`
+	Seed        int64                `json:"seed"` // Pass back as ?seed= to reproduce the same ranking
+	Suggestions []ActivitySuggestion `json:"suggestions"`
+	Suggestion  string               `json:"suggestion"` // Human readable summary of the top suggestion
+	Reason      string               `json:"reason"`
+}
+
+// NotInterestedActivity is an activity the user asked not to be recommended.
+type NotInterestedActivity struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	Activity  string    `json:"activity" gorm:"uniqueIndex;not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Recommendation feedback values.
+const (
+	FeedbackAccepted  = "accepted"  // The user intends to do the activity
+	FeedbackDismissed = "dismissed" // The user does not want to do it this time
+	FeedbackDidIt     = "did_it"    // The user did the activity
+)
+
+// Recommendation is a suggestion that was served for a day. The same activity suggested
+// again on the same day reuses the existing record.
+type Recommendation struct {
+	ID           uint       `json:"id" gorm:"primarykey"`
+	Date         time.Time  `json:"date" gorm:"uniqueIndex:idx_recommendation_date_activity;not null"` // Day the activity was recommended for
+	Activity     string     `json:"activity" gorm:"uniqueIndex:idx_recommendation_date_activity;index;not null"`
+	Rank         int        `json:"rank"` // 1-based position in the served list
+	Score        float64    `json:"score"`
+	Seed         int64      `json:"seed"`
+	Feedback     string     `json:"feedback,omitempty"` // accepted, dismissed or did_it
+	FeedbackAt   *time.Time `json:"feedback_at,omitempty"`
+	LinkedVibeID *uint      `json:"linked_vibe_id,omitempty" gorm:"index"` // Later vibe that included the activity
+	LinkedAt     *time.Time `json:"linked_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// Done reports whether the recommended activity was done, either by explicit feedback or
+// because a later vibe included it.
+func (r *Recommendation) Done() bool {
+	return r.Feedback == FeedbackDidIt || r.LinkedVibeID != nil
+}
+
+// RecommendationActivityStats summarizes how recommendations of one activity were received
+// and whether doing it paid off.
+type RecommendationActivityStats struct {
+	Activity       string  `json:"activity"`
+	Served         int     `json:"served"`
+	Accepted       int     `json:"accepted"`
+	Dismissed      int     `json:"dismissed"`
+	DidIt          int     `json:"did_it"`
+	Linked         int     `json:"linked"`          // Automatically linked to a later vibe
+	Done           int     `json:"done"`            // did_it or linked
+	AcceptanceRate float64 `json:"acceptance_rate"` // (accepted or done) / served
+	CompletionRate float64 `json:"completion_rate"` // done / served
+
+	// Outcomes on the recommended day, compared between days the suggestion was followed and
+	// days it was not. Nil when either group has no logged vibe.
+	GoodDayRateWhenDone    *float64 `json:"good_day_rate_when_done,omitempty"`
+	GoodDayRateWhenNotDone *float64 `json:"good_day_rate_when_not_done,omitempty"`
+	EnergyWhenDone         *float64 `json:"energy_when_done,omitempty"`
+	EnergyWhenNotDone      *float64 `json:"energy_when_not_done,omitempty"`
+	OutcomeLift            *float64 `json:"outcome_lift,omitempty"` // Good-day rate when done / when not done
+}
+
+// RecommendationStats reports recommendation feedback over a date range.
+type RecommendationStats struct {
+	StartDate      time.Time                     `json:"start_date"`
+	EndDate        time.Time                     `json:"end_date"`
+	Served         int                           `json:"served"`
+	AcceptanceRate float64                       `json:"acceptance_rate"`
+	CompletionRate float64                       `json:"completion_rate"`
+	Activities     []RecommendationActivityStats `json:"activities"`
+}
@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+// Reminder delivery statuses.
+const (
+	DeliveryStatusPending = "pending" // Waiting for its first attempt or a retry
+	DeliveryStatusSent    = "sent"
+	DeliveryStatusFailed  = "failed"  // Gave up after the maximum number of attempts
+	DeliveryStatusSkipped = "skipped" // A vibe was logged for the day before delivery, or the reminder was disabled
+)
+
+// Reminder asks a person to log their vibe at a local time on chosen weekdays. It only fires
+// on days without a vibe.
+type Reminder struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	Recipient string    `json:"recipient" gorm:"not null"` // Who the reminder is for, e.g. "alex"
+	Channel   string    `json:"channel" gorm:"not null"`   // webhook, smtp or stdout
+	Address   string    `json:"address"`                   // Webhook URL or email address; unused for stdout
+	Time      string    `json:"time" gorm:"not null"`      // Local time of day, HH:MM
+	Days      []string  `json:"days" gorm:"type:text[]"`   // Weekdays (mon..sun); empty means every day
+	Timezone  string    `json:"timezone" gorm:"not null"`  // IANA time zone, e.g. "Europe/Berlin"
+	Message   string    `json:"message"`                   // Custom text; a default is used when empty
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"` // Only occurrences after the last change fire
+}
+
+// ReminderDelivery is one scheduled occurrence of a reminder and the outcome of delivering it.
+// There is at most one delivery per reminder and occurrence, which keeps the schedule
+// idempotent across restarts.
+type ReminderDelivery struct {
+	ID            uint       `json:"id" gorm:"primarykey"`
+	ReminderID    uint       `json:"reminder_id" gorm:"uniqueIndex:idx_reminder_delivery_slot;not null"`
+	ScheduledFor  time.Time  `json:"scheduled_for" gorm:"uniqueIndex:idx_reminder_delivery_slot;not null"`
+	Day           string     `json:"day" gorm:"not null"` // Local day the reminder is about, YYYY-MM-DD
+	Channel       string     `json:"channel"`
+	Address       string     `json:"address"`
+	Status        string     `json:"status" gorm:"index;not null"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty" gorm:"index"`
+	LastError     string     `json:"last_error,omitempty"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
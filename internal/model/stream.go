@@ -0,0 +1,9 @@
+package model
+
+// LiveSummary is the state pushed to stream clients after every vibe change.
+type LiveSummary struct {
+	Date          string `json:"date"`                  // Today, YYYY-MM-DD
+	Today         *Vibe  `json:"today"`                 // Today's vibe, nil if not logged yet
+	LoggingStreak int    `json:"logging_streak"`        // Consecutive logged days up to today, or up to yesterday if today is still open
+	MoodStreak    int    `json:"mood_streak,omitempty"` // Current streak of today's mood
+}
@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// User is an account that API keys belong to.
+type User struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	Username  string    `json:"username" gorm:"uniqueIndex;not null"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// APIKey authenticates requests as its user. Only a SHA-256 hash of the key is stored; the
+// key itself is shown once, when it is created.
+type APIKey struct {
+	ID         uint       `json:"id" gorm:"primarykey"`
+	UserID     uint       `json:"user_id" gorm:"index;not null"`
+	User       User       `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix" gorm:"not null"`        // First characters of the key, to tell keys apart
+	KeyHash    string     `json:"-" gorm:"uniqueIndex;not null"` // Hex SHA-256 of the key
+	ExpiresAt  *time.Time `json:"expires_at"`                    // Nil for keys that do not expire
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// Expired reports whether the key has expired at t.
+func (k *APIKey) Expired(t time.Time) bool {
+	return k.ExpiresAt != nil && !t.Before(*k.ExpiresAt)
+}
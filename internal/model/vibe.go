@@ -17,6 +17,25 @@ type Vibe struct {
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"` // Add gorm.DeletedAt for soft deletes
+
+	// Metrics holds custom metric values keyed by metric name, e.g. {"sleep_hours": 7.5, "stress": 3}.
+	// It is the API representation of MetricValues and is not stored directly.
+	Metrics      map[string]interface{} `json:"metrics,omitempty" gorm:"-"`
+	MetricValues []VibeMetricValue      `json:"-" gorm:"foreignKey:VibeID;constraint:OnDelete:CASCADE"`
+}
+
+// PopulateMetrics fills Metrics from MetricValues. MetricValues must be loaded with their Definition.
+func (v *Vibe) PopulateMetrics() {
+	if len(v.MetricValues) == 0 {
+		return
+	}
+	v.Metrics = make(map[string]interface{}, len(v.MetricValues))
+	for _, mv := range v.MetricValues {
+		if mv.Definition.Name == "" {
+			continue
+		}
+		v.Metrics[mv.Definition.Name] = MetricValueForJSON(mv.Definition.Type, mv.Value)
+	}
 }
 
 // TableName specifies the table name for the Vibe model.
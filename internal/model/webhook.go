@@ -0,0 +1,70 @@
+package model
+
+import "time"
+
+// Webhook event types.
+const (
+	EventVibeCreated     = "vibe.created"
+	EventVibeUpdated     = "vibe.updated"
+	EventVibeDeleted     = "vibe.deleted"
+	EventBulkImported    = "bulk.imported"
+	EventStreakMilestone = "streak.milestone"
+	EventPing            = "ping" // Sent on request to test a subscription; not subject to event filters
+)
+
+// Webhook delivery statuses.
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryDead      = "dead" // Gave up after the maximum number of attempts; can be redelivered
+)
+
+// WebhookSubscription sends events to a URL. Deliveries are signed with Secret.
+type WebhookSubscription struct {
+	ID          uint      `json:"id" gorm:"primarykey"`
+	URL         string    `json:"url" gorm:"not null"`
+	Secret      string    `json:"secret,omitempty" gorm:"not null"` // Only returned when the subscription is created
+	Events      []string  `json:"events" gorm:"type:text[]"`        // Event filter; empty means all events
+	Description string    `json:"description"`
+	Active      bool      `json:"active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Wants reports whether the subscription receives events of the given type.
+func (s *WebhookSubscription) Wants(eventType string) bool {
+	if len(s.Events) == 0 || eventType == EventPing {
+		return true
+	}
+	for _, e := range s.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// OutboxEvent is an event waiting in the outbox for delivery to its subscribers.
+type OutboxEvent struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	Type      string    `json:"type" gorm:"index;not null"`
+	Payload   string    `json:"-" gorm:"type:jsonb;not null"` // JSON encoded event data
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WebhookDelivery is the delivery of one outbox event to one subscription.
+type WebhookDelivery struct {
+	ID             uint        `json:"id" gorm:"primarykey"`
+	SubscriptionID uint        `json:"subscription_id" gorm:"index;not null"`
+	EventID        uint        `json:"event_id" gorm:"index;not null"`
+	EventType      string      `json:"event_type"`
+	Status         string      `json:"status" gorm:"index;not null"`
+	Attempts       int         `json:"attempts"`
+	NextAttemptAt  *time.Time  `json:"next_attempt_at,omitempty" gorm:"index"`
+	LastError      string      `json:"last_error,omitempty"`
+	ResponseStatus int         `json:"response_status,omitempty"` // HTTP status of the last attempt
+	DeliveredAt    *time.Time  `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time   `json:"created_at"`
+	UpdatedAt      time.Time   `json:"updated_at"`
+	Event          OutboxEvent `json:"-" gorm:"foreignKey:EventID;constraint:OnDelete:CASCADE"`
+}
@@ -0,0 +1,42 @@
+// Package notifier delivers notifications such as reminders over pluggable channels.
+package notifier
+
+import (
+	"context"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/config"
+)
+
+// Channel names.
+const (
+	ChannelWebhook = "webhook"
+	ChannelSMTP    = "smtp"
+	ChannelStdout  = "stdout"
+)
+
+// Message is a notification for a single recipient.
+type Message struct {
+	To      string                 // Webhook URL or email address, depending on the channel
+	Subject string                 // Email subject or webhook title
+	Body    string                 // Plain text body
+	Data    map[string]interface{} // Structured details, sent as JSON by the webhook channel
+}
+
+// Notifier delivers messages over one channel. Send returns an error when delivery failed
+// and may be retried.
+type Notifier interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// NewNotifiers creates the notifiers available with the given configuration, keyed by channel.
+// The smtp channel is only available when SMTP_HOST is set.
+func NewNotifiers(cfg *config.AppConfig) map[string]Notifier {
+	notifiers := map[string]Notifier{
+		ChannelWebhook: NewWebhookNotifier(cfg.NotifierTimeout),
+		ChannelStdout:  NewStdoutNotifier(nil),
+	}
+	if cfg.SMTPHost != "" {
+		notifiers[ChannelSMTP] = NewSMTPNotifier(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPFrom, cfg.NotifierTimeout)
+	}
+	return notifiers
+}
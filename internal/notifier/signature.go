@@ -0,0 +1,72 @@
+package notifier
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureHeader carries the signature of a webhook delivery, in the form "t=<unix seconds>,v1=<hex>".
+const SignatureHeader = "X-Vibe-Signature"
+
+// ErrInvalidSignature is returned when a webhook signature does not verify.
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// computeSignature returns the hex HMAC-SHA256 of "<timestamp>.<body>".
+func computeSignature(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignPayload returns the signature header value for a webhook body. Including the timestamp
+// lets receivers reject replayed deliveries.
+func SignPayload(secret string, at time.Time, body []byte) string {
+	ts := at.Unix()
+	return fmt.Sprintf("t=%d,v1=%s", ts, computeSignature(secret, ts, body))
+}
+
+// VerifySignature checks a signature header against a webhook body. Signatures older than
+// tolerance are rejected; a zero tolerance disables the age check.
+func VerifySignature(secret, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	var ts int64
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return fmt.Errorf("%w: bad timestamp", ErrInvalidSignature)
+			}
+			ts = parsed
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	if ts == 0 || len(signatures) == 0 {
+		return fmt.Errorf("%w: missing timestamp or signature", ErrInvalidSignature)
+	}
+	if tolerance > 0 {
+		if age := now.Sub(time.Unix(ts, 0)); age > tolerance || age < -tolerance {
+			return fmt.Errorf("%w: timestamp outside tolerance", ErrInvalidSignature)
+		}
+	}
+	expected := computeSignature(secret, ts, body)
+	for _, sig := range signatures {
+		if hmac.Equal([]byte(sig), []byte(expected)) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
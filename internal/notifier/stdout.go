@@ -0,0 +1,32 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// StdoutNotifier prints messages instead of delivering them, for development.
+type StdoutNotifier struct {
+	mu  sync.Mutex
+	Out io.Writer
+}
+
+// NewStdoutNotifier creates a StdoutNotifier writing to out, or to os.Stdout when out is nil.
+func NewStdoutNotifier(out io.Writer) *StdoutNotifier {
+	if out == nil {
+		out = os.Stdout
+	}
+	return &StdoutNotifier{Out: out}
+}
+
+// Send writes the message on a single line.
+func (n *StdoutNotifier) Send(ctx context.Context, msg Message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	_, err := fmt.Fprintf(n.Out, "%s [notify] to=%q subject=%q body=%q\n", time.Now().Format(time.RFC3339), msg.To, msg.Subject, msg.Body)
+	return err
+}
@@ -0,0 +1,154 @@
+// Package ratelimit limits how many requests a client may make under named policies. Limits
+// use the generic cell rate algorithm (GCRA): a policy of Limit requests per Window allows a
+// burst of Limit requests, then one request every Window/Limit. The state of a client is a
+// single timestamp, kept in memory for a single server or in Redis for replicas that share
+// their limits.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/config"
+)
+
+// Policy names.
+const (
+	PolicyDefault = "default"
+	PolicyBulk    = "bulk"   // Bulk imports
+	PolicyExport  = "export" // Exports
+	PolicyAuth    = "auth"   // Failed authentications, per IP
+)
+
+// Policy allows Limit requests per Window.
+type Policy struct {
+	Name   string
+	Limit  int
+	Window time.Duration
+}
+
+// interval is the time one request occupies.
+func (p Policy) interval() time.Duration {
+	return p.Window / time.Duration(p.Limit)
+}
+
+// String formats the policy like the RateLimit-Policy header, e.g. "100;w=60".
+func (p Policy) String() string {
+	return fmt.Sprintf("%d;w=%d", p.Limit, int(math.Ceil(p.Window.Seconds())))
+}
+
+// PoliciesFromConfig returns the default policy of RATE_LIMIT_MAX requests per
+// RATE_LIMIT_WINDOW, the stricter bulk import and export policies and the policy of failed
+// authentications.
+func PoliciesFromConfig(cfg *config.AppConfig) []Policy {
+	return []Policy{
+		{Name: PolicyDefault, Limit: cfg.RateLimitMax, Window: cfg.RateLimitWindow},
+		{Name: PolicyBulk, Limit: cfg.RateLimitBulkMax, Window: cfg.RateLimitWindow},
+		{Name: PolicyExport, Limit: cfg.RateLimitExportMax, Window: cfg.RateLimitWindow},
+		{Name: PolicyAuth, Limit: cfg.RateLimitAuthMax, Window: cfg.RateLimitWindow},
+	}
+}
+
+// Result is the outcome of a request against a policy.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int           // Requests left in the current burst
+	Reset      time.Duration // Until the full burst is available again
+	RetryAfter time.Duration // Until the next request is allowed; zero when allowed
+}
+
+// Headers returns the RateLimit-* headers of the IETF draft and, for rejected requests,
+// Retry-After. Durations are rounded up to whole seconds.
+func (r Result) Headers(p Policy) map[string]string {
+	headers := map[string]string{
+		"RateLimit-Limit":     strconv.Itoa(r.Limit),
+		"RateLimit-Remaining": strconv.Itoa(r.Remaining),
+		"RateLimit-Reset":     strconv.Itoa(ceilSeconds(r.Reset)),
+		"RateLimit-Policy":    p.String(),
+	}
+	if !r.Allowed {
+		headers["Retry-After"] = strconv.Itoa(max(1, ceilSeconds(r.RetryAfter)))
+	}
+	return headers
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// Store keeps the state of clients. Take counts a request of key against the policy; Peek
+// returns what Take would, without counting the request.
+type Store interface {
+	Take(ctx context.Context, key string, p Policy) (Result, error)
+	Peek(ctx context.Context, key string, p Policy) (Result, error)
+}
+
+// gcra applies a request at now to the theoretical arrival time tat of a client (the zero
+// time for a new client). It returns the result and the new tat, unchanged when rejected.
+func gcra(now, tat time.Time, p Policy) (Result, time.Time) {
+	if tat.Before(now) {
+		tat = now
+	}
+	interval := p.interval()
+	next := tat.Add(interval)
+	if allowAt := next.Add(-p.Window); now.Before(allowAt) {
+		return Result{Limit: p.Limit, Reset: tat.Sub(now), RetryAfter: allowAt.Sub(now)}, tat
+	}
+	return resultAt(now, next, p), next
+}
+
+// resultAt describes an allowed request that moved the tat of a client to next.
+func resultAt(now, next time.Time, p Policy) Result {
+	used := next.Sub(now)
+	return Result{
+		Allowed:   true,
+		Limit:     p.Limit,
+		Remaining: int((p.Window - used) / p.interval()),
+		Reset:     used,
+	}
+}
+
+// Limiter checks requests against named policies.
+type Limiter struct {
+	store    Store
+	policies map[string]Policy
+}
+
+// NewLimiter creates a Limiter. Policies with a Limit below 1 or no Window are left out, so
+// their routes fall back to the default policy; without a default policy they are not limited.
+func NewLimiter(store Store, policies ...Policy) *Limiter {
+	l := &Limiter{store: store, policies: make(map[string]Policy)}
+	for _, p := range policies {
+		if p.Limit > 0 && p.Window > 0 {
+			l.policies[p.Name] = p
+		}
+	}
+	return l
+}
+
+// Policy returns a policy by name, falling back to the default policy. ok is false when
+// neither limits requests.
+func (l *Limiter) Policy(name string) (Policy, bool) {
+	if p, ok := l.policies[name]; ok {
+		return p, true
+	}
+	p, ok := l.policies[PolicyDefault]
+	return p, ok
+}
+
+// Take counts a request of a client, e.g. "user:alex" or "ip:203.0.113.7", against a policy
+// returned by Policy. Every policy has its own count.
+func (l *Limiter) Take(ctx context.Context, p Policy, client string) (Result, error) {
+	return l.store.Take(ctx, p.Name+":"+client, p)
+}
+
+// Peek tells whether a request of a client would be allowed under a policy, without
+// counting it. The failed authentication limit checks clients with it before counting the
+// failures only.
+func (l *Limiter) Peek(ctx context.Context, p Policy, client string) (Result, error) {
+	return l.store.Peek(ctx, p.Name+":"+client, p)
+}
@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// gcraScript runs gcra atomically in Redis, with the clock of Redis so that replicas agree.
+// Times are in microseconds. It returns whether the request is allowed, the time until
+// the tat and the time until the next request is allowed. With ARGV[3] set to 1 it only
+// peeks: an allowed request is not recorded.
+var gcraScript = redis.NewScript(`
+local interval = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local clock = redis.call('TIME')
+local now = tonumber(clock[1]) * 1000000 + tonumber(clock[2])
+local tat = now
+local stored = redis.call('GET', KEYS[1])
+if stored then
+  tat = math.max(tonumber(stored), now)
+end
+local nextTat = tat + interval
+local allowAt = nextTat - window
+if now < allowAt then
+  return {0, tat - now, allowAt - now}
+end
+if ARGV[3] == '1' then
+  return {1, nextTat - now, 0}
+end
+-- Lua numbers are formatted with 14 digits; timestamps in microseconds need 16.
+redis.call('SET', KEYS[1], string.format('%d', nextTat), 'PX', string.format('%d', math.ceil((nextTat - now) / 1000)))
+return {1, nextTat - now, 0}
+`)
+
+// RedisStore keeps the state of clients in Redis, so that replicas share their limits. Each
+// client is one key, which expires when its limit has fully reset.
+type RedisStore struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisStore creates a RedisStore whose keys start with prefix, e.g. "ratelimit:".
+func NewRedisStore(client redis.UniversalClient, prefix string) *RedisStore {
+	return &RedisStore{client: client, prefix: prefix}
+}
+
+// Take implements Store.
+func (s *RedisStore) Take(ctx context.Context, key string, p Policy) (Result, error) {
+	return s.run(ctx, key, p, false)
+}
+
+// Peek implements Store.
+func (s *RedisStore) Peek(ctx context.Context, key string, p Policy) (Result, error) {
+	return s.run(ctx, key, p, true)
+}
+
+func (s *RedisStore) run(ctx context.Context, key string, p Policy, peek bool) (Result, error) {
+	peekArg := 0
+	if peek {
+		peekArg = 1
+	}
+	values, err := gcraScript.Run(ctx, s.client, []string{s.prefix + key},
+		p.interval().Microseconds(), p.Window.Microseconds(), peekArg).Int64Slice()
+	if err != nil {
+		return Result{}, err
+	}
+	untilTat := time.Duration(values[1]) * time.Microsecond
+	if values[0] == 0 {
+		return Result{Limit: p.Limit, Reset: untilTat, RetryAfter: time.Duration(values[2]) * time.Microsecond}, nil
+	}
+	now := time.Now()
+	return resultAt(now, now.Add(untilTat), p), nil
+}
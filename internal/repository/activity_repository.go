@@ -0,0 +1,284 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/logging"
+	"github.com/aebalz/daily-vibe-tracker/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ActivityRepositoryInterface defines the interface for activity catalog repository operations.
+type ActivityRepositoryInterface interface {
+	CreateActivity(activity *model.Activity) (*model.Activity, error)
+	// CreateActivityIfAbsent adds activity unless an activity with its name exists, and
+	// returns the stored activity either way, so concurrent writers of a new activity end up
+	// with the same row.
+	CreateActivityIfAbsent(activity *model.Activity) (*model.Activity, error)
+	GetActivityByID(id uint) (*model.Activity, error)
+	GetAllActivities() ([]model.Activity, error)
+	UpdateActivity(id uint, updatedActivity *model.Activity) (*model.Activity, error)
+	DeleteActivity(id uint) error
+
+	// FindActivityByNameOrAlias looks up an activity whose canonical name or one of its aliases matches.
+	// The name is expected to already be normalized (lowercased and trimmed).
+	FindActivityByNameOrAlias(name string) (*model.Activity, error)
+
+	// RenameActivity updates the activity and rewrites oldName to the new name in all vibes.
+	RenameActivity(id uint, oldName string, updatedActivity *model.Activity) (*model.Activity, error)
+	// MergeActivities folds source into target: vibes are rewritten to the target name,
+	// source's children are re-parented, target gains source's aliases and source is deleted.
+	MergeActivities(source, target *model.Activity) (*model.Activity, error)
+	// ReplaceActivityInVibes rewrites every occurrence of oldName to newName in vibe activities.
+	ReplaceActivityInVibes(oldName, newName string) (int64, error)
+
+	// Usage
+	GetActivityUsage() ([]model.ActivityUsage, error)
+	GetDistinctVibeActivities() ([]string, error)
+
+	// WithTx returns a repository that runs its queries in the transaction tx.
+	WithTx(tx *gorm.DB) ActivityRepositoryInterface
+	// WithContext returns a repository whose queries and log lines carry ctx.
+	WithContext(ctx context.Context) ActivityRepositoryInterface
+}
+
+// ActivityRepository implements ActivityRepositoryInterface.
+type ActivityRepository struct {
+	DB     *gorm.DB
+	Logger *slog.Logger
+}
+
+// NewActivityRepository creates a new ActivityRepository. A nil logger logs to the default logger.
+func NewActivityRepository(db *gorm.DB, logger *slog.Logger) ActivityRepositoryInterface {
+	return &ActivityRepository{DB: db, Logger: logging.OrDefault(logger)}
+}
+
+// WithTx returns an ActivityRepository bound to tx.
+func (r *ActivityRepository) WithTx(tx *gorm.DB) ActivityRepositoryInterface {
+	return &ActivityRepository{DB: tx, Logger: r.Logger}
+}
+
+// WithContext returns an ActivityRepository whose queries run with ctx.
+func (r *ActivityRepository) WithContext(ctx context.Context) ActivityRepositoryInterface {
+	return &ActivityRepository{DB: r.DB.WithContext(ctx), Logger: r.Logger}
+}
+
+// context returns the context of the repository's queries, for its log lines.
+func (r *ActivityRepository) context() context.Context {
+	if r.DB.Statement != nil && r.DB.Statement.Context != nil {
+		return r.DB.Statement.Context
+	}
+	return context.Background()
+}
+
+// CreateActivity adds a new activity to the catalog.
+func (r *ActivityRepository) CreateActivity(activity *model.Activity) (*model.Activity, error) {
+	result := r.DB.Create(activity)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return activity, nil
+}
+
+// CreateActivityIfAbsent inserts activity with ON CONFLICT DO NOTHING on its name, then
+// reads the stored row back: the inserted one, or the one another writer committed first.
+func (r *ActivityRepository) CreateActivityIfAbsent(activity *model.Activity) (*model.Activity, error) {
+	result := r.DB.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true}).Create(activity)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected > 0 {
+		r.Logger.InfoContext(r.context(), "Created activity", slog.String("activity", activity.Name))
+		return activity, nil
+	}
+	var stored model.Activity
+	if err := r.DB.Where("name = ?", activity.Name).First(&stored).Error; err != nil {
+		return nil, err
+	}
+	return &stored, nil
+}
+
+// GetActivityByID retrieves a single activity by its ID.
+func (r *ActivityRepository) GetActivityByID(id uint) (*model.Activity, error) {
+	var activity model.Activity
+	result := r.DB.First(&activity, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &activity, nil
+}
+
+// GetAllActivities retrieves the whole activity catalog ordered by name.
+func (r *ActivityRepository) GetAllActivities() ([]model.Activity, error) {
+	var activities []model.Activity
+	result := r.DB.Order("name ASC").Find(&activities)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return activities, nil
+}
+
+// UpdateActivity modifies an existing activity without touching vibe history.
+func (r *ActivityRepository) UpdateActivity(id uint, updatedActivity *model.Activity) (*model.Activity, error) {
+	var existingActivity model.Activity
+	if err := r.DB.First(&existingActivity, id).Error; err != nil {
+		return nil, err // Activity not found
+	}
+
+	updatedActivity.ID = id
+	updatedActivity.CreatedAt = existingActivity.CreatedAt
+
+	result := r.DB.Save(updatedActivity)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return updatedActivity, nil
+}
+
+// DeleteActivity removes an activity from the catalog.
+// Children are detached and become top-level activities.
+func (r *ActivityRepository) DeleteActivity(id uint) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&model.Activity{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Model(&model.Activity{}).Where("parent_id = ?", id).Update("parent_id", nil).Error
+	})
+}
+
+// FindActivityByNameOrAlias looks up an activity by canonical name first, then by alias.
+// Returns gorm.ErrRecordNotFound if neither matches.
+func (r *ActivityRepository) FindActivityByNameOrAlias(name string) (*model.Activity, error) {
+	var activity model.Activity
+	result := r.DB.Where("name = ?", name).Limit(1).Find(&activity)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return &activity, nil
+	}
+
+	result = r.DB.Where("? = ANY(aliases)", name).Order("id ASC").First(&activity)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &activity, nil
+}
+
+// replaceActivityInVibes rewrites oldName to newName in vibe activity arrays within tx,
+// removing duplicates that the rewrite may introduce while keeping the original order.
+func replaceActivityInVibes(tx *gorm.DB, oldName, newName string) (int64, error) {
+	if oldName == newName {
+		return 0, nil
+	}
+	// Unscoped so soft-deleted vibes stay consistent if they are ever restored.
+	result := tx.Unscoped().Model(&model.Vibe{}).
+		Where("? = ANY(activities)", oldName).
+		Update("activities", gorm.Expr(
+			"ARRAY(SELECT a FROM unnest(array_replace(activities, ?::text, ?::text)) WITH ORDINALITY AS t(a, i) GROUP BY a ORDER BY MIN(i))",
+			oldName, newName,
+		))
+	return result.RowsAffected, result.Error
+}
+
+// ReplaceActivityInVibes rewrites every occurrence of oldName to newName in vibe activities.
+func (r *ActivityRepository) ReplaceActivityInVibes(oldName, newName string) (int64, error) {
+	rows, err := replaceActivityInVibes(r.DB, oldName, newName)
+	if err == nil && rows > 0 {
+		r.Logger.InfoContext(r.context(), "Rewrote activity in vibes", slog.String("from", oldName), slog.String("to", newName), slog.Int64("rows", rows))
+	}
+	return rows, err
+}
+
+// RenameActivity updates the activity and rewrites its old name in vibe history in a single transaction.
+func (r *ActivityRepository) RenameActivity(id uint, oldName string, updatedActivity *model.Activity) (*model.Activity, error) {
+	var rows int64
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		var existingActivity model.Activity
+		if err := tx.First(&existingActivity, id).Error; err != nil {
+			return err
+		}
+		updatedActivity.ID = id
+		updatedActivity.CreatedAt = existingActivity.CreatedAt
+		if err := tx.Save(updatedActivity).Error; err != nil {
+			return err
+		}
+		var err error
+		rows, err = replaceActivityInVibes(tx, oldName, updatedActivity.Name)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.Logger.InfoContext(r.context(), "Renamed activity", slog.String("from", oldName), slog.String("to", updatedActivity.Name), slog.Int64("vibes", rows))
+	return updatedActivity, nil
+}
+
+// MergeActivities folds source into target in a single transaction.
+func (r *ActivityRepository) MergeActivities(source, target *model.Activity) (*model.Activity, error) {
+	var rows int64
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		// Vibes may still carry an alias spelling if they predate the catalog, so rewrite those too.
+		for _, name := range append([]string{source.Name}, source.Aliases...) {
+			n, err := replaceActivityInVibes(tx, name, target.Name)
+			if err != nil {
+				return err
+			}
+			rows += n
+		}
+		if err := tx.Model(&model.Activity{}).Where("parent_id = ?", source.ID).Update("parent_id", target.ID).Error; err != nil {
+			return err
+		}
+		// Delete the source first so its name never resolves to two activities at once.
+		if err := tx.Delete(&model.Activity{}, source.ID).Error; err != nil {
+			return err
+		}
+		return tx.Save(target).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.Logger.InfoContext(r.context(), "Merged activities", slog.String("source", source.Name), slog.String("target", target.Name), slog.Int64("vibes", rows))
+	return target, nil
+}
+
+// GetActivityUsage aggregates usage count, last-used date, average energy and average mood
+// valence per activity name across all vibes.
+func (r *ActivityRepository) GetActivityUsage() ([]model.ActivityUsage, error) {
+	var usage []model.ActivityUsage
+	err := r.DB.Raw(`
+		SELECT a.activity AS name,
+			COUNT(*) AS usage_count,
+			MAX(v.date) AS last_used,
+			AVG(v.energy_level) AS average_energy,
+			AVG(m.valence) AS average_valence
+		FROM vibes v
+		CROSS JOIN LATERAL unnest(v.activities) AS a(activity)
+		LEFT JOIN moods m ON m.name = v.mood
+		WHERE v.deleted_at IS NULL AND a.activity <> ''
+		GROUP BY a.activity`).Scan(&usage).Error
+	if err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+// GetDistinctVibeActivities returns every distinct activity string stored on vibes.
+func (r *ActivityRepository) GetDistinctVibeActivities() ([]string, error) {
+	var names []string
+	err := r.DB.Raw(`
+		SELECT DISTINCT a.activity
+		FROM vibes v
+		CROSS JOIN LATERAL unnest(v.activities) AS a(activity)
+		WHERE v.deleted_at IS NULL`).Scan(&names).Error
+	if err != nil {
+		return nil, err
+	}
+	return names, nil
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AnomalyRepositoryInterface defines the interface for anomaly event repository operations.
+type AnomalyRepositoryInterface interface {
+	// CreateAnomalyEvent stores an event unless one of the same kind already exists for that day.
+	// It reports whether a new event was stored.
+	CreateAnomalyEvent(event *model.AnomalyEvent) (bool, error)
+	GetAnomalyEvents(filters map[string]interface{}, limit, offset int) ([]model.AnomalyEvent, int64, error)
+	GetAnomalyEventsForDateRange(startDate, endDate time.Time) ([]model.AnomalyEvent, error)
+	// DeleteAnomalyEventsForDateRange removes the events of a date range, e.g. before detection
+	// is re-run on edited history. It returns the number of removed events.
+	DeleteAnomalyEventsForDateRange(startDate, endDate time.Time) (int64, error)
+}
+
+// AnomalyRepository implements AnomalyRepositoryInterface.
+type AnomalyRepository struct {
+	DB *gorm.DB
+}
+
+// NewAnomalyRepository creates a new AnomalyRepository.
+func NewAnomalyRepository(db *gorm.DB) AnomalyRepositoryInterface {
+	return &AnomalyRepository{DB: db}
+}
+
+// CreateAnomalyEvent adds a new anomaly event, ignoring duplicates so detection can be re-run safely.
+func (r *AnomalyRepository) CreateAnomalyEvent(event *model.AnomalyEvent) (bool, error) {
+	result := r.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(event)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetAnomalyEvents retrieves anomaly events with filtering and pagination, most recent first.
+// Supported filters: start_date and end_date (time.Time), kind and severity (string).
+func (r *AnomalyRepository) GetAnomalyEvents(filters map[string]interface{}, limit, offset int) ([]model.AnomalyEvent, int64, error) {
+	var events []model.AnomalyEvent
+	var total int64
+
+	query := r.DB.Model(&model.AnomalyEvent{})
+	if startDate, ok := filters["start_date"].(time.Time); ok {
+		query = query.Where("date >= ?", startDate)
+	}
+	if endDate, ok := filters["end_date"].(time.Time); ok {
+		query = query.Where("date <= ?", endDate)
+	}
+	if kind, ok := filters["kind"].(string); ok && kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+	if severity, ok := filters["severity"].(string); ok && severity != "" {
+		query = query.Where("severity = ?", severity)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Order("date DESC").Order("id DESC").Limit(limit).Offset(offset).Find(&events).Error; err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
+
+// GetAnomalyEventsForDateRange retrieves all anomaly events within a date range, oldest first.
+func (r *AnomalyRepository) GetAnomalyEventsForDateRange(startDate, endDate time.Time) ([]model.AnomalyEvent, error) {
+	var events []model.AnomalyEvent
+	result := r.DB.Where("date BETWEEN ? AND ?", startDate, endDate).Order("date ASC").Find(&events)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return events, nil
+}
+
+// DeleteAnomalyEventsForDateRange removes all anomaly events within a date range.
+func (r *AnomalyRepository) DeleteAnomalyEventsForDateRange(startDate, endDate time.Time) (int64, error) {
+	result := r.DB.Where("date BETWEEN ? AND ?", startDate, endDate).Delete(&model.AnomalyEvent{})
+	return result.RowsAffected, result.Error
+}
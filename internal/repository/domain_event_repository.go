@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/logging"
+	"github.com/aebalz/daily-vibe-tracker/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DomainEventRepositoryInterface defines the interface for the transactional outbox of domain events.
+type DomainEventRepositoryInterface interface {
+	// Transaction runs fn in a database transaction. Writes made through tx, including
+	// events appended with it, are committed or rolled back together.
+	Transaction(fn func(tx *gorm.DB) error) error
+	Append(tx *gorm.DB, events ...*model.DomainEvent) error
+	// ClaimPending claims up to limit undispatched events that are due, oldest first, by
+	// moving their next attempt to now+lease. Other relays skip them until the lease runs
+	// out, so the subscribers can run outside of any transaction.
+	ClaimPending(now time.Time, lease time.Duration, limit int) ([]model.DomainEvent, error)
+	// ClaimByIDs claims the events among ids that are undispatched and due, oldest first,
+	// like ClaimPending. It lets a write publish its own events without a full relay round.
+	ClaimByIDs(ids []uint, now time.Time, lease time.Duration) ([]model.DomainEvent, error)
+	// SaveDispatch stores the outcome of a dispatch attempt of a claimed event.
+	SaveDispatch(event *model.DomainEvent) error
+	// PurgeDispatched removes events dispatched before the cutoff.
+	PurgeDispatched(before time.Time) (int64, error)
+
+	// WithContext returns a repository whose queries and log lines carry ctx.
+	WithContext(ctx context.Context) DomainEventRepositoryInterface
+}
+
+// DomainEventRepository implements DomainEventRepositoryInterface.
+type DomainEventRepository struct {
+	DB     *gorm.DB
+	Logger *slog.Logger
+}
+
+// NewDomainEventRepository creates a new DomainEventRepository. A nil logger logs to the
+// default logger.
+func NewDomainEventRepository(db *gorm.DB, logger *slog.Logger) DomainEventRepositoryInterface {
+	return &DomainEventRepository{DB: db, Logger: logging.OrDefault(logger)}
+}
+
+// WithContext returns a DomainEventRepository whose queries run with ctx.
+func (r *DomainEventRepository) WithContext(ctx context.Context) DomainEventRepositoryInterface {
+	return &DomainEventRepository{DB: r.DB.WithContext(ctx), Logger: r.Logger}
+}
+
+// context returns the context of the repository's queries, for its log lines.
+func (r *DomainEventRepository) context() context.Context {
+	if r.DB.Statement != nil && r.DB.Statement.Context != nil {
+		return r.DB.Statement.Context
+	}
+	return context.Background()
+}
+
+// Transaction runs fn in a database transaction.
+func (r *DomainEventRepository) Transaction(fn func(tx *gorm.DB) error) error {
+	return r.DB.Transaction(fn)
+}
+
+// Append stores events through tx.
+func (r *DomainEventRepository) Append(tx *gorm.DB, events ...*model.DomainEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return tx.Create(events).Error
+}
+
+// ClaimPending locks the due events, skipping those locked by another relay, and leases
+// them in the same transaction.
+func (r *DomainEventRepository) ClaimPending(now time.Time, lease time.Duration, limit int) ([]model.DomainEvent, error) {
+	return r.claim(now, lease, func(query *gorm.DB) *gorm.DB { return query.Limit(limit) })
+}
+
+// ClaimByIDs is ClaimPending restricted to the events with the given IDs.
+func (r *DomainEventRepository) ClaimByIDs(ids []uint, now time.Time, lease time.Duration) ([]model.DomainEvent, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return r.claim(now, lease, func(query *gorm.DB) *gorm.DB { return query.Where("id IN ?", ids) })
+}
+
+// claim locks the due events selected by scope, skipping those locked by another relay,
+// and leases them in the same transaction.
+func (r *DomainEventRepository) claim(now time.Time, lease time.Duration, scope func(*gorm.DB) *gorm.DB) ([]model.DomainEvent, error) {
+	var events []model.DomainEvent
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		query := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("dispatched_at IS NULL AND (next_attempt_at IS NULL OR next_attempt_at <= ?)", now).
+			Order("id ASC")
+		if err := scope(query).Find(&events).Error; err != nil || len(events) == 0 {
+			return err
+		}
+		leasedUntil := now.Add(lease)
+		ids := make([]uint, len(events))
+		for i := range events {
+			ids[i] = events[i].ID
+			events[i].NextAttemptAt = &leasedUntil
+		}
+		return tx.Model(&model.DomainEvent{}).Where("id IN ?", ids).Update("next_attempt_at", leasedUntil).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// SaveDispatch saves the dispatch state of the event.
+func (r *DomainEventRepository) SaveDispatch(event *model.DomainEvent) error {
+	return r.DB.Model(event).Select("DoneSubscribers", "Attempts", "NextAttemptAt", "LastError", "DispatchedAt").Updates(event).Error
+}
+
+// PurgeDispatched removes events dispatched before the cutoff.
+func (r *DomainEventRepository) PurgeDispatched(before time.Time) (int64, error) {
+	result := r.DB.Where("dispatched_at IS NOT NULL AND dispatched_at < ?", before).Delete(&model.DomainEvent{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	if result.RowsAffected > 0 {
+		r.Logger.InfoContext(r.context(), "Purged dispatched events", slog.Int64("events", result.RowsAffected))
+	}
+	return result.RowsAffected, nil
+}
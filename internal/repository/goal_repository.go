@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"github.com/aebalz/daily-vibe-tracker/internal/model"
+	"gorm.io/gorm"
+)
+
+// GoalRepositoryInterface defines the interface for goal repository operations.
+type GoalRepositoryInterface interface {
+	CreateGoal(goal *model.Goal) (*model.Goal, error)
+	GetGoalByID(id uint) (*model.Goal, error)
+	GetAllGoals(activeOnly bool) ([]model.Goal, error)
+	UpdateGoal(id uint, updatedGoal *model.Goal) (*model.Goal, error)
+	DeleteGoal(id uint) error
+}
+
+// GoalRepository implements GoalRepositoryInterface.
+type GoalRepository struct {
+	DB *gorm.DB
+}
+
+// NewGoalRepository creates a new GoalRepository.
+func NewGoalRepository(db *gorm.DB) GoalRepositoryInterface {
+	return &GoalRepository{DB: db}
+}
+
+// CreateGoal adds a new goal.
+func (r *GoalRepository) CreateGoal(goal *model.Goal) (*model.Goal, error) {
+	result := r.DB.Create(goal)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return goal, nil
+}
+
+// GetGoalByID retrieves a goal by its ID.
+func (r *GoalRepository) GetGoalByID(id uint) (*model.Goal, error) {
+	var goal model.Goal
+	result := r.DB.First(&goal, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &goal, nil
+}
+
+// GetAllGoals retrieves goals ordered by ID, optionally only the active ones.
+func (r *GoalRepository) GetAllGoals(activeOnly bool) ([]model.Goal, error) {
+	var goals []model.Goal
+	query := r.DB.Order("id ASC")
+	if activeOnly {
+		query = query.Where("active = ?", true)
+	}
+	if err := query.Find(&goals).Error; err != nil {
+		return nil, err
+	}
+	return goals, nil
+}
+
+// UpdateGoal modifies an existing goal.
+func (r *GoalRepository) UpdateGoal(id uint, updatedGoal *model.Goal) (*model.Goal, error) {
+	var existingGoal model.Goal
+	if err := r.DB.First(&existingGoal, id).Error; err != nil {
+		return nil, err // Goal not found
+	}
+
+	updatedGoal.ID = id
+	updatedGoal.CreatedAt = existingGoal.CreatedAt
+
+	result := r.DB.Save(updatedGoal)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return updatedGoal, nil
+}
+
+// DeleteGoal removes a goal.
+func (r *GoalRepository) DeleteGoal(id uint) error {
+	result := r.DB.Delete(&model.Goal{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
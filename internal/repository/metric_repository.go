@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/logging"
+	"github.com/aebalz/daily-vibe-tracker/internal/model"
+	"gorm.io/gorm"
+)
+
+// MetricRepositoryInterface defines the interface for custom metric definition repository operations.
+type MetricRepositoryInterface interface {
+	CreateMetricDefinition(def *model.MetricDefinition) (*model.MetricDefinition, error)
+	GetMetricDefinitionByID(id uint) (*model.MetricDefinition, error)
+	GetMetricDefinitionByName(name string) (*model.MetricDefinition, error)
+	GetAllMetricDefinitions() ([]model.MetricDefinition, error)
+	UpdateMetricDefinition(id uint, updatedDef *model.MetricDefinition) (*model.MetricDefinition, error)
+	DeleteMetricDefinition(id uint) error
+
+	CountMetricValues(definitionID uint) (int64, error)
+
+	// WithContext returns a repository whose queries and log lines carry ctx.
+	WithContext(ctx context.Context) MetricRepositoryInterface
+}
+
+// MetricRepository implements MetricRepositoryInterface.
+type MetricRepository struct {
+	DB     *gorm.DB
+	Logger *slog.Logger
+}
+
+// NewMetricRepository creates a new MetricRepository. A nil logger logs to the default logger.
+func NewMetricRepository(db *gorm.DB, logger *slog.Logger) MetricRepositoryInterface {
+	return &MetricRepository{DB: db, Logger: logging.OrDefault(logger)}
+}
+
+// WithContext returns a MetricRepository whose queries run with ctx.
+func (r *MetricRepository) WithContext(ctx context.Context) MetricRepositoryInterface {
+	return &MetricRepository{DB: r.DB.WithContext(ctx), Logger: r.Logger}
+}
+
+// context returns the context of the repository's queries, for its log lines.
+func (r *MetricRepository) context() context.Context {
+	if r.DB.Statement != nil && r.DB.Statement.Context != nil {
+		return r.DB.Statement.Context
+	}
+	return context.Background()
+}
+
+// CreateMetricDefinition adds a new metric definition.
+func (r *MetricRepository) CreateMetricDefinition(def *model.MetricDefinition) (*model.MetricDefinition, error) {
+	result := r.DB.Create(def)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return def, nil
+}
+
+// GetMetricDefinitionByID retrieves a metric definition by its ID.
+func (r *MetricRepository) GetMetricDefinitionByID(id uint) (*model.MetricDefinition, error) {
+	var def model.MetricDefinition
+	result := r.DB.First(&def, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &def, nil
+}
+
+// GetMetricDefinitionByName retrieves a metric definition by its name.
+func (r *MetricRepository) GetMetricDefinitionByName(name string) (*model.MetricDefinition, error) {
+	var def model.MetricDefinition
+	result := r.DB.Where("name = ?", name).First(&def)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &def, nil
+}
+
+// GetAllMetricDefinitions retrieves all metric definitions ordered by name.
+func (r *MetricRepository) GetAllMetricDefinitions() ([]model.MetricDefinition, error) {
+	var defs []model.MetricDefinition
+	result := r.DB.Order("name ASC").Find(&defs)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return defs, nil
+}
+
+// UpdateMetricDefinition modifies an existing metric definition.
+func (r *MetricRepository) UpdateMetricDefinition(id uint, updatedDef *model.MetricDefinition) (*model.MetricDefinition, error) {
+	var existingDef model.MetricDefinition
+	if err := r.DB.First(&existingDef, id).Error; err != nil {
+		return nil, err // Definition not found
+	}
+
+	updatedDef.ID = id
+	updatedDef.CreatedAt = existingDef.CreatedAt
+
+	result := r.DB.Save(updatedDef)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return updatedDef, nil
+}
+
+// DeleteMetricDefinition removes a metric definition together with all of its recorded values.
+func (r *MetricRepository) DeleteMetricDefinition(id uint) error {
+	var values int64
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		deleted := tx.Where("metric_definition_id = ?", id).Delete(&model.VibeMetricValue{})
+		if deleted.Error != nil {
+			return deleted.Error
+		}
+		values = deleted.RowsAffected
+		result := tx.Delete(&model.MetricDefinition{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	r.Logger.InfoContext(r.context(), "Deleted metric definition", slog.Uint64("id", uint64(id)), slog.Int64("values", values))
+	return nil
+}
+
+// CountMetricValues returns how many vibes have a value recorded for the definition.
+func (r *MetricRepository) CountMetricValues(definitionID uint) (int64, error) {
+	var count int64
+	err := r.DB.Model(&model.VibeMetricValue{}).Where("metric_definition_id = ?", definitionID).Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/logging"
+	"github.com/aebalz/daily-vibe-tracker/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MoodRepositoryInterface defines the interface for mood catalog repository operations.
+type MoodRepositoryInterface interface {
+	CreateMood(mood *model.Mood) (*model.Mood, error)
+	// CreateMoodIfAbsent adds mood unless a mood with its name exists, and returns the
+	// stored mood either way, so concurrent writers of a new mood end up with the same row.
+	CreateMoodIfAbsent(mood *model.Mood) (*model.Mood, error)
+	GetMoodByID(id uint) (*model.Mood, error)
+	GetAllMoods() ([]model.Mood, error)
+	UpdateMood(id uint, updatedMood *model.Mood) (*model.Mood, error)
+	DeleteMood(id uint) error
+
+	// FindMoodByNameOrAlias looks up a mood whose canonical name or one of its aliases matches.
+	// The name is expected to already be normalized (lowercased and trimmed).
+	FindMoodByNameOrAlias(name string) (*model.Mood, error)
+	CountMoods() (int64, error)
+
+	// WithTx returns a repository that runs its queries in the transaction tx.
+	WithTx(tx *gorm.DB) MoodRepositoryInterface
+	// WithContext returns a repository whose queries and log lines carry ctx.
+	WithContext(ctx context.Context) MoodRepositoryInterface
+}
+
+// MoodRepository implements MoodRepositoryInterface.
+type MoodRepository struct {
+	DB     *gorm.DB
+	Logger *slog.Logger
+}
+
+// NewMoodRepository creates a new MoodRepository. A nil logger logs to the default logger.
+func NewMoodRepository(db *gorm.DB, logger *slog.Logger) MoodRepositoryInterface {
+	return &MoodRepository{DB: db, Logger: logging.OrDefault(logger)}
+}
+
+// WithTx returns a MoodRepository bound to tx.
+func (r *MoodRepository) WithTx(tx *gorm.DB) MoodRepositoryInterface {
+	return &MoodRepository{DB: tx, Logger: r.Logger}
+}
+
+// WithContext returns a MoodRepository whose queries run with ctx.
+func (r *MoodRepository) WithContext(ctx context.Context) MoodRepositoryInterface {
+	return &MoodRepository{DB: r.DB.WithContext(ctx), Logger: r.Logger}
+}
+
+// context returns the context of the repository's queries, for its log lines.
+func (r *MoodRepository) context() context.Context {
+	if r.DB.Statement != nil && r.DB.Statement.Context != nil {
+		return r.DB.Statement.Context
+	}
+	return context.Background()
+}
+
+// CreateMood adds a new mood to the catalog.
+func (r *MoodRepository) CreateMood(mood *model.Mood) (*model.Mood, error) {
+	result := r.DB.Create(mood)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return mood, nil
+}
+
+// CreateMoodIfAbsent inserts mood with ON CONFLICT DO NOTHING on its name, then reads the
+// stored row back: the inserted one, or the one another writer committed first.
+func (r *MoodRepository) CreateMoodIfAbsent(mood *model.Mood) (*model.Mood, error) {
+	result := r.DB.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true}).Create(mood)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected > 0 {
+		r.Logger.InfoContext(r.context(), "Created mood", slog.String("mood", mood.Name))
+		return mood, nil
+	}
+	var stored model.Mood
+	if err := r.DB.Where("name = ?", mood.Name).First(&stored).Error; err != nil {
+		return nil, err
+	}
+	return &stored, nil
+}
+
+// GetMoodByID retrieves a single mood by its ID.
+func (r *MoodRepository) GetMoodByID(id uint) (*model.Mood, error) {
+	var mood model.Mood
+	result := r.DB.First(&mood, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &mood, nil
+}
+
+// GetAllMoods retrieves the whole mood catalog ordered by name.
+func (r *MoodRepository) GetAllMoods() ([]model.Mood, error) {
+	var moods []model.Mood
+	result := r.DB.Order("name ASC").Find(&moods)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return moods, nil
+}
+
+// UpdateMood modifies an existing mood in the catalog.
+func (r *MoodRepository) UpdateMood(id uint, updatedMood *model.Mood) (*model.Mood, error) {
+	var existingMood model.Mood
+	if err := r.DB.First(&existingMood, id).Error; err != nil {
+		return nil, err // Mood not found
+	}
+
+	updatedMood.ID = id
+	updatedMood.CreatedAt = existingMood.CreatedAt
+
+	result := r.DB.Save(updatedMood)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return updatedMood, nil
+}
+
+// DeleteMood removes a mood from the catalog.
+func (r *MoodRepository) DeleteMood(id uint) error {
+	result := r.DB.Delete(&model.Mood{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// FindMoodByNameOrAlias looks up a mood by canonical name first, then by alias.
+// Returns gorm.ErrRecordNotFound if neither matches.
+func (r *MoodRepository) FindMoodByNameOrAlias(name string) (*model.Mood, error) {
+	var mood model.Mood
+	// Canonical names take precedence over aliases so that an alias can never shadow a real mood.
+	result := r.DB.Where("name = ?", name).Limit(1).Find(&mood)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return &mood, nil
+	}
+
+	result = r.DB.Where("? = ANY(aliases)", name).Order("id ASC").First(&mood)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &mood, nil
+}
+
+// CountMoods returns the number of moods in the catalog.
+func (r *MoodRepository) CountMoods() (int64, error) {
+	var count int64
+	err := r.DB.Model(&model.Mood{}).Count(&count).Error
+	return count, err
+}
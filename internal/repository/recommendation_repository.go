@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RecommendationRepositoryInterface defines the interface for recommendation repository operations.
+type RecommendationRepositoryInterface interface {
+	// AddNotInterested marks an activity as not interesting. Adding it twice is not an error.
+	AddNotInterested(activity string) (*model.NotInterestedActivity, error)
+	RemoveNotInterested(activity string) error
+	GetNotInterested() ([]model.NotInterestedActivity, error)
+
+	// SaveRecommendations stores served recommendations, reusing the record of an activity
+	// already recommended for the same day. IDs are filled in on the given records.
+	SaveRecommendations(recommendations []*model.Recommendation) error
+	GetRecommendationByID(id uint) (*model.Recommendation, error)
+	SetFeedback(id uint, feedback string, at time.Time) (*model.Recommendation, error)
+	// LinkVibe links unlinked recommendations of the given activities made between startDate
+	// and endDate to a vibe, and reports how many were linked.
+	LinkVibe(vibeID uint, activities []string, startDate, endDate time.Time) (int64, error)
+	GetRecommendationsForDateRange(startDate, endDate time.Time) ([]model.Recommendation, error)
+}
+
+// RecommendationRepository implements RecommendationRepositoryInterface.
+type RecommendationRepository struct {
+	DB *gorm.DB
+}
+
+// NewRecommendationRepository creates a new RecommendationRepository.
+func NewRecommendationRepository(db *gorm.DB) RecommendationRepositoryInterface {
+	return &RecommendationRepository{DB: db}
+}
+
+// AddNotInterested stores a "not interested" entry for an activity.
+func (r *RecommendationRepository) AddNotInterested(activity string) (*model.NotInterestedActivity, error) {
+	entry := model.NotInterestedActivity{Activity: activity}
+	if err := r.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&entry).Error; err != nil {
+		return nil, err
+	}
+	if err := r.DB.Where("activity = ?", activity).First(&entry).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// RemoveNotInterested deletes the "not interested" entry for an activity.
+func (r *RecommendationRepository) RemoveNotInterested(activity string) error {
+	result := r.DB.Where("activity = ?", activity).Delete(&model.NotInterestedActivity{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetNotInterested retrieves all "not interested" entries ordered by activity.
+func (r *RecommendationRepository) GetNotInterested() ([]model.NotInterestedActivity, error) {
+	var entries []model.NotInterestedActivity
+	result := r.DB.Order("activity ASC").Find(&entries)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return entries, nil
+}
+
+// SaveRecommendations upserts served recommendations on (date, activity).
+func (r *RecommendationRepository) SaveRecommendations(recommendations []*model.Recommendation) error {
+	if len(recommendations) == 0 {
+		return nil
+	}
+	return r.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "date"}, {Name: "activity"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rank", "score", "seed", "updated_at"}),
+	}).Create(&recommendations).Error
+}
+
+// GetRecommendationByID retrieves a served recommendation by its ID.
+func (r *RecommendationRepository) GetRecommendationByID(id uint) (*model.Recommendation, error) {
+	var recommendation model.Recommendation
+	if err := r.DB.First(&recommendation, id).Error; err != nil {
+		return nil, err
+	}
+	return &recommendation, nil
+}
+
+// SetFeedback records feedback on a recommendation, replacing any earlier feedback.
+func (r *RecommendationRepository) SetFeedback(id uint, feedback string, at time.Time) (*model.Recommendation, error) {
+	result := r.DB.Model(&model.Recommendation{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"feedback":    feedback,
+		"feedback_at": at,
+	})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return r.GetRecommendationByID(id)
+}
+
+// LinkVibe sets the linked vibe on matching recommendations that are not linked yet.
+func (r *RecommendationRepository) LinkVibe(vibeID uint, activities []string, startDate, endDate time.Time) (int64, error) {
+	if len(activities) == 0 {
+		return 0, nil
+	}
+	result := r.DB.Model(&model.Recommendation{}).
+		Where("linked_vibe_id IS NULL AND activity IN ? AND date BETWEEN ? AND ?", activities, startDate, endDate).
+		Updates(map[string]interface{}{
+			"linked_vibe_id": vibeID,
+			"linked_at":      time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
+
+// GetRecommendationsForDateRange retrieves the recommendations served for days within a date range.
+func (r *RecommendationRepository) GetRecommendationsForDateRange(startDate, endDate time.Time) ([]model.Recommendation, error) {
+	var recommendations []model.Recommendation
+	result := r.DB.Where("date BETWEEN ? AND ?", startDate, endDate).Order("date ASC").Order("rank ASC").Find(&recommendations)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return recommendations, nil
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReminderRepositoryInterface defines the interface for reminder repository operations.
+type ReminderRepositoryInterface interface {
+	CreateReminder(reminder *model.Reminder) (*model.Reminder, error)
+	GetReminderByID(id uint) (*model.Reminder, error)
+	GetAllReminders(enabledOnly bool) ([]model.Reminder, error)
+	UpdateReminder(id uint, updatedReminder *model.Reminder) (*model.Reminder, error)
+	DeleteReminder(id uint) error
+
+	// CreateDelivery stores a delivery unless one already exists for the same reminder and
+	// occurrence. It reports whether a new delivery was stored.
+	CreateDelivery(delivery *model.ReminderDelivery) (bool, error)
+	UpdateDelivery(delivery *model.ReminderDelivery) error
+	// ClaimDueDeliveries claims up to limit pending deliveries whose next attempt is due,
+	// oldest first, by moving their next attempt to now+lease. Other schedulers skip them
+	// until the lease runs out, so a delivery is only retried by another instance when the
+	// one that claimed it did not save an outcome in time.
+	ClaimDueDeliveries(now time.Time, lease time.Duration, limit int) ([]model.ReminderDelivery, error)
+	GetDeliveries(reminderID uint, limit, offset int) ([]model.ReminderDelivery, int64, error)
+}
+
+// ReminderRepository implements ReminderRepositoryInterface.
+type ReminderRepository struct {
+	DB *gorm.DB
+}
+
+// NewReminderRepository creates a new ReminderRepository.
+func NewReminderRepository(db *gorm.DB) ReminderRepositoryInterface {
+	return &ReminderRepository{DB: db}
+}
+
+// CreateReminder adds a new reminder.
+func (r *ReminderRepository) CreateReminder(reminder *model.Reminder) (*model.Reminder, error) {
+	result := r.DB.Create(reminder)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return reminder, nil
+}
+
+// GetReminderByID retrieves a reminder by its ID.
+func (r *ReminderRepository) GetReminderByID(id uint) (*model.Reminder, error) {
+	var reminder model.Reminder
+	result := r.DB.First(&reminder, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &reminder, nil
+}
+
+// GetAllReminders retrieves reminders ordered by ID, optionally only the enabled ones.
+func (r *ReminderRepository) GetAllReminders(enabledOnly bool) ([]model.Reminder, error) {
+	var reminders []model.Reminder
+	query := r.DB.Order("id ASC")
+	if enabledOnly {
+		query = query.Where("enabled = ?", true)
+	}
+	if err := query.Find(&reminders).Error; err != nil {
+		return nil, err
+	}
+	return reminders, nil
+}
+
+// UpdateReminder modifies an existing reminder.
+func (r *ReminderRepository) UpdateReminder(id uint, updatedReminder *model.Reminder) (*model.Reminder, error) {
+	var existingReminder model.Reminder
+	if err := r.DB.First(&existingReminder, id).Error; err != nil {
+		return nil, err // Reminder not found
+	}
+
+	updatedReminder.ID = id
+	updatedReminder.CreatedAt = existingReminder.CreatedAt
+
+	result := r.DB.Save(updatedReminder)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return updatedReminder, nil
+}
+
+// DeleteReminder removes a reminder together with its delivery log.
+func (r *ReminderRepository) DeleteReminder(id uint) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("reminder_id = ?", id).Delete(&model.ReminderDelivery{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&model.Reminder{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// CreateDelivery adds a delivery, ignoring duplicates so scheduling can be re-run safely.
+func (r *ReminderRepository) CreateDelivery(delivery *model.ReminderDelivery) (bool, error) {
+	result := r.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(delivery)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// UpdateDelivery saves the outcome of a delivery attempt.
+func (r *ReminderRepository) UpdateDelivery(delivery *model.ReminderDelivery) error {
+	return r.DB.Save(delivery).Error
+}
+
+// ClaimDueDeliveries locks the due deliveries, skipping those locked by another scheduler,
+// and leases them in the same transaction.
+func (r *ReminderRepository) ClaimDueDeliveries(now time.Time, lease time.Duration, limit int) ([]model.ReminderDelivery, error) {
+	var deliveries []model.ReminderDelivery
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", model.DeliveryStatusPending, now).
+			Order("next_attempt_at ASC").Limit(limit).Find(&deliveries).Error
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+		leasedUntil := now.Add(lease)
+		ids := make([]uint, len(deliveries))
+		for i := range deliveries {
+			ids[i] = deliveries[i].ID
+			deliveries[i].NextAttemptAt = &leasedUntil
+		}
+		return tx.Model(&model.ReminderDelivery{}).Where("id IN ?", ids).Update("next_attempt_at", leasedUntil).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// GetDeliveries retrieves the delivery log of a reminder, most recent first.
+func (r *ReminderRepository) GetDeliveries(reminderID uint, limit, offset int) ([]model.ReminderDelivery, int64, error) {
+	var deliveries []model.ReminderDelivery
+	var total int64
+
+	query := r.DB.Model(&model.ReminderDelivery{}).Where("reminder_id = ?", reminderID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Order("scheduled_for DESC").Limit(limit).Offset(offset).Find(&deliveries).Error; err != nil {
+		return nil, 0, err
+	}
+	return deliveries, total, nil
+}
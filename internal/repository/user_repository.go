@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/model"
+	"gorm.io/gorm"
+)
+
+// UserRepositoryInterface defines the interface for user and API key repository operations.
+type UserRepositoryInterface interface {
+	CreateUser(user *model.User) (*model.User, error)
+	GetUserByUsername(username string) (*model.User, error)
+
+	CreateAPIKey(key *model.APIKey) (*model.APIKey, error)
+	// GetAPIKeyByHash returns the key with the given hash, with its user.
+	GetAPIKeyByHash(hash string) (*model.APIKey, error)
+	TouchAPIKey(id uint, usedAt time.Time) error
+
+	// WithContext returns a repository whose queries carry ctx.
+	WithContext(ctx context.Context) UserRepositoryInterface
+}
+
+// UserRepository implements UserRepositoryInterface.
+type UserRepository struct {
+	DB *gorm.DB
+}
+
+// NewUserRepository creates a new UserRepository.
+func NewUserRepository(db *gorm.DB) UserRepositoryInterface {
+	return &UserRepository{DB: db}
+}
+
+// WithContext returns a UserRepository whose queries run with ctx.
+func (r *UserRepository) WithContext(ctx context.Context) UserRepositoryInterface {
+	return &UserRepository{DB: r.DB.WithContext(ctx)}
+}
+
+// CreateUser adds a new user.
+func (r *UserRepository) CreateUser(user *model.User) (*model.User, error) {
+	result := r.DB.Create(user)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return user, nil
+}
+
+// GetUserByUsername retrieves a user by username.
+func (r *UserRepository) GetUserByUsername(username string) (*model.User, error) {
+	var user model.User
+	result := r.DB.Where("username = ?", username).First(&user)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &user, nil
+}
+
+// CreateAPIKey adds a new API key.
+func (r *UserRepository) CreateAPIKey(key *model.APIKey) (*model.APIKey, error) {
+	result := r.DB.Create(key)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return key, nil
+}
+
+// GetAPIKeyByHash retrieves an API key and its user by the hash of the key.
+func (r *UserRepository) GetAPIKeyByHash(hash string) (*model.APIKey, error) {
+	var key model.APIKey
+	result := r.DB.Preload("User").Where("key_hash = ?", hash).First(&key)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &key, nil
+}
+
+// TouchAPIKey records when a key was last used.
+func (r *UserRepository) TouchAPIKey(id uint, usedAt time.Time) error {
+	return r.DB.Model(&model.APIKey{}).Where("id = ?", id).Update("last_used_at", usedAt).Error
+}
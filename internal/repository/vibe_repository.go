@@ -2,20 +2,95 @@ package repository
 
 import (
 	"bytes"
+	"context"
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
+	"github.com/aebalz/daily-vibe-tracker/internal/logging"
 	"github.com/aebalz/daily-vibe-tracker/internal/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// MetricSortPrefix marks a sortBy value that sorts by a custom metric, e.g. "metric:sleep_hours".
+const MetricSortPrefix = "metric:"
+
+// metricValueSubquery selects a vibe's value for a named custom metric.
+const metricValueSubquery = "(SELECT mv.value FROM vibe_metric_values mv JOIN metric_definitions md ON md.id = mv.metric_definition_id WHERE mv.vibe_id = vibes.id AND md.name = ?)"
+
+// metricFilterOperators maps MetricFilter operators to SQL comparison operators.
+var metricFilterOperators = map[string]string{
+	"eq":  "=",
+	"ne":  "<>",
+	"gt":  ">",
+	"gte": ">=",
+	"lt":  "<",
+	"lte": "<=",
+}
+
+// applyVibeFilters applies the common vibe filters (date, date range, mood, custom metrics) to a query.
+func applyVibeFilters(query *gorm.DB, filters map[string]interface{}) *gorm.DB {
+	if date, ok := filters["date"]; ok {
+		query = query.Where("DATE(date) = ?", date)
+	}
+	if startDate, ok := filters["start_date"]; ok {
+		query = query.Where("DATE(date) >= ?", startDate)
+	}
+	if endDate, ok := filters["end_date"]; ok {
+		query = query.Where("DATE(date) <= ?", endDate)
+	}
+	if mood, ok := filters["mood"]; ok {
+		query = query.Where("mood = ?", mood)
+	}
+	if metricFilters, ok := filters["metrics"].([]model.MetricFilter); ok {
+		for _, f := range metricFilters {
+			op, valid := metricFilterOperators[f.Operator]
+			if !valid {
+				continue // Operators are validated by the service layer
+			}
+			query = query.Where(metricValueSubquery+" "+op+" ?", f.Name, f.Value)
+		}
+	}
+	return query
+}
+
+// applyVibeSort applies sorting to a vibe query, supporting custom metric sorts.
+func applyVibeSort(query *gorm.DB, sortBy, sortOrder, defaultOrder string) *gorm.DB {
+	if sortBy == "" || sortOrder == "" {
+		return query.Order(defaultOrder)
+	}
+	if metricName, ok := strings.CutPrefix(sortBy, MetricSortPrefix); ok {
+		// Vibes without a value for the metric always go last.
+		return query.Order(clause.Expr{
+			SQL:  metricValueSubquery + " " + sortOrder + " NULLS LAST",
+			Vars: []interface{}{metricName},
+		})
+	}
+	return query.Order(fmt.Sprintf("%s %s", sortBy, sortOrder))
+}
+
+// withMetrics preloads custom metric values together with their definitions.
+func withMetrics(query *gorm.DB) *gorm.DB {
+	return query.Preload("MetricValues.Definition")
+}
+
+// populateMetrics fills the Metrics map of each vibe from its preloaded metric values.
+func populateMetrics(vibes []model.Vibe) {
+	for i := range vibes {
+		vibes[i].PopulateMetrics()
+	}
+}
+
 // VibeRepositoryInterface defines the interface for vibe repository operations.
 type VibeRepositoryInterface interface {
 	CreateVibe(vibe *model.Vibe) (*model.Vibe, error)
 	GetVibeByID(id uint) (*model.Vibe, error)
+	// GetVibesByIDs retrieves the vibes with the given IDs in one query; missing IDs are skipped.
+	GetVibesByIDs(ids []uint) ([]model.Vibe, error)
 	GetAllVibes(filters map[string]interface{}, limit, offset int, sortBy, sortOrder string) ([]model.Vibe, int64, error)
 	UpdateVibe(id uint, updatedVibe *model.Vibe) (*model.Vibe, error)
 	DeleteVibe(id uint) error
@@ -27,17 +102,49 @@ type VibeRepositoryInterface interface {
 
 	// Bulk and Export
 	BulkInsertVibes(vibes []*model.Vibe) (int64, error)
+	// InsertMissingVibes inserts vibes in batches, skipping days that already have a vibe,
+	// including deleted ones. It returns the number of inserted vibes.
+	InsertMissingVibes(vibes []*model.Vibe) (int64, error)
 	ExportVibes(filters map[string]interface{}, format string, sortBy, sortOrder string) ([]byte, string, error)
+
+	// Trash: deleted vibes are kept, and keep their day taken, until they are purged.
+	CountDeletedVibes(deletedBefore time.Time) (int64, error)
+	PurgeDeletedVibes(deletedBefore time.Time) (int64, error)
+
+	// WithTx returns a repository that runs its queries in the transaction tx.
+	WithTx(tx *gorm.DB) VibeRepositoryInterface
+	// WithContext returns a repository whose queries and log lines carry ctx, e.g. the
+	// request ID and the cancellation of an HTTP request.
+	WithContext(ctx context.Context) VibeRepositoryInterface
 }
 
 // VibeRepository implements VibeRepositoryInterface.
 type VibeRepository struct {
-	DB *gorm.DB
+	DB     *gorm.DB
+	Logger *slog.Logger
 }
 
-// NewVibeRepository creates a new VibeRepository.
-func NewVibeRepository(db *gorm.DB) VibeRepositoryInterface {
-	return &VibeRepository{DB: db}
+// NewVibeRepository creates a new VibeRepository. A nil logger logs to the default logger.
+func NewVibeRepository(db *gorm.DB, logger *slog.Logger) VibeRepositoryInterface {
+	return &VibeRepository{DB: db, Logger: logging.OrDefault(logger)}
+}
+
+// WithTx returns a VibeRepository bound to tx.
+func (r *VibeRepository) WithTx(tx *gorm.DB) VibeRepositoryInterface {
+	return &VibeRepository{DB: tx, Logger: r.Logger}
+}
+
+// WithContext returns a VibeRepository whose queries run with ctx.
+func (r *VibeRepository) WithContext(ctx context.Context) VibeRepositoryInterface {
+	return &VibeRepository{DB: r.DB.WithContext(ctx), Logger: r.Logger}
+}
+
+// context returns the context of the repository's queries, for its log lines.
+func (r *VibeRepository) context() context.Context {
+	if r.DB.Statement != nil && r.DB.Statement.Context != nil {
+		return r.DB.Statement.Context
+	}
+	return context.Background()
 }
 
 // CreateVibe adds a new vibe to the database.
@@ -52,13 +159,28 @@ func (r *VibeRepository) CreateVibe(vibe *model.Vibe) (*model.Vibe, error) {
 // GetVibeByID retrieves a single vibe by its ID.
 func (r *VibeRepository) GetVibeByID(id uint) (*model.Vibe, error) {
 	var vibe model.Vibe
-	result := r.DB.First(&vibe, id)
+	result := withMetrics(r.DB).First(&vibe, id)
 	if result.Error != nil {
 		return nil, result.Error
 	}
+	vibe.PopulateMetrics()
 	return &vibe, nil
 }
 
+// GetVibesByIDs retrieves the vibes with the given IDs, ordered by ID.
+func (r *VibeRepository) GetVibesByIDs(ids []uint) ([]model.Vibe, error) {
+	var vibes []model.Vibe
+	if len(ids) == 0 {
+		return vibes, nil
+	}
+	result := withMetrics(r.DB).Where("id IN ?", ids).Order("id ASC").Find(&vibes)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	populateMetrics(vibes)
+	return vibes, nil
+}
+
 // GetAllVibes retrieves vibes with optional filters, pagination, and sorting.
 func (r *VibeRepository) GetAllVibes(filters map[string]interface{}, limit, offset int, sortBy, sortOrder string) ([]model.Vibe, int64, error) {
 	var vibes []model.Vibe
@@ -67,12 +189,7 @@ func (r *VibeRepository) GetAllVibes(filters map[string]interface{}, limit, offs
 	query := r.DB.Model(&model.Vibe{})
 
 	// Apply filters
-	if date, ok := filters["date"]; ok {
-		query = query.Where("DATE(date) = ?", date)
-	}
-	if mood, ok := filters["mood"]; ok {
-		query = query.Where("mood = ?", mood)
-	}
+	query = applyVibeFilters(query, filters)
 	// Add more filters as needed, e.g., energy_level
 
 	// Get total count before pagination
@@ -82,12 +199,7 @@ func (r *VibeRepository) GetAllVibes(filters map[string]interface{}, limit, offs
 	}
 
 	// Apply sorting
-	if sortBy != "" && sortOrder != "" {
-		orderClause := fmt.Sprintf("%s %s", sortBy, sortOrder)
-		query = query.Order(orderClause)
-	} else {
-		query = query.Order("date DESC") // Default sort
-	}
+	query = applyVibeSort(query, sortBy, sortOrder, "date DESC") // Default sort
 
 	// Apply pagination
 	if limit > 0 {
@@ -97,10 +209,11 @@ func (r *VibeRepository) GetAllVibes(filters map[string]interface{}, limit, offs
 		query = query.Offset(offset)
 	}
 
-	result := query.Find(&vibes)
+	result := withMetrics(query).Find(&vibes)
 	if result.Error != nil {
 		return nil, 0, result.Error
 	}
+	populateMetrics(vibes)
 	return vibes, totalCount, nil
 }
 
@@ -120,11 +233,30 @@ func (r *VibeRepository) UpdateVibe(id uint, updatedVibe *model.Vibe) (*model.Vi
 	updatedVibe.ID = id
 	updatedVibe.CreatedAt = existingVibe.CreatedAt
 
-	result := r.DB.Save(updatedVibe)
-	if result.Error != nil {
-		return nil, result.Error
+	// Custom metric values are replaced as a whole when provided (non-nil) and kept otherwise.
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("MetricValues").Save(updatedVibe).Error; err != nil {
+			return err
+		}
+		if updatedVibe.MetricValues == nil {
+			return nil
+		}
+		if err := tx.Where("vibe_id = ?", id).Delete(&model.VibeMetricValue{}).Error; err != nil {
+			return err
+		}
+		if len(updatedVibe.MetricValues) == 0 {
+			return nil
+		}
+		for i := range updatedVibe.MetricValues {
+			updatedVibe.MetricValues[i].ID = 0
+			updatedVibe.MetricValues[i].VibeID = id
+		}
+		return tx.Omit("Definition").Create(&updatedVibe.MetricValues).Error
+	})
+	if err != nil {
+		return nil, err
 	}
-	return updatedVibe, nil
+	return r.GetVibeByID(id)
 }
 
 // DeleteVibe removes a vibe from the database (soft delete if gorm.DeletedAt is used).
@@ -172,6 +304,24 @@ func (r *VibeRepository) GetVibeStatistics(period string, startDate, endDate tim
 	}
 	stats["average_energy_level"] = avgEnergyLevel
 
+	// Custom metric aggregates
+	var metricAggregates []model.MetricAggregate
+	err = r.DB.Raw(`
+		SELECT md.name, md.type, md.unit,
+			COUNT(mv.id) AS count,
+			COALESCE(AVG(mv.value), 0) AS average,
+			COALESCE(MIN(mv.value), 0) AS min,
+			COALESCE(MAX(mv.value), 0) AS max
+		FROM metric_definitions md
+		LEFT JOIN vibe_metric_values mv ON mv.metric_definition_id = md.id
+			AND mv.vibe_id IN (SELECT id FROM vibes WHERE date BETWEEN ? AND ? AND deleted_at IS NULL)
+		GROUP BY md.id, md.name, md.type, md.unit
+		ORDER BY md.name ASC`, startDate, endDate).Scan(&metricAggregates).Error
+	if err != nil {
+		return nil, fmt.Errorf("error getting custom metric aggregates: %w", err)
+	}
+	stats["metric_aggregates"] = metricAggregates
+
 	// Could add trends here, e.g., mood over time, requires more complex queries or processing
 
 	return stats, nil
@@ -180,10 +330,11 @@ func (r *VibeRepository) GetVibeStatistics(period string, startDate, endDate tim
 // GetVibesForDateRange retrieves all vibes within a specific date range.
 func (r *VibeRepository) GetVibesForDateRange(startDate, endDate time.Time) ([]model.Vibe, error) {
 	var vibes []model.Vibe
-	result := r.DB.Where("date BETWEEN ? AND ?", startDate, endDate).Order("date ASC").Find(&vibes)
+	result := withMetrics(r.DB).Where("date BETWEEN ? AND ?", startDate, endDate).Order("date ASC").Find(&vibes)
 	if result.Error != nil {
 		return nil, result.Error
 	}
+	populateMetrics(vibes)
 	return vibes, nil
 }
 
@@ -276,32 +427,62 @@ func (r *VibeRepository) BulkInsertVibes(vibes []*model.Vibe) (int64, error) {
 	if result.Error != nil {
 		return 0, result.Error
 	}
+	r.Logger.DebugContext(r.context(), "Inserted vibes", slog.Int64("rows", result.RowsAffected))
 	return result.RowsAffected, nil
 }
 
+// InsertMissingVibes inserts vibes with ON CONFLICT DO NOTHING on the unique date.
+func (r *VibeRepository) InsertMissingVibes(vibes []*model.Vibe) (int64, error) {
+	if len(vibes) == 0 {
+		return 0, nil
+	}
+	result := r.DB.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(&vibes, 500)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// CountDeletedVibes counts the soft-deleted vibes deleted before the given time.
+func (r *VibeRepository) CountDeletedVibes(deletedBefore time.Time) (int64, error) {
+	var count int64
+	err := r.DB.Unscoped().Model(&model.Vibe{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).
+		Count(&count).Error
+	return count, err
+}
+
+// PurgeDeletedVibes permanently removes the vibes soft-deleted before the given time. Their
+// metric values are removed by the ON DELETE CASCADE constraint.
+func (r *VibeRepository) PurgeDeletedVibes(deletedBefore time.Time) (int64, error) {
+	result := r.DB.Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).
+		Delete(&model.Vibe{})
+	if result.Error == nil {
+		r.Logger.InfoContext(r.context(), "Purged deleted vibes", slog.Int64("rows", result.RowsAffected), slog.Time("deleted_before", deletedBefore))
+	}
+	return result.RowsAffected, result.Error
+}
+
 // ExportVibes retrieves vibes based on filters and formats them as CSV or JSON.
 func (r *VibeRepository) ExportVibes(filters map[string]interface{}, format string, sortBy, sortOrder string) ([]byte, string, error) {
 	var vibes []model.Vibe
 	query := r.DB.Model(&model.Vibe{})
 
-	// Apply filters (similar to GetAllVibes)
-	if date, ok := filters["date"]; ok {
-		query = query.Where("DATE(date) = ?", date)
-	}
-	if mood, ok := filters["mood"]; ok {
-		query = query.Where("mood = ?", mood)
-	}
-	// Add more filters as needed
+	// Apply filters (same as GetAllVibes)
+	query = applyVibeFilters(query, filters)
 
 	// Apply sorting
-	if sortBy != "" && sortOrder != "" {
-		orderClause := fmt.Sprintf("%s %s", sortBy, sortOrder)
-		query = query.Order(orderClause)
-	} else {
-		query = query.Order("date ASC") // Default sort for export
+	query = applyVibeSort(query, sortBy, sortOrder, "date ASC") // Default sort for export
+
+	if err := withMetrics(query).Find(&vibes).Error; err != nil {
+		return nil, "", err
 	}
+	populateMetrics(vibes)
 
-	if err := query.Find(&vibes).Error; err != nil {
+	// Every metric definition becomes an extra CSV column, even if no exported vibe uses it.
+	var metricDefs []model.MetricDefinition
+	if err := r.DB.Order("name ASC").Find(&metricDefs).Error; err != nil {
 		return nil, "", err
 	}
 
@@ -316,6 +497,9 @@ func (r *VibeRepository) ExportVibes(filters map[string]interface{}, format stri
 		writer := csv.NewWriter(&buffer)
 		// Write header
 		header := []string{"ID", "Date", "Mood", "EnergyLevel", "Notes", "Activities"}
+		for _, def := range metricDefs {
+			header = append(header, def.Name)
+		}
 		if err = writer.Write(header); err != nil {
 			return nil, "", err
 		}
@@ -329,6 +513,14 @@ func (r *VibeRepository) ExportVibes(filters map[string]interface{}, format stri
 				vibe.Notes,
 				strings.Join(vibe.Activities, ";"), // CSV friendly format for array
 			}
+			for _, def := range metricDefs {
+				value, ok := vibe.Metrics[def.Name]
+				if !ok {
+					row = append(row, "")
+					continue
+				}
+				row = append(row, fmt.Sprintf("%v", value))
+			}
 			if err = writer.Write(row); err != nil {
 				return nil, "", err
 			}
@@ -349,6 +541,7 @@ func (r *VibeRepository) ExportVibes(filters map[string]interface{}, format stri
 		return nil, "", fmt.Errorf("unsupported export format: %s", format)
 	}
 
+	r.Logger.DebugContext(r.context(), "Exported vibes", slog.String("format", format), slog.Int("vibes", len(vibes)), slog.Int("bytes", len(data)))
 	return data, contentType, nil
 }
 
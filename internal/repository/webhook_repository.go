@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WebhookRepositoryInterface defines the interface for webhook subscription and outbox operations.
+type WebhookRepositoryInterface interface {
+	CreateSubscription(sub *model.WebhookSubscription) (*model.WebhookSubscription, error)
+	GetSubscriptionByID(id uint) (*model.WebhookSubscription, error)
+	GetAllSubscriptions(activeOnly bool) ([]model.WebhookSubscription, error)
+	UpdateSubscription(id uint, updatedSub *model.WebhookSubscription) (*model.WebhookSubscription, error)
+	DeleteSubscription(id uint) error
+
+	// EnqueueEvent stores an event in the outbox with a pending delivery for each subscription, atomically.
+	EnqueueEvent(event *model.OutboxEvent, subscriptionIDs []uint, now time.Time) ([]model.WebhookDelivery, error)
+	// ClaimDueDeliveries claims up to limit pending deliveries whose next attempt is due,
+	// with their event, oldest first, by moving their next attempt to now+lease. Other
+	// workers skip them until the lease runs out, so a delivery is only retried by another
+	// instance when the one that claimed it did not save an outcome in time.
+	ClaimDueDeliveries(now time.Time, lease time.Duration, limit int) ([]model.WebhookDelivery, error)
+	GetDeliveryByID(id uint) (*model.WebhookDelivery, error)
+	UpdateDelivery(delivery *model.WebhookDelivery) error
+	// GetDeliveries retrieves deliveries with filtering and pagination, most recent first.
+	// Supported filters: subscription_id (uint), status and event_type (string).
+	GetDeliveries(filters map[string]interface{}, limit, offset int) ([]model.WebhookDelivery, int64, error)
+}
+
+// WebhookRepository implements WebhookRepositoryInterface.
+type WebhookRepository struct {
+	DB *gorm.DB
+}
+
+// NewWebhookRepository creates a new WebhookRepository.
+func NewWebhookRepository(db *gorm.DB) WebhookRepositoryInterface {
+	return &WebhookRepository{DB: db}
+}
+
+// CreateSubscription adds a new webhook subscription.
+func (r *WebhookRepository) CreateSubscription(sub *model.WebhookSubscription) (*model.WebhookSubscription, error) {
+	result := r.DB.Create(sub)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return sub, nil
+}
+
+// GetSubscriptionByID retrieves a webhook subscription by its ID.
+func (r *WebhookRepository) GetSubscriptionByID(id uint) (*model.WebhookSubscription, error) {
+	var sub model.WebhookSubscription
+	result := r.DB.First(&sub, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &sub, nil
+}
+
+// GetAllSubscriptions retrieves webhook subscriptions ordered by ID, optionally only the active ones.
+func (r *WebhookRepository) GetAllSubscriptions(activeOnly bool) ([]model.WebhookSubscription, error) {
+	var subs []model.WebhookSubscription
+	query := r.DB.Order("id ASC")
+	if activeOnly {
+		query = query.Where("active = ?", true)
+	}
+	if err := query.Find(&subs).Error; err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+// UpdateSubscription modifies an existing webhook subscription.
+func (r *WebhookRepository) UpdateSubscription(id uint, updatedSub *model.WebhookSubscription) (*model.WebhookSubscription, error) {
+	var existingSub model.WebhookSubscription
+	if err := r.DB.First(&existingSub, id).Error; err != nil {
+		return nil, err // Subscription not found
+	}
+
+	updatedSub.ID = id
+	updatedSub.CreatedAt = existingSub.CreatedAt
+	if updatedSub.Secret == "" {
+		updatedSub.Secret = existingSub.Secret // Keep the secret unless a new one is given
+	}
+
+	result := r.DB.Save(updatedSub)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return updatedSub, nil
+}
+
+// DeleteSubscription removes a webhook subscription together with its deliveries.
+func (r *WebhookRepository) DeleteSubscription(id uint) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("subscription_id = ?", id).Delete(&model.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&model.WebhookSubscription{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// EnqueueEvent writes the event and its deliveries in one transaction.
+func (r *WebhookRepository) EnqueueEvent(event *model.OutboxEvent, subscriptionIDs []uint, now time.Time) ([]model.WebhookDelivery, error) {
+	deliveries := make([]model.WebhookDelivery, len(subscriptionIDs))
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(event).Error; err != nil {
+			return err
+		}
+		if len(subscriptionIDs) == 0 {
+			return nil
+		}
+		for i, id := range subscriptionIDs {
+			next := now
+			deliveries[i] = model.WebhookDelivery{
+				SubscriptionID: id,
+				EventID:        event.ID,
+				EventType:      event.Type,
+				Status:         model.WebhookDeliveryPending,
+				NextAttemptAt:  &next,
+			}
+		}
+		return tx.Omit("Event").Create(&deliveries).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// ClaimDueDeliveries locks the due deliveries, skipping those locked by another worker,
+// and leases them in the same transaction.
+func (r *WebhookRepository) ClaimDueDeliveries(now time.Time, lease time.Duration, limit int) ([]model.WebhookDelivery, error) {
+	var deliveries []model.WebhookDelivery
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		var ids []uint
+		err := tx.Model(&model.WebhookDelivery{}).Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", model.WebhookDeliveryPending, now).
+			Order("next_attempt_at ASC").Order("id ASC").Limit(limit).Pluck("id", &ids).Error
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+		if err := tx.Model(&model.WebhookDelivery{}).Where("id IN ?", ids).Update("next_attempt_at", now.Add(lease)).Error; err != nil {
+			return err
+		}
+		return tx.Preload("Event").Where("id IN ?", ids).Order("id ASC").Find(&deliveries).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// GetDeliveryByID retrieves a delivery with its event.
+func (r *WebhookRepository) GetDeliveryByID(id uint) (*model.WebhookDelivery, error) {
+	var delivery model.WebhookDelivery
+	result := r.DB.Preload("Event").First(&delivery, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &delivery, nil
+}
+
+// UpdateDelivery saves the outcome of a delivery attempt.
+func (r *WebhookRepository) UpdateDelivery(delivery *model.WebhookDelivery) error {
+	return r.DB.Omit("Event").Save(delivery).Error
+}
+
+// GetDeliveries retrieves deliveries with filtering and pagination.
+func (r *WebhookRepository) GetDeliveries(filters map[string]interface{}, limit, offset int) ([]model.WebhookDelivery, int64, error) {
+	var deliveries []model.WebhookDelivery
+	var total int64
+
+	query := r.DB.Model(&model.WebhookDelivery{})
+	if subscriptionID, ok := filters["subscription_id"].(uint); ok {
+		query = query.Where("subscription_id = ?", subscriptionID)
+	}
+	if status, ok := filters["status"].(string); ok && status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if eventType, ok := filters["event_type"].(string); ok && eventType != "" {
+		query = query.Where("event_type = ?", eventType)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&deliveries).Error; err != nil {
+		return nil, 0, err
+	}
+	return deliveries, total, nil
+}
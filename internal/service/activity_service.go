@@ -0,0 +1,379 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/config"
+	"github.com/aebalz/daily-vibe-tracker/internal/model"
+	"github.com/aebalz/daily-vibe-tracker/internal/repository"
+	"gorm.io/gorm"
+)
+
+// ActivityServiceInterface defines the interface for activity catalog operations.
+type ActivityServiceInterface interface {
+	CreateActivity(activity *model.Activity) (*model.Activity, error)
+	GetActivityByID(id uint) (*model.Activity, error)
+	GetAllActivitiesWithUsage() ([]model.ActivityWithUsage, error)
+	UpdateActivity(id uint, updatedActivity *model.Activity) (*model.Activity, error)
+	DeleteActivity(id uint) error
+	MergeActivities(sourceID, targetID uint) (*model.Activity, error)
+
+	// NormalizeActivities resolves each activity name or alias to its canonical name,
+	// auto-creating unknown activities, and removes blanks and duplicates.
+	NormalizeActivities(names []string) ([]string, error)
+	// LookupActivity returns the catalog entry for an activity name or alias without
+	// creating it. Returns gorm.ErrRecordNotFound if there is no match.
+	LookupActivity(name string) (*model.Activity, error)
+	// ReindexActivities registers every activity found in vibe history in the catalog and
+	// rewrites non-canonical spellings ("Gym ", "workout") to their canonical names.
+	ReindexActivities() (int64, error)
+
+	// WithTx returns a service whose catalog reads and writes run in the transaction tx,
+	// so that an activity created by NormalizeActivities is rolled back with the write
+	// that needed it.
+	WithTx(tx *gorm.DB) ActivityServiceInterface
+	// WithContext returns a service whose queries and log lines carry ctx, e.g. the span
+	// of the vibe write that normalizes activities.
+	WithContext(ctx context.Context) ActivityServiceInterface
+}
+
+// ActivityService implements ActivityServiceInterface.
+type ActivityService struct {
+	ActivityRepo repository.ActivityRepositoryInterface
+	Cfg          *config.AppConfig
+}
+
+// NewActivityService creates a new ActivityService.
+func NewActivityService(activityRepo repository.ActivityRepositoryInterface, cfg *config.AppConfig) ActivityServiceInterface {
+	return &ActivityService{
+		ActivityRepo: activityRepo,
+		Cfg:          cfg,
+	}
+}
+
+// WithTx returns a copy of the service bound to tx.
+func (s *ActivityService) WithTx(tx *gorm.DB) ActivityServiceInterface {
+	copied := *s
+	copied.ActivityRepo = s.ActivityRepo.WithTx(tx)
+	return &copied
+}
+
+// WithContext returns a copy of the service, and of its repository, bound to ctx.
+func (s *ActivityService) WithContext(ctx context.Context) ActivityServiceInterface {
+	copied := *s
+	copied.ActivityRepo = s.ActivityRepo.WithContext(ctx)
+	return &copied
+}
+
+// normalizeActivityName lowercases, trims and collapses inner whitespace in an activity name.
+func normalizeActivityName(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
+
+// ValidateActivity performs business logic validation on an activity catalog entry.
+func (s *ActivityService) ValidateActivity(activity *model.Activity) error {
+	if normalizeActivityName(activity.Name) == "" {
+		return fmt.Errorf("activity name cannot be empty")
+	}
+	if activity.ParentID != nil && *activity.ParentID == activity.ID && activity.ID != 0 {
+		return fmt.Errorf("an activity cannot be its own parent")
+	}
+	return nil
+}
+
+// prepareActivity normalizes the activity's name and aliases in place.
+func prepareActivity(activity *model.Activity) {
+	activity.Name = normalizeActivityName(activity.Name)
+	seen := map[string]bool{activity.Name: true}
+	aliases := make([]string, 0, len(activity.Aliases))
+	for _, alias := range activity.Aliases {
+		alias = normalizeActivityName(alias)
+		if alias == "" || seen[alias] {
+			continue
+		}
+		seen[alias] = true
+		aliases = append(aliases, alias)
+	}
+	activity.Aliases = aliases
+}
+
+// checkNameConflicts ensures that none of the activity's name or aliases already resolve
+// to a different catalog entry.
+func (s *ActivityService) checkNameConflicts(activity *model.Activity) error {
+	for _, name := range append([]string{activity.Name}, activity.Aliases...) {
+		existing, err := s.ActivityRepo.FindActivityByNameOrAlias(name)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+			return err
+		}
+		if existing.ID != activity.ID {
+			return fmt.Errorf("'%s' is already used by activity '%s'", name, existing.Name)
+		}
+	}
+	return nil
+}
+
+// checkParent ensures the parent exists and that assigning it would not create a cycle.
+func (s *ActivityService) checkParent(activity *model.Activity) error {
+	if activity.ParentID == nil {
+		return nil
+	}
+	visited := map[uint]bool{}
+	parentID := activity.ParentID
+	for parentID != nil {
+		if activity.ID != 0 && *parentID == activity.ID {
+			return fmt.Errorf("parent would create a cycle in the activity hierarchy")
+		}
+		if visited[*parentID] {
+			break // Existing cycle further up; not introduced by this change
+		}
+		visited[*parentID] = true
+		parent, err := s.ActivityRepo.GetActivityByID(*parentID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("parent activity %d not found", *parentID)
+			}
+			return err
+		}
+		parentID = parent.ParentID
+	}
+	return nil
+}
+
+// CreateActivity adds a new activity to the catalog.
+func (s *ActivityService) CreateActivity(activity *model.Activity) (*model.Activity, error) {
+	activity.ID = 0
+	if err := s.ValidateActivity(activity); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+	prepareActivity(activity)
+	if err := s.checkNameConflicts(activity); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+	if err := s.checkParent(activity); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+	return s.ActivityRepo.CreateActivity(activity)
+}
+
+// GetActivityByID retrieves a single activity by its ID.
+func (s *ActivityService) GetActivityByID(id uint) (*model.Activity, error) {
+	return s.ActivityRepo.GetActivityByID(id)
+}
+
+// UpdateActivity modifies an existing activity. Renaming rewrites the old name in all vibes.
+func (s *ActivityService) UpdateActivity(id uint, updatedActivity *model.Activity) (*model.Activity, error) {
+	updatedActivity.ID = id
+	if err := s.ValidateActivity(updatedActivity); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+	prepareActivity(updatedActivity)
+
+	existing, err := s.ActivityRepo.GetActivityByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkNameConflicts(updatedActivity); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+	if err := s.checkParent(updatedActivity); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+
+	if existing.Name != updatedActivity.Name {
+		return s.ActivityRepo.RenameActivity(id, existing.Name, updatedActivity)
+	}
+	return s.ActivityRepo.UpdateActivity(id, updatedActivity)
+}
+
+// DeleteActivity removes an activity from the catalog. Vibe history is left untouched.
+func (s *ActivityService) DeleteActivity(id uint) error {
+	return s.ActivityRepo.DeleteActivity(id)
+}
+
+// MergeActivities merges the source activity into the target, rewriting history.
+// The source name and its aliases become aliases of the target.
+func (s *ActivityService) MergeActivities(sourceID, targetID uint) (*model.Activity, error) {
+	if sourceID == targetID {
+		return nil, fmt.Errorf("validation error: cannot merge an activity into itself")
+	}
+	source, err := s.ActivityRepo.GetActivityByID(sourceID)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.ActivityRepo.GetActivityByID(targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Re-parenting source's children onto target must not make target its own ancestor.
+	for parentID := target.ParentID; parentID != nil; {
+		if *parentID == source.ID {
+			// Target lives below source; lift it to source's position in the tree.
+			target.ParentID = source.ParentID
+			break
+		}
+		parent, err := s.ActivityRepo.GetActivityByID(*parentID)
+		if err != nil {
+			break
+		}
+		parentID = parent.ParentID
+	}
+
+	target.Aliases = append(target.Aliases, source.Name)
+	target.Aliases = append(target.Aliases, source.Aliases...)
+	prepareActivity(target)
+
+	return s.ActivityRepo.MergeActivities(source, target)
+}
+
+// NormalizeActivities resolves activity names against the catalog.
+func (s *ActivityService) NormalizeActivities(names []string) ([]string, error) {
+	if names == nil {
+		return nil, nil
+	}
+	seen := make(map[string]bool, len(names))
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		normalized := normalizeActivityName(name)
+		if normalized == "" {
+			continue
+		}
+		activity, err := s.resolveActivity(normalized)
+		if err != nil {
+			return nil, fmt.Errorf("could not resolve activity '%s': %w", name, err)
+		}
+		if seen[activity.Name] {
+			continue
+		}
+		seen[activity.Name] = true
+		result = append(result, activity.Name)
+	}
+	return result, nil
+}
+
+// LookupActivity returns the catalog entry for an activity name or alias.
+func (s *ActivityService) LookupActivity(name string) (*model.Activity, error) {
+	return s.ActivityRepo.FindActivityByNameOrAlias(normalizeActivityName(name))
+}
+
+// resolveActivity finds an activity by name or alias, creating it if it does not exist yet.
+func (s *ActivityService) resolveActivity(normalized string) (*model.Activity, error) {
+	activity, err := s.ActivityRepo.FindActivityByNameOrAlias(normalized)
+	if err == nil {
+		return activity, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	return s.ActivityRepo.CreateActivityIfAbsent(&model.Activity{Name: normalized, Aliases: []string{}})
+}
+
+// ReindexActivities backfills the catalog from vibe history and canonicalizes stored names.
+// Returns the number of vibes that were rewritten.
+func (s *ActivityService) ReindexActivities() (int64, error) {
+	names, err := s.ActivityRepo.GetDistinctVibeActivities()
+	if err != nil {
+		return 0, fmt.Errorf("could not list activities from vibes: %w", err)
+	}
+	var rewritten int64
+	for _, raw := range names {
+		normalized := normalizeActivityName(raw)
+		if normalized == "" {
+			continue
+		}
+		activity, err := s.resolveActivity(normalized)
+		if err != nil {
+			return rewritten, fmt.Errorf("could not resolve activity '%s': %w", raw, err)
+		}
+		if activity.Name == raw {
+			continue
+		}
+		n, err := s.ActivityRepo.ReplaceActivityInVibes(raw, activity.Name)
+		if err != nil {
+			return rewritten, fmt.Errorf("could not rewrite activity '%s': %w", raw, err)
+		}
+		rewritten += n
+	}
+	return rewritten, nil
+}
+
+// GetAllActivitiesWithUsage lists the catalog with usage counts, last-used date and
+// average energy/mood valence. Category totals include all descendant activities.
+func (s *ActivityService) GetAllActivitiesWithUsage() ([]model.ActivityWithUsage, error) {
+	activities, err := s.ActivityRepo.GetAllActivities()
+	if err != nil {
+		return nil, err
+	}
+	usageRows, err := s.ActivityRepo.GetActivityUsage()
+	if err != nil {
+		return nil, fmt.Errorf("could not compute activity usage: %w", err)
+	}
+	usageByName := make(map[string]model.ActivityUsage, len(usageRows))
+	for _, u := range usageRows {
+		usageByName[u.Name] = u
+	}
+
+	byID := make(map[uint]*model.Activity, len(activities))
+	for i := range activities {
+		byID[activities[i].ID] = &activities[i]
+	}
+
+	result := make([]model.ActivityWithUsage, 0, len(activities))
+	indexByID := make(map[uint]int, len(activities))
+	for _, a := range activities {
+		item := model.ActivityWithUsage{Activity: a, Path: activityPath(&a, byID)}
+		if u, ok := usageByName[a.Name]; ok {
+			item.UsageCount = u.UsageCount
+			item.LastUsed = u.LastUsed
+			item.AverageEnergy = u.AverageEnergy
+			item.AverageValence = u.AverageValence
+		}
+		indexByID[a.ID] = len(result)
+		result = append(result, item)
+	}
+
+	// Roll usage up the hierarchy so categories report their descendants' usage too.
+	for _, a := range activities {
+		count := result[indexByID[a.ID]].UsageCount
+		visited := map[uint]bool{}
+		for cur := byID[a.ID]; cur != nil && !visited[cur.ID]; {
+			visited[cur.ID] = true
+			result[indexByID[cur.ID]].TotalUsageCount += count
+			if cur.ParentID == nil {
+				break
+			}
+			cur = byID[*cur.ParentID]
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].TotalUsageCount != result[j].TotalUsageCount {
+			return result[i].TotalUsageCount > result[j].TotalUsageCount
+		}
+		return result[i].Path < result[j].Path
+	})
+	return result, nil
+}
+
+// activityPath builds the "parent > child" path for an activity.
+func activityPath(activity *model.Activity, byID map[uint]*model.Activity) string {
+	parts := []string{activity.Name}
+	visited := map[uint]bool{activity.ID: true}
+	for parentID := activity.ParentID; parentID != nil; {
+		parent, ok := byID[*parentID]
+		if !ok || visited[parent.ID] {
+			break
+		}
+		visited[parent.ID] = true
+		parts = append([]string{parent.Name}, parts...)
+		parentID = parent.ParentID
+	}
+	return strings.Join(parts, " > ")
+}
@@ -0,0 +1,330 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/analytics"
+	"github.com/aebalz/daily-vibe-tracker/internal/config"
+	"github.com/aebalz/daily-vibe-tracker/internal/logging"
+	"github.com/aebalz/daily-vibe-tracker/internal/model"
+	"github.com/aebalz/daily-vibe-tracker/internal/repository"
+)
+
+const (
+	// anomalyBaselineDays is how much history before a day is used to build its baseline.
+	anomalyBaselineDays = 90
+	// anomalyWarmupDays is the number of logged days needed before a baseline is trusted.
+	anomalyWarmupDays = 7
+	// anomalyMinWindowDays is the number of logged days needed in a window to judge a mood cluster.
+	anomalyMinWindowDays = 3
+	// anomalyMinClusterShare is the smallest share of negative days that can count as a cluster.
+	anomalyMinClusterShare = 0.5
+	// minEnergyStdDev keeps a perfectly steady energy history from flagging a one-point dip.
+	minEnergyStdDev = 1.0
+)
+
+// AnomalyHook is called for every newly recorded anomaly event, e.g. to feed notifications.
+// Hooks run synchronously after the event is stored and should return quickly.
+type AnomalyHook func(event model.AnomalyEvent)
+
+// NewLogAnomalyHook returns a hook writing anomaly events to logger.
+func NewLogAnomalyHook(logger *slog.Logger) AnomalyHook {
+	logger = logging.OrDefault(logger)
+	return func(event model.AnomalyEvent) {
+		logger.Info("Anomaly detected", slog.String("date", event.Date.Format(dayKeyLayout)), slog.String("severity", event.Severity), slog.String("message", event.Message))
+	}
+}
+
+// AnomalyServiceInterface defines the interface for anomaly detection.
+type AnomalyServiceInterface interface {
+	// DetectAnomalies evaluates every logged day from startDate to endDate against the
+	// history before it and records new anomaly events. It returns the events recorded by
+	// this run; anomalies already on record are not reported again.
+	DetectAnomalies(startDate, endDate time.Time) ([]model.AnomalyEvent, error)
+	// DetectAnomaliesForWrites re-evaluates the days affected by vibes written for dates.
+	DetectAnomaliesForWrites(dates ...time.Time) ([]model.AnomalyEvent, error)
+	// RecomputeAnomalies removes the recorded events of a date range and detects them again,
+	// so events of edited or deleted vibes go away. It returns the number of removed events
+	// and the recorded ones.
+	RecomputeAnomalies(startDate, endDate time.Time) (int64, []model.AnomalyEvent, error)
+	GetAnomalies(filters map[string]interface{}, limit, offset int) ([]model.AnomalyEvent, int64, error)
+
+	// AddHook registers a hook that is called for each newly recorded anomaly.
+	AddHook(hook AnomalyHook)
+	// RunScheduler re-evaluates recent days every ANOMALY_CHECK_INTERVAL until ctx is done.
+	RunScheduler(ctx context.Context)
+}
+
+// AnomalyService implements AnomalyServiceInterface.
+// The app has a single vibe history, so baselines are computed over all vibes.
+type AnomalyService struct {
+	AnomalyRepo repository.AnomalyRepositoryInterface
+	VibeRepo    repository.VibeRepositoryInterface
+	MoodSvc     MoodServiceInterface // Mood catalog used to tell negative moods apart
+	Cfg         *config.AppConfig
+	Logger      *slog.Logger
+
+	hooksMu sync.RWMutex
+	hooks   []AnomalyHook
+}
+
+// NewAnomalyService creates a new AnomalyService. A nil logger logs to the default logger.
+func NewAnomalyService(anomalyRepo repository.AnomalyRepositoryInterface, vibeRepo repository.VibeRepositoryInterface, moodSvc MoodServiceInterface, cfg *config.AppConfig, logger *slog.Logger) AnomalyServiceInterface {
+	return &AnomalyService{
+		AnomalyRepo: anomalyRepo,
+		VibeRepo:    vibeRepo,
+		MoodSvc:     moodSvc,
+		Cfg:         cfg,
+		Logger:      logging.OrDefault(logger),
+	}
+}
+
+// AddHook registers a hook that is called for each newly recorded anomaly.
+func (s *AnomalyService) AddHook(hook AnomalyHook) {
+	s.hooksMu.Lock()
+	defer s.hooksMu.Unlock()
+	s.hooks = append(s.hooks, hook)
+}
+
+func (s *AnomalyService) notify(event model.AnomalyEvent) {
+	s.hooksMu.RLock()
+	defer s.hooksMu.RUnlock()
+	for _, hook := range s.hooks {
+		hook(event)
+	}
+}
+
+// RunScheduler periodically re-evaluates the most recent window of days, picking up
+// anything missed on write (e.g. edits to older vibes). It blocks until ctx is done.
+func (s *AnomalyService) RunScheduler(ctx context.Context) {
+	if s.Cfg.AnomalyCheckInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(s.Cfg.AnomalyCheckInterval)
+	defer ticker.Stop()
+	for {
+		now := time.Now()
+		if _, err := s.DetectAnomalies(now.AddDate(0, 0, -s.Cfg.AnomalyWindowDays), now); err != nil {
+			s.Logger.WarnContext(ctx, "Scheduled anomaly detection failed", slog.String("error", err.Error()))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DetectAnomaliesForWrites re-evaluates the days affected by vibes written for dates: the
+// written day itself and the following window, whose mood clusters may include it.
+func (s *AnomalyService) DetectAnomaliesForWrites(dates ...time.Time) ([]model.AnomalyEvent, error) {
+	if len(dates) == 0 {
+		return nil, nil
+	}
+	start, end := dates[0], dates[0]
+	for _, d := range dates[1:] {
+		if d.Before(start) {
+			start = d
+		}
+		if d.After(end) {
+			end = d
+		}
+	}
+	return s.DetectAnomalies(start, end.AddDate(0, 0, s.Cfg.AnomalyWindowDays-1))
+}
+
+// RecomputeAnomalies clears the events from startDate to endDate and runs DetectAnomalies.
+func (s *AnomalyService) RecomputeAnomalies(startDate, endDate time.Time) (int64, []model.AnomalyEvent, error) {
+	startDate = time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, startDate.Location())
+	endDate = time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 23, 59, 59, 0, endDate.Location())
+	removed, err := s.AnomalyRepo.DeleteAnomalyEventsForDateRange(startDate, endDate)
+	if err != nil {
+		return 0, nil, fmt.Errorf("could not remove anomaly events: %w", err)
+	}
+	events, err := s.DetectAnomalies(startDate, endDate)
+	return removed, events, err
+}
+
+// DetectAnomalies evaluates each logged day in the range for two signals:
+//   - energy_drop: energy far below an EWMA baseline of earlier days (z-score <= -threshold).
+//   - negative_mood_cluster: the share of negative moods over the last ANOMALY_WINDOW_DAYS
+//     far above the share in the preceding baseline period. A cluster is reported once,
+//     not again for each day the window keeps overlapping it.
+func (s *AnomalyService) DetectAnomalies(startDate, endDate time.Time) ([]model.AnomalyEvent, error) {
+	startDate = time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, startDate.Location())
+	if now := time.Now(); endDate.After(now) {
+		endDate = now
+	}
+	endDate = time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 23, 59, 59, 0, endDate.Location())
+	if startDate.After(endDate) {
+		return nil, nil
+	}
+
+	vibes, err := s.VibeRepo.GetVibesForDateRange(startDate.AddDate(0, 0, -anomalyBaselineDays), endDate)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch vibes for anomaly detection: %w", err)
+	}
+	valenceByMood, err := moodValences(s.MoodSvc)
+	if err != nil {
+		return nil, err
+	}
+	days := buildDaySamples(vibes, valenceByMood)
+	keys := make([]string, 0, len(days))
+	for key := range days {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	window := s.Cfg.AnomalyWindowDays
+	existing, err := s.AnomalyRepo.GetAnomalyEventsForDateRange(startDate.AddDate(0, 0, -window), endDate)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch existing anomaly events: %w", err)
+	}
+	var clusterDays []string
+	for _, e := range existing {
+		if e.Kind == model.AnomalyNegativeMoodCluster {
+			clusterDays = append(clusterDays, e.Date.Format(dayKeyLayout))
+		}
+	}
+
+	rangeStart, rangeEnd := startDate.Format(dayKeyLayout), endDate.Format(dayKeyLayout)
+	threshold := s.Cfg.AnomalyZThreshold
+	energy := analytics.NewEWMA(s.Cfg.AnomalyEWMAAlpha)
+	var candidates []model.AnomalyEvent
+	for _, key := range keys {
+		day := days[key]
+		inRange := key >= rangeStart && key <= rangeEnd
+		date, _ := time.Parse(dayKeyLayout, key)
+
+		if inRange && energy.Count >= anomalyWarmupDays {
+			if z := energy.ZScore(day.energy, minEnergyStdDev); z <= -threshold {
+				candidates = append(candidates, model.AnomalyEvent{
+					Kind:     model.AnomalyEnergyDrop,
+					Date:     date,
+					Severity: anomalySeverity(z, threshold),
+					Value:    day.energy,
+					Baseline: roundTo(energy.Mean, 2),
+					ZScore:   roundTo(z, 2),
+					Message:  fmt.Sprintf("Energy dropped to %.0f, well below the usual %.1f.", day.energy, energy.Mean),
+				})
+			}
+		}
+		energy.Update(day.energy)
+
+		if !inRange {
+			continue
+		}
+		windowStart := date.AddDate(0, 0, -window+1).Format(dayKeyLayout)
+		if containsDayBetween(clusterDays, windowStart, key) {
+			continue // Still inside a cluster that was already reported
+		}
+		share, n := negativeShare(days, windowStart, key)
+		baselineShare, baselineN := negativeShare(days, date.AddDate(0, 0, -anomalyBaselineDays).Format(dayKeyLayout), date.AddDate(0, 0, -window).Format(dayKeyLayout))
+		if n < anomalyMinWindowDays || baselineN < anomalyWarmupDays || share < anomalyMinClusterShare {
+			continue
+		}
+		if z := analytics.ProportionZScore(share, baselineShare, n); z >= threshold {
+			candidates = append(candidates, model.AnomalyEvent{
+				Kind:     model.AnomalyNegativeMoodCluster,
+				Date:     date,
+				Severity: anomalySeverity(z, threshold),
+				Value:    roundTo(share, 3),
+				Baseline: roundTo(baselineShare, 3),
+				ZScore:   roundTo(z, 2),
+				Message: fmt.Sprintf("%d of the last %d logged days had a negative mood, compared to a usual %.0f%%.",
+					int(math.Round(share*float64(n))), n, baselineShare*100),
+			})
+			clusterDays = append(clusterDays, key)
+		}
+	}
+
+	var recorded []model.AnomalyEvent
+	for i := range candidates {
+		created, err := s.AnomalyRepo.CreateAnomalyEvent(&candidates[i])
+		if err != nil {
+			return recorded, fmt.Errorf("could not record anomaly event: %w", err)
+		}
+		if created {
+			recorded = append(recorded, candidates[i])
+			s.notify(candidates[i])
+		}
+	}
+	return recorded, nil
+}
+
+// negativeShare returns the share of logged days from fromKey to toKey (inclusive) whose
+// mood has a negative valence, and the number of days with a known valence.
+func negativeShare(days map[string]*daySample, fromKey, toKey string) (float64, int) {
+	negative, total := 0, 0
+	for key, day := range days {
+		if key < fromKey || key > toKey || day.valence == nil {
+			continue
+		}
+		total++
+		if *day.valence < 0 {
+			negative++
+		}
+	}
+	if total == 0 {
+		return 0, 0
+	}
+	return float64(negative) / float64(total), total
+}
+
+// containsDayBetween reports whether any of keys falls between fromKey and toKey (inclusive).
+func containsDayBetween(keys []string, fromKey, toKey string) bool {
+	for _, key := range keys {
+		if key >= fromKey && key <= toKey {
+			return true
+		}
+	}
+	return false
+}
+
+// anomalySeverity grades a z-score: low just past the threshold, medium one standard
+// deviation further out and high two or more beyond it.
+func anomalySeverity(z, threshold float64) string {
+	switch excess := math.Abs(z) - threshold; {
+	case excess >= 2:
+		return model.SeverityHigh
+	case excess >= 1:
+		return model.SeverityMedium
+	default:
+		return model.SeverityLow
+	}
+}
+
+// GetAnomalies retrieves recorded anomaly events with filtering and pagination.
+func (s *AnomalyService) GetAnomalies(filters map[string]interface{}, limit, offset int) ([]model.AnomalyEvent, int64, error) {
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+	if offset < 0 {
+		offset = DefaultOffset
+	}
+	if kind, ok := filters["kind"].(string); ok && kind != "" {
+		kind = strings.ToLower(kind)
+		if kind != model.AnomalyEnergyDrop && kind != model.AnomalyNegativeMoodCluster {
+			return nil, 0, fmt.Errorf("%w: invalid kind '%s'. Allowed values: %s, %s", ErrValidation, kind, model.AnomalyEnergyDrop, model.AnomalyNegativeMoodCluster)
+		}
+		filters["kind"] = kind
+	}
+	if severity, ok := filters["severity"].(string); ok && severity != "" {
+		severity = strings.ToLower(severity)
+		if severity != model.SeverityLow && severity != model.SeverityMedium && severity != model.SeverityHigh {
+			return nil, 0, fmt.Errorf("%w: invalid severity '%s'. Allowed values: low, medium, high", ErrValidation, severity)
+		}
+		filters["severity"] = severity
+	}
+	return s.AnomalyRepo.GetAnomalyEvents(filters, limit, offset)
+}
@@ -0,0 +1,501 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/config"
+	"github.com/aebalz/daily-vibe-tracker/internal/model"
+	"github.com/aebalz/daily-vibe-tracker/internal/repository"
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultGoalHistoryPeriods is the number of periods returned by the history when no limit is given.
+	DefaultGoalHistoryPeriods = 12
+	// MaxGoalHistoryPeriods bounds the number of periods returned by the history.
+	MaxGoalHistoryPeriods = 366
+)
+
+// validGoalOperators lists the comparison operators a goal target can use, matching metric filters.
+var validGoalOperators = map[string]bool{"eq": true, "gt": true, "gte": true, "lt": true, "lte": true}
+
+// GoalServiceInterface defines the interface for goal operations.
+type GoalServiceInterface interface {
+	// CreateGoal validates and stores a goal. A non-empty Expression overrides the target fields.
+	CreateGoal(goal *model.Goal) (*model.Goal, error)
+	GetGoalByID(id uint) (*model.Goal, error)
+	GetAllGoals(activeOnly bool) ([]model.Goal, error)
+	UpdateGoal(id uint, updatedGoal *model.Goal) (*model.Goal, error)
+	DeleteGoal(id uint) error
+
+	// GetGoalProgress evaluates the current period of a goal, with its streaks.
+	GetGoalProgress(id uint) (*model.GoalProgress, error)
+	// GetAllGoalProgress evaluates the current period of every active goal.
+	GetAllGoalProgress() ([]model.GoalProgress, error)
+	// GetGoalHistory lists the hits and misses of the most recent periods of a goal.
+	GetGoalHistory(id uint, limit int) (*model.GoalHistory, error)
+}
+
+// GoalService implements GoalServiceInterface.
+type GoalService struct {
+	GoalRepo    repository.GoalRepositoryInterface
+	VibeRepo    repository.VibeRepositoryInterface
+	MoodSvc     MoodServiceInterface     // Mood catalog used to resolve mood goal subjects
+	ActivitySvc ActivityServiceInterface // Activity catalog used to resolve activity goal subjects
+	Cfg         *config.AppConfig
+}
+
+// NewGoalService creates a new GoalService.
+func NewGoalService(goalRepo repository.GoalRepositoryInterface, vibeRepo repository.VibeRepositoryInterface, moodSvc MoodServiceInterface, activitySvc ActivityServiceInterface, cfg *config.AppConfig) GoalServiceInterface {
+	return &GoalService{
+		GoalRepo:    goalRepo,
+		VibeRepo:    vibeRepo,
+		MoodSvc:     moodSvc,
+		ActivitySvc: activitySvc,
+		Cfg:         cfg,
+	}
+}
+
+// parseGoalExpression parses a "metric[:subject]:op:target" expression into the goal's
+// target fields, e.g. "activity:exercise:gte:3" or "average_energy:gte:6".
+func parseGoalExpression(goal *model.Goal, expr string) error {
+	parts := strings.Split(strings.TrimSpace(expr), ":")
+	switch len(parts) {
+	case 3:
+		goal.Metric, goal.Subject, goal.Operator = parts[0], "", parts[1]
+	case 4:
+		goal.Metric, goal.Subject, goal.Operator = parts[0], parts[1], parts[2]
+	default:
+		return fmt.Errorf("%w: expression must have the form metric[:subject]:op:target", ErrValidation)
+	}
+	target, err := strconv.ParseFloat(parts[len(parts)-1], 64)
+	if err != nil {
+		return fmt.Errorf("%w: expression target must be a number", ErrValidation)
+	}
+	goal.Target = target
+	return nil
+}
+
+// periodDays returns the maximum number of days in a goal period.
+func periodDays(period string) int {
+	switch period {
+	case model.GoalPeriodDaily:
+		return 1
+	case model.GoalPeriodWeekly:
+		return 7
+	default:
+		return 31
+	}
+}
+
+// normalizeGoal parses the expression, normalizes the goal's fields and validates them.
+func (s *GoalService) normalizeGoal(goal *model.Goal) error {
+	if goal.Expression != "" {
+		if err := parseGoalExpression(goal, goal.Expression); err != nil {
+			return err
+		}
+	}
+	goal.Name = strings.TrimSpace(goal.Name)
+	goal.Metric = strings.ToLower(strings.TrimSpace(goal.Metric))
+	goal.Operator = strings.ToLower(strings.TrimSpace(goal.Operator))
+	goal.Period = strings.ToLower(strings.TrimSpace(goal.Period))
+
+	switch goal.Period {
+	case model.GoalPeriodDaily, model.GoalPeriodWeekly, model.GoalPeriodMonthly:
+	default:
+		return fmt.Errorf("%w: invalid period '%s'. Allowed values: daily, weekly, monthly", ErrValidation, goal.Period)
+	}
+	if !validGoalOperators[goal.Operator] {
+		return fmt.Errorf("%w: invalid operator '%s'. Allowed values: eq, gt, gte, lt, lte", ErrValidation, goal.Operator)
+	}
+
+	switch goal.Metric {
+	case model.GoalMetricActivity:
+		name, err := s.canonicalActivity(goal.Subject)
+		if err != nil {
+			return err
+		}
+		goal.Subject = name
+	case model.GoalMetricMood:
+		if strings.TrimSpace(goal.Subject) == "" {
+			return fmt.Errorf("%w: mood goals require a subject", ErrValidation)
+		}
+		mood, err := s.MoodSvc.LookupMood(goal.Subject)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: '%s'", ErrUnknownMood, goal.Subject)
+			}
+			return err
+		}
+		goal.Subject = mood.Name
+	case model.GoalMetricAverageEnergy, model.GoalMetricLoggedDays:
+		goal.Subject = ""
+	default:
+		return fmt.Errorf("%w: invalid metric '%s'. Allowed values: activity, mood, average_energy, logged_days", ErrValidation, goal.Metric)
+	}
+
+	if goal.Metric == model.GoalMetricAverageEnergy {
+		if goal.Target < 1 || goal.Target > 10 {
+			return fmt.Errorf("%w: average energy target must be between 1 and 10", ErrValidation)
+		}
+	} else {
+		if goal.Target < 0 || goal.Target != math.Trunc(goal.Target) {
+			return fmt.Errorf("%w: day count target must be a whole number of at least 0", ErrValidation)
+		}
+		if int(goal.Target) > periodDays(goal.Period) {
+			return fmt.Errorf("%w: a %s period has at most %d days", ErrValidation, goal.Period, periodDays(goal.Period))
+		}
+	}
+
+	if goal.StartDate.IsZero() {
+		goal.StartDate = time.Now()
+	}
+	goal.StartDate = time.Date(goal.StartDate.Year(), goal.StartDate.Month(), goal.StartDate.Day(), 0, 0, 0, 0, time.Local)
+	goal.PopulateExpression()
+	if goal.Name == "" {
+		goal.Name = goal.Expression
+	}
+	return nil
+}
+
+// canonicalActivity resolves an activity name or alias to its catalog name. Activities that
+// have not been logged yet are kept as given, so a goal can introduce a new habit.
+func (s *GoalService) canonicalActivity(name string) (string, error) {
+	normalized := normalizeActivityName(name)
+	if normalized == "" {
+		return "", fmt.Errorf("%w: activity goals require a subject", ErrValidation)
+	}
+	activity, err := s.ActivitySvc.LookupActivity(normalized)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return normalized, nil
+		}
+		return "", err
+	}
+	return activity.Name, nil
+}
+
+// CreateGoal adds a new goal.
+func (s *GoalService) CreateGoal(goal *model.Goal) (*model.Goal, error) {
+	goal.ID = 0
+	if err := s.normalizeGoal(goal); err != nil {
+		return nil, err
+	}
+	return s.GoalRepo.CreateGoal(goal)
+}
+
+// GetGoalByID retrieves a goal by its ID.
+func (s *GoalService) GetGoalByID(id uint) (*model.Goal, error) {
+	goal, err := s.GoalRepo.GetGoalByID(id)
+	if err != nil {
+		return nil, err
+	}
+	goal.PopulateExpression()
+	return goal, nil
+}
+
+// GetAllGoals retrieves all goals, optionally only the active ones.
+func (s *GoalService) GetAllGoals(activeOnly bool) ([]model.Goal, error) {
+	goals, err := s.GoalRepo.GetAllGoals(activeOnly)
+	if err != nil {
+		return nil, err
+	}
+	for i := range goals {
+		goals[i].PopulateExpression()
+	}
+	return goals, nil
+}
+
+// UpdateGoal modifies an existing goal.
+func (s *GoalService) UpdateGoal(id uint, updatedGoal *model.Goal) (*model.Goal, error) {
+	if err := s.normalizeGoal(updatedGoal); err != nil {
+		return nil, err
+	}
+	return s.GoalRepo.UpdateGoal(id, updatedGoal)
+}
+
+// DeleteGoal removes a goal.
+func (s *GoalService) DeleteGoal(id uint) error {
+	return s.GoalRepo.DeleteGoal(id)
+}
+
+// goalPeriodStart returns the start of the goal period containing t. Weeks start on Monday.
+func goalPeriodStart(period string, t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch period {
+	case model.GoalPeriodWeekly:
+		offset := (int(day.Weekday()) + 6) % 7 // Days since Monday
+		return day.AddDate(0, 0, -offset)
+	case model.GoalPeriodMonthly:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+	default:
+		return day
+	}
+}
+
+// nextGoalPeriodStart returns the start of the period following the one starting at start.
+func nextGoalPeriodStart(period string, start time.Time) time.Time {
+	switch period {
+	case model.GoalPeriodWeekly:
+		return start.AddDate(0, 0, 7)
+	case model.GoalPeriodMonthly:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// compareGoalValue applies a goal operator.
+func compareGoalValue(value float64, operator string, target float64) bool {
+	switch operator {
+	case "eq":
+		return value == target
+	case "gt":
+		return value > target
+	case "gte":
+		return value >= target
+	case "lt":
+		return value < target
+	default: // lte
+		return value <= target
+	}
+}
+
+// goalPeriodStatus decides the status of a period. Completed periods are hits or misses.
+// Day counts only grow within a period, so the current period of a count goal is already
+// decided once a minimum is reached or a maximum is exceeded.
+func goalPeriodStatus(goal *model.Goal, value *float64, complete bool) string {
+	met := value != nil && compareGoalValue(*value, goal.Operator, goal.Target)
+	if complete {
+		if met {
+			return model.GoalStatusHit
+		}
+		return model.GoalStatusMiss
+	}
+	if goal.Metric == model.GoalMetricAverageEnergy || value == nil {
+		return model.GoalStatusInProgress
+	}
+	switch goal.Operator {
+	case "gt", "gte":
+		if met {
+			return model.GoalStatusHit
+		}
+	case "lt":
+		if *value >= goal.Target {
+			return model.GoalStatusMiss
+		}
+	case "lte", "eq":
+		if *value > goal.Target {
+			return model.GoalStatusMiss
+		}
+	}
+	return model.GoalStatusInProgress
+}
+
+// evaluateGoal evaluates every period of a goal from its start date up to now, oldest first.
+func (s *GoalService) evaluateGoal(goal *model.Goal, now time.Time) ([]model.GoalPeriodResult, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	first := goalPeriodStart(goal.Period, goal.StartDate.In(now.Location()))
+	if first.After(today) {
+		return []model.GoalPeriodResult{}, nil
+	}
+	vibes, err := s.VibeRepo.GetVibesForDateRange(first, today.Add(24*time.Hour-time.Second))
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch vibes for goal evaluation: %w", err)
+	}
+	byDay := make(map[string]*model.Vibe, len(vibes))
+	for i := range vibes {
+		byDay[vibes[i].Date.Format(dayKeyLayout)] = &vibes[i]
+	}
+
+	var results []model.GoalPeriodResult
+	for start := first; !start.After(today); start = nextGoalPeriodStart(goal.Period, start) {
+		next := nextGoalPeriodStart(goal.Period, start)
+		var count, energy float64
+		logged := 0
+		for day := start; day.Before(next) && !day.After(today); day = day.AddDate(0, 0, 1) {
+			vibe, ok := byDay[day.Format(dayKeyLayout)]
+			if !ok {
+				continue
+			}
+			logged++
+			energy += float64(vibe.EnergyLevel)
+			switch goal.Metric {
+			case model.GoalMetricActivity:
+				for _, a := range vibe.Activities {
+					if a == goal.Subject {
+						count++
+						break
+					}
+				}
+			case model.GoalMetricMood:
+				if vibe.Mood == goal.Subject {
+					count++
+				}
+			}
+		}
+
+		var value *float64
+		switch goal.Metric {
+		case model.GoalMetricAverageEnergy:
+			if logged > 0 {
+				avg := roundTo(energy/float64(logged), 2)
+				value = &avg
+			}
+		case model.GoalMetricLoggedDays:
+			days := float64(logged)
+			value = &days
+		default:
+			value = &count
+		}
+		results = append(results, model.GoalPeriodResult{
+			PeriodStart: start,
+			PeriodEnd:   next.Add(-time.Second),
+			Value:       value,
+			Status:      goalPeriodStatus(goal, value, !next.After(today)),
+			LoggedDays:  logged,
+		})
+	}
+	return results, nil
+}
+
+// goalStreaks returns the current and longest run of hit periods. An undecided current
+// period neither extends nor breaks the current streak.
+func goalStreaks(periods []model.GoalPeriodResult) (current, longest int) {
+	run := 0
+	for _, p := range periods {
+		if p.Status == model.GoalStatusHit {
+			run++
+			if run > longest {
+				longest = run
+			}
+		} else if p.Status == model.GoalStatusMiss {
+			run = 0
+		}
+	}
+	return run, longest
+}
+
+// buildGoalProgress summarizes the evaluated periods of a goal.
+func buildGoalProgress(goal *model.Goal, periods []model.GoalPeriodResult, now time.Time) model.GoalProgress {
+	progress := model.GoalProgress{Goal: *goal}
+	progress.CurrentStreak, progress.LongestStreak = goalStreaks(periods)
+	if len(periods) == 0 {
+		progress.Current = model.GoalPeriodResult{Status: model.GoalStatusInProgress}
+		return progress
+	}
+
+	var hits, decided int
+	for _, p := range periods[:len(periods)-1] {
+		decided++
+		if p.Status == model.GoalStatusHit {
+			hits++
+		}
+	}
+	if decided > 0 {
+		rate := roundTo(float64(hits)/float64(decided), 3)
+		progress.HitRate = &rate
+	}
+
+	current := periods[len(periods)-1]
+	progress.Current = current
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	progress.DaysLeft = int(current.PeriodEnd.Sub(today).Hours()/24) + 1
+
+	value := 0.0
+	if current.Value != nil {
+		value = *current.Value
+	}
+	switch goal.Operator {
+	case "gt", "gte", "eq":
+		if goal.Target > 0 {
+			progress.Percent = roundTo(math.Min(value/goal.Target, 1)*100, 1)
+		} else {
+			progress.Percent = 100
+		}
+		if goal.Metric != model.GoalMetricAverageEnergy && goal.Operator != "eq" {
+			needed := goal.Target
+			if goal.Operator == "gt" {
+				needed = math.Floor(goal.Target) + 1
+			}
+			remaining := math.Max(needed-value, 0)
+			progress.Remaining = &remaining
+		}
+	default:
+		if current.Value != nil && compareGoalValue(value, goal.Operator, goal.Target) {
+			progress.Percent = 100
+		}
+	}
+	return progress
+}
+
+// GetGoalProgress evaluates the current period of a goal.
+func (s *GoalService) GetGoalProgress(id uint) (*model.GoalProgress, error) {
+	goal, err := s.GetGoalByID(id)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	periods, err := s.evaluateGoal(goal, now)
+	if err != nil {
+		return nil, err
+	}
+	progress := buildGoalProgress(goal, periods, now)
+	return &progress, nil
+}
+
+// GetAllGoalProgress evaluates the current period of every active goal.
+func (s *GoalService) GetAllGoalProgress() ([]model.GoalProgress, error) {
+	goals, err := s.GetAllGoals(true)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	progress := make([]model.GoalProgress, 0, len(goals))
+	for i := range goals {
+		periods, err := s.evaluateGoal(&goals[i], now)
+		if err != nil {
+			return nil, err
+		}
+		progress = append(progress, buildGoalProgress(&goals[i], periods, now))
+	}
+	return progress, nil
+}
+
+// GetGoalHistory lists the most recent periods of a goal, newest first.
+func (s *GoalService) GetGoalHistory(id uint, limit int) (*model.GoalHistory, error) {
+	if limit == 0 {
+		limit = DefaultGoalHistoryPeriods
+	}
+	if limit < 1 || limit > MaxGoalHistoryPeriods {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrValidation, MaxGoalHistoryPeriods)
+	}
+	goal, err := s.GetGoalByID(id)
+	if err != nil {
+		return nil, err
+	}
+	periods, err := s.evaluateGoal(goal, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	history := &model.GoalHistory{Goal: *goal, Periods: []model.GoalPeriodResult{}}
+	history.CurrentStreak, history.LongestStreak = goalStreaks(periods)
+	for _, p := range periods {
+		switch p.Status {
+		case model.GoalStatusHit:
+			history.Hits++
+		case model.GoalStatusMiss:
+			history.Misses++
+		}
+	}
+	for i := len(periods) - 1; i >= 0 && len(history.Periods) < limit; i-- {
+		history.Periods = append(history.Periods, periods[i])
+	}
+	return history, nil
+}
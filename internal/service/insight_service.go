@@ -0,0 +1,618 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/analytics"
+	"github.com/aebalz/daily-vibe-tracker/internal/config"
+	"github.com/aebalz/daily-vibe-tracker/internal/model"
+	"github.com/aebalz/daily-vibe-tracker/internal/repository"
+)
+
+const (
+	// MaxCorrelationLag is the largest lag, in days, accepted for lagged correlations.
+	MaxCorrelationLag = 7
+	// DefaultCorrelationLag analyzes same-day and next-day effects.
+	DefaultCorrelationLag = 1
+
+	// DefaultForecastLookbackDays is how much history the mood forecast learns from.
+	DefaultForecastLookbackDays = 365
+	// MaxForecastLookbackDays bounds the history loaded for a forecast.
+	MaxForecastLookbackDays = 3 * 365
+
+	// forecastMinSupport is the number of observed transitions out of a state required
+	// before the forecast conditions on it.
+	forecastMinSupport = 3
+	// forecastSmoothing is the additive pseudo-count applied to every mood, so moods
+	// never seen after a state keep a small probability.
+	forecastSmoothing = 0.5
+
+	// goodEnergyLevel is the energy level from which a day counts as a good-energy day for lift.
+	goodEnergyLevel = 7
+	dayKeyLayout    = "2006-01-02"
+)
+
+// CorrelationOptions controls a correlation analysis. Zero values fall back to defaults:
+// the Period range (current month) when no dates are given, and the configured thresholds.
+type CorrelationOptions struct {
+	Period        string    // week, month or year; used when StartDate and EndDate are zero
+	StartDate     time.Time // Inclusive
+	EndDate       time.Time // Inclusive
+	MaxLag        int       // Correlate factors with outcomes 0..MaxLag days later
+	MinSamples    int       // Minimum paired days for a correlation to be reported
+	MinConfidence float64   // Minimum confidence (1 - p-value) for a correlation to be reported
+}
+
+// InsightServiceInterface defines the interface for statistical insights over vibe history.
+type InsightServiceInterface interface {
+	// GetCorrelations correlates activities and custom metrics with energy, mood valence and
+	// other metrics, including lagged effects, and returns the statistically significant ones.
+	GetCorrelations(opts CorrelationOptions) (*model.CorrelationReport, error)
+	// GetMoodForecast predicts the mood for the day after the most recent vibe from the
+	// last lookbackDays of history, combining mood transitions with day-of-week effects.
+	GetMoodForecast(lookbackDays int) (*model.MoodForecast, error)
+}
+
+// InsightService implements InsightServiceInterface.
+type InsightService struct {
+	VibeRepo repository.VibeRepositoryInterface
+	MoodSvc  MoodServiceInterface // Mood catalog used to turn moods into valence scores
+	Cfg      *config.AppConfig
+}
+
+// NewInsightService creates a new InsightService.
+func NewInsightService(vibeRepo repository.VibeRepositoryInterface, moodSvc MoodServiceInterface, cfg *config.AppConfig) InsightServiceInterface {
+	return &InsightService{
+		VibeRepo: vibeRepo,
+		MoodSvc:  moodSvc,
+		Cfg:      cfg,
+	}
+}
+
+// daySample holds the observations for a single calendar day.
+type daySample struct {
+	energy     float64
+	valence    *float64
+	activities map[string]bool
+	metrics    map[string]float64
+}
+
+// outcome returns the value of an outcome on the day, if it was recorded.
+func (d *daySample) outcome(name string) (float64, bool) {
+	switch name {
+	case model.OutcomeEnergy:
+		return d.energy, true
+	case model.OutcomeValence:
+		if d.valence == nil {
+			return 0, false
+		}
+		return *d.valence, true
+	default:
+		v, ok := d.metrics[name]
+		return v, ok
+	}
+}
+
+// factor returns the value of a factor on the day, if it was recorded.
+// Activities are always recorded: 1 if logged that day and 0 otherwise.
+func (d *daySample) factor(factorType, name string) (float64, bool) {
+	if factorType == model.FactorTypeActivity {
+		if d.activities[name] {
+			return 1, true
+		}
+		return 0, true
+	}
+	v, ok := d.metrics[name]
+	return v, ok
+}
+
+// resolveCorrelationOptions validates opts and fills in defaults.
+func (s *InsightService) resolveCorrelationOptions(opts CorrelationOptions) (CorrelationOptions, error) {
+	switch {
+	case opts.StartDate.IsZero() && opts.EndDate.IsZero():
+		opts.StartDate, opts.EndDate = periodDateRange(opts.Period, time.Now())
+	case opts.StartDate.IsZero() || opts.EndDate.IsZero():
+		return opts, fmt.Errorf("%w: start_date and end_date must be provided together", ErrValidation)
+	case opts.StartDate.After(opts.EndDate):
+		return opts, fmt.Errorf("%w: start_date must not be after end_date", ErrValidation)
+	}
+	if opts.MaxLag < 0 || opts.MaxLag > MaxCorrelationLag {
+		return opts, fmt.Errorf("%w: max_lag must be between 0 and %d", ErrValidation, MaxCorrelationLag)
+	}
+	if opts.MinSamples <= 0 {
+		opts.MinSamples = s.Cfg.InsightsMinSamples
+	}
+	if opts.MinSamples < 3 {
+		return opts, fmt.Errorf("%w: min_samples must be at least 3", ErrValidation)
+	}
+	if opts.MinConfidence == 0 {
+		opts.MinConfidence = s.Cfg.InsightsMinConfidence
+	}
+	if opts.MinConfidence < 0 || opts.MinConfidence >= 1 {
+		return opts, fmt.Errorf("%w: min_confidence must be between 0 and 1", ErrValidation)
+	}
+	return opts, nil
+}
+
+// GetCorrelations runs the correlation analysis described by opts.
+func (s *InsightService) GetCorrelations(opts CorrelationOptions) (*model.CorrelationReport, error) {
+	opts, err := s.resolveCorrelationOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch enough history before the range to pair lagged outcomes at the start of it.
+	vibes, err := s.VibeRepo.GetVibesForDateRange(opts.StartDate.AddDate(0, 0, -opts.MaxLag), opts.EndDate)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch vibes for correlation analysis: %w", err)
+	}
+	valenceByMood, err := moodValences(s.MoodSvc)
+	if err != nil {
+		return nil, err
+	}
+	days := buildDaySamples(vibes, valenceByMood)
+
+	report := &model.CorrelationReport{
+		StartDate:     opts.StartDate,
+		EndDate:       opts.EndDate,
+		MinSamples:    opts.MinSamples,
+		MinConfidence: opts.MinConfidence,
+		Correlations:  []model.CorrelationInsight{},
+	}
+	for lag := 0; lag <= opts.MaxLag; lag++ {
+		report.Lags = append(report.Lags, lag)
+	}
+	rangeStart := opts.StartDate.Format(dayKeyLayout)
+	for key := range days {
+		if key >= rangeStart {
+			report.Days++
+		}
+	}
+
+	activities, metrics := collectFactorNames(days)
+	outcomes := append([]string{model.OutcomeEnergy, model.OutcomeValence}, metrics...)
+	type factorRef struct{ kind, name string }
+	factors := make([]factorRef, 0, len(activities)+len(metrics))
+	for _, a := range activities {
+		factors = append(factors, factorRef{model.FactorTypeActivity, a})
+	}
+	for _, m := range metrics {
+		factors = append(factors, factorRef{model.FactorTypeMetric, m})
+	}
+
+	for _, lag := range report.Lags {
+		for _, f := range factors {
+			for _, outcome := range outcomes {
+				if lag == 0 && f.kind == model.FactorTypeMetric && f.name == outcome {
+					continue // A metric trivially correlates with itself on the same day
+				}
+				xs, ys := pairSamples(days, rangeStart, f.kind, f.name, outcome, lag)
+				if len(xs) < opts.MinSamples {
+					continue
+				}
+				insight, ok := correlate(f.kind, f.name, outcome, lag, xs, ys)
+				if !ok {
+					continue
+				}
+				report.Tested++
+				if insight.Confidence >= opts.MinConfidence {
+					report.Correlations = append(report.Correlations, insight)
+				}
+			}
+		}
+	}
+
+	sort.Slice(report.Correlations, func(i, j int) bool {
+		a, b := report.Correlations[i], report.Correlations[j]
+		if math.Abs(a.Pearson) != math.Abs(b.Pearson) {
+			return math.Abs(a.Pearson) > math.Abs(b.Pearson)
+		}
+		if a.Samples != b.Samples {
+			return a.Samples > b.Samples
+		}
+		if a.Factor != b.Factor {
+			return a.Factor < b.Factor
+		}
+		if a.Outcome != b.Outcome {
+			return a.Outcome < b.Outcome
+		}
+		return a.Lag < b.Lag
+	})
+	return report, nil
+}
+
+// moodValences returns the valence of every mood in the catalog, keyed by mood name.
+func moodValences(moodSvc MoodServiceInterface) (map[string]float64, error) {
+	moods, err := moodSvc.GetAllMoods()
+	if err != nil {
+		return nil, fmt.Errorf("could not load mood catalog: %w", err)
+	}
+	valenceByMood := make(map[string]float64, len(moods))
+	for _, m := range moods {
+		valenceByMood[m.Name] = m.Valence
+	}
+	return valenceByMood, nil
+}
+
+// buildDaySamples groups vibes by calendar day. If a day has several vibes, energy is
+// averaged, activities are combined and the latest metric values win.
+func buildDaySamples(vibes []model.Vibe, valenceByMood map[string]float64) map[string]*daySample {
+	days := make(map[string]*daySample)
+	counts := make(map[string]int)
+	for _, v := range vibes {
+		key := v.Date.Format(dayKeyLayout)
+		day, ok := days[key]
+		if !ok {
+			day = &daySample{activities: make(map[string]bool), metrics: make(map[string]float64)}
+			days[key] = day
+		}
+		counts[key]++
+		day.energy += (float64(v.EnergyLevel) - day.energy) / float64(counts[key])
+		if valence, ok := valenceByMood[v.Mood]; ok {
+			day.valence = &valence
+		}
+		for _, a := range v.Activities {
+			if a != "" {
+				day.activities[a] = true
+			}
+		}
+		for _, mv := range v.MetricValues {
+			if mv.Definition.Name != "" {
+				day.metrics[mv.Definition.Name] = mv.Value
+			}
+		}
+	}
+	return days
+}
+
+// collectFactorNames returns the sorted activity and metric names seen across all days.
+func collectFactorNames(days map[string]*daySample) (activities, metrics []string) {
+	seenActivities := make(map[string]bool)
+	seenMetrics := make(map[string]bool)
+	for _, d := range days {
+		for a := range d.activities {
+			seenActivities[a] = true
+		}
+		for m := range d.metrics {
+			seenMetrics[m] = true
+		}
+	}
+	for a := range seenActivities {
+		activities = append(activities, a)
+	}
+	for m := range seenMetrics {
+		metrics = append(metrics, m)
+	}
+	sort.Strings(activities)
+	sort.Strings(metrics)
+	return activities, metrics
+}
+
+// pairSamples pairs the factor on each day with the outcome lag days later. Only pairs whose
+// outcome day falls on or after rangeStart are used, and both days must have been logged,
+// so gaps in the history never pair non-consecutive days.
+func pairSamples(days map[string]*daySample, rangeStart, factorType, factorName, outcome string, lag int) (xs, ys []float64) {
+	for key, outcomeDay := range days {
+		if key < rangeStart {
+			continue
+		}
+		y, ok := outcomeDay.outcome(outcome)
+		if !ok {
+			continue
+		}
+		factorDay := outcomeDay
+		if lag > 0 {
+			date, _ := time.Parse(dayKeyLayout, key)
+			if factorDay, ok = days[date.AddDate(0, 0, -lag).Format(dayKeyLayout)]; !ok {
+				continue
+			}
+		}
+		x, ok := factorDay.factor(factorType, factorName)
+		if !ok {
+			continue
+		}
+		xs = append(xs, x)
+		ys = append(ys, y)
+	}
+	return xs, ys
+}
+
+// correlate computes the statistics for one factor/outcome pair. It returns false if the
+// correlation is undefined, e.g. because the activity was logged on every day.
+func correlate(factorType, factorName, outcome string, lag int, xs, ys []float64) (model.CorrelationInsight, bool) {
+	r := analytics.Pearson(xs, ys)
+	if math.IsNaN(r) {
+		return model.CorrelationInsight{}, false
+	}
+	p := analytics.CorrelationPValue(r, len(xs))
+	if math.IsNaN(p) {
+		return model.CorrelationInsight{}, false
+	}
+
+	insight := model.CorrelationInsight{
+		Factor:     factorName,
+		FactorType: factorType,
+		Outcome:    outcome,
+		Lag:        lag,
+		Samples:    len(xs),
+		Pearson:    roundTo(r, 3),
+		PValue:     roundTo(p, 4),
+		Confidence: roundTo(1-p, 4),
+		Direction:  "positive",
+	}
+	if r < 0 {
+		insight.Direction = "negative"
+	}
+	if rho := analytics.Spearman(xs, ys); !math.IsNaN(rho) {
+		rho = roundTo(rho, 3)
+		insight.Spearman = &rho
+	}
+
+	if factorType == model.FactorTypeActivity {
+		threshold := goodOutcomeThreshold(outcome, ys)
+		present := make([]bool, len(xs))
+		good := make([]bool, len(ys))
+		occurrences := 0
+		for i := range xs {
+			present[i] = xs[i] == 1
+			good[i] = ys[i] > threshold
+			if present[i] {
+				occurrences++
+			}
+		}
+		insight.Occurrences = &occurrences
+		if lift := analytics.Lift(present, good); !math.IsNaN(lift) {
+			lift = roundTo(lift, 2)
+			insight.Lift = &lift
+		}
+	}
+	insight.Summary = correlationSummary(insight)
+	return insight, true
+}
+
+// goodOutcomeThreshold returns the value an outcome must exceed to count as a good day for lift:
+// energy of at least goodEnergyLevel, a positive mood, or a metric above its median.
+func goodOutcomeThreshold(outcome string, values []float64) float64 {
+	switch outcome {
+	case model.OutcomeEnergy:
+		return goodEnergyLevel - 1
+	case model.OutcomeValence:
+		return 0
+	default:
+		return analytics.Median(values)
+	}
+}
+
+// correlationSummary renders a one-line, human readable description of an insight.
+func correlationSummary(insight model.CorrelationInsight) string {
+	direction := "higher"
+	if insight.Direction == "negative" {
+		direction = "lower"
+	}
+	var when string
+	switch insight.Lag {
+	case 0:
+		when = "the same day"
+	case 1:
+		when = "the next day"
+	default:
+		when = fmt.Sprintf("%d days later", insight.Lag)
+	}
+
+	var summary string
+	if insight.FactorType == model.FactorTypeActivity {
+		summary = fmt.Sprintf("After logging %s, %s tends to be %s %s", insight.Factor, insight.Outcome, direction, when)
+		if insight.Lag == 0 {
+			summary = fmt.Sprintf("On days with %s, %s tends to be %s", insight.Factor, insight.Outcome, direction)
+		}
+		if insight.Lift != nil {
+			summary += fmt.Sprintf(" (lift %.2fx)", *insight.Lift)
+		}
+	} else {
+		summary = fmt.Sprintf("Higher %s goes with %s %s %s", insight.Factor, direction, insight.Outcome, when)
+	}
+	return fmt.Sprintf("%s, r=%.2f over %d days.", summary, insight.Pearson, insight.Samples)
+}
+
+// roundTo rounds x to the given number of decimal places.
+func roundTo(x float64, places int) float64 {
+	scale := math.Pow(10, float64(places))
+	return math.Round(x*scale) / scale
+}
+
+// dailyMoods maps each logged calendar day to its mood. Vibes must be sorted by date;
+// if a day has several vibes, the latest one wins.
+func dailyMoods(vibes []model.Vibe) map[string]string {
+	moods := make(map[string]string, len(vibes))
+	for _, v := range vibes {
+		moods[v.Date.Format(dayKeyLayout)] = v.Mood
+	}
+	return moods
+}
+
+// previousDayKey returns the key of the calendar day before key.
+func previousDayKey(key string) string {
+	date, _ := time.Parse(dayKeyLayout, key)
+	return date.AddDate(0, 0, -1).Format(dayKeyLayout)
+}
+
+// moodTransitionMatrix counts mood transitions between strictly consecutive days and
+// normalizes them into probabilities.
+func moodTransitionMatrix(vibes []model.Vibe) model.MoodTransitionMatrix {
+	moods := dailyMoods(vibes)
+	counts := analytics.TransitionCounts{}
+	transitions := 0
+	for key, mood := range moods {
+		if prev, ok := moods[previousDayKey(key)]; ok {
+			counts.Add(prev, mood)
+			transitions++
+		}
+	}
+
+	matrix := model.MoodTransitionMatrix{
+		Transitions:   transitions,
+		States:        counts.States(),
+		Counts:        counts,
+		Probabilities: counts.Probabilities(),
+	}
+	for _, row := range matrix.Probabilities {
+		for to, p := range row {
+			row[to] = roundTo(p, 3)
+		}
+	}
+	return matrix
+}
+
+// GetMoodForecast predicts the next day's mood.
+// The transition model is second-order (the last two days) when that pair of moods has
+// been seen often enough, first-order (the last day) otherwise, and falls back to overall
+// mood frequencies when there is no vibe for today or yesterday. The result is then
+// weighted by how much more or less common each mood is on the target weekday.
+func (s *InsightService) GetMoodForecast(lookbackDays int) (*model.MoodForecast, error) {
+	if lookbackDays == 0 {
+		lookbackDays = DefaultForecastLookbackDays
+	}
+	if lookbackDays < 7 || lookbackDays > MaxForecastLookbackDays {
+		return nil, fmt.Errorf("%w: lookback_days must be between 7 and %d", ErrValidation, MaxForecastLookbackDays)
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	endOfToday := today.Add(23*time.Hour + 59*time.Minute + 59*time.Second)
+	vibes, err := s.VibeRepo.GetVibesForDateRange(today.AddDate(0, 0, -lookbackDays), endOfToday)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch vibes for mood forecast: %w", err)
+	}
+
+	forecast := &model.MoodForecast{
+		TargetDate:    today.AddDate(0, 0, 1),
+		RecentMoods:   []string{},
+		Method:        model.ForecastMethodBaseline,
+		Probabilities: []model.MoodProbability{},
+	}
+	if len(vibes) == 0 {
+		forecast.Weekday = forecast.TargetDate.Weekday().String()
+		forecast.Explanation = "Not enough data to forecast a mood yet."
+		return forecast, nil
+	}
+
+	moods := dailyMoods(vibes)
+	baseCounts := make(map[string]int)
+	weekdayCounts := make(map[time.Weekday]map[string]int)
+	first := analytics.TransitionCounts{}
+	second := analytics.TransitionCounts{}
+	for key, mood := range moods {
+		date, _ := time.Parse(dayKeyLayout, key)
+		baseCounts[mood]++
+		if weekdayCounts[date.Weekday()] == nil {
+			weekdayCounts[date.Weekday()] = make(map[string]int)
+		}
+		weekdayCounts[date.Weekday()][mood]++
+
+		prevKey := previousDayKey(key)
+		prev, ok := moods[prevKey]
+		if !ok {
+			continue
+		}
+		first.Add(prev, mood)
+		if prev2, ok := moods[previousDayKey(prevKey)]; ok {
+			second.Add(secondOrderState(prev2, prev), mood)
+		}
+	}
+	states := make([]string, 0, len(baseCounts))
+	for mood := range baseCounts {
+		states = append(states, mood)
+	}
+	sort.Strings(states)
+
+	// Forecast the day after the latest vibe, as long as that vibe is recent.
+	last := vibes[len(vibes)-1].Date
+	lastDay := time.Date(last.Year(), last.Month(), last.Day(), 0, 0, 0, 0, today.Location())
+	if !lastDay.Before(today.AddDate(0, 0, -1)) {
+		forecast.TargetDate = lastDay.AddDate(0, 0, 1)
+		lastKey := lastDay.Format(dayKeyLayout)
+		prev1 := moods[lastKey]
+		prev2, hasPrev2 := moods[previousDayKey(lastKey)]
+		switch {
+		case hasPrev2 && second.Total(secondOrderState(prev2, prev1)) >= forecastMinSupport:
+			forecast.Method = model.ForecastMethodSecondOrder
+			forecast.RecentMoods = []string{prev2, prev1}
+			forecast.Samples = second.Total(secondOrderState(prev2, prev1))
+		case first.Total(prev1) >= forecastMinSupport:
+			forecast.Method = model.ForecastMethodFirstOrder
+			forecast.RecentMoods = []string{prev1}
+			forecast.Samples = first.Total(prev1)
+		}
+	}
+	weekday := forecast.TargetDate.Weekday()
+	forecast.Weekday = weekday.String()
+
+	var dist map[string]float64
+	switch forecast.Method {
+	case model.ForecastMethodSecondOrder:
+		dist = second.Distribution(secondOrderState(forecast.RecentMoods[0], forecast.RecentMoods[1]), states, forecastSmoothing)
+	case model.ForecastMethodFirstOrder:
+		dist = first.Distribution(forecast.RecentMoods[0], states, forecastSmoothing)
+	default:
+		dist = smoothedFrequencies(baseCounts, states)
+	}
+
+	// Day-of-week effect: how much more (or less) likely each mood is on the target weekday
+	// than on an average day.
+	base := smoothedFrequencies(baseCounts, states)
+	onWeekday := smoothedFrequencies(weekdayCounts[weekday], states)
+	for _, mood := range states {
+		dist[mood] *= onWeekday[mood] / base[mood]
+	}
+	analytics.Normalize(dist)
+
+	for _, mood := range states {
+		forecast.Probabilities = append(forecast.Probabilities, model.MoodProbability{Mood: mood, Probability: roundTo(dist[mood], 3)})
+	}
+	sort.SliceStable(forecast.Probabilities, func(i, j int) bool {
+		return forecast.Probabilities[i].Probability > forecast.Probabilities[j].Probability
+	})
+	forecast.MostLikely = forecast.Probabilities[0].Mood
+	forecast.Explanation = forecastExplanation(forecast)
+	return forecast, nil
+}
+
+// secondOrderState is the state key for two consecutive days of moods.
+func secondOrderState(older, newer string) string {
+	return older + " -> " + newer
+}
+
+// smoothedFrequencies returns the additively smoothed relative frequency of each state.
+func smoothedFrequencies(counts map[string]int, states []string) map[string]float64 {
+	total := 0
+	for _, n := range counts {
+		total += n
+	}
+	denominator := float64(total) + forecastSmoothing*float64(len(states))
+	freqs := make(map[string]float64, len(states))
+	for _, s := range states {
+		freqs[s] = (float64(counts[s]) + forecastSmoothing) / denominator
+	}
+	return freqs
+}
+
+// forecastExplanation renders a one-line, human readable description of a forecast.
+func forecastExplanation(forecast *model.MoodForecast) string {
+	top := forecast.Probabilities[0]
+	percent := int(math.Round(top.Probability * 100))
+	switch forecast.Method {
+	case model.ForecastMethodSecondOrder:
+		older, newer := forecast.RecentMoods[0], forecast.RecentMoods[1]
+		if older == newer {
+			return fmt.Sprintf("After two %s days you usually feel %s next (%d%% on a %s).", newer, top.Mood, percent, forecast.Weekday)
+		}
+		return fmt.Sprintf("After a %s day followed by a %s day you usually feel %s next (%d%% on a %s).", older, newer, top.Mood, percent, forecast.Weekday)
+	case model.ForecastMethodFirstOrder:
+		return fmt.Sprintf("After a %s day you usually feel %s next (%d%% on a %s).", forecast.RecentMoods[0], top.Mood, percent, forecast.Weekday)
+	default:
+		return fmt.Sprintf("Not enough recent history; on a typical %s you feel %s (%d%%).", forecast.Weekday, top.Mood, percent)
+	}
+}
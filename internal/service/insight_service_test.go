@@ -0,0 +1,156 @@
+package service
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/config"
+	"github.com/aebalz/daily-vibe-tracker/internal/model"
+)
+
+// dayVibe returns a vibe on the given day of June 2025.
+func dayVibe(day int, mood string, energy int, activities ...string) model.Vibe {
+	return model.Vibe{Date: time.Date(2025, 6, day, 0, 0, 0, 0, time.UTC), Mood: mood, EnergyLevel: energy, Activities: activities}
+}
+
+func TestPairSamples(t *testing.T) {
+	gapped := []model.Vibe{
+		dayVibe(1, "happy", 5, "running"),
+		dayVibe(2, "happy", 6),
+		// No vibe on June 3.
+		dayVibe(4, "happy", 7, "running"),
+		dayVibe(5, "happy", 8),
+	}
+	tests := []struct {
+		name       string
+		vibes      []model.Vibe
+		rangeStart string
+		lag        int
+		wantXs     []float64
+		wantYs     []float64
+	}{
+		{"single day", gapped[:1], "2025-06-01", 0, []float64{1}, []float64{5}},
+		{"single day has nothing to lag", gapped[:1], "2025-06-01", 1, nil, nil},
+		{"same day", gapped, "2025-06-01", 0, []float64{0, 0, 1, 1}, []float64{5, 6, 7, 8}},
+		{"gap is not paired", gapped, "2025-06-01", 1, []float64{1, 1}, []float64{6, 8}},
+		{"outcomes before the range are skipped", gapped, "2025-06-04", 0, []float64{0, 1}, []float64{7, 8}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			days := buildDaySamples(tt.vibes, nil)
+			xs, ys := pairSamples(days, tt.rangeStart, model.FactorTypeActivity, "running", model.OutcomeEnergy, tt.lag)
+			// Days are paired in map order.
+			slices.Sort(xs)
+			slices.Sort(ys)
+			if !slices.Equal(xs, tt.wantXs) || !slices.Equal(ys, tt.wantYs) {
+				t.Errorf("got xs %v, ys %v, want %v, %v", xs, ys, tt.wantXs, tt.wantYs)
+			}
+		})
+	}
+}
+
+func TestMoodTransitionMatrix(t *testing.T) {
+	tests := []struct {
+		name            string
+		vibes           []model.Vibe
+		wantTransitions int
+		wantCounts      map[string]map[string]int
+	}{
+		{"no vibes", nil, 0, map[string]map[string]int{}},
+		{"single day", []model.Vibe{dayVibe(1, "happy", 7)}, 0, map[string]map[string]int{}},
+		{
+			"consecutive days",
+			[]model.Vibe{dayVibe(1, "happy", 7), dayVibe(2, "sad", 3), dayVibe(3, "happy", 7)},
+			2,
+			map[string]map[string]int{"happy": {"sad": 1}, "sad": {"happy": 1}},
+		},
+		{
+			"gap between days",
+			[]model.Vibe{dayVibe(1, "happy", 7), dayVibe(2, "sad", 3), dayVibe(4, "calm", 5), dayVibe(5, "calm", 5)},
+			2,
+			map[string]map[string]int{"happy": {"sad": 1}, "calm": {"calm": 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matrix := moodTransitionMatrix(tt.vibes)
+			if matrix.Transitions != tt.wantTransitions {
+				t.Errorf("got %d transitions, want %d", matrix.Transitions, tt.wantTransitions)
+			}
+			if !maps.EqualFunc(matrix.Counts, tt.wantCounts, maps.Equal) {
+				t.Errorf("got counts %v, want %v", matrix.Counts, tt.wantCounts)
+			}
+			for from, row := range matrix.Probabilities {
+				sum := 0.0
+				for _, p := range row {
+					sum += p
+				}
+				if sum < 0.999 || sum > 1.001 {
+					t.Errorf("probabilities from %s sum to %v", from, sum)
+				}
+			}
+		})
+	}
+}
+
+func TestGetCorrelationsMinSamples(t *testing.T) {
+	// Running on every other day, always with high energy: a perfect correlation over 12 days.
+	var alternating, everyDay []model.Vibe
+	for day := 1; day <= 12; day++ {
+		if day%2 == 0 {
+			alternating = append(alternating, dayVibe(day, "happy", 9, "running"))
+		} else {
+			alternating = append(alternating, dayVibe(day, "sad", 3))
+		}
+		everyDay = append(everyDay, dayVibe(day, "happy", day%10+1, "running"))
+	}
+	tests := []struct {
+		name       string
+		vibes      []model.Vibe
+		minSamples int
+		wantErr    bool
+		wantTested int
+		wantFound  bool
+	}{
+		{"enough samples", alternating, 12, false, 2, true},
+		{"fewer samples than required", alternating, 13, false, 0, false},
+		{"min samples below three", alternating, 2, true, 0, false},
+		{"zero variance is not tested", everyDay, 3, false, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &InsightService{
+				VibeRepo: &historyVibes{vibes: tt.vibes},
+				MoodSvc:  &catalogMoods{moods: []model.Mood{{Name: "happy", Valence: 0.8}, {Name: "sad", Valence: -0.6}}},
+				Cfg:      &config.AppConfig{},
+			}
+			report, err := svc.GetCorrelations(CorrelationOptions{
+				StartDate:     time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+				EndDate:       time.Date(2025, 6, 12, 0, 0, 0, 0, time.UTC),
+				MinSamples:    tt.minSamples,
+				MinConfidence: 0.95,
+			})
+			if tt.wantErr {
+				if !errors.Is(err, ErrValidation) {
+					t.Fatalf("got error %v, want a validation error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.Tested != tt.wantTested {
+				t.Errorf("tested %d pairs, want %d", report.Tested, tt.wantTested)
+			}
+			found := slices.ContainsFunc(report.Correlations, func(c model.CorrelationInsight) bool {
+				return c.Factor == "running" && c.Outcome == model.OutcomeEnergy && c.Samples == 12
+			})
+			if found != tt.wantFound {
+				t.Errorf("running/energy reported: %t, want %t (%+v)", found, tt.wantFound, report.Correlations)
+			}
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/config"
+	"github.com/aebalz/daily-vibe-tracker/internal/model"
+	"github.com/aebalz/daily-vibe-tracker/internal/repository"
+	"gorm.io/gorm"
+)
+
+// metricNamePattern restricts metric names to identifiers usable in query parameters and CSV headers.
+var metricNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,62}$`)
+
+// MetricServiceInterface defines the interface for custom metric operations.
+type MetricServiceInterface interface {
+	CreateMetricDefinition(def *model.MetricDefinition) (*model.MetricDefinition, error)
+	GetMetricDefinitionByID(id uint) (*model.MetricDefinition, error)
+	GetAllMetricDefinitions() ([]model.MetricDefinition, error)
+	UpdateMetricDefinition(id uint, updatedDef *model.MetricDefinition) (*model.MetricDefinition, error)
+	DeleteMetricDefinition(id uint) error
+
+	// ResolveVibeMetrics validates raw metric values keyed by metric name against their
+	// definitions and converts them to storable values.
+	ResolveVibeMetrics(metrics map[string]interface{}) ([]model.VibeMetricValue, map[string]interface{}, error)
+	// ParseMetricFilter parses a "name:op:value" filter expression, e.g. "sleep_hours:gte:7".
+	ParseMetricFilter(expr string) (model.MetricFilter, error)
+	// IsMetricDefined reports whether a metric with the given name exists.
+	IsMetricDefined(name string) bool
+
+	// WithContext returns a service whose queries and log lines carry ctx, e.g. the span
+	// of the vibe write that resolves metric values.
+	WithContext(ctx context.Context) MetricServiceInterface
+}
+
+// MetricService implements MetricServiceInterface.
+type MetricService struct {
+	MetricRepo repository.MetricRepositoryInterface
+	Cfg        *config.AppConfig
+}
+
+// NewMetricService creates a new MetricService.
+func NewMetricService(metricRepo repository.MetricRepositoryInterface, cfg *config.AppConfig) MetricServiceInterface {
+	return &MetricService{
+		MetricRepo: metricRepo,
+		Cfg:        cfg,
+	}
+}
+
+// WithContext returns a copy of the service, and of its repository, bound to ctx.
+func (s *MetricService) WithContext(ctx context.Context) MetricServiceInterface {
+	copied := *s
+	copied.MetricRepo = s.MetricRepo.WithContext(ctx)
+	return &copied
+}
+
+// normalizeMetricName lowercases a metric name and replaces spaces and dashes with underscores.
+func normalizeMetricName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(name)
+}
+
+// ValidateMetricDefinition performs business logic validation on a metric definition.
+func (s *MetricService) ValidateMetricDefinition(def *model.MetricDefinition) error {
+	if !metricNamePattern.MatchString(def.Name) {
+		return fmt.Errorf("metric name must start with a letter and contain only letters, digits and underscores")
+	}
+	switch def.Type {
+	case model.MetricTypeInt, model.MetricTypeFloat:
+	case model.MetricTypeBool:
+		if def.Min != nil || def.Max != nil {
+			return fmt.Errorf("bool metrics cannot have a range")
+		}
+	case model.MetricTypeScale:
+		if def.Min == nil || def.Max == nil {
+			return fmt.Errorf("scale metrics require both min and max")
+		}
+		if *def.Min != math.Trunc(*def.Min) || *def.Max != math.Trunc(*def.Max) {
+			return fmt.Errorf("scale bounds must be whole numbers")
+		}
+	default:
+		return fmt.Errorf("invalid metric type '%s'. Allowed values: int, float, bool, scale", def.Type)
+	}
+	if def.Min != nil && def.Max != nil && *def.Min > *def.Max {
+		return fmt.Errorf("min cannot be greater than max")
+	}
+	return nil
+}
+
+// CreateMetricDefinition adds a new metric definition.
+func (s *MetricService) CreateMetricDefinition(def *model.MetricDefinition) (*model.MetricDefinition, error) {
+	def.ID = 0
+	def.Name = normalizeMetricName(def.Name)
+	def.Type = strings.ToLower(strings.TrimSpace(def.Type))
+	if err := s.ValidateMetricDefinition(def); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+	if s.IsMetricDefined(def.Name) {
+		return nil, fmt.Errorf("validation error: metric '%s' already exists", def.Name)
+	}
+	return s.MetricRepo.CreateMetricDefinition(def)
+}
+
+// GetMetricDefinitionByID retrieves a metric definition by its ID.
+func (s *MetricService) GetMetricDefinitionByID(id uint) (*model.MetricDefinition, error) {
+	return s.MetricRepo.GetMetricDefinitionByID(id)
+}
+
+// GetAllMetricDefinitions retrieves all metric definitions.
+func (s *MetricService) GetAllMetricDefinitions() ([]model.MetricDefinition, error) {
+	return s.MetricRepo.GetAllMetricDefinitions()
+}
+
+// UpdateMetricDefinition modifies an existing metric definition.
+// The name and type cannot change once values have been recorded, since stored values
+// and client filters depend on them.
+func (s *MetricService) UpdateMetricDefinition(id uint, updatedDef *model.MetricDefinition) (*model.MetricDefinition, error) {
+	updatedDef.Name = normalizeMetricName(updatedDef.Name)
+	updatedDef.Type = strings.ToLower(strings.TrimSpace(updatedDef.Type))
+	if err := s.ValidateMetricDefinition(updatedDef); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+
+	existing, err := s.MetricRepo.GetMetricDefinitionByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Name != updatedDef.Name || existing.Type != updatedDef.Type {
+		count, err := s.MetricRepo.CountMetricValues(id)
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			return nil, fmt.Errorf("validation error: cannot change name or type of metric '%s' with %d recorded values", existing.Name, count)
+		}
+		if existing.Name != updatedDef.Name && s.IsMetricDefined(updatedDef.Name) {
+			return nil, fmt.Errorf("validation error: metric '%s' already exists", updatedDef.Name)
+		}
+	}
+	return s.MetricRepo.UpdateMetricDefinition(id, updatedDef)
+}
+
+// DeleteMetricDefinition removes a metric definition and all values recorded for it.
+func (s *MetricService) DeleteMetricDefinition(id uint) error {
+	return s.MetricRepo.DeleteMetricDefinition(id)
+}
+
+// IsMetricDefined reports whether a metric with the given name exists.
+func (s *MetricService) IsMetricDefined(name string) bool {
+	_, err := s.MetricRepo.GetMetricDefinitionByName(normalizeMetricName(name))
+	return err == nil
+}
+
+// ResolveVibeMetrics validates metric values against their definitions.
+// It returns the values to store and the normalized API representation.
+func (s *MetricService) ResolveVibeMetrics(metrics map[string]interface{}) ([]model.VibeMetricValue, map[string]interface{}, error) {
+	if metrics == nil {
+		return nil, nil, nil
+	}
+	values := make([]model.VibeMetricValue, 0, len(metrics))
+	normalized := make(map[string]interface{}, len(metrics))
+	for rawName, rawValue := range metrics {
+		name := normalizeMetricName(rawName)
+		def, err := s.MetricRepo.GetMetricDefinitionByName(name)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, nil, fmt.Errorf("unknown metric '%s'", rawName)
+			}
+			return nil, nil, err
+		}
+		if rawValue == nil {
+			continue // Explicit null clears the metric
+		}
+		value, err := coerceMetricValue(def, rawValue)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid value for metric '%s': %w", name, err)
+		}
+		values = append(values, model.VibeMetricValue{MetricDefinitionID: def.ID, Value: value})
+		normalized[name] = model.MetricValueForJSON(def.Type, value)
+	}
+	return values, normalized, nil
+}
+
+// coerceMetricValue converts a JSON-decoded value to float64 according to the metric type
+// and checks it against the definition's range.
+func coerceMetricValue(def *model.MetricDefinition, raw interface{}) (float64, error) {
+	var value float64
+	switch v := raw.(type) {
+	case bool:
+		if def.Type != model.MetricTypeBool {
+			return 0, fmt.Errorf("expected a number, got a boolean")
+		}
+		if v {
+			value = 1
+		}
+		return value, nil
+	case float64:
+		value = v
+	case float32:
+		value = float64(v)
+	case int:
+		value = float64(v)
+	case int64:
+		value = float64(v)
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("expected a number, got '%s'", v)
+		}
+		value = parsed
+	default:
+		return 0, fmt.Errorf("unsupported value type %T", raw)
+	}
+
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("value must be a finite number")
+	}
+	switch def.Type {
+	case model.MetricTypeBool:
+		if value != 0 && value != 1 {
+			return 0, fmt.Errorf("expected true/false or 0/1")
+		}
+	case model.MetricTypeInt, model.MetricTypeScale:
+		if value != math.Trunc(value) {
+			return 0, fmt.Errorf("expected a whole number")
+		}
+	}
+	if def.Min != nil && value < *def.Min {
+		return 0, fmt.Errorf("value %v is below the minimum of %v", value, *def.Min)
+	}
+	if def.Max != nil && value > *def.Max {
+		return 0, fmt.Errorf("value %v is above the maximum of %v", value, *def.Max)
+	}
+	return value, nil
+}
+
+// ParseMetricFilter parses a "name:op:value" filter expression.
+func (s *MetricService) ParseMetricFilter(expr string) (model.MetricFilter, error) {
+	parts := strings.Split(expr, ":")
+	if len(parts) != 3 {
+		return model.MetricFilter{}, fmt.Errorf("invalid metric filter '%s', expected name:op:value", expr)
+	}
+	name := normalizeMetricName(parts[0])
+	def, err := s.MetricRepo.GetMetricDefinitionByName(name)
+	if err != nil {
+		return model.MetricFilter{}, fmt.Errorf("unknown metric '%s' in filter", parts[0])
+	}
+	op := strings.ToLower(strings.TrimSpace(parts[1]))
+	switch op {
+	case "eq", "ne", "gt", "gte", "lt", "lte":
+	default:
+		return model.MetricFilter{}, fmt.Errorf("invalid operator '%s' in metric filter. Allowed values: eq, ne, gt, gte, lt, lte", parts[1])
+	}
+	rawValue := strings.TrimSpace(parts[2])
+	var value float64
+	if def.Type == model.MetricTypeBool && (rawValue == "true" || rawValue == "false") {
+		if rawValue == "true" {
+			value = 1
+		}
+	} else {
+		value, err = strconv.ParseFloat(rawValue, 64)
+		if err != nil {
+			return model.MetricFilter{}, fmt.Errorf("invalid value '%s' in metric filter", parts[2])
+		}
+	}
+	return model.MetricFilter{Name: name, Operator: op, Value: value}, nil
+}
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/config"
+	"github.com/aebalz/daily-vibe-tracker/internal/model"
+	"github.com/aebalz/daily-vibe-tracker/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Unknown mood policies, configured via MOOD_UNKNOWN_POLICY.
+const (
+	MoodPolicyReject = "reject" // Reject vibes whose mood is not in the catalog
+	MoodPolicyCreate = "create" // Auto-create a neutral catalog entry for the new mood
+	MoodPolicyOther  = "other"  // Map unknown moods to the catch-all "other" mood
+)
+
+// ErrUnknownMood is returned when a mood cannot be resolved and the policy is "reject".
+var ErrUnknownMood = errors.New("unknown mood")
+
+// MoodServiceInterface defines the interface for mood catalog operations.
+type MoodServiceInterface interface {
+	CreateMood(mood *model.Mood) (*model.Mood, error)
+	GetMoodByID(id uint) (*model.Mood, error)
+	GetAllMoods() ([]model.Mood, error)
+	UpdateMood(id uint, updatedMood *model.Mood) (*model.Mood, error)
+	DeleteMood(id uint) error
+
+	// ResolveMood returns the catalog entry for a mood name or alias, applying the
+	// configured unknown-mood policy when there is no match.
+	ResolveMood(name string) (*model.Mood, error)
+	// LookupMood returns the catalog entry for a mood name or alias without applying
+	// the unknown-mood policy. Returns gorm.ErrRecordNotFound if there is no match.
+	LookupMood(name string) (*model.Mood, error)
+	// EnsureDefaultMoods seeds the catalog with model.DefaultMoods if it is empty.
+	EnsureDefaultMoods() error
+
+	// WithTx returns a service whose catalog reads and writes run in the transaction tx,
+	// so that a mood created by ResolveMood is rolled back with the write that needed it.
+	WithTx(tx *gorm.DB) MoodServiceInterface
+	// WithContext returns a service whose queries and log lines carry ctx, e.g. the span
+	// of the vibe write that resolves a mood.
+	WithContext(ctx context.Context) MoodServiceInterface
+}
+
+// MoodService implements MoodServiceInterface.
+type MoodService struct {
+	MoodRepo repository.MoodRepositoryInterface
+	Cfg      *config.AppConfig
+}
+
+// NewMoodService creates a new MoodService.
+func NewMoodService(moodRepo repository.MoodRepositoryInterface, cfg *config.AppConfig) MoodServiceInterface {
+	return &MoodService{
+		MoodRepo: moodRepo,
+		Cfg:      cfg,
+	}
+}
+
+// WithTx returns a copy of the service bound to tx.
+func (s *MoodService) WithTx(tx *gorm.DB) MoodServiceInterface {
+	copied := *s
+	copied.MoodRepo = s.MoodRepo.WithTx(tx)
+	return &copied
+}
+
+// WithContext returns a copy of the service, and of its repository, bound to ctx.
+func (s *MoodService) WithContext(ctx context.Context) MoodServiceInterface {
+	copied := *s
+	copied.MoodRepo = s.MoodRepo.WithContext(ctx)
+	return &copied
+}
+
+// normalizeMoodName lowercases and trims a mood name or alias.
+func normalizeMoodName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// normalizeAliases lowercases, trims and de-duplicates aliases, dropping empty ones
+// and any alias equal to the canonical name.
+func normalizeAliases(name string, aliases []string) []string {
+	seen := map[string]bool{name: true}
+	result := make([]string, 0, len(aliases))
+	for _, alias := range aliases {
+		alias = normalizeMoodName(alias)
+		if alias == "" || seen[alias] {
+			continue
+		}
+		seen[alias] = true
+		result = append(result, alias)
+	}
+	return result
+}
+
+// ValidateMood performs business logic validation on a mood catalog entry.
+func (s *MoodService) ValidateMood(mood *model.Mood) error {
+	if strings.TrimSpace(mood.Name) == "" {
+		return fmt.Errorf("mood name cannot be empty")
+	}
+	if mood.Valence < -1 || mood.Valence > 1 {
+		return fmt.Errorf("valence must be between -1 and 1")
+	}
+	if mood.Arousal < 0 || mood.Arousal > 1 {
+		return fmt.Errorf("arousal must be between 0 and 1")
+	}
+	return nil
+}
+
+// checkAliasConflicts ensures that none of the mood's name or aliases already resolve
+// to a different catalog entry.
+func (s *MoodService) checkAliasConflicts(mood *model.Mood) error {
+	for _, name := range append([]string{mood.Name}, mood.Aliases...) {
+		existing, err := s.MoodRepo.FindMoodByNameOrAlias(name)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+			return err
+		}
+		if existing.ID != mood.ID {
+			return fmt.Errorf("'%s' is already used by mood '%s'", name, existing.Name)
+		}
+	}
+	return nil
+}
+
+// CreateMood adds a new mood to the catalog.
+func (s *MoodService) CreateMood(mood *model.Mood) (*model.Mood, error) {
+	if err := s.ValidateMood(mood); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+	mood.Name = normalizeMoodName(mood.Name)
+	mood.Aliases = normalizeAliases(mood.Name, mood.Aliases)
+	mood.ID = 0
+	if err := s.checkAliasConflicts(mood); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+	return s.MoodRepo.CreateMood(mood)
+}
+
+// GetMoodByID retrieves a single mood by its ID.
+func (s *MoodService) GetMoodByID(id uint) (*model.Mood, error) {
+	return s.MoodRepo.GetMoodByID(id)
+}
+
+// GetAllMoods retrieves the whole mood catalog.
+func (s *MoodService) GetAllMoods() ([]model.Mood, error) {
+	return s.MoodRepo.GetAllMoods()
+}
+
+// UpdateMood modifies an existing mood in the catalog.
+// Renaming a mood does not rewrite existing vibes; keep the old name as an alias if needed.
+func (s *MoodService) UpdateMood(id uint, updatedMood *model.Mood) (*model.Mood, error) {
+	if err := s.ValidateMood(updatedMood); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+	updatedMood.Name = normalizeMoodName(updatedMood.Name)
+	updatedMood.Aliases = normalizeAliases(updatedMood.Name, updatedMood.Aliases)
+	updatedMood.ID = id
+	if err := s.checkAliasConflicts(updatedMood); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+	return s.MoodRepo.UpdateMood(id, updatedMood)
+}
+
+// DeleteMood removes a mood from the catalog.
+func (s *MoodService) DeleteMood(id uint) error {
+	mood, err := s.MoodRepo.GetMoodByID(id)
+	if err != nil {
+		return err
+	}
+	if mood.Name == model.OtherMoodName {
+		return fmt.Errorf("the '%s' mood is required and cannot be deleted", model.OtherMoodName)
+	}
+	return s.MoodRepo.DeleteMood(id)
+}
+
+// LookupMood resolves a mood name or alias against the catalog without side effects.
+func (s *MoodService) LookupMood(name string) (*model.Mood, error) {
+	normalized := normalizeMoodName(name)
+	if normalized == "" {
+		return nil, fmt.Errorf("mood cannot be empty")
+	}
+	return s.MoodRepo.FindMoodByNameOrAlias(normalized)
+}
+
+// ResolveMood resolves a mood name or alias against the catalog, applying the
+// configured policy (reject / create / other) for unknown moods.
+func (s *MoodService) ResolveMood(name string) (*model.Mood, error) {
+	mood, err := s.LookupMood(name)
+	if err == nil {
+		return mood, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	normalized := normalizeMoodName(name)
+	switch s.Cfg.MoodUnknownPolicy {
+	case MoodPolicyReject:
+		return nil, fmt.Errorf("%w: '%s'", ErrUnknownMood, normalized)
+	case MoodPolicyOther:
+		other, err := s.MoodRepo.FindMoodByNameOrAlias(model.OtherMoodName)
+		if err != nil {
+			return nil, fmt.Errorf("could not resolve '%s' mood: %w", model.OtherMoodName, err)
+		}
+		return other, nil
+	default: // MoodPolicyCreate
+		// Auto-created moods are neutral until someone curates them via the moods API. A
+		// concurrent write may create the same mood first; both then resolve to its row.
+		return s.MoodRepo.CreateMoodIfAbsent(&model.Mood{
+			Name:    normalized,
+			Aliases: []string{},
+			Valence: 0,
+			Arousal: 0.5,
+		})
+	}
+}
+
+// EnsureDefaultMoods seeds the catalog with the default moods if it is empty.
+func (s *MoodService) EnsureDefaultMoods() error {
+	count, err := s.MoodRepo.CountMoods()
+	if err != nil {
+		return fmt.Errorf("could not count moods: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+	for _, mood := range model.DefaultMoods {
+		m := mood
+		if _, err := s.MoodRepo.CreateMood(&m); err != nil {
+			return fmt.Errorf("could not seed mood '%s': %w", m.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,542 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/config"
+	"github.com/aebalz/daily-vibe-tracker/internal/model"
+	"github.com/aebalz/daily-vibe-tracker/internal/repository"
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultRecommendationLimit is the number of suggestions returned when no limit is given.
+	DefaultRecommendationLimit = 3
+	// MaxRecommendationLimit bounds the number of suggestions per request.
+	MaxRecommendationLimit = 10
+
+	recommendationLookbackDays = 180
+	// recommendationHalfLifeDays is the age at which a day counts half as much as today.
+	recommendationHalfLifeDays = 30.0
+	// recommendationMinOccurrences is how often an activity must appear before it is suggested.
+	recommendationMinOccurrences = 2
+	// recommendationPriorDays shrinks the lift of rarely done activities toward 1, so one
+	// lucky day does not make an activity the top suggestion.
+	recommendationPriorDays = 3.0
+	// recommendationJitter is the maximum random noise added to a score. It only reorders
+	// near-ties, and is reproducible for a given seed.
+	recommendationJitter = 0.05
+	// recommendationFeedbackWeight scales the score adjustment from past feedback.
+	recommendationFeedbackWeight = 0.2
+	// recommendationLinkDays is how many days after a recommendation a vibe including the
+	// activity still counts as following it.
+	recommendationLinkDays = 1
+	// defaultRecommendationStatsDays is the range covered by stats when no dates are given.
+	defaultRecommendationStatsDays = 90
+)
+
+// validFeedback lists the accepted recommendation feedback values.
+var validFeedback = map[string]bool{
+	model.FeedbackAccepted:  true,
+	model.FeedbackDismissed: true,
+	model.FeedbackDidIt:     true,
+}
+
+// RecommendationOptions controls a recommendation request. Zero values fall back to defaults.
+type RecommendationOptions struct {
+	Date  time.Time // Day to recommend for; defaults to today
+	Limit int       // Number of suggestions; defaults to DefaultRecommendationLimit
+	Seed  int64     // Seed for tie-breaking; defaults to RECOMMENDATION_SEED, or a random seed if that is 0
+}
+
+// RecommendationServiceInterface defines the interface for activity recommendations.
+type RecommendationServiceInterface interface {
+	// RecommendActivities ranks activities for a day by historical mood/energy lift, recency
+	// and day-of-week fit, skipping activities already logged that day or marked not interested.
+	RecommendActivities(opts RecommendationOptions) (*model.RecommendationResult, error)
+
+	AddNotInterested(activity string) (*model.NotInterestedActivity, error)
+	RemoveNotInterested(activity string) error
+	GetNotInterested() ([]model.NotInterestedActivity, error)
+
+	GetRecommendation(id uint) (*model.Recommendation, error)
+	// SubmitFeedback records accepted, dismissed or did_it for a served recommendation.
+	SubmitFeedback(id uint, feedback string) (*model.Recommendation, error)
+	// LinkVibe marks recommendations of the vibe's activities made on the vibe's day or the
+	// day before as followed by that vibe.
+	LinkVibe(vibe *model.Vibe) (int64, error)
+	// GetRecommendationStats reports acceptance and outcome lift per activity. Zero dates
+	// default to the last 90 days.
+	GetRecommendationStats(startDate, endDate time.Time) (*model.RecommendationStats, error)
+}
+
+// RecommendationService implements RecommendationServiceInterface.
+type RecommendationService struct {
+	RecommendationRepo repository.RecommendationRepositoryInterface
+	VibeRepo           repository.VibeRepositoryInterface
+	MoodSvc            MoodServiceInterface     // Mood catalog used to tell good days apart
+	ActivitySvc        ActivityServiceInterface // Activity catalog used to resolve aliases in feedback
+	Cfg                *config.AppConfig
+}
+
+// NewRecommendationService creates a new RecommendationService.
+func NewRecommendationService(recommendationRepo repository.RecommendationRepositoryInterface, vibeRepo repository.VibeRepositoryInterface, moodSvc MoodServiceInterface, activitySvc ActivityServiceInterface, cfg *config.AppConfig) RecommendationServiceInterface {
+	return &RecommendationService{
+		RecommendationRepo: recommendationRepo,
+		VibeRepo:           vibeRepo,
+		MoodSvc:            moodSvc,
+		ActivitySvc:        activitySvc,
+		Cfg:                cfg,
+	}
+}
+
+// activityStats accumulates the recency-weighted history of one activity.
+type activityStats struct {
+	weight      float64 // Sum of day weights
+	goodWeight  float64 // Sum of weights of good days
+	energy      float64 // Weighted sum of energy
+	occurrences int
+	onWeekday   int // Occurrences on the target weekday
+	lastDone    time.Time
+}
+
+// RecommendActivities scores each activity as
+//
+//	ln(lift) + 0.1 * energy delta + 0.25 * ln(day-of-week fit) + feedback + jitter
+//
+// where every day of history is weighted by 0.5^(age / 30 days). A good day has a
+// positive mood and energy of at least 7. The feedback term is
+// 0.2 * ln((followed + 1) / (dismissed + 1)) over past recommendations of the activity.
+// Only activities with a positive score are suggested. Suggestions served for the current day
+// are stored so feedback can be given on them; those for another day are not, so looking at
+// past or future days does not add recommendations to the history.
+func (s *RecommendationService) RecommendActivities(opts RecommendationOptions) (*model.RecommendationResult, error) {
+	if opts.Limit == 0 {
+		opts.Limit = DefaultRecommendationLimit
+	}
+	if opts.Limit < 1 || opts.Limit > MaxRecommendationLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrValidation, MaxRecommendationLimit)
+	}
+	if opts.Seed == 0 {
+		opts.Seed = s.Cfg.RecommendationSeed
+	}
+	if opts.Seed == 0 {
+		opts.Seed = time.Now().UnixNano()
+	}
+	if opts.Date.IsZero() {
+		opts.Date = time.Now()
+	}
+	today := time.Date(opts.Date.Year(), opts.Date.Month(), opts.Date.Day(), 0, 0, 0, 0, opts.Date.Location())
+	todayKey := today.Format(dayKeyLayout)
+
+	vibes, err := s.VibeRepo.GetVibesForDateRange(today.AddDate(0, 0, -recommendationLookbackDays), today.Add(24*time.Hour-time.Second))
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch historical data for recommendation: %w", err)
+	}
+	valenceByMood, err := moodValences(s.MoodSvc)
+	if err != nil {
+		return nil, err
+	}
+	notInterested, err := s.RecommendationRepo.GetNotInterested()
+	if err != nil {
+		return nil, fmt.Errorf("could not load not-interested activities: %w", err)
+	}
+	excluded := make(map[string]bool, len(notInterested))
+	for _, n := range notInterested {
+		excluded[n.Activity] = true
+	}
+
+	feedback, err := s.feedbackAdjustments(today)
+	if err != nil {
+		return nil, err
+	}
+
+	days := buildDaySamples(vibes, valenceByMood)
+	if doneToday, ok := days[todayKey]; ok {
+		for a := range doneToday.activities {
+			excluded[a] = true
+		}
+	}
+
+	var totalWeight, goodWeight, energyWeighted float64
+	stats := make(map[string]*activityStats)
+	for key, day := range days {
+		if key >= todayKey {
+			continue // Today is still in progress and says nothing about outcomes yet
+		}
+		date, _ := time.Parse(dayKeyLayout, key)
+		age := today.Sub(time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, today.Location())).Hours() / 24
+		weight := math.Pow(0.5, age/recommendationHalfLifeDays)
+		good := day.energy >= goodEnergyLevel && day.valence != nil && *day.valence > 0
+
+		totalWeight += weight
+		energyWeighted += weight * day.energy
+		if good {
+			goodWeight += weight
+		}
+		for a := range day.activities {
+			st, ok := stats[a]
+			if !ok {
+				st = &activityStats{}
+				stats[a] = st
+			}
+			st.weight += weight
+			st.energy += weight * day.energy
+			st.occurrences++
+			if good {
+				st.goodWeight += weight
+			}
+			if date.Weekday() == today.Weekday() {
+				st.onWeekday++
+			}
+			if date.After(st.lastDone) {
+				st.lastDone = date
+			}
+		}
+	}
+
+	result := &model.RecommendationResult{
+		Date:        today,
+		Seed:        opts.Seed,
+		Suggestions: []model.ActivitySuggestion{},
+	}
+	if totalWeight == 0 {
+		result.Suggestion = "No specific activity suggestions yet. Maybe try something new today!"
+		result.Reason = "Not enough vibe history to learn from."
+		return result, nil
+	}
+	pGood := goodWeight / totalWeight
+	averageEnergy := energyWeighted / totalWeight
+
+	// Score in name order so the jitter drawn for each activity only depends on the seed.
+	names := make([]string, 0, len(stats))
+	for name := range stats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	rng := rand.New(rand.NewSource(opts.Seed))
+	for _, name := range names {
+		st := stats[name]
+		jitter := rng.Float64() * recommendationJitter
+		if excluded[name] || st.occurrences < recommendationMinOccurrences {
+			continue
+		}
+		lift := 1.0
+		if pGood > 0 {
+			lift = (st.goodWeight + recommendationPriorDays*pGood) / ((st.weight + recommendationPriorDays) * pGood)
+		}
+		energyDelta := st.energy/st.weight - averageEnergy
+		dowFit := (float64(st.onWeekday) + 1) / (float64(st.occurrences)/7 + 1)
+		score := math.Log(lift) + 0.1*energyDelta + 0.25*math.Log(dowFit) + feedback[name] + jitter
+		if score <= 0 {
+			continue
+		}
+		lastDone := st.lastDone
+		suggestion := model.ActivitySuggestion{
+			Activity:     name,
+			Score:        roundTo(score, 3),
+			Lift:         roundTo(lift, 2),
+			EnergyDelta:  roundTo(energyDelta, 2),
+			DayOfWeekFit: roundTo(dowFit, 2),
+			Feedback:     roundTo(feedback[name], 2),
+			Occurrences:  st.occurrences,
+			LastDone:     &lastDone,
+		}
+		suggestion.Reasons = suggestionReasons(suggestion, today)
+		result.Suggestions = append(result.Suggestions, suggestion)
+	}
+
+	sort.SliceStable(result.Suggestions, func(i, j int) bool {
+		return result.Suggestions[i].Score > result.Suggestions[j].Score
+	})
+	if len(result.Suggestions) > opts.Limit {
+		result.Suggestions = result.Suggestions[:opts.Limit]
+	}
+
+	if len(result.Suggestions) == 0 {
+		result.Suggestion = "No specific activity suggestions based on your recent vibes. Maybe try something new today!"
+		result.Reason = "No activity stands out for good days in your history."
+		return result, nil
+	}
+	if todayKey == time.Now().In(today.Location()).Format(dayKeyLayout) {
+		if err := s.saveSuggestions(today, opts.Seed, result.Suggestions); err != nil {
+			return nil, err
+		}
+	}
+	top := result.Suggestions[0]
+	result.Suggestion = fmt.Sprintf("Based on past good days, you might enjoy: %s", top.Activity)
+	result.Reason = strings.Join(top.Reasons, "; ") + "."
+	return result, nil
+}
+
+// suggestionReasons explains the main contributions to a suggestion's score.
+func suggestionReasons(s model.ActivitySuggestion, today time.Time) []string {
+	var reasons []string
+	if s.Lift >= 1.05 {
+		reasons = append(reasons, fmt.Sprintf("good days are %.0f%% more likely when you do it", (s.Lift-1)*100))
+	}
+	if s.EnergyDelta >= 0.5 {
+		reasons = append(reasons, fmt.Sprintf("your energy is %.1f points higher on average", s.EnergyDelta))
+	}
+	if s.DayOfWeekFit >= 1.2 {
+		reasons = append(reasons, fmt.Sprintf("you often do it on %ss", today.Weekday()))
+	}
+	if s.Feedback >= 0.1 {
+		reasons = append(reasons, "you often follow this suggestion")
+	} else if s.Feedback <= -0.1 {
+		reasons = append(reasons, "you have dismissed it before, so it ranks lower")
+	}
+	if s.LastDone != nil {
+		if daysAgo := int(today.Sub(*s.LastDone).Hours() / 24); daysAgo >= 7 {
+			reasons = append(reasons, fmt.Sprintf("you haven't done it in %d days", daysAgo))
+		}
+	}
+	if len(reasons) == 0 {
+		reasons = append(reasons, fmt.Sprintf("it is part of your routine (%d times recently)", s.Occurrences))
+	}
+	return reasons
+}
+
+// canonicalActivityName resolves an activity name or alias to its catalog name. Names not in
+// the catalog are normalized but kept, so feedback can be given before an activity is logged.
+func (s *RecommendationService) canonicalActivityName(name string) (string, error) {
+	normalized := normalizeActivityName(name)
+	if normalized == "" {
+		return "", fmt.Errorf("%w: activity cannot be empty", ErrValidation)
+	}
+	activity, err := s.ActivitySvc.LookupActivity(normalized)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return normalized, nil
+		}
+		return "", err
+	}
+	return activity.Name, nil
+}
+
+// AddNotInterested stops an activity from being recommended.
+func (s *RecommendationService) AddNotInterested(activity string) (*model.NotInterestedActivity, error) {
+	name, err := s.canonicalActivityName(activity)
+	if err != nil {
+		return nil, err
+	}
+	return s.RecommendationRepo.AddNotInterested(name)
+}
+
+// RemoveNotInterested allows an activity to be recommended again.
+func (s *RecommendationService) RemoveNotInterested(activity string) error {
+	name, err := s.canonicalActivityName(activity)
+	if err != nil {
+		return err
+	}
+	return s.RecommendationRepo.RemoveNotInterested(name)
+}
+
+// GetNotInterested lists the activities that are not recommended.
+func (s *RecommendationService) GetNotInterested() ([]model.NotInterestedActivity, error) {
+	return s.RecommendationRepo.GetNotInterested()
+}
+
+// feedbackAdjustments computes the feedback score term per activity from the recommendations
+// served in the lookback window before today.
+func (s *RecommendationService) feedbackAdjustments(today time.Time) (map[string]float64, error) {
+	past, err := s.RecommendationRepo.GetRecommendationsForDateRange(today.AddDate(0, 0, -recommendationLookbackDays), today.Add(-time.Second))
+	if err != nil {
+		return nil, fmt.Errorf("could not load recommendation feedback: %w", err)
+	}
+	followed := make(map[string]int)
+	dismissed := make(map[string]int)
+	for i := range past {
+		switch {
+		case past[i].Done() || past[i].Feedback == model.FeedbackAccepted:
+			followed[past[i].Activity]++
+		case past[i].Feedback == model.FeedbackDismissed:
+			dismissed[past[i].Activity]++
+		}
+	}
+	adjustments := make(map[string]float64, len(followed)+len(dismissed))
+	for _, activities := range []map[string]int{followed, dismissed} {
+		for name := range activities {
+			adjustments[name] = recommendationFeedbackWeight * math.Log(float64(followed[name]+1)/float64(dismissed[name]+1))
+		}
+	}
+	return adjustments, nil
+}
+
+// saveSuggestions stores the served suggestions and copies their IDs back.
+func (s *RecommendationService) saveSuggestions(day time.Time, seed int64, suggestions []model.ActivitySuggestion) error {
+	records := make([]*model.Recommendation, len(suggestions))
+	for i, suggestion := range suggestions {
+		records[i] = &model.Recommendation{
+			Date:     day,
+			Activity: suggestion.Activity,
+			Rank:     i + 1,
+			Score:    suggestion.Score,
+			Seed:     seed,
+		}
+	}
+	if err := s.RecommendationRepo.SaveRecommendations(records); err != nil {
+		return fmt.Errorf("could not save recommendations: %w", err)
+	}
+	for i := range suggestions {
+		suggestions[i].ID = records[i].ID
+	}
+	return nil
+}
+
+// GetRecommendation retrieves a served recommendation.
+func (s *RecommendationService) GetRecommendation(id uint) (*model.Recommendation, error) {
+	return s.RecommendationRepo.GetRecommendationByID(id)
+}
+
+// SubmitFeedback validates and records feedback on a served recommendation.
+func (s *RecommendationService) SubmitFeedback(id uint, feedback string) (*model.Recommendation, error) {
+	feedback = strings.ToLower(strings.TrimSpace(feedback))
+	if !validFeedback[feedback] {
+		return nil, fmt.Errorf("%w: feedback must be one of accepted, dismissed or did_it", ErrValidation)
+	}
+	return s.RecommendationRepo.SetFeedback(id, feedback, time.Now())
+}
+
+// LinkVibe links open recommendations to a vibe that includes the recommended activity.
+// Activities must already be normalized to catalog names.
+func (s *RecommendationService) LinkVibe(vibe *model.Vibe) (int64, error) {
+	if vibe == nil || vibe.ID == 0 || len(vibe.Activities) == 0 {
+		return 0, nil
+	}
+	day := time.Date(vibe.Date.Year(), vibe.Date.Month(), vibe.Date.Day(), 0, 0, 0, 0, vibe.Date.Location())
+	return s.RecommendationRepo.LinkVibe(vibe.ID, vibe.Activities, day.AddDate(0, 0, -recommendationLinkDays), day.Add(24*time.Hour-time.Second))
+}
+
+// recommendationOutcome accumulates the outcomes of recommended days in one group.
+type recommendationOutcome struct {
+	days, goodDays int
+	energy         float64
+}
+
+func (o recommendationOutcome) goodDayRate() *float64 {
+	if o.days == 0 {
+		return nil
+	}
+	rate := roundTo(float64(o.goodDays)/float64(o.days), 3)
+	return &rate
+}
+
+func (o recommendationOutcome) averageEnergy() *float64 {
+	if o.days == 0 {
+		return nil
+	}
+	avg := roundTo(o.energy/float64(o.days), 2)
+	return &avg
+}
+
+// GetRecommendationStats compares, per activity, the recommended days on which the suggestion
+// was followed with those on which it was not. Outcome lift is the ratio of good-day rates.
+func (s *RecommendationService) GetRecommendationStats(startDate, endDate time.Time) (*model.RecommendationStats, error) {
+	if endDate.IsZero() {
+		now := time.Now()
+		endDate = time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 0, now.Location())
+	}
+	if startDate.IsZero() {
+		startDate = time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 0, 0, 0, 0, endDate.Location()).AddDate(0, 0, -defaultRecommendationStatsDays+1)
+	}
+	if startDate.After(endDate) {
+		return nil, fmt.Errorf("%w: start_date must not be after end_date", ErrValidation)
+	}
+
+	recommendations, err := s.RecommendationRepo.GetRecommendationsForDateRange(startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("could not load recommendations: %w", err)
+	}
+	vibes, err := s.VibeRepo.GetVibesForDateRange(startDate, endDate.AddDate(0, 0, recommendationLinkDays))
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch vibes for recommendation stats: %w", err)
+	}
+	valenceByMood, err := moodValences(s.MoodSvc)
+	if err != nil {
+		return nil, err
+	}
+	days := buildDaySamples(vibes, valenceByMood)
+
+	stats := &model.RecommendationStats{
+		StartDate:  startDate,
+		EndDate:    endDate,
+		Activities: []model.RecommendationActivityStats{},
+	}
+	byActivity := make(map[string]*model.RecommendationActivityStats)
+	done := make(map[string]*recommendationOutcome)
+	notDone := make(map[string]*recommendationOutcome)
+	acceptedBy := make(map[string]int)
+	var accepted, completed int
+	for i := range recommendations {
+		rec := &recommendations[i]
+		st, ok := byActivity[rec.Activity]
+		if !ok {
+			st = &model.RecommendationActivityStats{Activity: rec.Activity}
+			byActivity[rec.Activity] = st
+			done[rec.Activity] = &recommendationOutcome{}
+			notDone[rec.Activity] = &recommendationOutcome{}
+		}
+		st.Served++
+		switch rec.Feedback {
+		case model.FeedbackAccepted:
+			st.Accepted++
+		case model.FeedbackDismissed:
+			st.Dismissed++
+		case model.FeedbackDidIt:
+			st.DidIt++
+		}
+		if rec.LinkedVibeID != nil {
+			st.Linked++
+		}
+		group := notDone[rec.Activity]
+		if rec.Done() {
+			st.Done++
+			completed++
+			group = done[rec.Activity]
+		}
+		if rec.Done() || rec.Feedback == model.FeedbackAccepted {
+			acceptedBy[rec.Activity]++
+			accepted++
+		}
+		if day, ok := days[rec.Date.Format(dayKeyLayout)]; ok {
+			group.days++
+			group.energy += day.energy
+			if day.energy >= goodEnergyLevel && day.valence != nil && *day.valence > 0 {
+				group.goodDays++
+			}
+		}
+	}
+
+	for name, st := range byActivity {
+		st.AcceptanceRate = roundTo(float64(acceptedBy[name])/float64(st.Served), 3)
+		st.CompletionRate = roundTo(float64(st.Done)/float64(st.Served), 3)
+		st.GoodDayRateWhenDone = done[name].goodDayRate()
+		st.GoodDayRateWhenNotDone = notDone[name].goodDayRate()
+		st.EnergyWhenDone = done[name].averageEnergy()
+		st.EnergyWhenNotDone = notDone[name].averageEnergy()
+		if st.GoodDayRateWhenDone != nil && st.GoodDayRateWhenNotDone != nil && *st.GoodDayRateWhenNotDone > 0 {
+			lift := roundTo(*st.GoodDayRateWhenDone / *st.GoodDayRateWhenNotDone, 2)
+			st.OutcomeLift = &lift
+		}
+		stats.Activities = append(stats.Activities, *st)
+	}
+	stats.Served = len(recommendations)
+	if stats.Served > 0 {
+		stats.AcceptanceRate = roundTo(float64(accepted)/float64(stats.Served), 3)
+		stats.CompletionRate = roundTo(float64(completed)/float64(stats.Served), 3)
+	}
+	sort.Slice(stats.Activities, func(i, j int) bool {
+		if stats.Activities[i].Served != stats.Activities[j].Served {
+			return stats.Activities[i].Served > stats.Activities[j].Served
+		}
+		return stats.Activities[i].Activity < stats.Activities[j].Activity
+	})
+	return stats, nil
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/config"
+	"github.com/aebalz/daily-vibe-tracker/internal/model"
+	"github.com/aebalz/daily-vibe-tracker/internal/repository"
+)
+
+// historyVibes serves a fixed vibe history.
+type historyVibes struct {
+	repository.VibeRepositoryInterface
+	vibes []model.Vibe
+}
+
+func (r *historyVibes) GetVibesForDateRange(startDate, endDate time.Time) ([]model.Vibe, error) {
+	var vibes []model.Vibe
+	for _, vibe := range r.vibes {
+		if !vibe.Date.Before(startDate) && !vibe.Date.After(endDate) {
+			vibes = append(vibes, vibe)
+		}
+	}
+	return vibes, nil
+}
+
+// memRecommendations keeps saved recommendations in memory, without feedback or exclusions.
+type memRecommendations struct {
+	repository.RecommendationRepositoryInterface
+	saved []*model.Recommendation
+}
+
+func (r *memRecommendations) GetNotInterested() ([]model.NotInterestedActivity, error) {
+	return nil, nil
+}
+
+func (r *memRecommendations) GetRecommendationsForDateRange(startDate, endDate time.Time) ([]model.Recommendation, error) {
+	return nil, nil
+}
+
+func (r *memRecommendations) SaveRecommendations(recommendations []*model.Recommendation) error {
+	for _, rec := range recommendations {
+		rec.ID = uint(len(r.saved) + 1)
+		r.saved = append(r.saved, rec)
+	}
+	return nil
+}
+
+// catalogMoods serves a fixed mood catalog.
+type catalogMoods struct {
+	MoodServiceInterface
+	moods []model.Mood
+}
+
+func (s *catalogMoods) GetAllMoods() ([]model.Mood, error) {
+	return s.moods, nil
+}
+
+// recommendationHistory returns 60 days before today: running is done on every good day,
+// cooking and reading on every other good day, and tv on the bad days.
+func recommendationHistory(today time.Time) []model.Vibe {
+	var vibes []model.Vibe
+	for i := 1; i <= 60; i++ {
+		vibe := model.Vibe{Date: today.AddDate(0, 0, -i), Mood: "happy", EnergyLevel: 8}
+		switch i % 3 {
+		case 0:
+			vibe.Activities = []string{"running", "reading"}
+		case 1:
+			vibe.Activities = []string{"running", "cooking"}
+		default:
+			vibe.Mood, vibe.EnergyLevel, vibe.Activities = "sad", 3, []string{"tv"}
+		}
+		vibes = append(vibes, vibe)
+	}
+	return vibes
+}
+
+func newTestRecommendationService(today time.Time) (*RecommendationService, *memRecommendations) {
+	recommendations := &memRecommendations{}
+	moods := &catalogMoods{moods: []model.Mood{{Name: "happy", Valence: 0.8}, {Name: "sad", Valence: -0.6}}}
+	svc := &RecommendationService{
+		RecommendationRepo: recommendations,
+		VibeRepo:           &historyVibes{vibes: recommendationHistory(today)},
+		MoodSvc:            moods,
+		Cfg:                &config.AppConfig{},
+	}
+	return svc, recommendations
+}
+
+func TestRecommendActivitiesIsDeterministicForASeed(t *testing.T) {
+	today := time.Date(2025, 6, 16, 0, 0, 0, 0, time.UTC)
+	svc, _ := newTestRecommendationService(today)
+	opts := RecommendationOptions{Date: today, Limit: MaxRecommendationLimit, Seed: 42}
+
+	first, err := svc.RecommendActivities(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := svc.RecommendActivities(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first.Suggestions) != 3 {
+		t.Fatalf("got %d suggestions, want running, cooking and reading: %+v", len(first.Suggestions), first.Suggestions)
+	}
+	if len(second.Suggestions) != len(first.Suggestions) {
+		t.Fatalf("got %d suggestions, then %d", len(first.Suggestions), len(second.Suggestions))
+	}
+	for i := range first.Suggestions {
+		a, b := first.Suggestions[i], second.Suggestions[i]
+		if a.Activity != b.Activity || a.Score != b.Score {
+			t.Errorf("suggestion %d: got %s (%v), then %s (%v)", i, a.Activity, a.Score, b.Activity, b.Score)
+		}
+	}
+	if first.Seed != 42 {
+		t.Errorf("got seed %d, want 42", first.Seed)
+	}
+}
+
+func TestRecommendActivitiesSeedOnlyChangesTheJitter(t *testing.T) {
+	today := time.Date(2025, 6, 16, 0, 0, 0, 0, time.UTC)
+	svc, _ := newTestRecommendationService(today)
+
+	bySeed := make(map[int64]map[string]model.ActivitySuggestion)
+	for _, seed := range []int64{1, 2} {
+		result, err := svc.RecommendActivities(RecommendationOptions{Date: today, Limit: MaxRecommendationLimit, Seed: seed})
+		if err != nil {
+			t.Fatal(err)
+		}
+		bySeed[seed] = make(map[string]model.ActivitySuggestion)
+		for _, suggestion := range result.Suggestions {
+			bySeed[seed][suggestion.Activity] = suggestion
+		}
+	}
+	if len(bySeed[1]) != len(bySeed[2]) {
+		t.Fatalf("got %d suggestions with seed 1 and %d with seed 2", len(bySeed[1]), len(bySeed[2]))
+	}
+	scoresDiffer := false
+	for name, a := range bySeed[1] {
+		b, ok := bySeed[2][name]
+		if !ok {
+			t.Fatalf("%s is only suggested with seed 1", name)
+		}
+		if a.Lift != b.Lift || a.EnergyDelta != b.EnergyDelta || a.DayOfWeekFit != b.DayOfWeekFit || a.Feedback != b.Feedback || a.Occurrences != b.Occurrences {
+			t.Errorf("%s: signals changed with the seed: %+v and %+v", name, a, b)
+		}
+		// Scores are rounded to 3 decimals, hence the tolerance.
+		if diff := math.Abs(a.Score - b.Score); diff > recommendationJitter+0.001 {
+			t.Errorf("%s: scores %v and %v differ by more than the jitter", name, a.Score, b.Score)
+		}
+		if a.Score != b.Score {
+			scoresDiffer = true
+		}
+	}
+	if !scoresDiffer {
+		t.Error("the seed did not change any score")
+	}
+}
+
+func TestRecommendActivitiesOnlySavesTheCurrentDay(t *testing.T) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	tests := []struct {
+		name  string
+		date  time.Time
+		saved bool
+	}{
+		{"today", today, true},
+		{"past day", today.AddDate(0, 0, -7), false},
+		{"future day", today.AddDate(0, 0, 1), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, recommendations := newTestRecommendationService(today)
+			result, err := svc.RecommendActivities(RecommendationOptions{Date: tt.date, Seed: 7})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(result.Suggestions) == 0 {
+				t.Fatal("no suggestions")
+			}
+			if saved := len(recommendations.saved) > 0; saved != tt.saved {
+				t.Fatalf("saved %d recommendations, want saved=%t", len(recommendations.saved), tt.saved)
+			}
+			for _, suggestion := range result.Suggestions {
+				if hasID := suggestion.ID != 0; hasID != tt.saved {
+					t.Errorf("%s has ID %d, want an ID only when saved", suggestion.Activity, suggestion.ID)
+				}
+			}
+		})
+	}
+}
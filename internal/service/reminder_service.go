@@ -0,0 +1,397 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/mail"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/config"
+	"github.com/aebalz/daily-vibe-tracker/internal/logging"
+	"github.com/aebalz/daily-vibe-tracker/internal/model"
+	"github.com/aebalz/daily-vibe-tracker/internal/notifier"
+	"github.com/aebalz/daily-vibe-tracker/internal/repository"
+)
+
+const (
+	// reminderBatchSize bounds the deliveries attempted per scheduler run.
+	reminderBatchSize  = 100
+	reminderTimeLayout = "15:04"
+	reminderSubject    = "Time to log your vibe"
+)
+
+// reminderWeekdays maps accepted weekday names to their canonical short form.
+var reminderWeekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// ReminderServiceInterface defines the interface for vibe logging reminders.
+type ReminderServiceInterface interface {
+	CreateReminder(reminder *model.Reminder) (*model.Reminder, error)
+	GetReminderByID(id uint) (*model.Reminder, error)
+	GetAllReminders() ([]model.Reminder, error)
+	UpdateReminder(id uint, updatedReminder *model.Reminder) (*model.Reminder, error)
+	DeleteReminder(id uint) error
+	GetDeliveries(reminderID uint, limit, offset int) ([]model.ReminderDelivery, int64, error)
+
+	// RunOnce schedules the reminders due at now and attempts due deliveries.
+	RunOnce(ctx context.Context, now time.Time) error
+	// RunScheduler calls RunOnce every REMINDER_CHECK_INTERVAL until ctx is cancelled.
+	RunScheduler(ctx context.Context)
+}
+
+// ReminderService implements ReminderServiceInterface. All scheduling state lives in the
+// delivery log, so reminders missed while the server was down still fire within the grace
+// period and pending retries resume after a restart.
+type ReminderService struct {
+	ReminderRepo repository.ReminderRepositoryInterface
+	VibeRepo     repository.VibeRepositoryInterface
+	Notifiers    map[string]notifier.Notifier // Available delivery channels
+	Cfg          *config.AppConfig
+	Logger       *slog.Logger
+}
+
+// NewReminderService creates a new ReminderService. A nil logger logs to the default logger.
+func NewReminderService(reminderRepo repository.ReminderRepositoryInterface, vibeRepo repository.VibeRepositoryInterface, notifiers map[string]notifier.Notifier, cfg *config.AppConfig, logger *slog.Logger) ReminderServiceInterface {
+	return &ReminderService{
+		ReminderRepo: reminderRepo,
+		VibeRepo:     vibeRepo,
+		Notifiers:    notifiers,
+		Cfg:          cfg,
+		Logger:       logging.OrDefault(logger),
+	}
+}
+
+// normalizeReminder validates a reminder and brings its fields into canonical form.
+func (s *ReminderService) normalizeReminder(reminder *model.Reminder) error {
+	reminder.Recipient = strings.TrimSpace(reminder.Recipient)
+	reminder.Channel = strings.ToLower(strings.TrimSpace(reminder.Channel))
+	reminder.Address = strings.TrimSpace(reminder.Address)
+	reminder.Time = strings.TrimSpace(reminder.Time)
+	reminder.Timezone = strings.TrimSpace(reminder.Timezone)
+
+	if reminder.Recipient == "" {
+		return fmt.Errorf("%w: recipient cannot be empty", ErrValidation)
+	}
+	if _, ok := s.Notifiers[reminder.Channel]; !ok {
+		channels := make([]string, 0, len(s.Notifiers))
+		for name := range s.Notifiers {
+			channels = append(channels, name)
+		}
+		sort.Strings(channels)
+		return fmt.Errorf("%w: channel '%s' is not available. Available channels: %s", ErrValidation, reminder.Channel, strings.Join(channels, ", "))
+	}
+	switch reminder.Channel {
+	case notifier.ChannelWebhook:
+		u, err := url.Parse(reminder.Address)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: webhook reminders require an http(s) URL as address", ErrValidation)
+		}
+	case notifier.ChannelSMTP:
+		addr, err := mail.ParseAddress(reminder.Address)
+		if err != nil {
+			return fmt.Errorf("%w: smtp reminders require an email address", ErrValidation)
+		}
+		reminder.Address = addr.Address
+	}
+
+	t, err := time.Parse(reminderTimeLayout, reminder.Time)
+	if err != nil {
+		return fmt.Errorf("%w: time must use the HH:MM format", ErrValidation)
+	}
+	reminder.Time = t.Format(reminderTimeLayout)
+
+	if reminder.Timezone == "" {
+		reminder.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(reminder.Timezone); err != nil {
+		return fmt.Errorf("%w: unknown timezone '%s'", ErrValidation, reminder.Timezone)
+	}
+
+	seen := make(map[time.Weekday]bool, len(reminder.Days))
+	for _, d := range reminder.Days {
+		name := strings.ToLower(strings.TrimSpace(d))
+		if len(name) > 3 {
+			name = name[:3] // "monday" -> "mon"
+		}
+		weekday, ok := reminderWeekdays[name]
+		if !ok {
+			return fmt.Errorf("%w: invalid day '%s'. Use mon, tue, wed, thu, fri, sat or sun", ErrValidation, d)
+		}
+		seen[weekday] = true
+	}
+	reminder.Days = []string{}
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		if seen[weekday] {
+			reminder.Days = append(reminder.Days, strings.ToLower(weekday.String()[:3]))
+		}
+	}
+	return nil
+}
+
+// CreateReminder adds a new reminder.
+func (s *ReminderService) CreateReminder(reminder *model.Reminder) (*model.Reminder, error) {
+	reminder.ID = 0
+	if err := s.normalizeReminder(reminder); err != nil {
+		return nil, err
+	}
+	return s.ReminderRepo.CreateReminder(reminder)
+}
+
+// GetReminderByID retrieves a reminder by its ID.
+func (s *ReminderService) GetReminderByID(id uint) (*model.Reminder, error) {
+	return s.ReminderRepo.GetReminderByID(id)
+}
+
+// GetAllReminders retrieves all reminders.
+func (s *ReminderService) GetAllReminders() ([]model.Reminder, error) {
+	return s.ReminderRepo.GetAllReminders(false)
+}
+
+// UpdateReminder modifies an existing reminder. Occurrences before the update do not fire.
+func (s *ReminderService) UpdateReminder(id uint, updatedReminder *model.Reminder) (*model.Reminder, error) {
+	if err := s.normalizeReminder(updatedReminder); err != nil {
+		return nil, err
+	}
+	return s.ReminderRepo.UpdateReminder(id, updatedReminder)
+}
+
+// DeleteReminder removes a reminder and its delivery log.
+func (s *ReminderService) DeleteReminder(id uint) error {
+	return s.ReminderRepo.DeleteReminder(id)
+}
+
+// GetDeliveries retrieves the delivery log of a reminder.
+func (s *ReminderService) GetDeliveries(reminderID uint, limit, offset int) ([]model.ReminderDelivery, int64, error) {
+	if _, err := s.ReminderRepo.GetReminderByID(reminderID); err != nil {
+		return nil, 0, err
+	}
+	if limit <= 0 || limit > MaxLimit {
+		limit = DefaultLimit
+	}
+	if offset < 0 {
+		offset = DefaultOffset
+	}
+	return s.ReminderRepo.GetDeliveries(reminderID, limit, offset)
+}
+
+// RunScheduler processes reminders on a fixed interval, starting immediately so reminders
+// missed during downtime are caught up.
+func (s *ReminderService) RunScheduler(ctx context.Context) {
+	if s.Cfg.ReminderCheckInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(s.Cfg.ReminderCheckInterval)
+	defer ticker.Stop()
+	for {
+		if err := s.RunOnce(ctx, time.Now()); err != nil {
+			s.Logger.WarnContext(ctx, "Reminder run failed", slog.String("error", err.Error()))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce schedules due reminders and attempts due deliveries.
+func (s *ReminderService) RunOnce(ctx context.Context, now time.Time) error {
+	if err := s.scheduleDue(now); err != nil {
+		return err
+	}
+	return s.deliverDue(ctx, now)
+}
+
+// occurrencesDue returns the occurrences of a reminder at or before now and within the grace
+// period. Yesterday is included so a late evening reminder survives a restart after midnight.
+func occurrencesDue(reminder *model.Reminder, now time.Time, grace time.Duration) []time.Time {
+	loc, err := time.LoadLocation(reminder.Timezone)
+	if err != nil {
+		return nil
+	}
+	at, err := time.Parse(reminderTimeLayout, reminder.Time)
+	if err != nil {
+		return nil
+	}
+	days := make(map[string]bool, len(reminder.Days))
+	for _, d := range reminder.Days {
+		days[d] = true
+	}
+	local := now.In(loc)
+	var due []time.Time
+	for offset := -1; offset <= 0; offset++ {
+		day := local.AddDate(0, 0, offset)
+		occurrence := time.Date(day.Year(), day.Month(), day.Day(), at.Hour(), at.Minute(), 0, 0, loc)
+		if occurrence.After(now) || now.Sub(occurrence) > grace || !occurrence.After(reminder.UpdatedAt) {
+			continue
+		}
+		if len(days) > 0 && !days[strings.ToLower(occurrence.Weekday().String()[:3])] {
+			continue
+		}
+		due = append(due, occurrence)
+	}
+	return due
+}
+
+// scheduleDue records a pending delivery for every due occurrence of an enabled reminder.
+func (s *ReminderService) scheduleDue(now time.Time) error {
+	reminders, err := s.ReminderRepo.GetAllReminders(true)
+	if err != nil {
+		return fmt.Errorf("could not load reminders: %w", err)
+	}
+	// A reminder must stay due for at least one check interval, or it could fall between runs.
+	grace := s.Cfg.ReminderGracePeriod
+	if grace < s.Cfg.ReminderCheckInterval {
+		grace = s.Cfg.ReminderCheckInterval
+	}
+	for i := range reminders {
+		reminder := &reminders[i]
+		for _, occurrence := range occurrencesDue(reminder, now, grace) {
+			next := now
+			delivery := &model.ReminderDelivery{
+				ReminderID:    reminder.ID,
+				ScheduledFor:  occurrence,
+				Day:           occurrence.Format(dayKeyLayout),
+				Channel:       reminder.Channel,
+				Address:       reminder.Address,
+				Status:        model.DeliveryStatusPending,
+				NextAttemptAt: &next,
+			}
+			if _, err := s.ReminderRepo.CreateDelivery(delivery); err != nil {
+				return fmt.Errorf("could not schedule reminder %d: %w", reminder.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// vibeLoggedOn reports whether a vibe exists for the day key.
+func (s *ReminderService) vibeLoggedOn(dayKey string) (bool, error) {
+	day, err := time.Parse(dayKeyLayout, dayKey)
+	if err != nil {
+		return false, err
+	}
+	// Vibe dates may carry any time zone, so fetch the neighbouring days and compare keys.
+	vibes, err := s.VibeRepo.GetVibesForDateRange(day.AddDate(0, 0, -1), day.AddDate(0, 0, 2))
+	if err != nil {
+		return false, err
+	}
+	for _, v := range vibes {
+		if v.Date.Format(dayKeyLayout) == dayKey {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// claimLease is how long claimed deliveries are left to this scheduler: long enough to
+// attempt a whole batch, each send bounded by NOTIFIER_TIMEOUT. Deliveries of a scheduler
+// that stops mid-batch are picked up by another one once it runs out.
+func (s *ReminderService) claimLease() time.Duration {
+	return s.Cfg.NotifierTimeout*reminderBatchSize + time.Minute
+}
+
+// deliverDue claims and attempts pending deliveries, so that schedulers of several
+// instances never send the same reminder twice. A delivery is skipped instead of sent when
+// a vibe was logged for its day in the meantime; failures are retried with exponential
+// backoff.
+func (s *ReminderService) deliverDue(ctx context.Context, now time.Time) error {
+	deliveries, err := s.ReminderRepo.ClaimDueDeliveries(now, s.claimLease(), reminderBatchSize)
+	if err != nil {
+		return fmt.Errorf("could not load due reminder deliveries: %w", err)
+	}
+	for i := range deliveries {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		delivery := &deliveries[i]
+		s.attemptDelivery(ctx, delivery, now)
+		if err := s.ReminderRepo.UpdateDelivery(delivery); err != nil {
+			return fmt.Errorf("could not save reminder delivery %d: %w", delivery.ID, err)
+		}
+	}
+	return nil
+}
+
+// attemptDelivery makes one delivery attempt and records its outcome on the delivery.
+func (s *ReminderService) attemptDelivery(ctx context.Context, delivery *model.ReminderDelivery, now time.Time) {
+	reminder, err := s.ReminderRepo.GetReminderByID(delivery.ReminderID)
+	if err != nil || !reminder.Enabled {
+		delivery.Status = model.DeliveryStatusSkipped
+		delivery.NextAttemptAt = nil
+		return
+	}
+	logged, err := s.vibeLoggedOn(delivery.Day)
+	if err != nil {
+		s.recordFailure(ctx, delivery, now, fmt.Errorf("could not check for a vibe: %w", err))
+		return
+	}
+	if logged {
+		delivery.Status = model.DeliveryStatusSkipped
+		delivery.NextAttemptAt = nil
+		return
+	}
+	n, ok := s.Notifiers[delivery.Channel]
+	if !ok {
+		// Retrying cannot help until the channel is configured.
+		delivery.Attempts++
+		delivery.Status = model.DeliveryStatusFailed
+		delivery.NextAttemptAt = nil
+		delivery.LastError = fmt.Sprintf("channel '%s' is not available", delivery.Channel)
+		return
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, s.Cfg.NotifierTimeout)
+	defer cancel()
+	err = n.Send(sendCtx, reminderMessage(reminder, delivery))
+	if err != nil {
+		s.recordFailure(ctx, delivery, now, err)
+		return
+	}
+	delivery.Attempts++
+	delivery.Status = model.DeliveryStatusSent
+	delivery.SentAt = &now
+	delivery.NextAttemptAt = nil
+	delivery.LastError = ""
+}
+
+// recordFailure counts a failed attempt and schedules a retry after
+// REMINDER_RETRY_BACKOFF * 2^(attempts-1), or gives up after REMINDER_MAX_ATTEMPTS.
+func (s *ReminderService) recordFailure(ctx context.Context, delivery *model.ReminderDelivery, now time.Time, err error) {
+	delivery.Attempts++
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= s.Cfg.ReminderMaxAttempts {
+		delivery.Status = model.DeliveryStatusFailed
+		delivery.NextAttemptAt = nil
+		s.Logger.WarnContext(ctx, "Reminder delivery failed", slog.Uint64("delivery_id", uint64(delivery.ID)), slog.Int("attempts", delivery.Attempts), slog.String("error", err.Error()))
+		return
+	}
+	next := now.Add(s.Cfg.ReminderRetryBackoff << (delivery.Attempts - 1))
+	delivery.NextAttemptAt = &next
+}
+
+// reminderMessage builds the notification for a delivery.
+func reminderMessage(reminder *model.Reminder, delivery *model.ReminderDelivery) notifier.Message {
+	body := reminder.Message
+	if body == "" {
+		day, _ := time.Parse(dayKeyLayout, delivery.Day)
+		body = fmt.Sprintf("Hi %s, you haven't logged your vibe for %s yet. How are you feeling today?", reminder.Recipient, day.Format("Monday, January 2"))
+	}
+	return notifier.Message{
+		To:      delivery.Address,
+		Subject: reminderSubject,
+		Body:    body,
+		Data: map[string]interface{}{
+			"reminder_id":   reminder.ID,
+			"recipient":     reminder.Recipient,
+			"day":           delivery.Day,
+			"scheduled_for": delivery.ScheduledFor,
+		},
+	}
+}
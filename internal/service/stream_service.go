@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/config"
+	"github.com/aebalz/daily-vibe-tracker/internal/events"
+	"github.com/aebalz/daily-vibe-tracker/internal/model"
+	"github.com/aebalz/daily-vibe-tracker/internal/repository"
+	"github.com/aebalz/daily-vibe-tracker/internal/stream"
+)
+
+// StreamEventSummary is the stream event carrying a refreshed model.LiveSummary.
+const StreamEventSummary = "summary"
+
+// StreamServiceInterface defines the interface for real-time vibe updates.
+type StreamServiceInterface interface {
+	// Subscribe connects a client of the user; see stream.Hub.Subscribe.
+	Subscribe(user, lastEventID string) (client *stream.Client, missed []stream.Message, complete bool, err error)
+	Unsubscribe(client *stream.Client)
+	// GetLiveSummary returns today's vibe and the current streaks.
+	GetLiveSummary(now time.Time) (*model.LiveSummary, error)
+	// HandleEvent pushes a vibe event and a refreshed summary to stream clients.
+	// It is meant to be subscribed to the event bus.
+	HandleEvent(ctx context.Context, event events.Event) error
+	// HeartbeatInterval is how often idle connections get a heartbeat.
+	HeartbeatInterval() time.Duration
+}
+
+// StreamService implements StreamServiceInterface.
+type StreamService struct {
+	Hub      *stream.Hub
+	VibeRepo repository.VibeRepositoryInterface
+	Cfg      *config.AppConfig
+}
+
+// NewStreamService creates a new StreamService.
+func NewStreamService(hub *stream.Hub, vibeRepo repository.VibeRepositoryInterface, cfg *config.AppConfig) StreamServiceInterface {
+	return &StreamService{
+		Hub:      hub,
+		VibeRepo: vibeRepo,
+		Cfg:      cfg,
+	}
+}
+
+// Subscribe connects a client of the user.
+func (s *StreamService) Subscribe(user, lastEventID string) (*stream.Client, []stream.Message, bool, error) {
+	return s.Hub.Subscribe(user, lastEventID)
+}
+
+// Unsubscribe disconnects a client.
+func (s *StreamService) Unsubscribe(client *stream.Client) {
+	s.Hub.Unsubscribe(client)
+}
+
+// HeartbeatInterval returns STREAM_HEARTBEAT_INTERVAL.
+func (s *StreamService) HeartbeatInterval() time.Duration {
+	return s.Cfg.StreamHeartbeatInterval
+}
+
+// GetLiveSummary returns today's vibe, the logging streak and the streak of today's mood.
+// Until today is logged, the logging streak counts up to yesterday, since it is not broken yet.
+func (s *StreamService) GetLiveSummary(now time.Time) (*model.LiveSummary, error) {
+	dayKey := now.Format(dayKeyLayout)
+	summary := &model.LiveSummary{Date: dayKey}
+
+	// Vibe dates may carry any time zone, so fetch the neighbouring days and compare keys.
+	vibes, err := s.VibeRepo.GetVibesForDateRange(now.AddDate(0, 0, -2), now.AddDate(0, 0, 2))
+	if err != nil {
+		return nil, fmt.Errorf("could not load today's vibe: %w", err)
+	}
+	for i := range vibes {
+		if vibes[i].Date.Format(dayKeyLayout) == dayKey {
+			summary.Today = &vibes[i]
+			break
+		}
+	}
+
+	streakEnd := now
+	if summary.Today == nil {
+		streakEnd = now.AddDate(0, 0, -1)
+	}
+	if summary.LoggingStreak, err = loggingStreakEndingOn(s.VibeRepo, streakEnd); err != nil {
+		return nil, fmt.Errorf("could not compute logging streak: %w", err)
+	}
+	if summary.Today != nil {
+		if summary.MoodStreak, err = s.VibeRepo.GetMoodStreak(summary.Today.Mood, true); err != nil {
+			return nil, fmt.Errorf("could not compute mood streak: %w", err)
+		}
+	}
+	return summary, nil
+}
+
+// streamPayload returns the data sent to stream clients for a vibe event.
+func streamPayload(event events.Event) interface{} {
+	switch e := event.(type) {
+	case events.VibeCreated:
+		return e.Vibe
+	case events.VibeUpdated:
+		return map[string]interface{}{"vibe": e.After, "changes": e.Changes}
+	case events.VibeDeleted:
+		return map[string]interface{}{"id": e.Vibe.ID, "date": e.Vibe.Date}
+	case events.BulkImported:
+		return map[string]interface{}{"count": e.Count}
+	}
+	return nil
+}
+
+// HandleEvent publishes the vibe event followed by a refreshed summary. Vibes are not owned
+// by users yet, so both go to every client, anonymous or authenticated.
+func (s *StreamService) HandleEvent(ctx context.Context, event events.Event) error {
+	msg, err := stream.NewMessage(event.EventType(), streamPayload(event))
+	if err != nil {
+		return err
+	}
+	s.Hub.Publish(msg)
+
+	summary, err := s.GetLiveSummary(time.Now())
+	if err != nil {
+		return err
+	}
+	msg, err = stream.NewMessage(StreamEventSummary, summary)
+	if err != nil {
+		return err
+	}
+	s.Hub.Publish(msg)
+	return nil
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/config"
+	"github.com/aebalz/daily-vibe-tracker/internal/logging"
+	"github.com/aebalz/daily-vibe-tracker/internal/model"
+	"github.com/aebalz/daily-vibe-tracker/internal/repository"
+	"gorm.io/gorm"
+)
+
+const (
+	// APIKeyPrefix starts every API key, so leaked keys are easy to recognize.
+	APIKeyPrefix   = "vbt_"
+	apiKeyBytes    = 24
+	apiKeyShownLen = len(APIKeyPrefix) + 8
+	// apiKeyTouchInterval limits how often the last-used time of a key is written.
+	apiKeyTouchInterval = time.Minute
+)
+
+// ErrInvalidAPIKey is returned for unknown and expired API keys.
+var ErrInvalidAPIKey = errors.New("invalid API key")
+
+var usernamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{1,31}$`)
+
+// UserServiceInterface defines the interface for users and their API keys.
+type UserServiceInterface interface {
+	CreateUser(user *model.User) (*model.User, error)
+	// CreateAPIKey creates a key for a user and returns it with its record. The key cannot be
+	// retrieved again. A nil expiresAt creates a key that does not expire.
+	CreateAPIKey(username, name string, expiresAt *time.Time) (string, *model.APIKey, error)
+	// Authenticate returns the user of an API key, or ErrInvalidAPIKey.
+	Authenticate(key string) (*model.User, error)
+
+	// WithContext returns a service whose queries and log lines carry ctx, e.g. the request
+	// ID of an HTTP request.
+	WithContext(ctx context.Context) UserServiceInterface
+}
+
+// UserService implements UserServiceInterface.
+type UserService struct {
+	UserRepo repository.UserRepositoryInterface
+	Cfg      *config.AppConfig
+	Logger   *slog.Logger
+	ctx      context.Context // Context of the requests, set by WithContext
+}
+
+// NewUserService creates a new UserService. A nil logger logs to the default logger.
+func NewUserService(userRepo repository.UserRepositoryInterface, cfg *config.AppConfig, logger *slog.Logger) UserServiceInterface {
+	return &UserService{
+		UserRepo: userRepo,
+		Cfg:      cfg,
+		Logger:   logging.OrDefault(logger),
+	}
+}
+
+// WithContext returns a copy of the service, and of its repository, bound to ctx.
+func (s *UserService) WithContext(ctx context.Context) UserServiceInterface {
+	copied := *s
+	copied.ctx = ctx
+	copied.UserRepo = s.UserRepo.WithContext(ctx)
+	return &copied
+}
+
+// context returns the context set by WithContext, or the background context.
+func (s *UserService) context() context.Context {
+	if s.ctx == nil {
+		return context.Background()
+	}
+	return s.ctx
+}
+
+// hashAPIKey returns the hex SHA-256 of a key. Keys are random, so an unsalted hash is enough.
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateUser adds a user with a lowercased username.
+func (s *UserService) CreateUser(user *model.User) (*model.User, error) {
+	user.ID = 0
+	user.Username = strings.ToLower(strings.TrimSpace(user.Username))
+	user.Email = strings.TrimSpace(user.Email)
+	if !usernamePattern.MatchString(user.Username) {
+		return nil, fmt.Errorf("%w: username must be 2-32 characters of a-z, 0-9, '.', '_' or '-'", ErrValidation)
+	}
+	if user.Email != "" && !strings.Contains(user.Email, "@") {
+		return nil, fmt.Errorf("%w: invalid email '%s'", ErrValidation, user.Email)
+	}
+	if _, err := s.UserRepo.GetUserByUsername(user.Username); err == nil {
+		return nil, fmt.Errorf("%w: user '%s' already exists", ErrValidation, user.Username)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	return s.UserRepo.CreateUser(user)
+}
+
+// CreateAPIKey generates a random key for a user and stores its hash.
+func (s *UserService) CreateAPIKey(username, name string, expiresAt *time.Time) (string, *model.APIKey, error) {
+	user, err := s.UserRepo.GetUserByUsername(strings.ToLower(strings.TrimSpace(username)))
+	if err != nil {
+		return "", nil, err
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return "", nil, fmt.Errorf("%w: expiry must be in the future", ErrValidation)
+	}
+
+	secret := make([]byte, apiKeyBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", nil, fmt.Errorf("could not generate API key: %w", err)
+	}
+	key := APIKeyPrefix + hex.EncodeToString(secret)
+	record, err := s.UserRepo.CreateAPIKey(&model.APIKey{
+		UserID:    user.ID,
+		Name:      strings.TrimSpace(name),
+		Prefix:    key[:apiKeyShownLen],
+		KeyHash:   hashAPIKey(key),
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	record.User = *user
+	return key, record, nil
+}
+
+// Authenticate looks up a key by its hash and records its use at most once a minute.
+func (s *UserService) Authenticate(key string) (*model.User, error) {
+	if !strings.HasPrefix(key, APIKeyPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+	record, err := s.UserRepo.GetAPIKeyByHash(hashAPIKey(key))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+	now := time.Now()
+	if record.Expired(now) {
+		return nil, ErrInvalidAPIKey
+	}
+	if record.LastUsedAt == nil || now.Sub(*record.LastUsedAt) >= apiKeyTouchInterval {
+		if err := s.UserRepo.TouchAPIKey(record.ID, now); err != nil {
+			s.Logger.WarnContext(s.context(), "Could not record the use of an API key", slog.Uint64("api_key_id", uint64(record.ID)), slog.String("error", err.Error()))
+		}
+	}
+	return &record.User, nil
+}
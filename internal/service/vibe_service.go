@@ -1,14 +1,24 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"math/rand"
+	"log/slog"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/aebalz/daily-vibe-tracker/internal/config"
+	"github.com/aebalz/daily-vibe-tracker/internal/events"
+	"github.com/aebalz/daily-vibe-tracker/internal/logging"
+	"github.com/aebalz/daily-vibe-tracker/internal/metrics"
 	"github.com/aebalz/daily-vibe-tracker/internal/model"
 	"github.com/aebalz/daily-vibe-tracker/internal/repository"
+	"github.com/aebalz/daily-vibe-tracker/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
 	// "github.com/go-playground/validator/v10" // Example for more complex validation
 )
 
@@ -21,40 +31,92 @@ const (
 	DefaultSortOrder = "desc"
 )
 
+// ErrValidation is wrapped by errors caused by invalid vibe input (as opposed to storage failures).
+var ErrValidation = errors.New("validation error")
+
+// sortableVibeFields lists the vibe columns that can be used for sorting.
+// Custom metrics can be sorted on with the "metric:<name>" form.
+var sortableVibeFields = map[string]bool{
+	"id": true, "date": true, "mood": true, "energy_level": true, "created_at": true, "updated_at": true,
+}
+
 // VibeServiceInterface defines the interface for vibe service operations.
 type VibeServiceInterface interface {
 	CreateVibe(vibe *model.Vibe) (*model.Vibe, error)
 	GetVibeByID(id uint) (*model.Vibe, error)
+	// GetVibesByIDs retrieves several vibes in one lookup; missing IDs are skipped.
+	GetVibesByIDs(ids []uint) ([]model.Vibe, error)
 	GetAllVibes(filters map[string]interface{}, limit, offset int, sortBy, sortOrder string) ([]model.Vibe, int64, error)
 	UpdateVibe(id uint, updatedVibe *model.Vibe) (*model.Vibe, error)
 	DeleteVibe(id uint) error
 
 	GetVibeStatistics(period string) (map[string]interface{}, error)
-	GetTodaysVibeRecommendation() (map[string]interface{}, error)
 	GetMoodStreak(mood string) (map[string]interface{}, error)
 
 	ExportVibes(filters map[string]interface{}, format string, sortBy, sortOrder string) ([]byte, string, error)
 	BulkImportVibes(vibes []*model.Vibe) (int64, error)
 
+	// WithContext returns a service whose queries and log lines carry ctx, e.g. the request
+	// ID of an HTTP request. Handlers call it once per request.
+	WithContext(ctx context.Context) VibeServiceInterface
+
 	// ValidateVibe(vibe *model.Vibe) error // Example for a validation helper
 }
 
 // VibeService implements VibeServiceInterface.
 type VibeService struct {
-	VibeRepo repository.VibeRepositoryInterface
-	Cfg      *config.AppConfig // To access CacheTTLExpiration etc.
+	VibeRepo    repository.VibeRepositoryInterface
+	MoodSvc     MoodServiceInterface     // Mood catalog used to normalize moods on write
+	ActivitySvc ActivityServiceInterface // Activity catalog used to normalize activities on write
+	MetricSvc   MetricServiceInterface   // Custom metric definitions used to validate metric values
+	Outbox      *events.Outbox           // Records vibe events with each write and publishes them after the commit
+	Cfg         *config.AppConfig        // To access CacheTTLExpiration etc.
+	Logger      *slog.Logger
+	ctx         context.Context // Context of the requests, set by WithContext
 	// validate *validator.Validate // For struct validation if needed
 }
 
-// NewVibeService creates a new VibeService.
-func NewVibeService(vibeRepo repository.VibeRepositoryInterface, cfg *config.AppConfig) VibeServiceInterface {
+// NewVibeService creates a new VibeService. A nil logger logs to the default logger.
+func NewVibeService(vibeRepo repository.VibeRepositoryInterface, moodSvc MoodServiceInterface, activitySvc ActivityServiceInterface, metricSvc MetricServiceInterface, outbox *events.Outbox, cfg *config.AppConfig, logger *slog.Logger) VibeServiceInterface {
 	return &VibeService{
-		VibeRepo: vibeRepo,
-		Cfg:      cfg,
+		VibeRepo:    vibeRepo,
+		MoodSvc:     moodSvc,
+		ActivitySvc: activitySvc,
+		MetricSvc:   metricSvc,
+		Outbox:      outbox,
+		Cfg:         cfg,
+		Logger:      logging.OrDefault(logger),
 		// validate: validator.New(), // Initialize validator
 	}
 }
 
+// WithContext returns a copy of the service, and of its repository and catalog services,
+// bound to ctx.
+func (s *VibeService) WithContext(ctx context.Context) VibeServiceInterface {
+	copied := *s
+	copied.ctx = ctx
+	copied.VibeRepo = s.VibeRepo.WithContext(ctx)
+	copied.MoodSvc = s.MoodSvc.WithContext(ctx)
+	copied.ActivitySvc = s.ActivitySvc.WithContext(ctx)
+	copied.MetricSvc = s.MetricSvc.WithContext(ctx)
+	return &copied
+}
+
+// context returns the context set by WithContext, or the background context.
+func (s *VibeService) context() context.Context {
+	if s.ctx == nil {
+		return context.Background()
+	}
+	return s.ctx
+}
+
+// startSpan starts the span of a service method and returns a copy of the service bound to
+// it, so that the queries of the method are its children. End the span with tracing.End.
+func (s *VibeService) startSpan(method string, attrs ...attribute.KeyValue) (*VibeService, trace.Span) {
+	ctx, span := tracing.Start(s.context(), "VibeService."+method, attrs...)
+	return s.WithContext(ctx).(*VibeService), span
+}
+
 // --- Cache Key Generators ---
 func getVibeCacheKey(id uint) string {
 	return fmt.Sprintf("vibe:%d", id)
@@ -70,12 +132,13 @@ func getVibeStatsCacheKey(period string) string {
 }
 
 // --- Helper for Cache Invalidation ---
+// Once a cache exists, invalidation belongs in a vibe event subscriber (see VibeSubscribers).
 // func (s *VibeService) invalidateVibeCache(id uint) {
 // 	if s.Cache != nil {
 // 		key := getVibeCacheKey(id)
 // 		err := s.Cache.Delete(context.Background(), key)
 // 		if err != nil {
-// 			fmt.Printf("Warning: failed to delete vibe %d from cache: %v\n", id, err)
+// 			s.Logger.WarnContext(s.context(), "Failed to delete vibe from cache", "vibe_id", id, "error", err)
 // 		}
 // 	}
 // }
@@ -87,7 +150,7 @@ func getVibeStatsCacheKey(period string) string {
 // 		key := getVibeStatsCacheKey(period)
 // 		err := s.Cache.Delete(context.Background(), key)
 // 		if err != nil {
-// 			fmt.Printf("Warning: failed to delete stats cache for period %s: %v\n", period, err)
+// 			s.Logger.WarnContext(s.context(), "Failed to delete stats from cache", "period", period, "error", err)
 // 		}
 // 		// Potentially invalidate all stats keys if a vibe change could affect multiple periods
 // 		// e.g., s.Cache.DeletePattern(context.Background(), "stats:*")
@@ -104,6 +167,9 @@ func (s *VibeService) ValidateVibe(vibe *model.Vibe) error {
 	if strings.TrimSpace(vibe.Mood) == "" {
 		return fmt.Errorf("mood cannot be empty")
 	}
+	if err := s.validateVibeMetrics(vibe); err != nil {
+		return err
+	}
 	// Example: Check if date is not in the future (if that's a rule)
 	// if vibe.Date.After(time.Now()) {
 	// 	return fmt.Errorf("vibe date cannot be in the future")
@@ -111,29 +177,120 @@ func (s *VibeService) ValidateVibe(vibe *model.Vibe) error {
 	return nil
 }
 
+// validateVibeMetrics checks custom metric values against their definitions and prepares
+// them for storage. A nil Metrics map leaves existing values untouched on update.
+func (s *VibeService) validateVibeMetrics(vibe *model.Vibe) error {
+	values, normalized, err := s.MetricSvc.ResolveVibeMetrics(vibe.Metrics)
+	if err != nil {
+		return err
+	}
+	vibe.MetricValues = values
+	vibe.Metrics = normalized
+	return nil
+}
+
+// parseMetricFilters converts raw "name:op:value" expressions in filters["metric_filters"]
+// into filters["metrics"] for the repository.
+func (s *VibeService) parseMetricFilters(filters map[string]interface{}) error {
+	rawFilters, ok := filters["metric_filters"].([]string)
+	if !ok {
+		return nil
+	}
+	delete(filters, "metric_filters")
+	parsed := make([]model.MetricFilter, 0, len(rawFilters))
+	for _, raw := range rawFilters {
+		f, err := s.MetricSvc.ParseMetricFilter(raw)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrValidation, err)
+		}
+		parsed = append(parsed, f)
+	}
+	filters["metrics"] = parsed
+	return nil
+}
+
+// normalizeSortBy returns sortBy if it is a sortable vibe field or a defined custom metric,
+// falling back to the default sort field otherwise.
+func (s *VibeService) normalizeSortBy(sortBy string) string {
+	sortBy = strings.ToLower(strings.TrimSpace(sortBy))
+	if sortableVibeFields[sortBy] {
+		return sortBy
+	}
+	if metricName, ok := strings.CutPrefix(sortBy, repository.MetricSortPrefix); ok && s.MetricSvc.IsMetricDefined(metricName) {
+		return repository.MetricSortPrefix + normalizeMetricName(metricName)
+	}
+	return DefaultSortBy
+}
+
+// normalizeVibeMood resolves the vibe's mood against the mood catalog and replaces it
+// with the canonical name, applying the configured unknown-mood policy.
+func (s *VibeService) normalizeVibeMood(vibe *model.Vibe) error {
+	mood, err := s.MoodSvc.ResolveMood(vibe.Mood)
+	if err != nil {
+		return err
+	}
+	vibe.Mood = mood.Name
+	return nil
+}
+
+// normalizeVibeActivities resolves the vibe's activities against the activity catalog,
+// so that "Gym", "gym " and aliases like "workout" are all stored under one canonical name.
+func (s *VibeService) normalizeVibeActivities(vibe *model.Vibe) error {
+	activities, err := s.ActivitySvc.NormalizeActivities(vibe.Activities)
+	if err != nil {
+		return err
+	}
+	vibe.Activities = activities
+	return nil
+}
+
+// canonicalMoodName maps a mood name or alias to its canonical catalog name for lookups.
+// Unknown moods are returned lowercased and trimmed, without applying the unknown-mood policy.
+func (s *VibeService) canonicalMoodName(name string) string {
+	if mood, err := s.MoodSvc.LookupMood(name); err == nil {
+		return mood.Name
+	}
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
 // CreateVibe handles the business logic for creating a new vibe.
-func (s *VibeService) CreateVibe(vibe *model.Vibe) (*model.Vibe, error) {
+func (s *VibeService) CreateVibe(vibe *model.Vibe) (_ *model.Vibe, err error) {
+	s, span := s.startSpan("CreateVibe")
+	defer func() { tracing.End(span, err) }()
+
 	if err := s.ValidateVibe(vibe); err != nil {
-		return nil, fmt.Errorf("validation error: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrValidation, err)
 	}
-	// Additional business logic before saving, if any.
-	// For example, normalizing mood strings to lowercase.
-	vibe.Mood = strings.ToLower(strings.TrimSpace(vibe.Mood))
 
-	createdVibe, err := s.VibeRepo.CreateVibe(vibe)
+	var createdVibe *model.Vibe
+	err = s.write(func(tx *VibeService) ([]events.Event, error) {
+		// Normalize the mood and activities against the catalogs (aliases -> canonical name).
+		if err := tx.normalizeVibeMood(vibe); err != nil {
+			return nil, err
+		}
+		if err := tx.normalizeVibeActivities(vibe); err != nil {
+			return nil, err
+		}
+		created, err := tx.VibeRepo.CreateVibe(vibe)
+		if err != nil {
+			return nil, err
+		}
+		createdVibe = created
+		return []events.Event{events.VibeCreated{Vibe: *created}}, nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	// Invalidate stats cache as new data might change statistics
-	// s.invalidateStatsCache("week") // Invalidate all relevant periods or use a pattern
-	// s.invalidateStatsCache("month")
-	// s.invalidateStatsCache("year")
+	metrics.ObserveVibeCreated(metrics.SourceAPI, createdVibe.EnergyLevel)
 	// No need to invalidate GetVibeByID cache for a newly created vibe, as it won't be cached yet by its ID.
 	return createdVibe, nil
 }
 
 // GetVibeByID retrieves a single vibe by its ID, using cache if available.
-func (s *VibeService) GetVibeByID(id uint) (*model.Vibe, error) {
+func (s *VibeService) GetVibeByID(id uint) (_ *model.Vibe, err error) {
+	s, span := s.startSpan("GetVibeByID", attribute.Int("vibe.id", int(id)))
+	defer func() { tracing.End(span, err) }()
+
 	// if s.Cache != nil {
 	// 	var vibe model.Vibe
 	// 	cacheKey := getVibeCacheKey(id)
@@ -152,26 +309,35 @@ func (s *VibeService) GetVibeByID(id uint) (*model.Vibe, error) {
 	// if s.Cache != nil && vibe != nil { // vibe != nil to avoid caching non-existent records that returned error
 	// 	cacheKey := getVibeCacheKey(id)
 	// 	if err := s.Cache.Set(context.Background(), cacheKey, vibe); err != nil {
-	// 		fmt.Printf("Warning: failed to set vibe %d in cache: %v\n", id, err)
+	// 		s.Logger.WarnContext(s.context(), "Failed to cache vibe", "vibe_id", id, "error", err)
 	// 	}
 	// }
 	return vibe, nil
 }
 
+// GetVibesByIDs retrieves the vibes with the given IDs, e.g. for batched lookups.
+func (s *VibeService) GetVibesByIDs(ids []uint) (_ []model.Vibe, err error) {
+	s, span := s.startSpan("GetVibesByIDs", attribute.Int("vibe.count", len(ids)))
+	defer func() { tracing.End(span, err) }()
+
+	return s.VibeRepo.GetVibesByIDs(ids)
+}
+
 // GetAllVibes retrieves vibes with filters, pagination, and sorting.
 // Caching for GetAllVibes can be complex due to various filter combinations.
 // Consider caching only for very common filter sets or use a very short TTL if implemented.
 // For now, not caching GetAllVibes.
-func (s *VibeService) GetAllVibes(filters map[string]interface{}, limit, offset int, sortBy, sortOrder string) ([]model.Vibe, int64, error) {
+func (s *VibeService) GetAllVibes(filters map[string]interface{}, limit, offset int, sortBy, sortOrder string) (_ []model.Vibe, _ int64, err error) {
+	s, span := s.startSpan("GetAllVibes", attribute.Int("limit", limit), attribute.Int("offset", offset))
+	defer func() { tracing.End(span, err) }()
+
 	if limit <= 0 || limit > MaxLimit {
 		limit = DefaultLimit
 	}
 	if offset < 0 {
 		offset = DefaultOffset
 	}
-	if sortBy == "" {
-		sortBy = DefaultSortBy
-	}
+	sortBy = s.normalizeSortBy(sortBy)
 	if sortOrder == "" {
 		sortOrder = DefaultSortOrder
 	} else {
@@ -183,65 +349,93 @@ func (s *VibeService) GetAllVibes(filters map[string]interface{}, limit, offset
 
 	// Sanitize/validate filter values if necessary
 	if mood, ok := filters["mood"].(string); ok {
-		filters["mood"] = strings.ToLower(strings.TrimSpace(mood))
+		filters["mood"] = s.canonicalMoodName(mood)
+	}
+	if err := s.parseMetricFilters(filters); err != nil {
+		return nil, 0, err
 	}
 
 	return s.VibeRepo.GetAllVibes(filters, limit, offset, sortBy, sortOrder)
 }
 
 // UpdateVibe handles the business logic for updating an existing vibe.
-func (s *VibeService) UpdateVibe(id uint, updatedVibe *model.Vibe) (*model.Vibe, error) {
+func (s *VibeService) UpdateVibe(id uint, updatedVibe *model.Vibe) (_ *model.Vibe, err error) {
+	s, span := s.startSpan("UpdateVibe", attribute.Int("vibe.id", int(id)))
+	defer func() { tracing.End(span, err) }()
+
 	if err := s.ValidateVibe(updatedVibe); err != nil {
-		return nil, fmt.Errorf("validation error: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrValidation, err)
 	}
-	// Ensure mood is consistent
-	updatedVibe.Mood = strings.ToLower(strings.TrimSpace(updatedVibe.Mood))
 
 	// The repository's UpdateVibe should fetch the existing record first.
 	// Additional service-level checks can be done here if needed,
 	// e.g., checking if the user is authorized to update this vibe (if users were implemented).
-	resultVibe, err := s.VibeRepo.UpdateVibe(id, updatedVibe)
+	var resultVibe *model.Vibe
+	err = s.write(func(tx *VibeService) ([]events.Event, error) {
+		before, err := tx.VibeRepo.GetVibeByID(id)
+		if err != nil {
+			return nil, err
+		}
+		// Ensure mood and activities are consistent with the catalogs
+		if err := tx.normalizeVibeMood(updatedVibe); err != nil {
+			return nil, err
+		}
+		if err := tx.normalizeVibeActivities(updatedVibe); err != nil {
+			return nil, err
+		}
+		after, err := tx.VibeRepo.UpdateVibe(id, updatedVibe)
+		if err != nil {
+			return nil, err
+		}
+		resultVibe = after
+		return []events.Event{events.VibeUpdated{Before: *before, After: *after, Changes: events.DiffVibes(*before, *after)}}, nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	// Invalidate caches
-	// s.invalidateVibeCache(id)
-	// s.invalidateStatsCache("week")
-	// s.invalidateStatsCache("month")
-	// s.invalidateStatsCache("year")
 	return resultVibe, nil
 }
 
-// DeleteVibe handles the business logic for deleting a vibe.
-func (s *VibeService) DeleteVibe(id uint) error {
-	// Add any business logic before deletion if needed.
-	err := s.VibeRepo.DeleteVibe(id)
-	if err != nil {
+// write runs fn against a copy of the service whose vibe repository and mood and activity
+// catalogs are bound to one transaction, and records the events fn returns in the outbox in
+// that transaction. Subscribers see the events only after the commit, and moods and
+// activities created on the way are rolled back with a failed write. Without an outbox the write runs on its own and
+// no events are published.
+func (s *VibeService) write(fn func(tx *VibeService) ([]events.Event, error)) error {
+	if s.Outbox == nil {
+		_, err := fn(s)
 		return err
 	}
-	// Invalidate caches
-	// s.invalidateVibeCache(id)
-	// s.invalidateStatsCache("week")
-	// s.invalidateStatsCache("month")
-	// s.invalidateStatsCache("year")
-	return nil
+	return s.Outbox.Write(s.context(), func(tx *gorm.DB) ([]events.Event, error) {
+		copied := *s
+		copied.VibeRepo = s.VibeRepo.WithTx(tx).WithContext(s.context())
+		copied.MoodSvc = s.MoodSvc.WithTx(tx).WithContext(s.context())
+		copied.ActivitySvc = s.ActivitySvc.WithTx(tx).WithContext(s.context())
+		return fn(&copied)
+	})
 }
 
-// GetVibeStatistics calculates and returns vibe statistics, using cache if available.
-func (s *VibeService) GetVibeStatistics(period string) (map[string]interface{}, error) {
-	// cacheKey := getVibeStatsCacheKey(period)
-	// if s.Cache != nil {
-	// 	var stats map[string]interface{}
-	// 	if err := s.Cache.Get(context.Background(), cacheKey, &stats); err == nil {
-	// 		// Cache hit
-	// 		return stats, nil
-	// 	}
-	// 	// Cache miss or error, proceed to compute
-	// }
+// DeleteVibe handles the business logic for deleting a vibe.
+func (s *VibeService) DeleteVibe(id uint) (err error) {
+	s, span := s.startSpan("DeleteVibe", attribute.Int("vibe.id", int(id)))
+	defer func() { tracing.End(span, err) }()
 
-	var startDate, endDate time.Time
-	now := time.Now()
+	// Add any business logic before deletion if needed.
+	return s.write(func(tx *VibeService) ([]events.Event, error) {
+		deleted, err := tx.VibeRepo.GetVibeByID(id)
+		if err != nil {
+			return nil, err
+		}
+		if err := tx.VibeRepo.DeleteVibe(id); err != nil {
+			return nil, err
+		}
+		return []events.Event{events.VibeDeleted{Vibe: *deleted}}, nil
+	})
+}
 
+// periodDateRange returns the start and end of the week, month or year containing now.
+// Unknown periods default to the current month.
+func periodDateRange(period string, now time.Time) (startDate, endDate time.Time) {
 	// Determine date range based on period
 	switch strings.ToLower(period) {
 	case "week":
@@ -265,6 +459,25 @@ func (s *VibeService) GetVibeStatistics(period string) (map[string]interface{},
 		startDate = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
 		endDate = startDate.AddDate(0, 1, -1).Add(23*time.Hour + 59*time.Minute + 59*time.Second)
 	}
+	return startDate, endDate
+}
+
+// GetVibeStatistics calculates and returns vibe statistics, using cache if available.
+func (s *VibeService) GetVibeStatistics(period string) (_ map[string]interface{}, err error) {
+	s, span := s.startSpan("GetVibeStatistics", attribute.String("period", period))
+	defer func() { tracing.End(span, err) }()
+
+	// cacheKey := getVibeStatsCacheKey(period)
+	// if s.Cache != nil {
+	// 	var stats map[string]interface{}
+	// 	if err := s.Cache.Get(context.Background(), cacheKey, &stats); err == nil {
+	// 		// Cache hit
+	// 		return stats, nil
+	// 	}
+	// 	// Cache miss or error, proceed to compute
+	// }
+
+	startDate, endDate := periodDateRange(period, time.Now())
 
 	stats, err := s.VibeRepo.GetVibeStatistics(period, startDate, endDate)
 	if err != nil {
@@ -276,12 +489,15 @@ func (s *VibeService) GetVibeStatistics(period string) (map[string]interface{},
 	if err != nil {
 		// Log this error but don't fail the whole stats call, or decide if this data is critical
 		// For now, we'll proceed without these advanced stats if data fetching fails
-		fmt.Printf("Warning: could not fetch vibes for advanced analytics: %v\n", err)
+		s.Logger.WarnContext(s.context(), "Could not fetch vibes for advanced analytics", "period", period, "error", err)
 	} else {
 		if len(vibesForPeriod) > 0 {
+			// The analytics run in memory; their span shows how much of the call they take.
+			_, analyticsSpan := tracing.Start(s.context(), "VibeService.analytics", attribute.Int("vibe.count", len(vibesForPeriod)))
 			stats["mood_patterns"] = s.calculateMoodPatterns(vibesForPeriod)
 			stats["mood_energy_correlation"] = s.calculateMoodEnergyCorrelation(vibesForPeriod)
 			stats["activity_mood_correlation"] = s.calculateActivityMoodCorrelation(vibesForPeriod, 5) // Top 5 activities
+			analyticsSpan.End()
 		} else {
 			stats["mood_patterns"] = "Not enough data for mood patterns."
 			stats["mood_energy_correlation"] = "Not enough data for mood-energy correlation."
@@ -291,35 +507,23 @@ func (s *VibeService) GetVibeStatistics(period string) (map[string]interface{},
 
 	// if s.Cache != nil && len(stats) > 0 { // len(stats) > 0 to avoid caching empty/error states if logic allows
 	// 	if err := s.Cache.Set(context.Background(), cacheKey, stats); err != nil {
-	// 		fmt.Printf("Warning: failed to set stats for period %s in cache: %v\n", period, err)
+	// 		s.Logger.WarnContext(s.context(), "Failed to cache stats", "period", period, "error", err)
 	// 	}
 	// }
 
 	return stats, nil
 }
 
-// calculateMoodPatterns identifies common transitions between moods.
-// Assumes vibes are sorted by date.
+// calculateMoodPatterns returns the mood transition matrix over strictly consecutive days.
 func (s *VibeService) calculateMoodPatterns(vibes []model.Vibe) interface{} {
 	if len(vibes) < 2 {
 		return "Not enough data for mood patterns (need at least 2 entries)."
 	}
-	patterns := make(map[string]int)
-	// Ensure vibes are sorted by date for accurate pattern detection
-	// The GetVibesForDateRange repository method already sorts by date ASC
-
-	for i := 0; i < len(vibes)-1; i++ {
-		// Check if the next vibe is on the subsequent day for a true daily transition
-		// This makes the pattern more meaningful as a "next day" transition.
-		// For simplicity, we'll just count transitions between any two consecutive records in the period.
-		// A more advanced version would filter for strictly consecutive days.
-		pattern := fmt.Sprintf("%s -> %s", vibes[i].Mood, vibes[i+1].Mood)
-		patterns[pattern]++
+	matrix := moodTransitionMatrix(vibes)
+	if matrix.Transitions == 0 {
+		return "No mood transitions between consecutive days found in the period."
 	}
-	if len(patterns) == 0 {
-		return "No mood transitions found in the period."
-	}
-	return patterns
+	return matrix
 }
 
 // calculateMoodEnergyCorrelation calculates the average energy level for each mood.
@@ -386,15 +590,13 @@ func (s *VibeService) calculateActivityMoodCorrelation(vibes []model.Vibe, topNA
 		sortedActivities = append(sortedActivities, activityCount{Name: name, Count: count})
 	}
 
-	// Sort activities by frequency (descending)
-	// Using a simple sort, for larger N consider a heap
-	for i := 0; i < len(sortedActivities); i++ {
-		for j := i + 1; j < len(sortedActivities); j++ {
-			if sortedActivities[j].Count > sortedActivities[i].Count {
-				sortedActivities[i], sortedActivities[j] = sortedActivities[j], sortedActivities[i]
-			}
+	// Sort activities by frequency (descending), breaking ties by name so the top N is stable
+	sort.Slice(sortedActivities, func(i, j int) bool {
+		if sortedActivities[i].Count != sortedActivities[j].Count {
+			return sortedActivities[i].Count > sortedActivities[j].Count
 		}
-	}
+		return sortedActivities[i].Name < sortedActivities[j].Name
+	})
 
 	limit := topNActivities
 	if len(sortedActivities) < topNActivities {
@@ -415,56 +617,15 @@ func (s *VibeService) calculateActivityMoodCorrelation(vibes []model.Vibe, topNA
 	return result
 }
 
-// GetTodaysVibeRecommendation provides a simple recommendation.
-func (s *VibeService) GetTodaysVibeRecommendation() (map[string]interface{}, error) {
-	// Simple recommendation: Suggest activities from past good days.
-	// A "good day" could be defined as mood = "happy" or "great" and energy_level >= 7.
-	// This is a placeholder for a more sophisticated algorithm.
-
-	// Fetch recent positive vibes
-	// For a more robust recommendation, consider a wider range or user-specific history.
-	threeMonthsAgo := time.Now().AddDate(0, -3, 0)
-	vibes, err := s.VibeRepo.GetVibesForDateRange(threeMonthsAgo, time.Now())
-	if err != nil {
-		return nil, fmt.Errorf("could not fetch historical data for recommendation: %w", err)
-	}
-
-	var potentialActivities []string
-	highEnergyMoods := map[string]bool{"happy": true, "great": true, "energetic": true, "excited": true, "motivated": true}
-
-	for _, vibe := range vibes {
-		if vibe.EnergyLevel >= 7 && highEnergyMoods[vibe.Mood] {
-			for _, activity := range vibe.Activities {
-				if activity != "" {
-					potentialActivities = append(potentialActivities, activity)
-				}
-			}
-		}
-	}
-
-	if len(potentialActivities) == 0 {
-		return map[string]interface{}{
-			"suggestion": "No specific activity suggestions based on recent high-energy, positive vibes. Maybe try something new today!",
-			"reason":     "Could not find relevant past activities.",
-		}, nil
-	}
-
-	// Pick a random activity from the list
-	rand.Seed(time.Now().UnixNano())
-	suggestedActivity := potentialActivities[rand.Intn(len(potentialActivities))]
-
-	return map[string]interface{}{
-		"suggestion": fmt.Sprintf("Based on past good days, you might enjoy: %s", suggestedActivity),
-		"reason":     "This activity was associated with high energy and positive mood in the past.",
-	}, nil
-}
-
 // GetMoodStreak gets current and longest streak for a given mood.
-func (s *VibeService) GetMoodStreak(mood string) (map[string]interface{}, error) {
+func (s *VibeService) GetMoodStreak(mood string) (_ map[string]interface{}, err error) {
+	s, span := s.startSpan("GetMoodStreak")
+	defer func() { tracing.End(span, err) }()
+
 	if strings.TrimSpace(mood) == "" {
 		return nil, fmt.Errorf("mood parameter cannot be empty")
 	}
-	normalizedMood := strings.ToLower(strings.TrimSpace(mood))
+	normalizedMood := s.canonicalMoodName(mood)
 
 	currentStreak, err := s.VibeRepo.GetMoodStreak(normalizedMood, true)
 	if err != nil {
@@ -484,13 +645,15 @@ func (s *VibeService) GetMoodStreak(mood string) (map[string]interface{}, error)
 }
 
 // ExportVibes handles data export logic.
-func (s *VibeService) ExportVibes(filters map[string]interface{}, format string, sortBy, sortOrder string) ([]byte, string, error) {
+func (s *VibeService) ExportVibes(filters map[string]interface{}, format string, sortBy, sortOrder string) (_ []byte, _ string, err error) {
+	s, span := s.startSpan("ExportVibes", attribute.String("export.format", format))
+	defer func() { tracing.End(span, err) }()
+	start := time.Now()
+
 	if format == "" {
 		return nil, "", fmt.Errorf("export format must be specified (e.g., csv, json)")
 	}
-	if sortBy == "" {
-		sortBy = DefaultSortBy
-	}
+	sortBy = s.normalizeSortBy(sortBy)
 	if sortOrder == "" {
 		sortOrder = DefaultSortOrder
 	} else {
@@ -499,11 +662,25 @@ func (s *VibeService) ExportVibes(filters map[string]interface{}, format string,
 			sortOrder = DefaultSortOrder
 		}
 	}
-	return s.VibeRepo.ExportVibes(filters, format, sortBy, sortOrder)
+	if mood, ok := filters["mood"].(string); ok {
+		filters["mood"] = s.canonicalMoodName(mood)
+	}
+	if err := s.parseMetricFilters(filters); err != nil {
+		return nil, "", err
+	}
+	data, contentType, err := s.VibeRepo.ExportVibes(filters, format, sortBy, sortOrder)
+	metrics.ObserveExport(format, len(data), time.Since(start), err)
+	return data, contentType, err
 }
 
 // BulkImportVibes handles bulk import of vibes.
-func (s *VibeService) BulkImportVibes(vibes []*model.Vibe) (int64, error) {
+func (s *VibeService) BulkImportVibes(vibes []*model.Vibe) (_ int64, err error) {
+	s, span := s.startSpan("BulkImportVibes", attribute.Int("vibe.count", len(vibes)))
+	defer func() { tracing.End(span, err) }()
+	defer func() {
+		metrics.ObserveBulkImport(len(vibes), err, errors.Is(err, ErrValidation) || errors.Is(err, ErrUnknownMood))
+	}()
+
 	if len(vibes) == 0 {
 		return 0, fmt.Errorf("no vibes provided for bulk import")
 	}
@@ -511,9 +688,8 @@ func (s *VibeService) BulkImportVibes(vibes []*model.Vibe) (int64, error) {
 	// Validate each vibe before attempting to insert
 	for i, vibe := range vibes {
 		if err := s.ValidateVibe(vibe); err != nil {
-			return 0, fmt.Errorf("validation error for vibe at index %d: %w", i, err)
+			return 0, fmt.Errorf("%w for vibe at index %d: %w", ErrValidation, i, err)
 		}
-		vibe.Mood = strings.ToLower(strings.TrimSpace(vibe.Mood)) // Normalize mood
 	}
 
 	// Additional business logic for bulk import can be added here.
@@ -522,7 +698,34 @@ func (s *VibeService) BulkImportVibes(vibes []*model.Vibe) (int64, error) {
 	// For true "import" functionality, one might consider an "upsert" strategy or error aggregation.
 	// For now, we rely on the repository's BulkInsertVibes which uses GORM's batch create.
 
-	return s.VibeRepo.BulkInsertVibes(vibes)
+	var inserted int64
+	err = s.write(func(tx *VibeService) ([]events.Event, error) {
+		for i, vibe := range vibes {
+			if err := tx.normalizeVibeMood(vibe); err != nil { // Normalize mood against the catalog
+				return nil, fmt.Errorf("validation error for vibe at index %d: %w", i, err)
+			}
+			if err := tx.normalizeVibeActivities(vibe); err != nil {
+				return nil, fmt.Errorf("error normalizing activities for vibe at index %d: %w", i, err)
+			}
+		}
+		n, err := tx.VibeRepo.BulkInsertVibes(vibes)
+		if err != nil {
+			return nil, err
+		}
+		inserted = n
+		imported := make([]model.Vibe, 0, len(vibes))
+		for _, vibe := range vibes {
+			imported = append(imported, *vibe)
+		}
+		return []events.Event{events.BulkImported{Count: n, Vibes: imported}}, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	for _, vibe := range vibes {
+		metrics.ObserveVibeCreated(metrics.SourceBulk, vibe.EnergyLevel)
+	}
+	return inserted, nil
 }
 
 /*
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/config"
+	"github.com/aebalz/daily-vibe-tracker/internal/events"
+	"github.com/aebalz/daily-vibe-tracker/internal/model"
+	"github.com/aebalz/daily-vibe-tracker/internal/repository"
+)
+
+// VibeSubscribers holds the side effects of vibe writes. They subscribe to vibe events on the
+// bus instead of being called by VibeService, so new reactions never touch the write path.
+type VibeSubscribers struct {
+	VibeRepo          repository.VibeRepositoryInterface
+	AnomalySvc        AnomalyServiceInterface        // Re-checks written days for anomalies
+	RecommendationSvc RecommendationServiceInterface // Links written vibes to the recommendations they followed
+	WebhookSvc        WebhookServiceInterface        // Forwards vibe events to webhook subscriptions
+	Cfg               *config.AppConfig
+}
+
+// NewVibeSubscribers creates the vibe event subscribers. Any service may be nil to disable its reaction.
+func NewVibeSubscribers(vibeRepo repository.VibeRepositoryInterface, anomalySvc AnomalyServiceInterface, recommendationSvc RecommendationServiceInterface, webhookSvc WebhookServiceInterface, cfg *config.AppConfig) *VibeSubscribers {
+	return &VibeSubscribers{
+		VibeRepo:          vibeRepo,
+		AnomalySvc:        anomalySvc,
+		RecommendationSvc: recommendationSvc,
+		WebhookSvc:        webhookSvc,
+		Cfg:               cfg,
+	}
+}
+
+// Register subscribes the reactions to the bus. Recommendation links and webhook events are
+// synchronous, so they are in place when the write returns; anomaly detection and streak
+// milestones run asynchronously because they scan history.
+func (v *VibeSubscribers) Register(bus *events.Bus) {
+	if v.RecommendationSvc != nil {
+		bus.Subscribe("recommendations", v.linkRecommendations, events.TypeVibeCreated, events.TypeVibeUpdated, events.TypeBulkImported)
+	}
+	if v.WebhookSvc != nil {
+		bus.Subscribe("webhooks", v.publishWebhooks)
+		bus.SubscribeAsync("streak-milestones", v.Cfg.EventQueueSize, v.publishStreakMilestone, events.TypeVibeCreated)
+	}
+	if v.AnomalySvc != nil {
+		bus.SubscribeAsync("anomalies", v.Cfg.EventQueueSize, v.detectAnomalies, events.TypeVibeCreated, events.TypeVibeUpdated, events.TypeBulkImported)
+	}
+}
+
+// writtenVibes returns the vibes an event wrote.
+func writtenVibes(event events.Event) []model.Vibe {
+	switch e := event.(type) {
+	case events.VibeCreated:
+		return []model.Vibe{e.Vibe}
+	case events.VibeUpdated:
+		return []model.Vibe{e.After}
+	case events.BulkImported:
+		return e.Vibes
+	}
+	return nil
+}
+
+// linkRecommendations links written vibes to earlier recommendations of their activities.
+func (v *VibeSubscribers) linkRecommendations(ctx context.Context, event events.Event) error {
+	for _, vibe := range writtenVibes(event) {
+		if _, err := v.RecommendationSvc.LinkVibe(&vibe); err != nil {
+			return fmt.Errorf("failed to link vibe %d to recommendations: %w", vibe.ID, err)
+		}
+	}
+	return nil
+}
+
+// detectAnomalies re-checks the days of written vibes for anomalies.
+func (v *VibeSubscribers) detectAnomalies(ctx context.Context, event events.Event) error {
+	vibes := writtenVibes(event)
+	dates := make([]time.Time, 0, len(vibes)+1)
+	for _, vibe := range vibes {
+		dates = append(dates, vibe.Date)
+	}
+	if updated, ok := event.(events.VibeUpdated); ok && !updated.Before.Date.Equal(updated.After.Date) {
+		dates = append(dates, updated.Before.Date) // The old day lost its vibe
+	}
+	_, err := v.AnomalySvc.DetectAnomaliesForWrites(dates...)
+	return err
+}
+
+// publishWebhooks forwards vibe events to webhook subscriptions.
+func (v *VibeSubscribers) publishWebhooks(ctx context.Context, event events.Event) error {
+	switch e := event.(type) {
+	case events.VibeCreated:
+		return v.WebhookSvc.Publish(model.EventVibeCreated, e.Vibe)
+	case events.VibeUpdated:
+		return v.WebhookSvc.Publish(model.EventVibeUpdated, e.After)
+	case events.VibeDeleted:
+		return v.WebhookSvc.Publish(model.EventVibeDeleted, map[string]interface{}{"id": e.Vibe.ID})
+	case events.BulkImported:
+		dayKeys := make([]string, 0, len(e.Vibes))
+		for _, vibe := range e.Vibes {
+			dayKeys = append(dayKeys, vibe.Date.Format(dayKeyLayout))
+		}
+		return v.WebhookSvc.Publish(model.EventBulkImported, map[string]interface{}{"count": e.Count, "dates": dayKeys})
+	}
+	return nil
+}
+
+// isStreakMilestone reports whether a logging streak of n days is worth an event:
+// a fixed set of early milestones, then every full year.
+func isStreakMilestone(n int) bool {
+	switch n {
+	case 3, 7, 14, 30, 60, 100, 180:
+		return true
+	}
+	return n > 0 && n%365 == 0
+}
+
+// loggingStreakEndingOn counts the consecutive days with a vibe up to and including day.
+func loggingStreakEndingOn(vibeRepo repository.VibeRepositoryInterface, day time.Time) (int, error) {
+	const window = 366 // Days fetched per query; long streaks take several
+	cursor := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	streak := 0
+	for {
+		start := cursor.AddDate(0, 0, -window)
+		// Vibe dates may carry any time zone, so fetch a day on either side and compare keys.
+		vibes, err := vibeRepo.GetVibesForDateRange(start.AddDate(0, 0, -1), cursor.AddDate(0, 0, 2))
+		if err != nil {
+			return 0, err
+		}
+		logged := make(map[string]bool, len(vibes))
+		for _, vibe := range vibes {
+			logged[vibe.Date.Format(dayKeyLayout)] = true
+		}
+		for d := cursor; d.After(start); d = d.AddDate(0, 0, -1) {
+			if !logged[d.Format(dayKeyLayout)] {
+				return streak, nil
+			}
+			streak++
+		}
+		cursor = start
+	}
+}
+
+// publishStreakMilestone emits streak.milestone when a created vibe extends the current
+// logging streak to a milestone. Backfilled days further in the past do not count.
+func (v *VibeSubscribers) publishStreakMilestone(ctx context.Context, event events.Event) error {
+	created, ok := event.(events.VibeCreated)
+	if !ok {
+		return nil
+	}
+	day := created.Vibe.Date
+	dayKey := day.Format(dayKeyLayout)
+	later, err := v.VibeRepo.GetVibesForDateRange(day, time.Now().AddDate(0, 0, 2))
+	if err != nil {
+		return fmt.Errorf("failed to compute logging streak: %w", err)
+	}
+	for _, vibe := range later {
+		if vibe.Date.Format(dayKeyLayout) > dayKey {
+			return nil // A later day is logged, so this vibe does not end the streak
+		}
+	}
+	streak, err := loggingStreakEndingOn(v.VibeRepo, day)
+	if err != nil {
+		return fmt.Errorf("failed to compute logging streak: %w", err)
+	}
+	if !isStreakMilestone(streak) {
+		return nil
+	}
+	return v.WebhookSvc.Publish(model.EventStreakMilestone, map[string]interface{}{
+		"streak_days": streak,
+		"date":        dayKey,
+	})
+}
@@ -0,0 +1,426 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/config"
+	"github.com/aebalz/daily-vibe-tracker/internal/logging"
+	"github.com/aebalz/daily-vibe-tracker/internal/model"
+	"github.com/aebalz/daily-vibe-tracker/internal/notifier"
+	"github.com/aebalz/daily-vibe-tracker/internal/repository"
+)
+
+const (
+	// webhookBatchSize bounds the deliveries attempted per worker run.
+	webhookBatchSize = 100
+	// webhookErrorBodyLimit bounds how much of a failed response is kept as the delivery error.
+	webhookErrorBodyLimit = 512
+	webhookSecretBytes    = 32
+
+	WebhookEventHeader    = "X-Vibe-Event"
+	WebhookDeliveryHeader = "X-Vibe-Delivery"
+)
+
+// WebhookEventTypes lists the event types subscriptions can filter on.
+var WebhookEventTypes = []string{
+	model.EventVibeCreated, model.EventVibeUpdated, model.EventVibeDeleted,
+	model.EventBulkImported, model.EventStreakMilestone,
+}
+
+// WebhookEnvelope is the JSON body posted to subscribers.
+type WebhookEnvelope struct {
+	ID        uint            `json:"id"` // Outbox event ID; identical across redeliveries, so receivers can deduplicate
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// WebhookServiceInterface defines the interface for outgoing webhooks.
+type WebhookServiceInterface interface {
+	CreateSubscription(sub *model.WebhookSubscription) (*model.WebhookSubscription, error)
+	GetSubscriptionByID(id uint) (*model.WebhookSubscription, error)
+	GetAllSubscriptions() ([]model.WebhookSubscription, error)
+	UpdateSubscription(id uint, updatedSub *model.WebhookSubscription) (*model.WebhookSubscription, error)
+	DeleteSubscription(id uint) error
+
+	// Publish writes an event to the outbox for every active subscription that wants it.
+	Publish(eventType string, data interface{}) error
+	// Ping queues a ping event for one subscription, regardless of its event filter.
+	Ping(subscriptionID uint) (*model.WebhookDelivery, error)
+
+	GetDeliveries(filters map[string]interface{}, limit, offset int) ([]model.WebhookDelivery, int64, error)
+	// Redeliver queues a delivery again with a fresh attempt budget, e.g. from the dead-letter queue.
+	Redeliver(deliveryID uint) (*model.WebhookDelivery, error)
+
+	// DeliverDue attempts all deliveries due at now.
+	DeliverDue(ctx context.Context, now time.Time) error
+	// RunWorker calls DeliverDue every WEBHOOK_POLL_INTERVAL until ctx is cancelled.
+	RunWorker(ctx context.Context)
+}
+
+// WebhookService implements WebhookServiceInterface. Events are stored in the outbox before
+// any delivery is attempted, so nothing is lost when a receiver is down or the server restarts.
+type WebhookService struct {
+	WebhookRepo repository.WebhookRepositoryInterface
+	Client      *http.Client // Used for deliveries; times out after NOTIFIER_TIMEOUT
+	Cfg         *config.AppConfig
+	Logger      *slog.Logger
+}
+
+// NewWebhookService creates a new WebhookService. A nil logger logs to the default logger.
+func NewWebhookService(webhookRepo repository.WebhookRepositoryInterface, cfg *config.AppConfig, logger *slog.Logger) WebhookServiceInterface {
+	return &WebhookService{
+		WebhookRepo: webhookRepo,
+		Client:      &http.Client{Timeout: cfg.NotifierTimeout},
+		Cfg:         cfg,
+		Logger:      logging.OrDefault(logger),
+	}
+}
+
+// generateWebhookSecret returns a random hex secret.
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, webhookSecretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
+
+// normalizeSubscription validates a subscription and brings its fields into canonical form.
+func normalizeSubscription(sub *model.WebhookSubscription) error {
+	sub.URL = strings.TrimSpace(sub.URL)
+	sub.Secret = strings.TrimSpace(sub.Secret)
+	sub.Description = strings.TrimSpace(sub.Description)
+
+	u, err := url.Parse(sub.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an http(s) URL", ErrValidation)
+	}
+
+	known := make(map[string]bool, len(WebhookEventTypes))
+	for _, t := range WebhookEventTypes {
+		known[t] = true
+	}
+	seen := make(map[string]bool, len(sub.Events))
+	events := []string{}
+	for _, e := range sub.Events {
+		name := strings.ToLower(strings.TrimSpace(e))
+		if !known[name] {
+			return fmt.Errorf("%w: unknown event '%s'. Valid events: %s", ErrValidation, e, strings.Join(WebhookEventTypes, ", "))
+		}
+		if !seen[name] {
+			seen[name] = true
+			events = append(events, name)
+		}
+	}
+	sub.Events = events
+	return nil
+}
+
+// CreateSubscription adds a new subscription. A secret is generated when none is given;
+// the returned subscription is the only place it is shown.
+func (s *WebhookService) CreateSubscription(sub *model.WebhookSubscription) (*model.WebhookSubscription, error) {
+	sub.ID = 0
+	if err := normalizeSubscription(sub); err != nil {
+		return nil, err
+	}
+	if sub.Secret == "" {
+		secret, err := generateWebhookSecret()
+		if err != nil {
+			return nil, fmt.Errorf("could not generate webhook secret: %w", err)
+		}
+		sub.Secret = secret
+	}
+	return s.WebhookRepo.CreateSubscription(sub)
+}
+
+// GetSubscriptionByID retrieves a subscription by its ID, without its secret.
+func (s *WebhookService) GetSubscriptionByID(id uint) (*model.WebhookSubscription, error) {
+	sub, err := s.WebhookRepo.GetSubscriptionByID(id)
+	if err != nil {
+		return nil, err
+	}
+	sub.Secret = ""
+	return sub, nil
+}
+
+// GetAllSubscriptions retrieves all subscriptions, without their secrets.
+func (s *WebhookService) GetAllSubscriptions() ([]model.WebhookSubscription, error) {
+	subs, err := s.WebhookRepo.GetAllSubscriptions(false)
+	if err != nil {
+		return nil, err
+	}
+	for i := range subs {
+		subs[i].Secret = ""
+	}
+	return subs, nil
+}
+
+// UpdateSubscription modifies a subscription. An empty secret keeps the current one; a new
+// secret is echoed back once, like on creation.
+func (s *WebhookService) UpdateSubscription(id uint, updatedSub *model.WebhookSubscription) (*model.WebhookSubscription, error) {
+	if err := normalizeSubscription(updatedSub); err != nil {
+		return nil, err
+	}
+	rotated := updatedSub.Secret != ""
+	sub, err := s.WebhookRepo.UpdateSubscription(id, updatedSub)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		sub.Secret = ""
+	}
+	return sub, nil
+}
+
+// DeleteSubscription removes a subscription and its deliveries.
+func (s *WebhookService) DeleteSubscription(id uint) error {
+	return s.WebhookRepo.DeleteSubscription(id)
+}
+
+// Publish stores the event in the outbox with a delivery per interested subscription.
+// Events nobody subscribed to are not stored.
+func (s *WebhookService) Publish(eventType string, data interface{}) error {
+	subs, err := s.WebhookRepo.GetAllSubscriptions(true)
+	if err != nil {
+		return fmt.Errorf("could not load webhook subscriptions: %w", err)
+	}
+	var ids []uint
+	for i := range subs {
+		if subs[i].Wants(eventType) {
+			ids = append(ids, subs[i].ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err = s.enqueue(eventType, data, ids)
+	return err
+}
+
+// enqueue writes an event and its deliveries to the outbox.
+func (s *WebhookService) enqueue(eventType string, data interface{}, subscriptionIDs []uint) ([]model.WebhookDelivery, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode %s event: %w", eventType, err)
+	}
+	event := &model.OutboxEvent{Type: eventType, Payload: string(payload)}
+	deliveries, err := s.WebhookRepo.EnqueueEvent(event, subscriptionIDs, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("could not store %s event: %w", eventType, err)
+	}
+	return deliveries, nil
+}
+
+// Ping queues a ping event for the subscription and returns its delivery.
+func (s *WebhookService) Ping(subscriptionID uint) (*model.WebhookDelivery, error) {
+	if _, err := s.WebhookRepo.GetSubscriptionByID(subscriptionID); err != nil {
+		return nil, err
+	}
+	deliveries, err := s.enqueue(model.EventPing, map[string]interface{}{"subscription_id": subscriptionID}, []uint{subscriptionID})
+	if err != nil {
+		return nil, err
+	}
+	return &deliveries[0], nil
+}
+
+// GetDeliveries retrieves deliveries, optionally filtered by subscription_id, status and event_type.
+func (s *WebhookService) GetDeliveries(filters map[string]interface{}, limit, offset int) ([]model.WebhookDelivery, int64, error) {
+	if status, ok := filters["status"].(string); ok && status != "" {
+		switch status {
+		case model.WebhookDeliveryPending, model.WebhookDeliveryDelivered, model.WebhookDeliveryDead:
+		default:
+			return nil, 0, fmt.Errorf("%w: invalid status '%s'. Use pending, delivered or dead", ErrValidation, status)
+		}
+	}
+	if limit <= 0 || limit > MaxLimit {
+		limit = DefaultLimit
+	}
+	if offset < 0 {
+		offset = DefaultOffset
+	}
+	return s.WebhookRepo.GetDeliveries(filters, limit, offset)
+}
+
+// Redeliver resets a delivery to pending with a fresh attempt budget. The receiver gets the
+// same event ID again, so it can tell a redelivery from a new event.
+func (s *WebhookService) Redeliver(deliveryID uint) (*model.WebhookDelivery, error) {
+	delivery, err := s.WebhookRepo.GetDeliveryByID(deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.WebhookRepo.GetSubscriptionByID(delivery.SubscriptionID); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	delivery.Status = model.WebhookDeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = &now
+	if err := s.WebhookRepo.UpdateDelivery(delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// RunWorker delivers due webhooks on a fixed interval, starting immediately so deliveries
+// pending from before a restart resume right away.
+func (s *WebhookService) RunWorker(ctx context.Context) {
+	if s.Cfg.WebhookPollInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(s.Cfg.WebhookPollInterval)
+	defer ticker.Stop()
+	for {
+		if err := s.DeliverDue(ctx, time.Now()); err != nil {
+			s.Logger.WarnContext(ctx, "Webhook delivery run failed", slog.String("error", err.Error()))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// claimLease is how long claimed deliveries are left to this worker: long enough to attempt
+// a whole batch, each request bounded by NOTIFIER_TIMEOUT. Deliveries of a worker that
+// stops mid-batch are picked up by another one once it runs out.
+func (s *WebhookService) claimLease() time.Duration {
+	return s.Cfg.NotifierTimeout*webhookBatchSize + time.Minute
+}
+
+// DeliverDue claims and attempts due deliveries, so that workers of several instances never
+// post the same delivery twice, retrying failures with exponential backoff.
+func (s *WebhookService) DeliverDue(ctx context.Context, now time.Time) error {
+	deliveries, err := s.WebhookRepo.ClaimDueDeliveries(now, s.claimLease(), webhookBatchSize)
+	if err != nil {
+		return fmt.Errorf("could not load due webhook deliveries: %w", err)
+	}
+	subs := make(map[uint]*model.WebhookSubscription)
+	for i := range deliveries {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		delivery := &deliveries[i]
+		sub, ok := subs[delivery.SubscriptionID]
+		if !ok {
+			sub, err = s.WebhookRepo.GetSubscriptionByID(delivery.SubscriptionID)
+			if err != nil {
+				sub = nil
+			}
+			subs[delivery.SubscriptionID] = sub
+		}
+		s.attemptDelivery(ctx, sub, delivery, now)
+		if err := s.WebhookRepo.UpdateDelivery(delivery); err != nil {
+			return fmt.Errorf("could not save webhook delivery %d: %w", delivery.ID, err)
+		}
+	}
+	return nil
+}
+
+// attemptDelivery makes one delivery attempt and records its outcome on the delivery.
+func (s *WebhookService) attemptDelivery(ctx context.Context, sub *model.WebhookSubscription, delivery *model.WebhookDelivery, now time.Time) {
+	if sub == nil {
+		// The subscription is gone; retrying cannot help.
+		delivery.Attempts++
+		delivery.Status = model.WebhookDeliveryDead
+		delivery.NextAttemptAt = nil
+		delivery.LastError = "subscription not found"
+		return
+	}
+	if !sub.Active {
+		// Hold deliveries of paused subscriptions until they are re-activated.
+		next := now.Add(s.Cfg.WebhookMaxBackoff)
+		delivery.NextAttemptAt = &next
+		return
+	}
+
+	status, err := s.send(ctx, sub, delivery, now)
+	delivery.Attempts++
+	delivery.ResponseStatus = status
+	if err != nil {
+		s.recordFailure(ctx, delivery, now, err)
+		return
+	}
+	delivery.Status = model.WebhookDeliveryDelivered
+	delivery.DeliveredAt = &now
+	delivery.NextAttemptAt = nil
+	delivery.LastError = ""
+}
+
+// send posts the signed event to the subscription and returns the response status.
+// Any non-2xx response counts as a failure.
+func (s *WebhookService) send(ctx context.Context, sub *model.WebhookSubscription, delivery *model.WebhookDelivery, now time.Time) (int, error) {
+	body, err := json.Marshal(WebhookEnvelope{
+		ID:        delivery.Event.ID,
+		Type:      delivery.Event.Type,
+		CreatedAt: delivery.Event.CreatedAt.UTC(),
+		Data:      json.RawMessage(delivery.Event.Payload),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("could not encode webhook body: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("invalid webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, delivery.Event.Type)
+	req.Header.Set(WebhookDeliveryHeader, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(notifier.SignatureHeader, notifier.SignPayload(sub.Secret, now, body))
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, webhookErrorBodyLimit))
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg := fmt.Sprintf("webhook responded with status %d", resp.StatusCode)
+		if text := strings.TrimSpace(string(snippet)); text != "" {
+			msg += ": " + text
+		}
+		return resp.StatusCode, fmt.Errorf("%s", msg)
+	}
+	return resp.StatusCode, nil
+}
+
+// webhookBackoff returns the delay before the next attempt: WEBHOOK_RETRY_BACKOFF * 2^(attempts-1),
+// capped at WEBHOOK_MAX_BACKOFF.
+func webhookBackoff(base, maxDelay time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		return maxDelay
+	}
+	return delay
+}
+
+// recordFailure schedules a retry, or moves the delivery to the dead-letter queue after
+// WEBHOOK_MAX_ATTEMPTS.
+func (s *WebhookService) recordFailure(ctx context.Context, delivery *model.WebhookDelivery, now time.Time, err error) {
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= s.Cfg.WebhookMaxAttempts {
+		delivery.Status = model.WebhookDeliveryDead
+		delivery.NextAttemptAt = nil
+		s.Logger.WarnContext(ctx, "Webhook delivery is dead", slog.Uint64("delivery_id", uint64(delivery.ID)), slog.Int("attempts", delivery.Attempts), slog.String("error", err.Error()))
+		return
+	}
+	next := now.Add(webhookBackoff(s.Cfg.WebhookRetryBackoff, s.Cfg.WebhookMaxBackoff, delivery.Attempts))
+	delivery.NextAttemptAt = &next
+}
@@ -0,0 +1,203 @@
+// Package stream fans out real-time events to connected Server-Sent Events and WebSocket
+// clients. Vibes are not owned by users yet, so every client receives every event; a short
+// replay buffer lets a client that reconnects with the ID of the last event it saw receive
+// what it missed. Connections are limited in total and per user.
+package stream
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AnonymousUser is the user of clients without one; they share one per-user limit.
+const AnonymousUser = "anonymous"
+
+// EventResync tells a client that events were missed beyond the replay buffer and it
+// should reload its state instead of relying on the stream.
+const EventResync = "resync"
+
+var (
+	// ErrTooManyConnections is returned when the global or per-user connection limit is reached.
+	ErrTooManyConnections = errors.New("too many stream connections")
+	// ErrHubClosed is returned by Subscribe after Close.
+	ErrHubClosed = errors.New("stream hub is closed")
+)
+
+// Message is one event pushed to stream clients.
+type Message struct {
+	ID    string          `json:"id,omitempty"` // Empty for messages that are not replayable, like the initial summary
+	Event string          `json:"event"`
+	Data  json.RawMessage `json:"data"`
+	At    time.Time       `json:"at"`
+}
+
+// NewMessage builds an unnumbered message.
+func NewMessage(event string, data interface{}) (Message, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return Message{}, fmt.Errorf("could not encode %s stream message: %w", event, err)
+	}
+	return Message{Event: event, Data: payload, At: time.Now().UTC()}, nil
+}
+
+// WriteSSE writes the message in the text/event-stream format.
+func WriteSSE(w io.Writer, msg Message) error {
+	var b strings.Builder
+	if msg.ID != "" {
+		b.WriteString("id: " + msg.ID + "\n")
+	}
+	b.WriteString("event: " + msg.Event + "\n")
+	// JSON never contains raw newlines, so the payload fits one data line.
+	b.WriteString("data: ")
+	b.Write(msg.Data)
+	b.WriteString("\n\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Client is one connection. Messages arrive on C; C is closed when the client is
+// disconnected by the hub, e.g. because it fell too far behind.
+type Client struct {
+	User string // Counted against the per-user limit
+	C    chan Message
+}
+
+// Hub fans out messages to all clients.
+type Hub struct {
+	mu      sync.Mutex
+	replay  []Message // Oldest first, at most replaySize
+	clients map[*Client]struct{}
+	users   map[string]int // Connections per user
+	seq     uint64
+	closed  bool
+
+	maxConnections int
+	maxPerUser     int
+	replaySize     int
+	clientBuffer   int
+}
+
+// NewHub creates a Hub. Event IDs start at the current time in microseconds, so IDs from
+// before a restart are never mistaken for current ones.
+func NewHub(maxConnections, maxPerUser, replaySize, clientBuffer int) *Hub {
+	return &Hub{
+		clients:        make(map[*Client]struct{}),
+		users:          make(map[string]int),
+		seq:            uint64(time.Now().UnixMicro()),
+		maxConnections: maxConnections,
+		maxPerUser:     maxPerUser,
+		replaySize:     replaySize,
+		clientBuffer:   clientBuffer,
+	}
+}
+
+// Subscribe connects a client of the user. With a lastEventID it also returns the buffered
+// messages after that event; complete is false when some of them are no longer buffered
+// and the client should resync.
+func (h *Hub) Subscribe(user, lastEventID string) (client *Client, missed []Message, complete bool, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil, nil, false, ErrHubClosed
+	}
+	if (h.maxConnections > 0 && len(h.clients) >= h.maxConnections) || (h.maxPerUser > 0 && h.users[user] >= h.maxPerUser) {
+		return nil, nil, false, ErrTooManyConnections
+	}
+	client = &Client{User: user, C: make(chan Message, h.clientBuffer)}
+	h.clients[client] = struct{}{}
+	h.users[user]++
+
+	complete = true
+	if lastEventID != "" {
+		missed, complete = h.since(lastEventID)
+	}
+	return client, missed, complete, nil
+}
+
+// since returns the buffered messages after lastEventID and whether nothing in between was lost.
+func (h *Hub) since(lastEventID string) ([]Message, bool) {
+	last, err := strconv.ParseUint(lastEventID, 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	var missed []Message
+	for _, msg := range h.replay {
+		id, _ := strconv.ParseUint(msg.ID, 10, 64)
+		if id > last {
+			missed = append(missed, msg)
+		}
+	}
+	if len(h.replay) == 0 {
+		return nil, false // Nothing buffered, e.g. after a restart: the client cannot know what it missed
+	}
+	oldest, _ := strconv.ParseUint(h.replay[0].ID, 10, 64)
+	// The client is up to date if it saw the event right before the oldest buffered one.
+	return missed, last+1 >= oldest
+}
+
+// Unsubscribe disconnects a client. It is safe to call more than once.
+func (h *Hub) Unsubscribe(client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.remove(client)
+}
+
+func (h *Hub) remove(client *Client) {
+	if _, ok := h.clients[client]; !ok {
+		return
+	}
+	delete(h.clients, client)
+	close(client.C)
+	if h.users[client.User]--; h.users[client.User] == 0 {
+		delete(h.users, client.User)
+	}
+}
+
+// Publish numbers the message, buffers it for replay and sends it to every client.
+// A client whose buffer is full is disconnected; it can reconnect with Last-Event-ID.
+func (h *Hub) Publish(msg Message) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return
+	}
+	h.seq++
+	msg.ID = strconv.FormatUint(h.seq, 10)
+	if h.replaySize > 0 {
+		h.replay = append(h.replay, msg)
+		if len(h.replay) > h.replaySize {
+			h.replay = h.replay[len(h.replay)-h.replaySize:]
+		}
+	}
+	for client := range h.clients {
+		select {
+		case client.C <- msg:
+		default:
+			h.remove(client)
+		}
+	}
+}
+
+// Connections returns the number of connected clients.
+func (h *Hub) Connections() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.clients)
+}
+
+// Close disconnects every client and rejects new ones, so open streams end and the HTTP
+// server can shut down.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for client := range h.clients {
+		h.remove(client)
+	}
+}
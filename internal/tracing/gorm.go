@@ -0,0 +1,101 @@
+package tracing
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// gormSpanKey is the key of the span of a statement in the gorm.DB instance.
+const gormSpanKey = "tracing:span"
+
+// GormPlugin starts a client span for each query of GORM, under the span of the query's
+// context. The SQL of the span is sanitized: GORM sends values as bind parameters, and
+// literals written into raw SQL are replaced with "?".
+type GormPlugin struct{}
+
+// Name implements gorm.Plugin.
+func (GormPlugin) Name() string { return "tracing" }
+
+// Initialize implements gorm.Plugin.
+func (GormPlugin) Initialize(db *gorm.DB) error {
+	callbacks := []struct {
+		operation string
+		before    func(name string, fn func(*gorm.DB)) error
+		after     func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", db.Callback().Create().Before("gorm:create").Register, db.Callback().Create().After("gorm:create").Register},
+		{"query", db.Callback().Query().Before("gorm:query").Register, db.Callback().Query().After("gorm:query").Register},
+		{"update", db.Callback().Update().Before("gorm:update").Register, db.Callback().Update().After("gorm:update").Register},
+		{"delete", db.Callback().Delete().Before("gorm:delete").Register, db.Callback().Delete().After("gorm:delete").Register},
+		{"row", db.Callback().Row().Before("gorm:row").Register, db.Callback().Row().After("gorm:row").Register},
+		{"raw", db.Callback().Raw().Before("gorm:raw").Register, db.Callback().Raw().After("gorm:raw").Register},
+	}
+	for _, cb := range callbacks {
+		if err := cb.before("tracing:before_"+cb.operation, startQuerySpan); err != nil {
+			return err
+		}
+		if err := cb.after("tracing:after_"+cb.operation, endQuerySpan); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func startQuerySpan(db *gorm.DB) {
+	if db.Statement == nil || db.Statement.Context == nil {
+		return
+	}
+	ctx, span := Tracer().Start(db.Statement.Context, "gorm.query", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL))
+	if !span.IsRecording() {
+		return
+	}
+	db.Statement.Context = ctx
+	db.InstanceSet(gormSpanKey, span)
+}
+
+func endQuerySpan(db *gorm.DB) {
+	value, ok := db.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+	sql := SanitizeSQL(db.Statement.SQL.String())
+	operation := strings.ToUpper(strings.SplitN(strings.TrimSpace(sql), " ", 2)[0])
+	name := strings.TrimSpace(operation + " " + db.Statement.Table)
+	if name != "" {
+		span.SetName(name)
+	}
+	span.SetAttributes(
+		semconv.DBOperationName(operation),
+		semconv.DBCollectionName(db.Statement.Table),
+		semconv.DBQueryText(sql),
+		attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+	)
+	var err error
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		err = db.Error
+	}
+	End(span, err)
+}
+
+var (
+	sqlStringLiteral  = regexp.MustCompile(`'(?:[^']|'')*'`)
+	sqlNumericLiteral = regexp.MustCompile(`(^|[^\w$.])\d+(?:\.\d+)?\b`)
+)
+
+// SanitizeSQL replaces the string and numeric literals of a statement with "?", so spans do
+// not carry the notes or other data of users. Bind parameters ($1) stay as they are.
+func SanitizeSQL(sql string) string {
+	sql = sqlStringLiteral.ReplaceAllString(sql, "?")
+	return sqlNumericLiteral.ReplaceAllString(sql, "${1}?")
+}
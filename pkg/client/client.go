@@ -0,0 +1,341 @@
+// Package client is a Go client for the Daily Vibe Tracker REST API.
+//
+//	c, err := client.New("http://localhost:8080", client.WithAPIKey(os.Getenv("VIBE_API_KEY")))
+//	if err != nil {
+//		return err
+//	}
+//	vibe, err := c.CreateVibe(ctx, client.VibeInput{Date: time.Now(), Mood: "happy", EnergyLevel: 7})
+//
+// Requests are retried with exponential backoff when the server answers 429 or 5xx, and
+// the Retry-After header is honored. Only idempotent requests are retried after a 5xx or a
+// network error; others are retried only after a 429, which the server sends before doing
+// any work.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers sent by the client.
+const (
+	APIKeyHeader    = "X-API-Key"
+	RequestIDHeader = "X-Request-ID"
+)
+
+// DefaultUserAgent is sent unless WithUserAgent sets another one.
+const DefaultUserAgent = "daily-vibe-tracker-go-client/1.0"
+
+// TokenSource returns the bearer token (e.g. a JWT) for a request. It is called before
+// every attempt, so it can refresh expired tokens.
+type TokenSource func(ctx context.Context) (string, error)
+
+// RetryPolicy controls how failed requests are retried.
+type RetryPolicy struct {
+	MaxRetries    int           // Retries after the first attempt; 0 disables retries
+	InitialDelay  time.Duration // Delay before the first retry, doubled for every further one
+	MaxDelay      time.Duration // Upper bound of the computed backoff delay
+	MaxRetryAfter time.Duration // Longest Retry-After the client waits for; longer ones fail the request. 0 means no limit
+}
+
+// DefaultRetryPolicy is used unless WithRetryPolicy sets another one.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries:    3,
+	InitialDelay:  200 * time.Millisecond,
+	MaxDelay:      5 * time.Second,
+	MaxRetryAfter: time.Minute,
+}
+
+// Client calls the vibe API. It is safe for concurrent use.
+type Client struct {
+	baseURL     *url.URL
+	httpClient  *http.Client
+	apiKey      string
+	tokenSource TokenSource
+	userAgent   string
+	retry       RetryPolicy
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for requests. The default has a 30s timeout;
+// exports use the context for their deadline instead, see ExportVibes.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithAPIKey sends key in the X-API-Key header.
+func WithAPIKey(key string) Option {
+	return func(c *Client) { c.apiKey = key }
+}
+
+// WithBearerToken sends a fixed token, e.g. a JWT, as "Authorization: Bearer <token>".
+func WithBearerToken(token string) Option {
+	return func(c *Client) {
+		c.tokenSource = func(context.Context) (string, error) { return token, nil }
+	}
+}
+
+// WithTokenSource sends the token returned by ts as "Authorization: Bearer <token>".
+func WithTokenSource(ts TokenSource) Option {
+	return func(c *Client) { c.tokenSource = ts }
+}
+
+// WithUserAgent sets the User-Agent header.
+func WithUserAgent(ua string) Option {
+	return func(c *Client) { c.userAgent = ua }
+}
+
+// WithRetryPolicy sets how failed requests are retried.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) { c.retry = p }
+}
+
+// New creates a client for the API at baseURL, e.g. "http://localhost:8080".
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimRight(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base URL %q: scheme must be http or https", baseURL)
+	}
+	c := &Client{
+		baseURL:    u,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		userAgent:  DefaultUserAgent,
+		retry:      DefaultRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// APIError is returned for responses with an error status.
+type APIError struct {
+	StatusCode int
+	Message    string        // The "error" field of the response, or the status text
+	RequestID  string        // X-Request-ID of the response, useful when reporting problems
+	RetryAfter time.Duration // Parsed Retry-After header, if any
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("vibe API: %d %s", e.StatusCode, e.Message)
+}
+
+// IsNotFound reports whether err is an APIError with status 404.
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// request describes one API call.
+type request struct {
+	method      string
+	path        string
+	query       url.Values
+	body        interface{} // Encoded as JSON when not nil
+	accept      string
+	noTimeout   bool // Use the context deadline only, for streamed responses
+	alwaysRetry bool // The server does not act on the request until it succeeds, so 5xx can be retried
+}
+
+func (r *request) idempotent() bool {
+	switch r.method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return r.alwaysRetry
+}
+
+// do sends req, retrying as the policy allows, and decodes a JSON response into out.
+func (c *Client) do(ctx context.Context, req *request, out interface{}) error {
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if out == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("vibe API: could not decode response: %w", err)
+	}
+	return nil
+}
+
+// send sends req and returns the first successful response. The caller closes its body.
+func (c *Client) send(ctx context.Context, req *request) (*http.Response, error) {
+	var body []byte
+	if req.body != nil {
+		var err error
+		if body, err = json.Marshal(req.body); err != nil {
+			return nil, fmt.Errorf("vibe API: could not encode request: %w", err)
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		token, err := c.token(ctx)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := c.attempt(ctx, req, body, token)
+		if err == nil && resp.StatusCode < 400 {
+			return resp, nil
+		}
+
+		var wait time.Duration
+		retryable := false
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			retryable = req.idempotent()
+		} else {
+			apiErr := decodeAPIError(resp)
+			err = apiErr
+			switch {
+			case resp.StatusCode == http.StatusTooManyRequests:
+				retryable = true
+			case resp.StatusCode >= 500:
+				retryable = req.idempotent()
+			}
+			if apiErr.RetryAfter > 0 {
+				if c.retry.MaxRetryAfter > 0 && apiErr.RetryAfter > c.retry.MaxRetryAfter {
+					return nil, err
+				}
+				wait = apiErr.RetryAfter
+			}
+		}
+		if !retryable || attempt >= c.retry.MaxRetries {
+			return nil, err
+		}
+		if wait == 0 {
+			wait = c.backoff(attempt)
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return nil, err // Waiting would outlive the context
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt sends req once.
+func (c *Client) attempt(ctx context.Context, req *request, body []byte, token string) (*http.Response, error) {
+	u := *c.baseURL
+	u.Path += req.path
+	if len(req.query) > 0 {
+		u.RawQuery = req.query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, u.String(), reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	accept := req.accept
+	if accept == "" {
+		accept = "application/json"
+	}
+	httpReq.Header.Set("Accept", accept)
+	httpReq.Header.Set("User-Agent", c.userAgent)
+	if c.apiKey != "" {
+		httpReq.Header.Set(APIKeyHeader, c.apiKey)
+	}
+	if token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	hc := c.httpClient
+	if req.noTimeout && hc.Timeout > 0 {
+		noTimeout := *hc
+		noTimeout.Timeout = 0
+		hc = &noTimeout
+	}
+	return hc.Do(httpReq)
+}
+
+// token returns the bearer token for the next attempt, if a token source is set.
+func (c *Client) token(ctx context.Context) (string, error) {
+	if c.tokenSource == nil {
+		return "", nil
+	}
+	token, err := c.tokenSource(ctx)
+	if err != nil {
+		return "", fmt.Errorf("vibe API: could not get token: %w", err)
+	}
+	return token, nil
+}
+
+// backoff returns the delay before retry number attempt+1: exponential with full jitter.
+func (c *Client) backoff(attempt int) time.Duration {
+	delay := c.retry.InitialDelay << attempt
+	if delay <= 0 || (c.retry.MaxDelay > 0 && delay > c.retry.MaxDelay) {
+		delay = c.retry.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(delay))) + 1
+}
+
+// decodeAPIError reads an error response and closes its body.
+func decodeAPIError(resp *http.Response) *APIError {
+	defer resp.Body.Close()
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Message:    http.StatusText(resp.StatusCode),
+		RequestID:  resp.Header.Get(RequestIDHeader),
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+	}
+	var body struct {
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&body); err == nil && body.Error != "" {
+		apiErr.Message = body.Error
+	}
+	return apiErr
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
@@ -0,0 +1,412 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"gorm.io/gorm"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/config"
+	"github.com/aebalz/daily-vibe-tracker/internal/handler"
+	"github.com/aebalz/daily-vibe-tracker/internal/model"
+	"github.com/aebalz/daily-vibe-tracker/internal/ratelimit"
+	"github.com/aebalz/daily-vibe-tracker/internal/service"
+	fiberserver "github.com/aebalz/daily-vibe-tracker/pkg/fiber"
+	ginserver "github.com/aebalz/daily-vibe-tracker/pkg/gin"
+)
+
+// memVibes is an in-memory vibe service behind the real handlers. The next failGets
+// lookups by ID fail, as with an unavailable database.
+type memVibes struct {
+	mu       sync.Mutex
+	vibes    map[uint]model.Vibe
+	nextID   uint
+	failGets int
+	gets     int
+	creates  int
+}
+
+func newMemVibes() *memVibes {
+	return &memVibes{vibes: make(map[uint]model.Vibe)}
+}
+
+func (s *memVibes) WithContext(ctx context.Context) service.VibeServiceInterface { return s }
+
+func (s *memVibes) CreateVibe(vibe *model.Vibe) (*model.Vibe, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.creates++
+	return s.insert(*vibe), nil
+}
+
+func (s *memVibes) insert(vibe model.Vibe) *model.Vibe {
+	s.nextID++
+	vibe.ID = s.nextID
+	vibe.CreatedAt = time.Now().UTC()
+	vibe.UpdatedAt = vibe.CreatedAt
+	s.vibes[vibe.ID] = vibe
+	return &vibe
+}
+
+func (s *memVibes) GetVibeByID(id uint) (*model.Vibe, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.gets++
+	if s.failGets > 0 {
+		s.failGets--
+		return nil, errors.New("database unavailable")
+	}
+	vibe, ok := s.vibes[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &vibe, nil
+}
+
+func (s *memVibes) GetVibesByIDs(ids []uint) ([]model.Vibe, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var vibes []model.Vibe
+	for _, id := range ids {
+		if vibe, ok := s.vibes[id]; ok {
+			vibes = append(vibes, vibe)
+		}
+	}
+	return vibes, nil
+}
+
+// selected returns the vibes matching the mood filter, ordered by date.
+func (s *memVibes) selected(filters map[string]interface{}, sortOrder string) []model.Vibe {
+	var vibes []model.Vibe
+	for _, vibe := range s.vibes {
+		if mood, ok := filters["mood"].(string); ok && vibe.Mood != mood {
+			continue
+		}
+		vibes = append(vibes, vibe)
+	}
+	sort.Slice(vibes, func(i, j int) bool {
+		if sortOrder == "asc" {
+			return vibes[i].Date.Before(vibes[j].Date)
+		}
+		return vibes[i].Date.After(vibes[j].Date)
+	})
+	return vibes
+}
+
+func (s *memVibes) GetAllVibes(filters map[string]interface{}, limit, offset int, sortBy, sortOrder string) ([]model.Vibe, int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	vibes := s.selected(filters, sortOrder)
+	total := int64(len(vibes))
+	vibes = vibes[min(offset, len(vibes)):]
+	return vibes[:min(limit, len(vibes))], total, nil
+}
+
+func (s *memVibes) UpdateVibe(id uint, updated *model.Vibe) (*model.Vibe, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	vibe, ok := s.vibes[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	vibe.Mood, vibe.EnergyLevel, vibe.Notes, vibe.Activities = updated.Mood, updated.EnergyLevel, updated.Notes, updated.Activities
+	vibe.UpdatedAt = time.Now().UTC()
+	s.vibes[id] = vibe
+	return &vibe, nil
+}
+
+func (s *memVibes) DeleteVibe(id uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.vibes[id]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	delete(s.vibes, id)
+	return nil
+}
+
+func (s *memVibes) GetVibeStatistics(period string) (map[string]interface{}, error) {
+	return map[string]interface{}{"period": period}, nil
+}
+
+func (s *memVibes) GetMoodStreak(mood string) (map[string]interface{}, error) {
+	return map[string]interface{}{"mood": mood}, nil
+}
+
+func (s *memVibes) ExportVibes(filters map[string]interface{}, format string, sortBy, sortOrder string) ([]byte, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := json.Marshal(s.selected(filters, sortOrder))
+	return data, "application/json", err
+}
+
+func (s *memVibes) BulkImportVibes(vibes []*model.Vibe) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, vibe := range vibes {
+		s.insert(*vibe)
+	}
+	return int64(len(vibes)), nil
+}
+
+// frameworks are the servers the client is tested against.
+var frameworks = []string{"fiber", "gin"}
+
+// newServer starts a test server of the framework with the real routes and handlers in
+// front of svc. A non-nil limiter rate limits it.
+func newServer(t *testing.T, framework string, svc service.VibeServiceInterface, limiter *ratelimit.Limiter) *httptest.Server {
+	cfg := &config.AppConfig{AppName: "client-test", AppEnv: "production"}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	vibeHandler := &handler.VibeHandler{Service: svc}
+	var server *httptest.Server
+	if framework == "fiber" {
+		server = httptest.NewServer(adaptor.FiberApp(fiberserver.NewFiberServer(cfg, vibeHandler, limiter, logger)))
+	} else {
+		server = httptest.NewServer(ginserver.NewGinServer(cfg, vibeHandler, limiter, logger))
+	}
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestClient(t *testing.T, baseURL string, policy RetryPolicy) *Client {
+	t.Helper()
+	c, err := New(baseURL, WithAPIKey("test-key"), WithRetryPolicy(policy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// fastRetries retries quickly, so that tests of 5xx responses do not wait for the backoff.
+var fastRetries = RetryPolicy{MaxRetries: 3, InitialDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond, MaxRetryAfter: 5 * time.Second}
+
+func day(n int) time.Time {
+	return time.Date(2024, time.March, n, 0, 0, 0, 0, time.UTC)
+}
+
+func TestClientCRUD(t *testing.T) {
+	for _, framework := range frameworks {
+		t.Run(framework, func(t *testing.T) {
+			ctx := context.Background()
+			c := newTestClient(t, newServer(t, framework, newMemVibes(), nil).URL, fastRetries)
+
+			created, err := c.CreateVibe(ctx, VibeInput{Date: day(1), Mood: "happy", EnergyLevel: 7, Activities: []string{"reading"}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if created.ID == 0 || created.Mood != "happy" || !created.Date.Equal(day(1)) {
+				t.Fatalf("created %+v", created)
+			}
+
+			got, err := c.GetVibe(ctx, created.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.ID != created.ID || got.EnergyLevel != 7 {
+				t.Fatalf("got %+v, want %+v", got, created)
+			}
+
+			updated, err := c.UpdateVibe(ctx, created.ID, VibeInput{Date: day(1), Mood: "calm", EnergyLevel: 5, Notes: "updated"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if updated.Mood != "calm" || updated.EnergyLevel != 5 || updated.Notes != "updated" {
+				t.Fatalf("updated %+v", updated)
+			}
+
+			patched, err := c.PatchVibe(ctx, created.ID, VibePatch{EnergyLevel: Int(9)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if patched.Mood != "calm" || patched.EnergyLevel != 9 {
+				t.Fatalf("patched %+v, want mood calm and energy 9", patched)
+			}
+
+			if err := c.DeleteVibe(ctx, created.ID); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := c.GetVibe(ctx, created.ID); !IsNotFound(err) {
+				t.Fatalf("get deleted vibe: %v, want a 404 APIError", err)
+			}
+
+			_, err = c.CreateVibe(ctx, VibeInput{Date: day(1), Mood: "happy", EnergyLevel: 11})
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest || apiErr.RequestID == "" {
+				t.Fatalf("invalid vibe: %v, want a 400 APIError with a request ID", err)
+			}
+		})
+	}
+}
+
+func TestClientPagination(t *testing.T) {
+	for _, framework := range frameworks {
+		t.Run(framework, func(t *testing.T) {
+			svc := newMemVibes()
+			for i := 1; i <= 7; i++ {
+				mood := "happy"
+				if i%2 == 0 {
+					mood = "calm"
+				}
+				svc.insert(model.Vibe{Date: day(i), Mood: mood, EnergyLevel: 5})
+			}
+			ctx := context.Background()
+			c := newTestClient(t, newServer(t, framework, svc, nil).URL, fastRetries)
+
+			var sizes []int
+			for page, err := range c.VibePages(ctx, NewFilter().SortBy("date").Asc().Limit(3)) {
+				if err != nil {
+					t.Fatal(err)
+				}
+				sizes = append(sizes, len(page.Data))
+			}
+			if len(sizes) != 3 || sizes[0] != 3 || sizes[1] != 3 || sizes[2] != 1 {
+				t.Fatalf("page sizes %v, want [3 3 1]", sizes)
+			}
+
+			var days []int
+			for vibe, err := range c.AllVibes(ctx, NewFilter().Mood("happy").SortBy("date").Asc().Limit(2)) {
+				if err != nil {
+					t.Fatal(err)
+				}
+				days = append(days, vibe.Date.Day())
+			}
+			if len(days) != 4 || days[0] != 1 || days[3] != 7 {
+				t.Fatalf("happy days %v, want [1 3 5 7]", days)
+			}
+
+			// Stopping early does not fetch further pages.
+			count := 0
+			for _, err := range c.AllVibes(ctx, NewFilter().Limit(2)) {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if count++; count == 3 {
+					break
+				}
+			}
+			if count != 3 {
+				t.Fatalf("iterated over %d vibes, want 3", count)
+			}
+		})
+	}
+}
+
+func TestClientExportAndBulkImport(t *testing.T) {
+	for _, framework := range frameworks {
+		t.Run(framework, func(t *testing.T) {
+			ctx := context.Background()
+			c := newTestClient(t, newServer(t, framework, newMemVibes(), nil).URL, fastRetries)
+
+			result, err := c.BulkImportVibes(ctx, []VibeInput{
+				{Date: day(2), Mood: "calm", EnergyLevel: 4},
+				{Date: day(1), Mood: "happy", EnergyLevel: 8},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.ImportedCount != 2 {
+				t.Fatalf("imported %d vibes, want 2", result.ImportedCount)
+			}
+
+			export, err := c.ExportVibes(ctx, FormatJSON, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			export.Close()
+			if export.ContentType != "application/json" {
+				t.Fatalf("export content type %q, want application/json", export.ContentType)
+			}
+
+			var moods []string
+			for vibe, err := range c.ExportedVibes(ctx, nil) {
+				if err != nil {
+					t.Fatal(err)
+				}
+				moods = append(moods, vibe.Mood)
+			}
+			if len(moods) != 2 || moods[0] != "happy" || moods[1] != "calm" {
+				t.Fatalf("exported moods %v, want [happy calm] in date order", moods)
+			}
+
+			if _, err := c.BulkImportVibes(ctx, nil); err == nil {
+				t.Fatal("empty bulk import succeeded, want a 400 APIError")
+			}
+		})
+	}
+}
+
+func TestClientRetriesServerErrors(t *testing.T) {
+	for _, framework := range frameworks {
+		t.Run(framework, func(t *testing.T) {
+			svc := newMemVibes()
+			vibe := svc.insert(model.Vibe{Date: day(1), Mood: "happy", EnergyLevel: 5})
+			ctx := context.Background()
+			c := newTestClient(t, newServer(t, framework, svc, nil).URL, fastRetries)
+
+			svc.failGets = 2
+			if _, err := c.GetVibe(ctx, vibe.ID); err != nil {
+				t.Fatalf("GET after two 500s: %v", err)
+			}
+			if svc.gets != 3 {
+				t.Fatalf("%d GET attempts, want 3", svc.gets)
+			}
+
+			// PATCH reads the vibe first, so a failed lookup answers 500 without writing;
+			// the client still does not retry a request that is not idempotent.
+			svc.failGets, svc.gets = 1, 0
+			_, err := c.PatchVibe(ctx, vibe.ID, VibePatch{EnergyLevel: Int(6)})
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusInternalServerError {
+				t.Fatalf("PATCH after a 500: %v, want a 500 APIError", err)
+			}
+			if svc.gets != 1 {
+				t.Fatalf("%d PATCH attempts, want 1", svc.gets)
+			}
+		})
+	}
+}
+
+func TestClientRetriesRateLimits(t *testing.T) {
+	for _, framework := range frameworks {
+		t.Run(framework, func(t *testing.T) {
+			// One request per second, so the second request is rejected with Retry-After: 1.
+			limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), ratelimit.Policy{Name: ratelimit.PolicyDefault, Limit: 1, Window: time.Second})
+			svc := newMemVibes()
+			server := newServer(t, framework, svc, limiter)
+			ctx := context.Background()
+			impatient := newTestClient(t, server.URL, RetryPolicy{MaxRetries: 3, MaxRetryAfter: time.Millisecond})
+			if _, err := impatient.CreateVibe(ctx, VibeInput{Date: day(1), Mood: "happy", EnergyLevel: 5}); err != nil {
+				t.Fatal(err)
+			}
+			_, err := impatient.CreateVibe(ctx, VibeInput{Date: day(2), Mood: "happy", EnergyLevel: 5})
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests || apiErr.RetryAfter != time.Second {
+				t.Fatalf("request over the limit: %v, want a 429 APIError with a Retry-After of 1s", err)
+			}
+
+			// POST is not idempotent, but a 429 is retried since the server did nothing.
+			svc.creates = 0
+			c := newTestClient(t, server.URL, fastRetries)
+			start := time.Now()
+			if _, err := c.CreateVibe(ctx, VibeInput{Date: day(3), Mood: "happy", EnergyLevel: 5}); err != nil {
+				t.Fatal(err)
+			}
+			if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+				t.Fatalf("retried after %s, want the Retry-After of 1s rather than the backoff", elapsed)
+			}
+			if svc.creates != 1 {
+				t.Fatalf("%d vibes created, want 1", svc.creates)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// MetricOp compares a custom metric value in a filter.
+type MetricOp string
+
+// Metric filter operators.
+const (
+	OpEq  MetricOp = "eq"
+	OpNe  MetricOp = "ne"
+	OpGt  MetricOp = "gt"
+	OpGte MetricOp = "gte"
+	OpLt  MetricOp = "lt"
+	OpLte MetricOp = "lte"
+)
+
+// Filter selects, sorts and pages vibes. Build it by chaining:
+//
+//	client.NewFilter().From(monthStart).Mood("happy").Metric("sleep_hours", client.OpGte, 7).SortBy("energy_level").Desc().Limit(20)
+//
+// A nil *Filter lists everything with the server defaults.
+type Filter struct {
+	date      *time.Time
+	from      *time.Time
+	to        *time.Time
+	mood      string
+	metrics   []string
+	sortBy    string
+	sortOrder string
+	limit     int
+	offset    int
+}
+
+// NewFilter returns an empty filter.
+func NewFilter() *Filter {
+	return &Filter{}
+}
+
+// Date keeps the vibe of one day.
+func (f *Filter) Date(day time.Time) *Filter {
+	f.date = &day
+	return f
+}
+
+// From keeps vibes on or after a day.
+func (f *Filter) From(day time.Time) *Filter {
+	f.from = &day
+	return f
+}
+
+// To keeps vibes on or before a day.
+func (f *Filter) To(day time.Time) *Filter {
+	f.to = &day
+	return f
+}
+
+// Mood keeps vibes with a mood. Aliases are resolved by the server.
+func (f *Filter) Mood(mood string) *Filter {
+	f.mood = mood
+	return f
+}
+
+// Metric keeps vibes whose custom metric compares to value with op. Filters add up.
+func (f *Filter) Metric(name string, op MetricOp, value interface{}) *Filter {
+	f.metrics = append(f.metrics, fmt.Sprintf("%s:%s:%v", name, op, value))
+	return f
+}
+
+// SortBy sorts by date, mood, energy_level, created_at, updated_at or metric:<name>.
+func (f *Filter) SortBy(field string) *Filter {
+	f.sortBy = field
+	return f
+}
+
+// Asc sorts in ascending order.
+func (f *Filter) Asc() *Filter {
+	f.sortOrder = "asc"
+	return f
+}
+
+// Desc sorts in descending order.
+func (f *Filter) Desc() *Filter {
+	f.sortOrder = "desc"
+	return f
+}
+
+// Limit sets the page size, 1 to 100. Exports ignore it.
+func (f *Filter) Limit(n int) *Filter {
+	f.limit = n
+	return f
+}
+
+// Offset skips the first n vibes. Exports ignore it.
+func (f *Filter) Offset(n int) *Filter {
+	f.offset = n
+	return f
+}
+
+// clone returns a copy that can be changed without affecting f.
+func (f *Filter) clone() *Filter {
+	if f == nil {
+		return NewFilter()
+	}
+	c := *f
+	c.metrics = append([]string(nil), f.metrics...)
+	return &c
+}
+
+// values returns the query parameters of the filter. Paging is left out when paged is false.
+func (f *Filter) values(paged bool) url.Values {
+	q := url.Values{}
+	if f == nil {
+		return q
+	}
+	if f.date != nil {
+		q.Set("date", f.date.Format("2006-01-02"))
+	}
+	if f.from != nil {
+		q.Set("start_date", f.from.Format("2006-01-02"))
+	}
+	if f.to != nil {
+		q.Set("end_date", f.to.Format("2006-01-02"))
+	}
+	if f.mood != "" {
+		q.Set("mood", f.mood)
+	}
+	for _, m := range f.metrics {
+		q.Add("metric", m)
+	}
+	if f.sortBy != "" {
+		q.Set("sort_by", f.sortBy)
+	}
+	if f.sortOrder != "" {
+		q.Set("sort_order", f.sortOrder)
+	}
+	if paged {
+		if f.limit > 0 {
+			q.Set("limit", strconv.Itoa(f.limit))
+		}
+		if f.offset > 0 {
+			q.Set("offset", strconv.Itoa(f.offset))
+		}
+	}
+	return q
+}
@@ -0,0 +1,26 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// Mood is an entry of the mood catalog.
+type Mood struct {
+	ID      uint     `json:"id"`
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases"`
+	Valence float64  `json:"valence"` // -1 (very negative) to 1 (very positive)
+	Arousal float64  `json:"arousal"` // 0 (calm) to 1 (activated)
+	Emoji   string   `json:"emoji"`
+	Color   string   `json:"color"` // Hex color, e.g. "#FFD700"
+}
+
+// Moods returns the mood catalog.
+func (c *Client) Moods(ctx context.Context) ([]Mood, error) {
+	var moods []Mood
+	if err := c.do(ctx, &request{method: http.MethodGet, path: "/api/v1/moods"}, &moods); err != nil {
+		return nil, err
+	}
+	return moods, nil
+}
@@ -0,0 +1,145 @@
+package client
+
+import "time"
+
+// Vibe is a daily vibe entry.
+type Vibe struct {
+	ID          uint                   `json:"id"`
+	Date        time.Time              `json:"date"`
+	Mood        string                 `json:"mood"`
+	EnergyLevel int                    `json:"energy_level"`
+	Notes       string                 `json:"notes"`
+	Activities  []string               `json:"activities"`
+	Metrics     map[string]interface{} `json:"metrics,omitempty"` // Custom metric values keyed by metric name
+	CreatedAt   time.Time              `json:"created_at"`
+	UpdatedAt   time.Time              `json:"updated_at"`
+}
+
+// VibeInput holds the fields of a new vibe, or all fields of a vibe for UpdateVibe.
+type VibeInput struct {
+	Date        time.Time              `json:"date"`
+	Mood        string                 `json:"mood"`         // Mood name or alias from the mood catalog
+	EnergyLevel int                    `json:"energy_level"` // 1 to 10
+	Notes       string                 `json:"notes,omitempty"`
+	Activities  []string               `json:"activities,omitempty"`
+	Metrics     map[string]interface{} `json:"metrics,omitempty"` // On update, nil keeps the stored values
+}
+
+// VibePatch holds the fields PatchVibe changes. Nil fields keep their value; use the
+// pointer helpers, e.g. VibePatch{EnergyLevel: client.Int(8)}.
+type VibePatch struct {
+	Date        *time.Time             `json:"date,omitempty"`
+	Mood        *string                `json:"mood,omitempty"`
+	EnergyLevel *int                   `json:"energy_level,omitempty"`
+	Notes       *string                `json:"notes,omitempty"`
+	Activities  *[]string              `json:"activities,omitempty"`
+	Metrics     map[string]interface{} `json:"metrics,omitempty"` // Replaces all custom metric values when set
+}
+
+// String returns a pointer to s.
+func String(s string) *string { return &s }
+
+// Int returns a pointer to n.
+func Int(n int) *int { return &n }
+
+// Time returns a pointer to t.
+func Time(t time.Time) *time.Time { return &t }
+
+// Strings returns a pointer to s.
+func Strings(s ...string) *[]string { return &s }
+
+// VibePage is one page of a vibe list.
+type VibePage struct {
+	Data       []Vibe `json:"data"`
+	Total      int64  `json:"total"`
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`
+	Page       int    `json:"page"`
+	TotalPages int    `json:"total_pages"`
+}
+
+// HasNext reports whether there are vibes after this page.
+func (p *VibePage) HasNext() bool {
+	return int64(p.Offset+len(p.Data)) < p.Total && len(p.Data) > 0
+}
+
+// Period is the time span of statistics.
+type Period string
+
+// Statistics periods.
+const (
+	PeriodWeek  Period = "week"
+	PeriodMonth Period = "month"
+	PeriodYear  Period = "year"
+)
+
+// MoodCount is the number of vibes with a mood.
+type MoodCount struct {
+	Mood  string `json:"mood"`
+	Count int    `json:"count"`
+}
+
+// MetricAggregate summarizes the values of a custom metric.
+type MetricAggregate struct {
+	Name    string  `json:"name"`
+	Type    string  `json:"type"`
+	Unit    string  `json:"unit"`
+	Count   int64   `json:"count"`
+	Average float64 `json:"average"` // For bool metrics this is the share of "true" values
+	Min     float64 `json:"min"`
+	Max     float64 `json:"max"`
+}
+
+// Statistics summarizes the vibes of a period. The analytics fields hold either the
+// computed result or a message explaining why there was not enough data.
+type Statistics struct {
+	AverageEnergyLevel      float64           `json:"average_energy_level"`
+	MoodDistribution        []MoodCount       `json:"mood_distribution"`
+	MetricAggregates        []MetricAggregate `json:"metric_aggregates"`
+	MoodPatterns            interface{}       `json:"mood_patterns"`
+	MoodEnergyCorrelation   interface{}       `json:"mood_energy_correlation"`
+	ActivityMoodCorrelation interface{}       `json:"activity_mood_correlation"`
+}
+
+// MoodStreak is the current and longest run of consecutive days with a mood.
+type MoodStreak struct {
+	Mood          string `json:"mood"`
+	CurrentStreak int    `json:"current_streak"`
+	LongestStreak int    `json:"longest_streak"`
+}
+
+// ActivitySuggestion is a recommended activity with the signals behind its score.
+type ActivitySuggestion struct {
+	ID           uint       `json:"id"` // Recommendation ID, used to send feedback; 0 for another day than today
+	Activity     string     `json:"activity"`
+	Score        float64    `json:"score"`
+	Lift         float64    `json:"lift"`
+	EnergyDelta  float64    `json:"energy_delta"`
+	DayOfWeekFit float64    `json:"day_of_week_fit"`
+	Feedback     float64    `json:"feedback"`
+	Occurrences  int        `json:"occurrences"`
+	LastDone     *time.Time `json:"last_done,omitempty"`
+	Reasons      []string   `json:"reasons"`
+}
+
+// Today is the recommendation for a day.
+type Today struct {
+	Date        time.Time            `json:"date"`
+	Seed        int64                `json:"seed"` // Pass back in TodayOptions.Seed to reproduce the ranking
+	Suggestions []ActivitySuggestion `json:"suggestions"`
+	Suggestion  string               `json:"suggestion"`
+	Reason      string               `json:"reason"`
+}
+
+// TodayOptions are the optional parameters of Today.
+type TodayOptions struct {
+	Date  time.Time // Recommend for this day instead of today
+	Limit int       // Number of suggestions, 1 to 10
+	Seed  int64     // Seed for tie-breaking
+}
+
+// BulkImportResult is the result of BulkImportVibes.
+type BulkImportResult struct {
+	Message       string `json:"message"`
+	ImportedCount int64  `json:"imported_count"`
+}
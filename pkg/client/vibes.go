@@ -0,0 +1,231 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const vibesPath = "/api/v1/vibes"
+
+func vibePath(id uint) string {
+	return vibesPath + "/" + strconv.FormatUint(uint64(id), 10)
+}
+
+// CreateVibe records a vibe.
+func (c *Client) CreateVibe(ctx context.Context, in VibeInput) (*Vibe, error) {
+	var vibe Vibe
+	if err := c.do(ctx, &request{method: http.MethodPost, path: vibesPath, body: in}, &vibe); err != nil {
+		return nil, err
+	}
+	return &vibe, nil
+}
+
+// GetVibe returns a vibe by ID. A missing vibe is reported as an APIError, see IsNotFound.
+func (c *Client) GetVibe(ctx context.Context, id uint) (*Vibe, error) {
+	var vibe Vibe
+	if err := c.do(ctx, &request{method: http.MethodGet, path: vibePath(id)}, &vibe); err != nil {
+		return nil, err
+	}
+	return &vibe, nil
+}
+
+// ListVibes returns one page of the vibes selected by filter.
+func (c *Client) ListVibes(ctx context.Context, filter *Filter) (*VibePage, error) {
+	var page VibePage
+	if err := c.do(ctx, &request{method: http.MethodGet, path: vibesPath, query: filter.values(true)}, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// VibePages iterates over the pages of the vibes selected by filter, starting at its
+// offset. Iteration stops after the first error.
+//
+//	for page, err := range c.VibePages(ctx, client.NewFilter().Limit(50)) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func (c *Client) VibePages(ctx context.Context, filter *Filter) iter.Seq2[*VibePage, error] {
+	return func(yield func(*VibePage, error) bool) {
+		f := filter.clone()
+		for {
+			page, err := c.ListVibes(ctx, f)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(page, nil) || !page.HasNext() {
+				return
+			}
+			f.offset = page.Offset + len(page.Data)
+		}
+	}
+}
+
+// AllVibes iterates over every vibe selected by filter, fetching pages as needed.
+// Iteration stops after the first error.
+func (c *Client) AllVibes(ctx context.Context, filter *Filter) iter.Seq2[Vibe, error] {
+	return func(yield func(Vibe, error) bool) {
+		for page, err := range c.VibePages(ctx, filter) {
+			if err != nil {
+				yield(Vibe{}, err)
+				return
+			}
+			for _, vibe := range page.Data {
+				if !yield(vibe, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// UpdateVibe replaces the fields of a vibe. in.Metrics nil keeps the stored metric values.
+func (c *Client) UpdateVibe(ctx context.Context, id uint, in VibeInput) (*Vibe, error) {
+	var vibe Vibe
+	if err := c.do(ctx, &request{method: http.MethodPut, path: vibePath(id), body: in}, &vibe); err != nil {
+		return nil, err
+	}
+	return &vibe, nil
+}
+
+// PatchVibe changes only the fields set in patch.
+func (c *Client) PatchVibe(ctx context.Context, id uint, patch VibePatch) (*Vibe, error) {
+	var vibe Vibe
+	if err := c.do(ctx, &request{method: http.MethodPatch, path: vibePath(id), body: patch}, &vibe); err != nil {
+		return nil, err
+	}
+	return &vibe, nil
+}
+
+// DeleteVibe deletes a vibe.
+func (c *Client) DeleteVibe(ctx context.Context, id uint) error {
+	return c.do(ctx, &request{method: http.MethodDelete, path: vibePath(id)}, nil)
+}
+
+// Stats returns the statistics of the current week, month or year. An empty period means
+// the month.
+func (c *Client) Stats(ctx context.Context, period Period) (*Statistics, error) {
+	q := url.Values{}
+	if period != "" {
+		q.Set("period", string(period))
+	}
+	var stats Statistics
+	if err := c.do(ctx, &request{method: http.MethodGet, path: vibesPath + "/stats", query: q}, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// Streak returns the current and longest streak of a mood.
+func (c *Client) Streak(ctx context.Context, mood string) (*MoodStreak, error) {
+	var streak MoodStreak
+	q := url.Values{"mood": {mood}}
+	if err := c.do(ctx, &request{method: http.MethodGet, path: vibesPath + "/streak", query: q}, &streak); err != nil {
+		return nil, err
+	}
+	return &streak, nil
+}
+
+// Today returns the activity recommendations for today, or for opts.Date. opts may be nil.
+func (c *Client) Today(ctx context.Context, opts *TodayOptions) (*Today, error) {
+	q := url.Values{}
+	if opts != nil {
+		if !opts.Date.IsZero() {
+			q.Set("date", opts.Date.Format("2006-01-02"))
+		}
+		if opts.Limit > 0 {
+			q.Set("limit", strconv.Itoa(opts.Limit))
+		}
+		if opts.Seed != 0 {
+			q.Set("seed", strconv.FormatInt(opts.Seed, 10))
+		}
+	}
+	var today Today
+	if err := c.do(ctx, &request{method: http.MethodGet, path: vibesPath + "/today", query: q}, &today); err != nil {
+		return nil, err
+	}
+	return &today, nil
+}
+
+// ExportFormat is the file format of an export.
+type ExportFormat string
+
+// Export formats.
+const (
+	FormatCSV  ExportFormat = "csv"
+	FormatJSON ExportFormat = "json"
+)
+
+// Export is a streamed export. Read it like a file and close it when done.
+type Export struct {
+	io.ReadCloser
+	ContentType string
+}
+
+// ExportVibes streams the vibes selected by filter in the given format. The paging of the
+// filter is ignored; the default order is by date, ascending. The HTTP client timeout does
+// not apply, so large exports are bounded only by ctx.
+func (c *Client) ExportVibes(ctx context.Context, format ExportFormat, filter *Filter) (*Export, error) {
+	q := filter.values(false)
+	q.Set("format", string(format))
+	accept := "text/csv"
+	if format == FormatJSON {
+		accept = "application/json"
+	}
+	resp, err := c.send(ctx, &request{method: http.MethodGet, path: vibesPath + "/export", query: q, accept: accept, noTimeout: true})
+	if err != nil {
+		return nil, err
+	}
+	return &Export{ReadCloser: resp.Body, ContentType: resp.Header.Get("Content-Type")}, nil
+}
+
+// ExportedVibes iterates over a JSON export, decoding one vibe at a time instead of
+// loading the whole export. Iteration stops after the first error.
+func (c *Client) ExportedVibes(ctx context.Context, filter *Filter) iter.Seq2[Vibe, error] {
+	return func(yield func(Vibe, error) bool) {
+		export, err := c.ExportVibes(ctx, FormatJSON, filter)
+		if err != nil {
+			yield(Vibe{}, err)
+			return
+		}
+		defer export.Close()
+
+		dec := json.NewDecoder(export)
+		if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+			if err == nil {
+				err = fmt.Errorf("unexpected token %v", tok)
+			}
+			yield(Vibe{}, fmt.Errorf("vibe API: could not decode export: %w", err))
+			return
+		}
+		for dec.More() {
+			var vibe Vibe
+			if err := dec.Decode(&vibe); err != nil {
+				yield(Vibe{}, fmt.Errorf("vibe API: could not decode export: %w", err))
+				return
+			}
+			if !yield(vibe, nil) {
+				return
+			}
+		}
+	}
+}
+
+// BulkImportVibes imports vibes in one transaction. Nothing is imported if any vibe is
+// invalid.
+func (c *Client) BulkImportVibes(ctx context.Context, vibes []VibeInput) (*BulkImportResult, error) {
+	var result BulkImportResult
+	if err := c.do(ctx, &request{method: http.MethodPost, path: vibesPath + "/bulk", body: vibes}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -3,11 +3,13 @@ package database
 import (
 	"fmt"
 	"log"
-	"os"
+	"log/slog"
 	"time"
 
 	"github.com/aebalz/daily-vibe-tracker/internal/config"
+	"github.com/aebalz/daily-vibe-tracker/internal/logging"
 	"github.com/aebalz/daily-vibe-tracker/internal/model"
+	"github.com/aebalz/daily-vibe-tracker/internal/tracing"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -15,8 +17,9 @@ import (
 
 var DB *gorm.DB
 
-// ConnectDB initializes the database connection using GORM.
-func ConnectDB(cfg *config.AppConfig) (*gorm.DB, error) {
+// ConnectDB initializes the database connection using GORM. SQL goes to appLogger: every
+// statement in development or with LOG_LEVEL=debug, otherwise only slow and failed queries.
+func ConnectDB(cfg *config.AppConfig, appLogger *slog.Logger) (*gorm.DB, error) {
 	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=%s TimeZone=%s",
 		cfg.DBHost,
 		cfg.DBUser,
@@ -27,20 +30,11 @@ func ConnectDB(cfg *config.AppConfig) (*gorm.DB, error) {
 		cfg.DBTimezone,
 	)
 
-	logLevel := logger.Silent
-	if cfg.AppEnv == "development" {
+	logLevel := logger.Warn
+	if cfg.AppEnv == "development" || cfg.LogLevel == "debug" {
 		logLevel = logger.Info
 	}
-
-	newLogger := logger.New(
-		log.New(os.Stdout, "\r\n", log.LstdFlags), // io writer
-		logger.Config{
-			SlowThreshold:             time.Second, // Slow SQL threshold
-			LogLevel:                  logLevel,    // Log level
-			IgnoreRecordNotFoundError: true,        // Ignore ErrRecordNotFound error for logger
-			Colorful:                  true,        // Disable color
-		},
-	)
+	newLogger := logging.NewGormLogger(appLogger, logLevel, cfg.DBSlowQueryThreshold)
 
 	var err error
 	DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{
@@ -54,6 +48,10 @@ func ConnectDB(cfg *config.AppConfig) (*gorm.DB, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
+	// Each query gets a span under the span of its context (see tracing.GormPlugin).
+	if err := DB.Use(tracing.GormPlugin{}); err != nil {
+		return nil, fmt.Errorf("failed to register the tracing plugin: %w", err)
+	}
 
 	sqlDB, err := DB.DB()
 	if err != nil {
@@ -69,13 +67,16 @@ func ConnectDB(cfg *config.AppConfig) (*gorm.DB, error) {
 	return DB, nil
 }
 
+// Models lists the models whose tables MigrateDB creates.
+var Models = []interface{}{&model.Vibe{}, &model.Mood{}, &model.Activity{}, &model.MetricDefinition{}, &model.VibeMetricValue{}, &model.AnomalyEvent{}, &model.NotInterestedActivity{}, &model.Recommendation{}, &model.Goal{}, &model.Reminder{}, &model.ReminderDelivery{}, &model.WebhookSubscription{}, &model.OutboxEvent{}, &model.WebhookDelivery{}, &model.DomainEvent{}, &model.User{}, &model.APIKey{}}
+
 // MigrateDB runs GORM auto-migrations for the defined models.
 // In a production environment, a more robust migration tool (like golang-migrate/migrate) is recommended.
 func MigrateDB(db *gorm.DB) error {
 	if db == nil {
 		return fmt.Errorf("database connection is not initialized")
 	}
-	err := db.AutoMigrate(&model.Vibe{})
+	err := db.AutoMigrate(Models...)
 	if err != nil {
 		return fmt.Errorf("failed to migrate database: %w", err)
 	}
@@ -83,6 +84,33 @@ func MigrateDB(db *gorm.DB) error {
 	return nil
 }
 
+// PendingMigrations lists the tables and columns of Models that are missing from the
+// database, e.g. "table users" or "column vibes.notes". MigrateDB would create them.
+func PendingMigrations(db *gorm.DB) ([]string, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database connection is not initialized")
+	}
+	var pending []string
+	migrator := db.Migrator()
+	for _, m := range Models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(m); err != nil {
+			return nil, fmt.Errorf("failed to parse model %T: %w", m, err)
+		}
+		table := stmt.Schema.Table
+		if !migrator.HasTable(m) {
+			pending = append(pending, "table "+table)
+			continue
+		}
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName != "" && !migrator.HasColumn(m, field.DBName) {
+				pending = append(pending, "column "+table+"."+field.DBName)
+			}
+		}
+	}
+	return pending, nil
+}
+
 // CloseDB closes the database connection.
 func CloseDB() {
 	if DB != nil {
@@ -1,55 +1,53 @@
 package fiber
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"log/slog"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/gofiber/fiber/v2/middleware/requestid"
 	swaggoFiber "github.com/swaggo/fiber-swagger"
 
 	"github.com/aebalz/daily-vibe-tracker/internal/config"
 	"github.com/aebalz/daily-vibe-tracker/internal/handler" // Will be created later
+	"github.com/aebalz/daily-vibe-tracker/internal/logging"
 	customMiddleware "github.com/aebalz/daily-vibe-tracker/internal/middleware"
+	"github.com/aebalz/daily-vibe-tracker/internal/ratelimit"
 
 	// Import docs for swagger
 	_ "github.com/aebalz/daily-vibe-tracker/docs"
-	"github.com/gofiber/adaptor/v2"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 // NewFiberServer creates and configures a new Fiber application.
-func NewFiberServer(cfg *config.AppConfig, vibeHandler *handler.VibeHandler) *fiber.App {
+// A nil limiter disables rate limiting. Requests are logged to logger.
+func NewFiberServer(cfg *config.AppConfig, vibeHandler *handler.VibeHandler, limiter *ratelimit.Limiter, logger *slog.Logger) *fiber.App {
+	routes := vibeHandler.Routes()
 	app := fiber.New(fiber.Config{
-		AppName:      cfg.AppName,
-		ReadTimeout:  cfg.ServerReadTimeout,
-		WriteTimeout: cfg.ServerWriteTimeout,
-		IdleTimeout:  cfg.ServerIdleTimeout,
-		ErrorHandler: customErrorHandler, // Optional: Custom error handler
+		AppName:       cfg.AppName,
+		ReadTimeout:   cfg.ServerReadTimeout,
+		WriteTimeout:  cfg.ServerWriteTimeout,
+		IdleTimeout:   cfg.ServerIdleTimeout,
+		CaseSensitive: true, // Like Gin
+		ErrorHandler:  newErrorHandler(routes, logging.OrDefault(logger)),
 	})
 
-	// Middleware
+	// Middleware, in the same order as the Gin server
 	app.Use(recover.New())
 	app.Use(requestid.New())
-	app.Use(logger.New(logger.Config{
-		Format: "[${time}] ${ip} ${status} - ${method} ${path} ${latency}\nREQUEST_ID: ${locals:requestid}\n",
-	}))
-	app.Use(cors.New(cors.Config{
-		AllowOrigins: cfg.CorsAllowedOrigins[0], // Fiber's CORS AllowOrigins is a string. Adjust if multiple needed via other means.
-		AllowHeaders: "Origin, Content-Type, Accept, Authorization, X-Request-ID",
-		AllowMethods: "GET, POST, PUT, DELETE, OPTIONS",
-	}))
-
-	// Add Custom Middleware (Metrics, Rate Limiting)
+	app.Use(requestContextMiddleware)
+	app.Use(customMiddleware.ClientIPFiber(cfg.TrustedProxies))
+	app.Use(customMiddleware.TracingFiber())
+	app.Use(customMiddleware.RequestLoggerFiber(logger))
+	app.Use(customMiddleware.CORSFiber(cfg.CorsAllowedOrigins))
+
+	// Add Custom Middleware (Metrics)
 	// These should come after basic middleware like logger/requestid but before routes.
+	// Rate limiting runs per route, below, once the user is authenticated.
 	app.Use(customMiddleware.MetricsMiddlewareFiber())
-	// Apply rate limiter globally or to specific groups/routes as needed
-	// Example: Global application (adjust rps and burst as needed)
-	// For specific groups: api.Use(customMiddleware.RateLimiterFiber(10, 20))
-	app.Use(customMiddleware.RateLimiterFiber(cfg.RateLimitPerSecond, cfg.RateLimitBurst))
 
 	// Swagger UI
 	// BasePath for swagger UI itself. If docs.SwaggerInfo.BasePath is /api/v1,
@@ -57,58 +55,64 @@ func NewFiberServer(cfg *config.AppConfig, vibeHandler *handler.VibeHandler) *fi
 	// itself is served from /swagger/*
 	app.Get("/swagger/*", swaggoFiber.WrapHandler) // Serves Swagger UI
 
-	// Prometheus Metrics Endpoint
-	app.Get("/metrics", adaptor.HTTPHandler(promhttp.Handler()))
-
-	// Routes
-	// Health Check Route
-	if vibeHandler != nil && vibeHandler.HealthHandler != nil {
-		app.Get("/health", vibeHandler.HealthHandler.CheckHealthFiber)
-	} else {
-		app.Get("/health", func(c *fiber.Ctx) error {
-			return c.Status(fiber.StatusOK).JSON(fiber.Map{"status": "initializing health handler"})
-		})
+	// API key authentication for the API routes, behind the limit of failed authentications per IP
+	var auth []fiber.Handler
+	if vibeHandler != nil && vibeHandler.AuthHandler != nil {
+		auth = append(auth, customMiddleware.AuthFailureLimiterFiber(limiter, logger), vibeHandler.AuthHandler.AuthenticateFiber)
 	}
 
-	// Vibe Routes
-	apiV1 := app.Group("/api/v1") // All vibe routes will be under /api/v1
-	{
-		vibesGroup := apiV1.Group("/vibes")
-		// Apply specific middleware to this group if needed
-		// vibesGroup.Use(customMiddleware.AnotherSpecificMiddleware())
-
-		vibesGroup.Post("/", vibeHandler.CreateVibeFiber)
-		vibesGroup.Get("/", vibeHandler.GetAllVibesFiber)
-		vibesGroup.Get("/stats", vibeHandler.GetVibeStatsFiber)
-		vibesGroup.Get("/today", vibeHandler.GetTodaysVibeRecommendationFiber)
-		vibesGroup.Get("/streak", vibeHandler.GetMoodStreakFiber)
-		vibesGroup.Get("/export", vibeHandler.ExportVibesFiber)
-		vibesGroup.Post("/bulk", vibeHandler.BulkImportVibesFiber)
-		vibesGroup.Get("/:id", vibeHandler.GetVibeByIDFiber)
-		vibesGroup.Put("/:id", vibeHandler.UpdateVibeFiber)
-		vibesGroup.Delete("/:id", vibeHandler.DeleteVibeFiber)
+	// Routes, from the table shared with the Gin server. Fiber matches paths with and
+	// without a trailing slash; GET routes also answer HEAD. API routes are rate limited
+	// per user, under the policy of the route.
+	for _, route := range routes {
+		handlers := []fiber.Handler{route.Fiber}
+		if route.Auth {
+			handlers = append(append([]fiber.Handler{}, auth...),
+				customMiddleware.RateLimiterFiber(limiter, route.RateLimit, handler.UserIDKey, logger), route.Fiber)
+		}
+		app.Add(route.Method, route.Path, handlers...)
+		if route.Method == fiber.MethodGet {
+			app.Add(fiber.MethodHead, route.Path, handlers...)
+		}
 	}
 
 	return app
 }
 
-// customErrorHandler for Fiber
-func customErrorHandler(ctx *fiber.Ctx, err error) error {
-	code := fiber.StatusInternalServerError
-	message := "Internal Server Error"
-
-	if e, ok := err.(*fiber.Error); ok {
-		code = e.Code
-		message = e.Message
+// requestContextMiddleware stores the request ID of requestid.New in the context of the
+// request, where the logger of the services and of GORM finds it.
+func requestContextMiddleware(c *fiber.Ctx) error {
+	if requestID, ok := c.Locals(requestid.ConfigDefault.ContextKey).(string); ok {
+		c.SetUserContext(logging.WithRequestID(c.UserContext(), requestID))
 	}
+	return c.Next()
+}
 
-	// Log the error internally
-	log.Printf("Fiber Error: %v - Path: %s", err, ctx.Path())
-
-	return ctx.Status(code).JSON(fiber.Map{
-		"error":   true,
-		"message": message,
-	})
+// newErrorHandler returns the Fiber error handler. Errors have the {"error": message} shape of
+// the handlers and of the Gin server; 405 responses list the allowed methods in Allow.
+func newErrorHandler(routes []handler.Route, logger *slog.Logger) fiber.ErrorHandler {
+	return func(ctx *fiber.Ctx, err error) error {
+		code := fiber.StatusInternalServerError
+		message := "Internal Server Error"
+
+		var e *fiber.Error
+		if errors.As(err, &e) {
+			code = e.Code
+			message = e.Message
+		}
+		if code == fiber.StatusMethodNotAllowed {
+			if allowed := handler.AllowedMethods(routes, ctx.Path()); len(allowed) > 0 {
+				ctx.Set(fiber.HeaderAllow, strings.Join(allowed, ", "))
+			}
+		}
+
+		// The request itself is logged by the request logger; unexpected errors need their cause.
+		if code >= fiber.StatusInternalServerError {
+			logger.ErrorContext(ctx.UserContext(), "Request failed", slog.String("path", ctx.Path()), slog.String("error", err.Error()))
+		}
+
+		return ctx.Status(code).JSON(fiber.Map{"error": message})
+	}
 }
 
 // StartFiberServer starts the Fiber server.
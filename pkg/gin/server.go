@@ -3,11 +3,13 @@ package gin
 import (
 	"context"
 	"fmt"
+	"html"
 	"log"
+	"log/slog"
 	"net/http"
+	"strings"
 	"time"
 
-	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	swaggoFiles "github.com/swaggo/files"
@@ -15,8 +17,9 @@ import (
 
 	"github.com/aebalz/daily-vibe-tracker/internal/config"
 	"github.com/aebalz/daily-vibe-tracker/internal/handler" // Will be created later
+	"github.com/aebalz/daily-vibe-tracker/internal/logging"
 	customMiddleware "github.com/aebalz/daily-vibe-tracker/internal/middleware"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/aebalz/daily-vibe-tracker/internal/ratelimit"
 
 	// Import docs for swagger
 	_ "github.com/aebalz/daily-vibe-tracker/docs"
@@ -25,32 +28,49 @@ import (
 const RequestIDKey = "requestID"
 
 // NewGinServer creates and configures a new Gin application.
-func NewGinServer(cfg *config.AppConfig, vibeHandler *handler.VibeHandler) *gin.Engine {
+// A nil limiter disables rate limiting. Requests are logged to logger.
+func NewGinServer(cfg *config.AppConfig, vibeHandler *handler.VibeHandler, limiter *ratelimit.Limiter, logger *slog.Logger) *gin.Engine {
 	if cfg.AppEnv == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	} else {
 		gin.SetMode(gin.DebugMode)
 	}
 
+	routes := vibeHandler.Routes()
 	router := gin.New()
+	// Like Fiber: no redirects for trailing slashes (routes are registered with and without
+	// one below), and 405 rather than 404 for known paths with another method.
+	router.RedirectTrailingSlash = false
+	router.HandleMethodNotAllowed = true
+	// Forwarding headers are read by ClientIPGin, from TRUSTED_PROXIES only; Gin's own
+	// ClientIP would trust every proxy.
+	if err := router.SetTrustedProxies(nil); err != nil {
+		log.Printf("Warning: Failed to reset the trusted proxies of Gin: %v", err)
+	}
 
-	// Middleware
-	router.Use(gin.Recovery())        // Recovery middleware
-	router.Use(requestIDMiddleware()) // Request ID middleware
-	router.Use(loggingMiddleware())   // Custom logging middleware
-	// Add Metrics and Rate Limiting middleware
+	// Middleware, in the same order as the Fiber server
+	router.Use(gin.CustomRecovery(recoveryHandler))              // Recovery middleware
+	router.Use(requestIDMiddleware())                            // Request ID middleware
+	router.Use(customMiddleware.ClientIPGin(cfg.TrustedProxies)) // Client IP behind trusted proxies
+	router.Use(customMiddleware.TracingGin())                    // Server span, child of the traceparent header
+	router.Use(customMiddleware.RequestLoggerGin(logger))        // Request logging
+	router.Use(customMiddleware.CORSGin(cfg.CorsAllowedOrigins))
+	// Add Metrics middleware; rate limiting runs per route, below, once the user is authenticated.
 	router.Use(customMiddleware.MetricsMiddlewareGin())
-	router.Use(customMiddleware.RateLimiterGin(cfg.RateLimitPerSecond, cfg.RateLimitBurst))
 
-	corsConfig := cors.DefaultConfig()
-	if len(cfg.CorsAllowedOrigins) == 1 && cfg.CorsAllowedOrigins[0] == "*" {
-		corsConfig.AllowAllOrigins = true
-	} else {
-		corsConfig.AllowOrigins = cfg.CorsAllowedOrigins
-	}
-	corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Request-ID"}
-	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
-	router.Use(cors.New(corsConfig))
+	// Errors have the {"error": message} shape of the handlers and of the Fiber server.
+	router.NoRoute(func(c *gin.Context) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Cannot " + c.Request.Method + " " + html.EscapeString(c.Request.URL.EscapedPath())})
+	})
+	router.NoMethod(func(c *gin.Context) {
+		// Allow lists the methods of the route table, as in Fiber, rather than Gin's own.
+		if allowed := handler.AllowedMethods(routes, c.Request.URL.Path); len(allowed) > 0 {
+			c.Header("Allow", strings.Join(allowed, ", "))
+		} else {
+			c.Writer.Header().Del("Allow")
+		}
+		c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Method Not Allowed"})
+	})
 
 	// Swagger UI
 	// BasePath for swagger UI itself. If docs.SwaggerInfo.BasePath is /api/v1,
@@ -60,90 +80,62 @@ func NewGinServer(cfg *config.AppConfig, vibeHandler *handler.VibeHandler) *gin.
 	url := ginSwagger.URL("/swagger/doc.json")
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggoFiles.Handler, url))
 
-	// Prometheus Metrics Endpoint
-	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
-
-	// Routes
-	// Health Check Route
-	if vibeHandler != nil && vibeHandler.HealthHandler != nil {
-		router.GET("/health", vibeHandler.HealthHandler.CheckHealthGin)
-	} else {
-		router.GET("/health", func(c *gin.Context) {
-			c.JSON(http.StatusOK, gin.H{"status": "initializing health handler"})
-		})
+	// API key authentication for the API routes, behind the limit of failed authentications per IP
+	var auth []gin.HandlerFunc
+	if vibeHandler != nil && vibeHandler.AuthHandler != nil {
+		auth = append(auth, customMiddleware.AuthFailureLimiterGin(limiter, logger), vibeHandler.AuthHandler.AuthenticateGin)
 	}
 
-	// Vibe Routes
-	apiV1 := router.Group("/api/v1") // All vibe routes will be under /api/v1
-	{
-		vibesGroup := apiV1.Group("/vibes")
-		// Example of group specific middleware:
-		// vibesGroup.Use(anotherMiddleware())
-
-		vibesGroup.POST("/", vibeHandler.CreateVibeGin)
-		vibesGroup.GET("/", vibeHandler.GetAllVibesGin)
-		vibesGroup.GET("/stats", vibeHandler.GetVibeStatsGin)
-		vibesGroup.GET("/today", vibeHandler.GetTodaysVibeRecommendationGin)
-		vibesGroup.GET("/streak", vibeHandler.GetMoodStreakGin)
-		vibesGroup.GET("/export", vibeHandler.ExportVibesGin)
-		vibesGroup.POST("/bulk", vibeHandler.BulkImportVibesGin)
-		vibesGroup.GET("/:id", vibeHandler.GetVibeByIDGin)
-		vibesGroup.PUT("/:id", vibeHandler.UpdateVibeGin)
-		vibesGroup.DELETE("/:id", vibeHandler.DeleteVibeGin)
+	// Routes, from the table shared with the Fiber server. GET routes also answer HEAD, as
+	// they do in Fiber. API routes are rate limited per user, under the policy of the route.
+	for _, route := range routes {
+		handlers := []gin.HandlerFunc{route.Gin}
+		if route.Auth {
+			handlers = append(append([]gin.HandlerFunc{}, auth...),
+				customMiddleware.RateLimiterGin(limiter, route.RateLimit, handler.UserIDKey, logger), route.Gin)
+		}
+		methods := []string{route.Method}
+		if route.Method == http.MethodGet {
+			methods = append(methods, http.MethodHead)
+		}
+		for _, method := range methods {
+			router.Handle(method, route.Path, handlers...)
+			router.Handle(method, route.Path+"/", handlers...)
+		}
 	}
 
 	return router
 }
 
-// requestIDMiddleware adds a request ID to each request.
+// recoveryHandler answers requests whose handler panicked like Fiber's recover middleware.
+func recoveryHandler(c *gin.Context, recovered interface{}) {
+	c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+}
+
+// requestIDMiddleware adds a request ID to each request, keeping the X-Request-ID of the
+// request when it has one, like Fiber's requestid middleware.
 func requestIDMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		requestID := uuid.New().String()
+		requestID := c.GetHeader("X-Request-ID")
+		if requestID == "" {
+			requestID = uuid.New().String()
+		}
 		c.Set(RequestIDKey, requestID)
+		// For the logger of the services and of GORM
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), requestID))
 		c.Writer.Header().Set("X-Request-ID", requestID)
 		c.Next()
 	}
 }
 
-// loggingMiddleware logs requests using a structured format.
-func loggingMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		start := time.Now()
-		path := c.Request.URL.Path
-		raw := c.Request.URL.RawQuery
-
-		c.Next() // Process request
-
-		// Log details after request has been processed
-		end := time.Now()
-		latency := end.Sub(start)
-		clientIP := c.ClientIP()
-		method := c.Request.Method
-		statusCode := c.Writer.Status()
-		errorMessage := c.Errors.ByType(gin.ErrorTypePrivate).String()
-		requestID, _ := c.Get(RequestIDKey)
-
-		if raw != "" {
-			path = path + "?" + raw
-		}
-
-		// Using standard log package for simplicity, can be replaced with zerolog or other structured logger
-		log.Printf("[GIN] %s | %3d | %13v | %15s | %s %s | %s | RequestID: %s",
-			end.Format("2006/01/02 - 15:04:05"),
-			statusCode,
-			latency,
-			clientIP,
-			method,
-			path,
-			errorMessage,
-			requestID,
-		)
-	}
-}
-
 // StartGinServer starts the Gin server.
+// With SERVER_FRAMEWORK=both it listens on GIN_PORT, next to the Fiber server.
 func StartGinServer(router *gin.Engine, cfg *config.AppConfig) (*http.Server, error) {
-	addr := fmt.Sprintf("%s:%d", cfg.ServerHost, cfg.ServerPort)
+	port := cfg.ServerPort
+	if cfg.ServerFramework == "both" {
+		port = cfg.GinPort
+	}
+	addr := fmt.Sprintf("%s:%d", cfg.ServerHost, port)
 
 	srv := &http.Server{
 		Addr:         addr,
@@ -0,0 +1,89 @@
+package grpc
+
+import (
+	"fmt"
+	"log"
+	"log/slog"
+	"net"
+	"time"
+
+	"github.com/aebalz/daily-vibe-tracker/internal/config"
+	"github.com/aebalz/daily-vibe-tracker/internal/handler"
+	"github.com/aebalz/daily-vibe-tracker/internal/middleware"
+	vibev1 "github.com/aebalz/daily-vibe-tracker/pkg/proto/vibe/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// NewGRPCServer creates a gRPC server serving the vibe API, the health service and reflection.
+// Calls are logged to logger.
+func NewGRPCServer(cfg *config.AppConfig, vibeHandler *handler.VibeGRPCHandler, logger *slog.Logger) *grpc.Server {
+	if len(cfg.GRPCAPIKeys) == 0 {
+		log.Println("Warning: GRPC_API_KEYS is empty. The gRPC API accepts calls without credentials.")
+	}
+
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			middleware.GRPCLoggingUnaryInterceptor(logger),
+			middleware.GRPCMetricsUnaryInterceptor(),
+			middleware.GRPCAuthUnaryInterceptor(cfg.GRPCAPIKeys),
+		),
+		grpc.ChainStreamInterceptor(
+			middleware.GRPCLoggingStreamInterceptor(logger),
+			middleware.GRPCMetricsStreamInterceptor(),
+			middleware.GRPCAuthStreamInterceptor(cfg.GRPCAPIKeys),
+		),
+	)
+
+	vibev1.RegisterVibeServiceServer(srv, vibeHandler)
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus(vibev1.VibeService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(srv, healthServer)
+
+	reflection.Register(srv)
+
+	return srv
+}
+
+// StartGRPCServer listens on the gRPC port and serves in the background.
+func StartGRPCServer(srv *grpc.Server, cfg *config.AppConfig) error {
+	addr := fmt.Sprintf("%s:%d", cfg.ServerHost, cfg.GRPCPort)
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("could not listen on %s: %w", addr, err)
+	}
+
+	log.Printf("Starting gRPC server on %s", addr)
+
+	go func() {
+		if err := srv.Serve(lis); err != nil && err != grpc.ErrServerStopped {
+			log.Fatalf("grpc serve: %s\n", err)
+		}
+	}()
+
+	return nil
+}
+
+// ShutdownGRPCServer lets running calls finish, and cancels them after the timeout.
+func ShutdownGRPCServer(srv *grpc.Server, timeout time.Duration) {
+	log.Println("Shutting down gRPC server...")
+
+	done := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		log.Println("gRPC server did not stop in time, closing open calls")
+		srv.Stop()
+	}
+
+	log.Println("gRPC server exiting")
+}
@@ -0,0 +1,6 @@
+// Package proto holds the Go code generated from the definitions in /proto.
+// Regenerate it with `go generate ./pkg/proto` after changing a .proto file; this needs
+// protoc, protoc-gen-go and protoc-gen-go-grpc on the PATH.
+package proto
+
+//go:generate protoc -I ../../proto --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative vibe/v1/vibe.proto
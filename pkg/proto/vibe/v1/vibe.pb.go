@@ -0,0 +1,1346 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: vibe/v1/vibe.proto
+
+// Package vibe.v1 is the gRPC API of the Daily Vibe Tracker. It mirrors the REST vibe
+// endpoints under /api/v1/vibes.
+
+package vibev1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Period int32
+
+const (
+	// The current month.
+	Period_PERIOD_UNSPECIFIED Period = 0
+	Period_PERIOD_WEEK        Period = 1
+	Period_PERIOD_MONTH       Period = 2
+	Period_PERIOD_YEAR        Period = 3
+)
+
+// Enum value maps for Period.
+var (
+	Period_name = map[int32]string{
+		0: "PERIOD_UNSPECIFIED",
+		1: "PERIOD_WEEK",
+		2: "PERIOD_MONTH",
+		3: "PERIOD_YEAR",
+	}
+	Period_value = map[string]int32{
+		"PERIOD_UNSPECIFIED": 0,
+		"PERIOD_WEEK":        1,
+		"PERIOD_MONTH":       2,
+		"PERIOD_YEAR":        3,
+	}
+)
+
+func (x Period) Enum() *Period {
+	p := new(Period)
+	*p = x
+	return p
+}
+
+func (x Period) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Period) Descriptor() protoreflect.EnumDescriptor {
+	return file_vibe_v1_vibe_proto_enumTypes[0].Descriptor()
+}
+
+func (Period) Type() protoreflect.EnumType {
+	return &file_vibe_v1_vibe_proto_enumTypes[0]
+}
+
+func (x Period) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Period.Descriptor instead.
+func (Period) EnumDescriptor() ([]byte, []int) {
+	return file_vibe_v1_vibe_proto_rawDescGZIP(), []int{0}
+}
+
+type Vibe struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Date        *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"`
+	Mood        string                 `protobuf:"bytes,3,opt,name=mood,proto3" json:"mood,omitempty"`
+	EnergyLevel int32                  `protobuf:"varint,4,opt,name=energy_level,json=energyLevel,proto3" json:"energy_level,omitempty"`
+	Notes       string                 `protobuf:"bytes,5,opt,name=notes,proto3" json:"notes,omitempty"`
+	Activities  []string               `protobuf:"bytes,6,rep,name=activities,proto3" json:"activities,omitempty"`
+	// Custom metric values keyed by metric name, e.g. {"sleep_hours": 7.5}.
+	Metrics       *structpb.Struct       `protobuf:"bytes,7,opt,name=metrics,proto3" json:"metrics,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Vibe) Reset() {
+	*x = Vibe{}
+	mi := &file_vibe_v1_vibe_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Vibe) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Vibe) ProtoMessage() {}
+
+func (x *Vibe) ProtoReflect() protoreflect.Message {
+	mi := &file_vibe_v1_vibe_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Vibe.ProtoReflect.Descriptor instead.
+func (*Vibe) Descriptor() ([]byte, []int) {
+	return file_vibe_v1_vibe_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Vibe) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Vibe) GetDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Date
+	}
+	return nil
+}
+
+func (x *Vibe) GetMood() string {
+	if x != nil {
+		return x.Mood
+	}
+	return ""
+}
+
+func (x *Vibe) GetEnergyLevel() int32 {
+	if x != nil {
+		return x.EnergyLevel
+	}
+	return 0
+}
+
+func (x *Vibe) GetNotes() string {
+	if x != nil {
+		return x.Notes
+	}
+	return ""
+}
+
+func (x *Vibe) GetActivities() []string {
+	if x != nil {
+		return x.Activities
+	}
+	return nil
+}
+
+func (x *Vibe) GetMetrics() *structpb.Struct {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
+}
+
+func (x *Vibe) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Vibe) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// VibeInput holds the writable fields of a vibe.
+type VibeInput struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Date  *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
+	// Mood name or alias from the mood catalog.
+	Mood string `protobuf:"bytes,2,opt,name=mood,proto3" json:"mood,omitempty"`
+	// 1 to 10.
+	EnergyLevel   int32            `protobuf:"varint,3,opt,name=energy_level,json=energyLevel,proto3" json:"energy_level,omitempty"`
+	Notes         string           `protobuf:"bytes,4,opt,name=notes,proto3" json:"notes,omitempty"`
+	Activities    []string         `protobuf:"bytes,5,rep,name=activities,proto3" json:"activities,omitempty"`
+	Metrics       *structpb.Struct `protobuf:"bytes,6,opt,name=metrics,proto3" json:"metrics,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VibeInput) Reset() {
+	*x = VibeInput{}
+	mi := &file_vibe_v1_vibe_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VibeInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VibeInput) ProtoMessage() {}
+
+func (x *VibeInput) ProtoReflect() protoreflect.Message {
+	mi := &file_vibe_v1_vibe_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VibeInput.ProtoReflect.Descriptor instead.
+func (*VibeInput) Descriptor() ([]byte, []int) {
+	return file_vibe_v1_vibe_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *VibeInput) GetDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Date
+	}
+	return nil
+}
+
+func (x *VibeInput) GetMood() string {
+	if x != nil {
+		return x.Mood
+	}
+	return ""
+}
+
+func (x *VibeInput) GetEnergyLevel() int32 {
+	if x != nil {
+		return x.EnergyLevel
+	}
+	return 0
+}
+
+func (x *VibeInput) GetNotes() string {
+	if x != nil {
+		return x.Notes
+	}
+	return ""
+}
+
+func (x *VibeInput) GetActivities() []string {
+	if x != nil {
+		return x.Activities
+	}
+	return nil
+}
+
+func (x *VibeInput) GetMetrics() *structpb.Struct {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
+}
+
+type VibeFilter struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Day in YYYY-MM-DD form.
+	Date string `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
+	Mood string `protobuf:"bytes,2,opt,name=mood,proto3" json:"mood,omitempty"`
+	// Custom metric filters as name:op:value, e.g. sleep_hours:gte:7.
+	Metrics       []string `protobuf:"bytes,3,rep,name=metrics,proto3" json:"metrics,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VibeFilter) Reset() {
+	*x = VibeFilter{}
+	mi := &file_vibe_v1_vibe_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VibeFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VibeFilter) ProtoMessage() {}
+
+func (x *VibeFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_vibe_v1_vibe_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VibeFilter.ProtoReflect.Descriptor instead.
+func (*VibeFilter) Descriptor() ([]byte, []int) {
+	return file_vibe_v1_vibe_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *VibeFilter) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *VibeFilter) GetMood() string {
+	if x != nil {
+		return x.Mood
+	}
+	return ""
+}
+
+func (x *VibeFilter) GetMetrics() []string {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
+}
+
+type CreateVibeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Vibe          *VibeInput             `protobuf:"bytes,1,opt,name=vibe,proto3" json:"vibe,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateVibeRequest) Reset() {
+	*x = CreateVibeRequest{}
+	mi := &file_vibe_v1_vibe_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateVibeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateVibeRequest) ProtoMessage() {}
+
+func (x *CreateVibeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vibe_v1_vibe_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateVibeRequest.ProtoReflect.Descriptor instead.
+func (*CreateVibeRequest) Descriptor() ([]byte, []int) {
+	return file_vibe_v1_vibe_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateVibeRequest) GetVibe() *VibeInput {
+	if x != nil {
+		return x.Vibe
+	}
+	return nil
+}
+
+type GetVibeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetVibeRequest) Reset() {
+	*x = GetVibeRequest{}
+	mi := &file_vibe_v1_vibe_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVibeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVibeRequest) ProtoMessage() {}
+
+func (x *GetVibeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vibe_v1_vibe_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVibeRequest.ProtoReflect.Descriptor instead.
+func (*GetVibeRequest) Descriptor() ([]byte, []int) {
+	return file_vibe_v1_vibe_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetVibeRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ListVibesRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Filter *VibeFilter            `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	// Page size, 1 to 100. Defaults to 10.
+	Limit  int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset int32 `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	// date, mood, energy_level, created_at, updated_at or metric:<name>. Defaults to date.
+	SortBy string `protobuf:"bytes,4,opt,name=sort_by,json=sortBy,proto3" json:"sort_by,omitempty"`
+	// asc or desc. Defaults to desc.
+	SortOrder     string `protobuf:"bytes,5,opt,name=sort_order,json=sortOrder,proto3" json:"sort_order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListVibesRequest) Reset() {
+	*x = ListVibesRequest{}
+	mi := &file_vibe_v1_vibe_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListVibesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVibesRequest) ProtoMessage() {}
+
+func (x *ListVibesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vibe_v1_vibe_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVibesRequest.ProtoReflect.Descriptor instead.
+func (*ListVibesRequest) Descriptor() ([]byte, []int) {
+	return file_vibe_v1_vibe_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListVibesRequest) GetFilter() *VibeFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *ListVibesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListVibesRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ListVibesRequest) GetSortBy() string {
+	if x != nil {
+		return x.SortBy
+	}
+	return ""
+}
+
+func (x *ListVibesRequest) GetSortOrder() string {
+	if x != nil {
+		return x.SortOrder
+	}
+	return ""
+}
+
+type ListVibesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Vibes         []*Vibe                `protobuf:"bytes,1,rep,name=vibes,proto3" json:"vibes,omitempty"`
+	Total         int64                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListVibesResponse) Reset() {
+	*x = ListVibesResponse{}
+	mi := &file_vibe_v1_vibe_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListVibesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVibesResponse) ProtoMessage() {}
+
+func (x *ListVibesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vibe_v1_vibe_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVibesResponse.ProtoReflect.Descriptor instead.
+func (*ListVibesResponse) Descriptor() ([]byte, []int) {
+	return file_vibe_v1_vibe_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListVibesResponse) GetVibes() []*Vibe {
+	if x != nil {
+		return x.Vibes
+	}
+	return nil
+}
+
+func (x *ListVibesResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ListVibesResponse) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListVibesResponse) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type UpdateVibeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Vibe          *VibeInput             `protobuf:"bytes,2,opt,name=vibe,proto3" json:"vibe,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateVibeRequest) Reset() {
+	*x = UpdateVibeRequest{}
+	mi := &file_vibe_v1_vibe_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateVibeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateVibeRequest) ProtoMessage() {}
+
+func (x *UpdateVibeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vibe_v1_vibe_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateVibeRequest.ProtoReflect.Descriptor instead.
+func (*UpdateVibeRequest) Descriptor() ([]byte, []int) {
+	return file_vibe_v1_vibe_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UpdateVibeRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateVibeRequest) GetVibe() *VibeInput {
+	if x != nil {
+		return x.Vibe
+	}
+	return nil
+}
+
+type DeleteVibeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteVibeRequest) Reset() {
+	*x = DeleteVibeRequest{}
+	mi := &file_vibe_v1_vibe_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteVibeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteVibeRequest) ProtoMessage() {}
+
+func (x *DeleteVibeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vibe_v1_vibe_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteVibeRequest.ProtoReflect.Descriptor instead.
+func (*DeleteVibeRequest) Descriptor() ([]byte, []int) {
+	return file_vibe_v1_vibe_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DeleteVibeRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type GetStatisticsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Period        Period                 `protobuf:"varint,1,opt,name=period,proto3,enum=vibe.v1.Period" json:"period,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatisticsRequest) Reset() {
+	*x = GetStatisticsRequest{}
+	mi := &file_vibe_v1_vibe_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatisticsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatisticsRequest) ProtoMessage() {}
+
+func (x *GetStatisticsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vibe_v1_vibe_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatisticsRequest.ProtoReflect.Descriptor instead.
+func (*GetStatisticsRequest) Descriptor() ([]byte, []int) {
+	return file_vibe_v1_vibe_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetStatisticsRequest) GetPeriod() Period {
+	if x != nil {
+		return x.Period
+	}
+	return Period_PERIOD_UNSPECIFIED
+}
+
+type MoodCount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Mood          string                 `protobuf:"bytes,1,opt,name=mood,proto3" json:"mood,omitempty"`
+	Count         int32                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoodCount) Reset() {
+	*x = MoodCount{}
+	mi := &file_vibe_v1_vibe_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoodCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoodCount) ProtoMessage() {}
+
+func (x *MoodCount) ProtoReflect() protoreflect.Message {
+	mi := &file_vibe_v1_vibe_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoodCount.ProtoReflect.Descriptor instead.
+func (*MoodCount) Descriptor() ([]byte, []int) {
+	return file_vibe_v1_vibe_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *MoodCount) GetMood() string {
+	if x != nil {
+		return x.Mood
+	}
+	return ""
+}
+
+func (x *MoodCount) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type MetricAggregate struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type  string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Unit  string                 `protobuf:"bytes,3,opt,name=unit,proto3" json:"unit,omitempty"`
+	Count int64                  `protobuf:"varint,4,opt,name=count,proto3" json:"count,omitempty"`
+	// For bool metrics this is the share of true values.
+	Average       float64 `protobuf:"fixed64,5,opt,name=average,proto3" json:"average,omitempty"`
+	Min           float64 `protobuf:"fixed64,6,opt,name=min,proto3" json:"min,omitempty"`
+	Max           float64 `protobuf:"fixed64,7,opt,name=max,proto3" json:"max,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MetricAggregate) Reset() {
+	*x = MetricAggregate{}
+	mi := &file_vibe_v1_vibe_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MetricAggregate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetricAggregate) ProtoMessage() {}
+
+func (x *MetricAggregate) ProtoReflect() protoreflect.Message {
+	mi := &file_vibe_v1_vibe_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetricAggregate.ProtoReflect.Descriptor instead.
+func (*MetricAggregate) Descriptor() ([]byte, []int) {
+	return file_vibe_v1_vibe_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *MetricAggregate) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *MetricAggregate) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *MetricAggregate) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *MetricAggregate) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *MetricAggregate) GetAverage() float64 {
+	if x != nil {
+		return x.Average
+	}
+	return 0
+}
+
+func (x *MetricAggregate) GetMin() float64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *MetricAggregate) GetMax() float64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+type Statistics struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	AverageEnergyLevel float64                `protobuf:"fixed64,1,opt,name=average_energy_level,json=averageEnergyLevel,proto3" json:"average_energy_level,omitempty"`
+	MoodDistribution   []*MoodCount           `protobuf:"bytes,2,rep,name=mood_distribution,json=moodDistribution,proto3" json:"mood_distribution,omitempty"`
+	MetricAggregates   []*MetricAggregate     `protobuf:"bytes,3,rep,name=metric_aggregates,json=metricAggregates,proto3" json:"metric_aggregates,omitempty"`
+	// Mood patterns and correlations, in the same form as the REST statistics.
+	Analytics     *structpb.Struct `protobuf:"bytes,4,opt,name=analytics,proto3" json:"analytics,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Statistics) Reset() {
+	*x = Statistics{}
+	mi := &file_vibe_v1_vibe_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Statistics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Statistics) ProtoMessage() {}
+
+func (x *Statistics) ProtoReflect() protoreflect.Message {
+	mi := &file_vibe_v1_vibe_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Statistics.ProtoReflect.Descriptor instead.
+func (*Statistics) Descriptor() ([]byte, []int) {
+	return file_vibe_v1_vibe_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Statistics) GetAverageEnergyLevel() float64 {
+	if x != nil {
+		return x.AverageEnergyLevel
+	}
+	return 0
+}
+
+func (x *Statistics) GetMoodDistribution() []*MoodCount {
+	if x != nil {
+		return x.MoodDistribution
+	}
+	return nil
+}
+
+func (x *Statistics) GetMetricAggregates() []*MetricAggregate {
+	if x != nil {
+		return x.MetricAggregates
+	}
+	return nil
+}
+
+func (x *Statistics) GetAnalytics() *structpb.Struct {
+	if x != nil {
+		return x.Analytics
+	}
+	return nil
+}
+
+type GetMoodStreakRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Mood          string                 `protobuf:"bytes,1,opt,name=mood,proto3" json:"mood,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMoodStreakRequest) Reset() {
+	*x = GetMoodStreakRequest{}
+	mi := &file_vibe_v1_vibe_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMoodStreakRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMoodStreakRequest) ProtoMessage() {}
+
+func (x *GetMoodStreakRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vibe_v1_vibe_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMoodStreakRequest.ProtoReflect.Descriptor instead.
+func (*GetMoodStreakRequest) Descriptor() ([]byte, []int) {
+	return file_vibe_v1_vibe_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetMoodStreakRequest) GetMood() string {
+	if x != nil {
+		return x.Mood
+	}
+	return ""
+}
+
+type MoodStreak struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Mood          string                 `protobuf:"bytes,1,opt,name=mood,proto3" json:"mood,omitempty"`
+	CurrentStreak int32                  `protobuf:"varint,2,opt,name=current_streak,json=currentStreak,proto3" json:"current_streak,omitempty"`
+	LongestStreak int32                  `protobuf:"varint,3,opt,name=longest_streak,json=longestStreak,proto3" json:"longest_streak,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoodStreak) Reset() {
+	*x = MoodStreak{}
+	mi := &file_vibe_v1_vibe_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoodStreak) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoodStreak) ProtoMessage() {}
+
+func (x *MoodStreak) ProtoReflect() protoreflect.Message {
+	mi := &file_vibe_v1_vibe_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoodStreak.ProtoReflect.Descriptor instead.
+func (*MoodStreak) Descriptor() ([]byte, []int) {
+	return file_vibe_v1_vibe_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *MoodStreak) GetMood() string {
+	if x != nil {
+		return x.Mood
+	}
+	return ""
+}
+
+func (x *MoodStreak) GetCurrentStreak() int32 {
+	if x != nil {
+		return x.CurrentStreak
+	}
+	return 0
+}
+
+func (x *MoodStreak) GetLongestStreak() int32 {
+	if x != nil {
+		return x.LongestStreak
+	}
+	return 0
+}
+
+type ExportVibesRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Filter *VibeFilter            `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	SortBy string                 `protobuf:"bytes,2,opt,name=sort_by,json=sortBy,proto3" json:"sort_by,omitempty"`
+	// asc or desc. Defaults to asc.
+	SortOrder     string `protobuf:"bytes,3,opt,name=sort_order,json=sortOrder,proto3" json:"sort_order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportVibesRequest) Reset() {
+	*x = ExportVibesRequest{}
+	mi := &file_vibe_v1_vibe_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportVibesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportVibesRequest) ProtoMessage() {}
+
+func (x *ExportVibesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vibe_v1_vibe_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportVibesRequest.ProtoReflect.Descriptor instead.
+func (*ExportVibesRequest) Descriptor() ([]byte, []int) {
+	return file_vibe_v1_vibe_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ExportVibesRequest) GetFilter() *VibeFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *ExportVibesRequest) GetSortBy() string {
+	if x != nil {
+		return x.SortBy
+	}
+	return ""
+}
+
+func (x *ExportVibesRequest) GetSortOrder() string {
+	if x != nil {
+		return x.SortOrder
+	}
+	return ""
+}
+
+type BulkImportVibesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ImportedCount int64                  `protobuf:"varint,1,opt,name=imported_count,json=importedCount,proto3" json:"imported_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkImportVibesResponse) Reset() {
+	*x = BulkImportVibesResponse{}
+	mi := &file_vibe_v1_vibe_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkImportVibesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkImportVibesResponse) ProtoMessage() {}
+
+func (x *BulkImportVibesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vibe_v1_vibe_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkImportVibesResponse.ProtoReflect.Descriptor instead.
+func (*BulkImportVibesResponse) Descriptor() ([]byte, []int) {
+	return file_vibe_v1_vibe_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *BulkImportVibesResponse) GetImportedCount() int64 {
+	if x != nil {
+		return x.ImportedCount
+	}
+	return 0
+}
+
+var File_vibe_v1_vibe_proto protoreflect.FileDescriptor
+
+const file_vibe_v1_vibe_proto_rawDesc = "" +
+	"\n" +
+	"\x12vibe/v1/vibe.proto\x12\avibe.v1\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xdc\x02\n" +
+	"\x04Vibe\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12.\n" +
+	"\x04date\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04date\x12\x12\n" +
+	"\x04mood\x18\x03 \x01(\tR\x04mood\x12!\n" +
+	"\fenergy_level\x18\x04 \x01(\x05R\venergyLevel\x12\x14\n" +
+	"\x05notes\x18\x05 \x01(\tR\x05notes\x12\x1e\n" +
+	"\n" +
+	"activities\x18\x06 \x03(\tR\n" +
+	"activities\x121\n" +
+	"\ametrics\x18\a \x01(\v2\x17.google.protobuf.StructR\ametrics\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xdb\x01\n" +
+	"\tVibeInput\x12.\n" +
+	"\x04date\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04date\x12\x12\n" +
+	"\x04mood\x18\x02 \x01(\tR\x04mood\x12!\n" +
+	"\fenergy_level\x18\x03 \x01(\x05R\venergyLevel\x12\x14\n" +
+	"\x05notes\x18\x04 \x01(\tR\x05notes\x12\x1e\n" +
+	"\n" +
+	"activities\x18\x05 \x03(\tR\n" +
+	"activities\x121\n" +
+	"\ametrics\x18\x06 \x01(\v2\x17.google.protobuf.StructR\ametrics\"N\n" +
+	"\n" +
+	"VibeFilter\x12\x12\n" +
+	"\x04date\x18\x01 \x01(\tR\x04date\x12\x12\n" +
+	"\x04mood\x18\x02 \x01(\tR\x04mood\x12\x18\n" +
+	"\ametrics\x18\x03 \x03(\tR\ametrics\";\n" +
+	"\x11CreateVibeRequest\x12&\n" +
+	"\x04vibe\x18\x01 \x01(\v2\x12.vibe.v1.VibeInputR\x04vibe\" \n" +
+	"\x0eGetVibeRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"\xa5\x01\n" +
+	"\x10ListVibesRequest\x12+\n" +
+	"\x06filter\x18\x01 \x01(\v2\x13.vibe.v1.VibeFilterR\x06filter\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x05R\x06offset\x12\x17\n" +
+	"\asort_by\x18\x04 \x01(\tR\x06sortBy\x12\x1d\n" +
+	"\n" +
+	"sort_order\x18\x05 \x01(\tR\tsortOrder\"|\n" +
+	"\x11ListVibesResponse\x12#\n" +
+	"\x05vibes\x18\x01 \x03(\v2\r.vibe.v1.VibeR\x05vibes\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x03R\x05total\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x04 \x01(\x05R\x06offset\"K\n" +
+	"\x11UpdateVibeRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12&\n" +
+	"\x04vibe\x18\x02 \x01(\v2\x12.vibe.v1.VibeInputR\x04vibe\"#\n" +
+	"\x11DeleteVibeRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"?\n" +
+	"\x14GetStatisticsRequest\x12'\n" +
+	"\x06period\x18\x01 \x01(\x0e2\x0f.vibe.v1.PeriodR\x06period\"5\n" +
+	"\tMoodCount\x12\x12\n" +
+	"\x04mood\x18\x01 \x01(\tR\x04mood\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x05R\x05count\"\xa1\x01\n" +
+	"\x0fMetricAggregate\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x12\n" +
+	"\x04unit\x18\x03 \x01(\tR\x04unit\x12\x14\n" +
+	"\x05count\x18\x04 \x01(\x03R\x05count\x12\x18\n" +
+	"\aaverage\x18\x05 \x01(\x01R\aaverage\x12\x10\n" +
+	"\x03min\x18\x06 \x01(\x01R\x03min\x12\x10\n" +
+	"\x03max\x18\a \x01(\x01R\x03max\"\xfd\x01\n" +
+	"\n" +
+	"Statistics\x120\n" +
+	"\x14average_energy_level\x18\x01 \x01(\x01R\x12averageEnergyLevel\x12?\n" +
+	"\x11mood_distribution\x18\x02 \x03(\v2\x12.vibe.v1.MoodCountR\x10moodDistribution\x12E\n" +
+	"\x11metric_aggregates\x18\x03 \x03(\v2\x18.vibe.v1.MetricAggregateR\x10metricAggregates\x125\n" +
+	"\tanalytics\x18\x04 \x01(\v2\x17.google.protobuf.StructR\tanalytics\"*\n" +
+	"\x14GetMoodStreakRequest\x12\x12\n" +
+	"\x04mood\x18\x01 \x01(\tR\x04mood\"n\n" +
+	"\n" +
+	"MoodStreak\x12\x12\n" +
+	"\x04mood\x18\x01 \x01(\tR\x04mood\x12%\n" +
+	"\x0ecurrent_streak\x18\x02 \x01(\x05R\rcurrentStreak\x12%\n" +
+	"\x0elongest_streak\x18\x03 \x01(\x05R\rlongestStreak\"y\n" +
+	"\x12ExportVibesRequest\x12+\n" +
+	"\x06filter\x18\x01 \x01(\v2\x13.vibe.v1.VibeFilterR\x06filter\x12\x17\n" +
+	"\asort_by\x18\x02 \x01(\tR\x06sortBy\x12\x1d\n" +
+	"\n" +
+	"sort_order\x18\x03 \x01(\tR\tsortOrder\"@\n" +
+	"\x17BulkImportVibesResponse\x12%\n" +
+	"\x0eimported_count\x18\x01 \x01(\x03R\rimportedCount*T\n" +
+	"\x06Period\x12\x16\n" +
+	"\x12PERIOD_UNSPECIFIED\x10\x00\x12\x0f\n" +
+	"\vPERIOD_WEEK\x10\x01\x12\x10\n" +
+	"\fPERIOD_MONTH\x10\x02\x12\x0f\n" +
+	"\vPERIOD_YEAR\x10\x032\xca\x04\n" +
+	"\vVibeService\x127\n" +
+	"\n" +
+	"CreateVibe\x12\x1a.vibe.v1.CreateVibeRequest\x1a\r.vibe.v1.Vibe\x121\n" +
+	"\aGetVibe\x12\x17.vibe.v1.GetVibeRequest\x1a\r.vibe.v1.Vibe\x12B\n" +
+	"\tListVibes\x12\x19.vibe.v1.ListVibesRequest\x1a\x1a.vibe.v1.ListVibesResponse\x127\n" +
+	"\n" +
+	"UpdateVibe\x12\x1a.vibe.v1.UpdateVibeRequest\x1a\r.vibe.v1.Vibe\x12@\n" +
+	"\n" +
+	"DeleteVibe\x12\x1a.vibe.v1.DeleteVibeRequest\x1a\x16.google.protobuf.Empty\x12C\n" +
+	"\rGetStatistics\x12\x1d.vibe.v1.GetStatisticsRequest\x1a\x13.vibe.v1.Statistics\x12C\n" +
+	"\rGetMoodStreak\x12\x1d.vibe.v1.GetMoodStreakRequest\x1a\x13.vibe.v1.MoodStreak\x12;\n" +
+	"\vExportVibes\x12\x1b.vibe.v1.ExportVibesRequest\x1a\r.vibe.v1.Vibe0\x01\x12I\n" +
+	"\x0fBulkImportVibes\x12\x12.vibe.v1.VibeInput\x1a .vibe.v1.BulkImportVibesResponse(\x01B?Z=github.com/aebalz/daily-vibe-tracker/pkg/proto/vibe/v1;vibev1b\x06proto3"
+
+var (
+	file_vibe_v1_vibe_proto_rawDescOnce sync.Once
+	file_vibe_v1_vibe_proto_rawDescData []byte
+)
+
+func file_vibe_v1_vibe_proto_rawDescGZIP() []byte {
+	file_vibe_v1_vibe_proto_rawDescOnce.Do(func() {
+		file_vibe_v1_vibe_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_vibe_v1_vibe_proto_rawDesc), len(file_vibe_v1_vibe_proto_rawDesc)))
+	})
+	return file_vibe_v1_vibe_proto_rawDescData
+}
+
+var file_vibe_v1_vibe_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_vibe_v1_vibe_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_vibe_v1_vibe_proto_goTypes = []any{
+	(Period)(0),                     // 0: vibe.v1.Period
+	(*Vibe)(nil),                    // 1: vibe.v1.Vibe
+	(*VibeInput)(nil),               // 2: vibe.v1.VibeInput
+	(*VibeFilter)(nil),              // 3: vibe.v1.VibeFilter
+	(*CreateVibeRequest)(nil),       // 4: vibe.v1.CreateVibeRequest
+	(*GetVibeRequest)(nil),          // 5: vibe.v1.GetVibeRequest
+	(*ListVibesRequest)(nil),        // 6: vibe.v1.ListVibesRequest
+	(*ListVibesResponse)(nil),       // 7: vibe.v1.ListVibesResponse
+	(*UpdateVibeRequest)(nil),       // 8: vibe.v1.UpdateVibeRequest
+	(*DeleteVibeRequest)(nil),       // 9: vibe.v1.DeleteVibeRequest
+	(*GetStatisticsRequest)(nil),    // 10: vibe.v1.GetStatisticsRequest
+	(*MoodCount)(nil),               // 11: vibe.v1.MoodCount
+	(*MetricAggregate)(nil),         // 12: vibe.v1.MetricAggregate
+	(*Statistics)(nil),              // 13: vibe.v1.Statistics
+	(*GetMoodStreakRequest)(nil),    // 14: vibe.v1.GetMoodStreakRequest
+	(*MoodStreak)(nil),              // 15: vibe.v1.MoodStreak
+	(*ExportVibesRequest)(nil),      // 16: vibe.v1.ExportVibesRequest
+	(*BulkImportVibesResponse)(nil), // 17: vibe.v1.BulkImportVibesResponse
+	(*timestamppb.Timestamp)(nil),   // 18: google.protobuf.Timestamp
+	(*structpb.Struct)(nil),         // 19: google.protobuf.Struct
+	(*emptypb.Empty)(nil),           // 20: google.protobuf.Empty
+}
+var file_vibe_v1_vibe_proto_depIdxs = []int32{
+	18, // 0: vibe.v1.Vibe.date:type_name -> google.protobuf.Timestamp
+	19, // 1: vibe.v1.Vibe.metrics:type_name -> google.protobuf.Struct
+	18, // 2: vibe.v1.Vibe.created_at:type_name -> google.protobuf.Timestamp
+	18, // 3: vibe.v1.Vibe.updated_at:type_name -> google.protobuf.Timestamp
+	18, // 4: vibe.v1.VibeInput.date:type_name -> google.protobuf.Timestamp
+	19, // 5: vibe.v1.VibeInput.metrics:type_name -> google.protobuf.Struct
+	2,  // 6: vibe.v1.CreateVibeRequest.vibe:type_name -> vibe.v1.VibeInput
+	3,  // 7: vibe.v1.ListVibesRequest.filter:type_name -> vibe.v1.VibeFilter
+	1,  // 8: vibe.v1.ListVibesResponse.vibes:type_name -> vibe.v1.Vibe
+	2,  // 9: vibe.v1.UpdateVibeRequest.vibe:type_name -> vibe.v1.VibeInput
+	0,  // 10: vibe.v1.GetStatisticsRequest.period:type_name -> vibe.v1.Period
+	11, // 11: vibe.v1.Statistics.mood_distribution:type_name -> vibe.v1.MoodCount
+	12, // 12: vibe.v1.Statistics.metric_aggregates:type_name -> vibe.v1.MetricAggregate
+	19, // 13: vibe.v1.Statistics.analytics:type_name -> google.protobuf.Struct
+	3,  // 14: vibe.v1.ExportVibesRequest.filter:type_name -> vibe.v1.VibeFilter
+	4,  // 15: vibe.v1.VibeService.CreateVibe:input_type -> vibe.v1.CreateVibeRequest
+	5,  // 16: vibe.v1.VibeService.GetVibe:input_type -> vibe.v1.GetVibeRequest
+	6,  // 17: vibe.v1.VibeService.ListVibes:input_type -> vibe.v1.ListVibesRequest
+	8,  // 18: vibe.v1.VibeService.UpdateVibe:input_type -> vibe.v1.UpdateVibeRequest
+	9,  // 19: vibe.v1.VibeService.DeleteVibe:input_type -> vibe.v1.DeleteVibeRequest
+	10, // 20: vibe.v1.VibeService.GetStatistics:input_type -> vibe.v1.GetStatisticsRequest
+	14, // 21: vibe.v1.VibeService.GetMoodStreak:input_type -> vibe.v1.GetMoodStreakRequest
+	16, // 22: vibe.v1.VibeService.ExportVibes:input_type -> vibe.v1.ExportVibesRequest
+	2,  // 23: vibe.v1.VibeService.BulkImportVibes:input_type -> vibe.v1.VibeInput
+	1,  // 24: vibe.v1.VibeService.CreateVibe:output_type -> vibe.v1.Vibe
+	1,  // 25: vibe.v1.VibeService.GetVibe:output_type -> vibe.v1.Vibe
+	7,  // 26: vibe.v1.VibeService.ListVibes:output_type -> vibe.v1.ListVibesResponse
+	1,  // 27: vibe.v1.VibeService.UpdateVibe:output_type -> vibe.v1.Vibe
+	20, // 28: vibe.v1.VibeService.DeleteVibe:output_type -> google.protobuf.Empty
+	13, // 29: vibe.v1.VibeService.GetStatistics:output_type -> vibe.v1.Statistics
+	15, // 30: vibe.v1.VibeService.GetMoodStreak:output_type -> vibe.v1.MoodStreak
+	1,  // 31: vibe.v1.VibeService.ExportVibes:output_type -> vibe.v1.Vibe
+	17, // 32: vibe.v1.VibeService.BulkImportVibes:output_type -> vibe.v1.BulkImportVibesResponse
+	24, // [24:33] is the sub-list for method output_type
+	15, // [15:24] is the sub-list for method input_type
+	15, // [15:15] is the sub-list for extension type_name
+	15, // [15:15] is the sub-list for extension extendee
+	0,  // [0:15] is the sub-list for field type_name
+}
+
+func init() { file_vibe_v1_vibe_proto_init() }
+func file_vibe_v1_vibe_proto_init() {
+	if File_vibe_v1_vibe_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_vibe_v1_vibe_proto_rawDesc), len(file_vibe_v1_vibe_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   17,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_vibe_v1_vibe_proto_goTypes,
+		DependencyIndexes: file_vibe_v1_vibe_proto_depIdxs,
+		EnumInfos:         file_vibe_v1_vibe_proto_enumTypes,
+		MessageInfos:      file_vibe_v1_vibe_proto_msgTypes,
+	}.Build()
+	File_vibe_v1_vibe_proto = out.File
+	file_vibe_v1_vibe_proto_goTypes = nil
+	file_vibe_v1_vibe_proto_depIdxs = nil
+}
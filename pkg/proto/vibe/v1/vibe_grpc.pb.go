@@ -0,0 +1,444 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: vibe/v1/vibe.proto
+
+// Package vibe.v1 is the gRPC API of the Daily Vibe Tracker. It mirrors the REST vibe
+// endpoints under /api/v1/vibes.
+
+package vibev1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	VibeService_CreateVibe_FullMethodName      = "/vibe.v1.VibeService/CreateVibe"
+	VibeService_GetVibe_FullMethodName         = "/vibe.v1.VibeService/GetVibe"
+	VibeService_ListVibes_FullMethodName       = "/vibe.v1.VibeService/ListVibes"
+	VibeService_UpdateVibe_FullMethodName      = "/vibe.v1.VibeService/UpdateVibe"
+	VibeService_DeleteVibe_FullMethodName      = "/vibe.v1.VibeService/DeleteVibe"
+	VibeService_GetStatistics_FullMethodName   = "/vibe.v1.VibeService/GetStatistics"
+	VibeService_GetMoodStreak_FullMethodName   = "/vibe.v1.VibeService/GetMoodStreak"
+	VibeService_ExportVibes_FullMethodName     = "/vibe.v1.VibeService/ExportVibes"
+	VibeService_BulkImportVibes_FullMethodName = "/vibe.v1.VibeService/BulkImportVibes"
+)
+
+// VibeServiceClient is the client API for VibeService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// VibeService manages daily vibes.
+type VibeServiceClient interface {
+	CreateVibe(ctx context.Context, in *CreateVibeRequest, opts ...grpc.CallOption) (*Vibe, error)
+	GetVibe(ctx context.Context, in *GetVibeRequest, opts ...grpc.CallOption) (*Vibe, error)
+	// ListVibes returns one page of vibes matching the filter.
+	ListVibes(ctx context.Context, in *ListVibesRequest, opts ...grpc.CallOption) (*ListVibesResponse, error)
+	// UpdateVibe replaces a vibe. The date and custom metrics are kept when they are not set.
+	UpdateVibe(ctx context.Context, in *UpdateVibeRequest, opts ...grpc.CallOption) (*Vibe, error)
+	DeleteVibe(ctx context.Context, in *DeleteVibeRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// GetStatistics summarizes the vibes of the current week, month or year.
+	GetStatistics(ctx context.Context, in *GetStatisticsRequest, opts ...grpc.CallOption) (*Statistics, error)
+	// GetMoodStreak returns the current and longest streak of consecutive days with a mood.
+	GetMoodStreak(ctx context.Context, in *GetMoodStreakRequest, opts ...grpc.CallOption) (*MoodStreak, error)
+	// ExportVibes streams every vibe matching the filter.
+	ExportVibes(ctx context.Context, in *ExportVibesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Vibe], error)
+	// BulkImportVibes imports the streamed vibes in one transaction once the client closes
+	// the stream. Nothing is imported if any vibe is invalid.
+	BulkImportVibes(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[VibeInput, BulkImportVibesResponse], error)
+}
+
+type vibeServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewVibeServiceClient(cc grpc.ClientConnInterface) VibeServiceClient {
+	return &vibeServiceClient{cc}
+}
+
+func (c *vibeServiceClient) CreateVibe(ctx context.Context, in *CreateVibeRequest, opts ...grpc.CallOption) (*Vibe, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Vibe)
+	err := c.cc.Invoke(ctx, VibeService_CreateVibe_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vibeServiceClient) GetVibe(ctx context.Context, in *GetVibeRequest, opts ...grpc.CallOption) (*Vibe, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Vibe)
+	err := c.cc.Invoke(ctx, VibeService_GetVibe_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vibeServiceClient) ListVibes(ctx context.Context, in *ListVibesRequest, opts ...grpc.CallOption) (*ListVibesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListVibesResponse)
+	err := c.cc.Invoke(ctx, VibeService_ListVibes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vibeServiceClient) UpdateVibe(ctx context.Context, in *UpdateVibeRequest, opts ...grpc.CallOption) (*Vibe, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Vibe)
+	err := c.cc.Invoke(ctx, VibeService_UpdateVibe_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vibeServiceClient) DeleteVibe(ctx context.Context, in *DeleteVibeRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, VibeService_DeleteVibe_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vibeServiceClient) GetStatistics(ctx context.Context, in *GetStatisticsRequest, opts ...grpc.CallOption) (*Statistics, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Statistics)
+	err := c.cc.Invoke(ctx, VibeService_GetStatistics_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vibeServiceClient) GetMoodStreak(ctx context.Context, in *GetMoodStreakRequest, opts ...grpc.CallOption) (*MoodStreak, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MoodStreak)
+	err := c.cc.Invoke(ctx, VibeService_GetMoodStreak_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vibeServiceClient) ExportVibes(ctx context.Context, in *ExportVibesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Vibe], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &VibeService_ServiceDesc.Streams[0], VibeService_ExportVibes_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportVibesRequest, Vibe]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type VibeService_ExportVibesClient = grpc.ServerStreamingClient[Vibe]
+
+func (c *vibeServiceClient) BulkImportVibes(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[VibeInput, BulkImportVibesResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &VibeService_ServiceDesc.Streams[1], VibeService_BulkImportVibes_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[VibeInput, BulkImportVibesResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type VibeService_BulkImportVibesClient = grpc.ClientStreamingClient[VibeInput, BulkImportVibesResponse]
+
+// VibeServiceServer is the server API for VibeService service.
+// All implementations must embed UnimplementedVibeServiceServer
+// for forward compatibility.
+//
+// VibeService manages daily vibes.
+type VibeServiceServer interface {
+	CreateVibe(context.Context, *CreateVibeRequest) (*Vibe, error)
+	GetVibe(context.Context, *GetVibeRequest) (*Vibe, error)
+	// ListVibes returns one page of vibes matching the filter.
+	ListVibes(context.Context, *ListVibesRequest) (*ListVibesResponse, error)
+	// UpdateVibe replaces a vibe. The date and custom metrics are kept when they are not set.
+	UpdateVibe(context.Context, *UpdateVibeRequest) (*Vibe, error)
+	DeleteVibe(context.Context, *DeleteVibeRequest) (*emptypb.Empty, error)
+	// GetStatistics summarizes the vibes of the current week, month or year.
+	GetStatistics(context.Context, *GetStatisticsRequest) (*Statistics, error)
+	// GetMoodStreak returns the current and longest streak of consecutive days with a mood.
+	GetMoodStreak(context.Context, *GetMoodStreakRequest) (*MoodStreak, error)
+	// ExportVibes streams every vibe matching the filter.
+	ExportVibes(*ExportVibesRequest, grpc.ServerStreamingServer[Vibe]) error
+	// BulkImportVibes imports the streamed vibes in one transaction once the client closes
+	// the stream. Nothing is imported if any vibe is invalid.
+	BulkImportVibes(grpc.ClientStreamingServer[VibeInput, BulkImportVibesResponse]) error
+	mustEmbedUnimplementedVibeServiceServer()
+}
+
+// UnimplementedVibeServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedVibeServiceServer struct{}
+
+func (UnimplementedVibeServiceServer) CreateVibe(context.Context, *CreateVibeRequest) (*Vibe, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateVibe not implemented")
+}
+func (UnimplementedVibeServiceServer) GetVibe(context.Context, *GetVibeRequest) (*Vibe, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVibe not implemented")
+}
+func (UnimplementedVibeServiceServer) ListVibes(context.Context, *ListVibesRequest) (*ListVibesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListVibes not implemented")
+}
+func (UnimplementedVibeServiceServer) UpdateVibe(context.Context, *UpdateVibeRequest) (*Vibe, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateVibe not implemented")
+}
+func (UnimplementedVibeServiceServer) DeleteVibe(context.Context, *DeleteVibeRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteVibe not implemented")
+}
+func (UnimplementedVibeServiceServer) GetStatistics(context.Context, *GetStatisticsRequest) (*Statistics, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatistics not implemented")
+}
+func (UnimplementedVibeServiceServer) GetMoodStreak(context.Context, *GetMoodStreakRequest) (*MoodStreak, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMoodStreak not implemented")
+}
+func (UnimplementedVibeServiceServer) ExportVibes(*ExportVibesRequest, grpc.ServerStreamingServer[Vibe]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportVibes not implemented")
+}
+func (UnimplementedVibeServiceServer) BulkImportVibes(grpc.ClientStreamingServer[VibeInput, BulkImportVibesResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method BulkImportVibes not implemented")
+}
+func (UnimplementedVibeServiceServer) mustEmbedUnimplementedVibeServiceServer() {}
+func (UnimplementedVibeServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafeVibeServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to VibeServiceServer will
+// result in compilation errors.
+type UnsafeVibeServiceServer interface {
+	mustEmbedUnimplementedVibeServiceServer()
+}
+
+func RegisterVibeServiceServer(s grpc.ServiceRegistrar, srv VibeServiceServer) {
+	// If the following call pancis, it indicates UnimplementedVibeServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&VibeService_ServiceDesc, srv)
+}
+
+func _VibeService_CreateVibe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateVibeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VibeServiceServer).CreateVibe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VibeService_CreateVibe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VibeServiceServer).CreateVibe(ctx, req.(*CreateVibeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VibeService_GetVibe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVibeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VibeServiceServer).GetVibe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VibeService_GetVibe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VibeServiceServer).GetVibe(ctx, req.(*GetVibeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VibeService_ListVibes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListVibesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VibeServiceServer).ListVibes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VibeService_ListVibes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VibeServiceServer).ListVibes(ctx, req.(*ListVibesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VibeService_UpdateVibe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateVibeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VibeServiceServer).UpdateVibe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VibeService_UpdateVibe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VibeServiceServer).UpdateVibe(ctx, req.(*UpdateVibeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VibeService_DeleteVibe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteVibeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VibeServiceServer).DeleteVibe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VibeService_DeleteVibe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VibeServiceServer).DeleteVibe(ctx, req.(*DeleteVibeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VibeService_GetStatistics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatisticsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VibeServiceServer).GetStatistics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VibeService_GetStatistics_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VibeServiceServer).GetStatistics(ctx, req.(*GetStatisticsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VibeService_GetMoodStreak_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMoodStreakRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VibeServiceServer).GetMoodStreak(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VibeService_GetMoodStreak_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VibeServiceServer).GetMoodStreak(ctx, req.(*GetMoodStreakRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VibeService_ExportVibes_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportVibesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(VibeServiceServer).ExportVibes(m, &grpc.GenericServerStream[ExportVibesRequest, Vibe]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type VibeService_ExportVibesServer = grpc.ServerStreamingServer[Vibe]
+
+func _VibeService_BulkImportVibes_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(VibeServiceServer).BulkImportVibes(&grpc.GenericServerStream[VibeInput, BulkImportVibesResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type VibeService_BulkImportVibesServer = grpc.ClientStreamingServer[VibeInput, BulkImportVibesResponse]
+
+// VibeService_ServiceDesc is the grpc.ServiceDesc for VibeService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var VibeService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "vibe.v1.VibeService",
+	HandlerType: (*VibeServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateVibe",
+			Handler:    _VibeService_CreateVibe_Handler,
+		},
+		{
+			MethodName: "GetVibe",
+			Handler:    _VibeService_GetVibe_Handler,
+		},
+		{
+			MethodName: "ListVibes",
+			Handler:    _VibeService_ListVibes_Handler,
+		},
+		{
+			MethodName: "UpdateVibe",
+			Handler:    _VibeService_UpdateVibe_Handler,
+		},
+		{
+			MethodName: "DeleteVibe",
+			Handler:    _VibeService_DeleteVibe_Handler,
+		},
+		{
+			MethodName: "GetStatistics",
+			Handler:    _VibeService_GetStatistics_Handler,
+		},
+		{
+			MethodName: "GetMoodStreak",
+			Handler:    _VibeService_GetMoodStreak_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExportVibes",
+			Handler:       _VibeService_ExportVibes_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "BulkImportVibes",
+			Handler:       _VibeService_BulkImportVibes_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "vibe/v1/vibe.proto",
+}